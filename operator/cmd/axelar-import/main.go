@@ -0,0 +1,96 @@
+// Command axelar-import converts a node run with the axelar-node Helm chart
+// or the axelarate-community scripts into an AxelarNode manifest.
+//
+//	axelar-import --release my-node --values values.yaml --values values-validator.yaml --adopt
+//	axelar-import --config-dir ~/.axelar --image-tag v0.35.5
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/importer"
+)
+
+// stringList is a flag that can be repeated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func main() {
+	var (
+		values    stringList
+		release   string
+		configDir string
+		output    string
+		opts      importer.Options
+	)
+	flag.Var(&values, "values", "Values file of the Helm release, can be repeated and is merged in order like helm -f")
+	flag.StringVar(&release, "release", "", "Name of the Helm release")
+	flag.StringVar(&configDir, "config-dir", "", "axelard home directory to import instead of Helm values, such as ~/.axelar")
+	flag.StringVar(&opts.Name, "name", "", "Name of the AxelarNode, defaults to the full name of the release or the moniker")
+	flag.StringVar(&opts.Namespace, "namespace", "", "Namespace of the AxelarNode")
+	flag.BoolVar(&opts.Adopt, "adopt", false, "Take over the Deployment, Service and data PVC of the Helm release instead of creating new ones")
+	flag.StringVar(&opts.ImageTag, "image-tag", "", "axelar-core version of the node, overriding the one of the values")
+	flag.StringVar(&output, "o", "", "File the manifest is written to, stdout when empty")
+	flag.Parse()
+
+	result, err := run(values, release, configDir, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "axelar-import: %v\n", err)
+		os.Exit(1)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
+	manifest, err := importer.Manifest(result.Node)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "axelar-import: %v\n", err)
+		os.Exit(1)
+	}
+	if output == "" {
+		os.Stdout.Write(manifest)
+		return
+	}
+	if err := os.WriteFile(output, manifest, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "axelar-import: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run imports the node from the Helm values or the home directory
+func run(values []string, release, configDir string, opts importer.Options) (*importer.Result, error) {
+	switch {
+	case configDir != "" && len(values) > 0:
+		return nil, fmt.Errorf("--config-dir and --values are exclusive")
+	case configDir != "":
+		if opts.Adopt {
+			return nil, fmt.Errorf("--adopt only applies to Helm releases")
+		}
+		return importer.FromConfigDir(configDir, opts)
+	case len(values) > 0:
+		if release == "" {
+			return nil, fmt.Errorf("--release is required with --values")
+		}
+		files := make([][]byte, 0, len(values))
+		for _, path := range values {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, data)
+		}
+		return importer.FromHelmValues(release, files, opts)
+	}
+	return nil, fmt.Errorf("either --values or --config-dir is required")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// loadSchema returns the OpenAPI schema of the served version of the CRD
+func loadSchema(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the AxelarNode CRD, set --crd: %w", err)
+	}
+	var crd struct {
+		Spec struct {
+			Versions []struct {
+				Name   string `json:"name"`
+				Schema struct {
+					OpenAPIV3Schema map[string]interface{} `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := sigsyaml.Unmarshal(data, &crd); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Name == blockchainv1alpha1.SchemeGroupVersion.Version {
+			return version.Schema.OpenAPIV3Schema, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no %s schema", path, blockchainv1alpha1.SchemeGroupVersion.Version)
+}
+
+// applyDefaults sets the defaults of the schema on the fields the object
+// leaves unset, recursing into the objects and arrays it holds, as the API
+// server does when the node is created
+func applyDefaults(object map[string]interface{}, schema map[string]interface{}) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	for key, value := range properties {
+		property, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, set := object[key]; !set {
+			if def, ok := property["default"]; ok {
+				object[key] = runtime.DeepCopyJSONValue(def)
+			}
+		}
+		defaultValue(object[key], property)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		for key := range object {
+			if _, known := properties[key]; !known {
+				defaultValue(object[key], additional)
+			}
+		}
+	}
+}
+
+// defaultValue applies the defaults of the schema within a value
+func defaultValue(value interface{}, schema map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		applyDefaults(v, schema)
+	case []interface{}:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range v {
+			defaultValue(item, items)
+		}
+	}
+}
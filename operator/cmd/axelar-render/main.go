@@ -0,0 +1,156 @@
+// Command axelar-render prints the manifests the operator creates for the
+// AxelarNodes of a file, without a cluster, so they can be reviewed and
+// diffed in CI.
+//
+//	axelar-render -f node.yaml
+//	axelar-render -f node.yaml --crd config/crd/axelarnode-crd.yaml --namespace axelar
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/controller"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(blockchainv1alpha1.AddToScheme(scheme))
+}
+
+func main() {
+	var (
+		file      string
+		crd       string
+		namespace string
+		output    string
+	)
+	reconciler := &controller.AxelarNodeReconciler{Log: logr.Discard(), Scheme: scheme}
+	flag.StringVar(&file, "f", "-", "File holding the AxelarNodes, stdin when -")
+	flag.StringVar(&crd, "crd", "config/crd/axelarnode-crd.yaml", "AxelarNode CRD whose defaults are applied to the nodes, like the API server does")
+	flag.StringVar(&namespace, "namespace", "default", "Namespace of the nodes that do not set one")
+	flag.StringVar(&reconciler.ProxyImage, "rpc-proxy-image", controller.DefaultProxyImage, "The image of the RPC proxy sidecar, as set on the operator")
+	flag.StringVar(&reconciler.ToolsImage, "tools-image", controller.DefaultToolsImage, "The image of the tools init containers, as set on the operator")
+	flag.StringVar(&reconciler.TLSProxyImage, "tls-proxy-image", controller.DefaultTLSProxyImage, "The image of the TLS proxy sidecar, as set on the operator")
+	flag.StringVar(&output, "o", "", "File the manifests are written to, stdout when empty")
+	flag.Parse()
+
+	manifests, err := run(reconciler, file, crd, namespace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "axelar-render: %v\n", err)
+		os.Exit(1)
+	}
+	if output == "" {
+		os.Stdout.Write(manifests)
+		return
+	}
+	if err := os.WriteFile(output, manifests, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "axelar-render: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run renders the manifests of every AxelarNode of the file. Other documents
+// are skipped.
+func run(reconciler *controller.AxelarNodeReconciler, file, crd, namespace string) ([]byte, error) {
+	schema, err := loadSchema(crd)
+	if err != nil {
+		return nil, err
+	}
+
+	var in io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var out bytes.Buffer
+	decoder := yaml.NewYAMLOrJSONDecoder(in, 4096)
+	for {
+		object := map[string]interface{}{}
+		if err := decoder.Decode(&object); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if object["kind"] != "AxelarNode" {
+			if len(object) > 0 {
+				fmt.Fprintf(os.Stderr, "skipping %v %v\n", object["kind"], name(object))
+			}
+			continue
+		}
+
+		applyDefaults(object, schema)
+		data, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		node := &blockchainv1alpha1.AxelarNode{}
+		if err := json.Unmarshal(data, node); err != nil {
+			return nil, fmt.Errorf("AxelarNode %s: %w", name(object), err)
+		}
+		if node.Namespace == "" {
+			node.Namespace = namespace
+		}
+
+		objects, err := reconciler.Render(node)
+		if err != nil {
+			return nil, fmt.Errorf("AxelarNode %s: %w", node.Name, err)
+		}
+		for _, obj := range objects {
+			manifest, err := marshal(obj)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString("---\n")
+			out.Write(manifest)
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// name returns the name of a decoded object
+func name(object map[string]interface{}) interface{} {
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		return metadata["name"]
+	}
+	return nil
+}
+
+// marshal renders an object as YAML, without the empty status and creation
+// timestamp of objects that were never stored
+func marshal(obj client.Object) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	delete(object, "status")
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	return sigsyaml.Marshal(object)
+}
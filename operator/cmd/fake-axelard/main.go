@@ -0,0 +1,265 @@
+// Command fake-axelard stands in for axelard in end-to-end tests. It serves
+// the Tendermint RPC endpoints the operator and the probes query, /status,
+// /net_info, /abci_info and /health, and the Prometheus metrics of a node whose height
+// grows by one every block time, so controllers can be exercised without
+// syncing a chain.
+//
+// The image installs it as startNodeProc, the command of the node container,
+// and as axelard, whose status subcommand backs the exec probe. The listen
+// addresses, moniker and chain ID are read from the configuration the
+// operator mounts in $HOME/config. Its behavior is set by environment:
+//
+//	FAKE_AXELARD_VERSION      version reported in node_info and abci_info, defaults to the build version
+//	FAKE_AXELARD_CATCHUP      how long the node reports catching_up after start, 30s by default
+//	FAKE_AXELARD_BLOCK_TIME   time between blocks, 1s by default
+//	FAKE_AXELARD_STALL_AFTER  time after which the height stops growing, never when unset
+//	FAKE_AXELARD_PEERS        number of connected peers, 4 by default
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "v0.0.0-fake"
+
+// config holds the settings read from the mounted configuration
+type config struct {
+	Moniker string `toml:"moniker"`
+	RPC     struct {
+		Laddr string `toml:"laddr"`
+	} `toml:"rpc"`
+	Instrumentation struct {
+		Prometheus           bool   `toml:"prometheus"`
+		PrometheusListenAddr string `toml:"prometheus_listen_addr"`
+	} `toml:"instrumentation"`
+}
+
+// node is the simulated chain state
+type node struct {
+	id         string
+	moniker    string
+	network    string
+	version    string
+	started    time.Time
+	catchUp    time.Duration
+	blockTime  time.Duration
+	stallAfter time.Duration
+	peers      int
+}
+
+func main() {
+	if filepath.Base(os.Args[0]) == "axelard" {
+		os.Exit(cli(os.Args[1:]))
+	}
+
+	home := os.Getenv("HOME")
+	if home == "" {
+		home = "/home/axelard"
+	}
+	var cfg config
+	if _, err := toml.DecodeFile(filepath.Join(home, "config", "config.toml"), &cfg); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "fake-axelard: %v\n", err)
+		os.Exit(1)
+	}
+	rpcAddr := listenAddr(cfg.RPC.Laddr, ":26657")
+	prometheusAddr := listenAddr(cfg.Instrumentation.PrometheusListenAddr, ":26660")
+
+	n := &node{
+		id:         "fakefakefakefakefakefakefakefakefakefake",
+		moniker:    cfg.Moniker,
+		network:    "axelar-fake-1",
+		version:    envString("FAKE_AXELARD_VERSION", version),
+		started:    time.Now(),
+		catchUp:    envDuration("FAKE_AXELARD_CATCHUP", 30*time.Second),
+		blockTime:  envDuration("FAKE_AXELARD_BLOCK_TIME", time.Second),
+		stallAfter: envDuration("FAKE_AXELARD_STALL_AFTER", 0),
+		peers:      envInt("FAKE_AXELARD_PEERS", 4),
+	}
+	if chainID, err := os.ReadFile(filepath.Join(home, "config", "chain-id")); err == nil && len(chainID) > 0 {
+		n.network = strings.TrimSpace(string(chainID))
+	}
+	if n.moniker == "" {
+		n.moniker = os.Getenv("NODE_MONIKER")
+	}
+	if n.blockTime <= 0 {
+		n.blockTime = time.Second
+	}
+
+	rpc := http.NewServeMux()
+	rpc.HandleFunc("/status", n.serveStatus)
+	rpc.HandleFunc("/net_info", n.serveNetInfo)
+	rpc.HandleFunc("/abci_info", n.serveABCIInfo)
+	rpc.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) { writeResult(w, struct{}{}) })
+
+	metrics := http.NewServeMux()
+	metrics.HandleFunc("/metrics", n.serveMetrics)
+
+	fmt.Printf("fake-axelard %s serving RPC on %s and metrics on %s\n", n.version, rpcAddr, prometheusAddr)
+	errs := make(chan error, 2)
+	go func() { errs <- http.ListenAndServe(rpcAddr, rpc) }()
+	go func() { errs <- http.ListenAndServe(prometheusAddr, metrics) }()
+	fmt.Fprintf(os.Stderr, "fake-axelard: %v\n", <-errs)
+	os.Exit(1)
+}
+
+// height returns the latest block height, which grows every block time until
+// the node stalls
+func (n *node) height() int64 {
+	elapsed := time.Since(n.started)
+	if n.stallAfter > 0 && elapsed > n.stallAfter {
+		elapsed = n.stallAfter
+	}
+	return 1 + int64(elapsed/n.blockTime)
+}
+
+// catchingUp reports whether the node is still catching up
+func (n *node) catchingUp() bool {
+	return time.Since(n.started) < n.catchUp
+}
+
+func (n *node) serveStatus(w http.ResponseWriter, _ *http.Request) {
+	height := n.height()
+	writeResult(w, map[string]interface{}{
+		"node_info": map[string]interface{}{
+			"id":      n.id,
+			"network": n.network,
+			"version": n.version,
+			"moniker": n.moniker,
+		},
+		"sync_info": map[string]interface{}{
+			"latest_block_height": strconv.FormatInt(height, 10),
+			"latest_block_time":   n.started.Add(time.Duration(height-1) * n.blockTime).UTC().Format(time.RFC3339Nano),
+			"catching_up":         n.catchingUp(),
+		},
+		"validator_info": map[string]interface{}{
+			"address":      "",
+			"voting_power": "0",
+		},
+	})
+}
+
+func (n *node) serveNetInfo(w http.ResponseWriter, _ *http.Request) {
+	writeResult(w, map[string]interface{}{
+		"listening": true,
+		"n_peers":   strconv.Itoa(n.peers),
+		"peers":     []interface{}{},
+	})
+}
+
+func (n *node) serveABCIInfo(w http.ResponseWriter, _ *http.Request) {
+	writeResult(w, map[string]interface{}{
+		"response": map[string]interface{}{
+			"data":              "axelar",
+			"version":           n.version,
+			"last_block_height": strconv.FormatInt(n.height(), 10),
+		},
+	})
+}
+
+func (n *node) serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# TYPE tendermint_consensus_height gauge\ntendermint_consensus_height{chain_id=%q} %d\n", n.network, n.height())
+	fmt.Fprintf(w, "# TYPE tendermint_consensus_latest_block_height gauge\ntendermint_consensus_latest_block_height{chain_id=%q} %d\n", n.network, n.height())
+	fmt.Fprintf(w, "# TYPE tendermint_p2p_peers gauge\ntendermint_p2p_peers{chain_id=%q} %d\n", n.network, n.peers)
+	syncing := 0
+	if n.catchingUp() {
+		syncing = 1
+	}
+	fmt.Fprintf(w, "# TYPE tendermint_blocksync_syncing gauge\ntendermint_blocksync_syncing{chain_id=%q} %d\n", n.network, syncing)
+}
+
+// writeResult writes a JSON-RPC response holding result
+func writeResult(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      -1,
+		"result":  result,
+	})
+}
+
+// cli implements the axelard subcommands the probes run
+func cli(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: axelard status [--node tcp://host:port] | version")
+		return 1
+	}
+	switch args[0] {
+	case "version":
+		fmt.Println(envString("FAKE_AXELARD_VERSION", version))
+		return 0
+	case "status":
+		address := "tcp://127.0.0.1:26657"
+		for i := 1; i < len(args); i++ {
+			if args[i] == "--node" && i+1 < len(args) {
+				address = args[i+1]
+			} else if strings.HasPrefix(args[i], "--node=") {
+				address = strings.TrimPrefix(args[i], "--node=")
+			}
+		}
+		url := "http://" + strings.TrimPrefix(address, "tcp://") + "/status"
+		resp, err := (&http.Client{Timeout: 5 * time.Second}).Get(url)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			fmt.Fprintf(os.Stderr, "status returned HTTP %d\n", resp.StatusCode)
+			return 1
+		}
+		var body struct {
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println(string(body.Result))
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+	return 1
+}
+
+// listenAddr turns a Tendermint listen address such as tcp://0.0.0.0:26657
+// into the address to listen on
+func listenAddr(laddr, fallback string) string {
+	if laddr == "" {
+		return fallback
+	}
+	return strings.TrimPrefix(laddr, "tcp://")
+}
+
+func envString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return d
+}
+
+func envInt(key string, fallback int) int {
+	i, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return i
+}
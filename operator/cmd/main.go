@@ -1,21 +1,53 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	"github.com/axelar-network/axelar-k8s-operator/pkg/addrbook"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/admin"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
 	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/backup"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/caching"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/chatops"
 	"github.com/axelar-network/axelar-k8s-operator/pkg/controller"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/featuregate"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/gateway"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/integrity"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/metricsauth"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/migration"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/networks"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/nodeclient"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/peerlist"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/remote"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/rpcproxy"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/snapshot"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/statusapi"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/statushistory"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tenancy"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/usage"
 )
 
 var (
@@ -29,17 +61,184 @@ func init() {
 }
 
 func main() {
+	// The operator image also provides the RPC proxy sidecar
+	if len(os.Args) > 1 && os.Args[1] == "rpc-proxy" {
+		runRPCProxy(os.Args[2:])
+		return
+	}
+	// and the authenticating RPC gateway sidecar
+	if len(os.Args) > 1 && os.Args[1] == "gateway" {
+		runGateway(os.Args[2:])
+		return
+	}
+	// and the snapshot downloader init container
+	if len(os.Args) > 1 && os.Args[1] == "snapshot-download" {
+		runSnapshotDownload(os.Args[2:])
+		return
+	}
+	// and the address book seeding and backup
+	if len(os.Args) > 2 && os.Args[1] == "addrbook" {
+		runAddrbook(os.Args[2], os.Args[3:])
+		return
+	}
+	// and the encryption of backup archives
+	if len(os.Args) > 2 && os.Args[1] == "backup-crypt" {
+		runBackupCrypt(os.Args[2], os.Args[3:])
+		return
+	}
+	// and the escrow of validator keys
+	if len(os.Args) > 1 && os.Args[1] == "key-escrow" {
+		runKeyEscrow(os.Args[2:])
+		return
+	}
+	// and the chain data integrity checks
+	if len(os.Args) > 1 && os.Args[1] == "integrity-check" {
+		runIntegrityCheck(os.Args[2:])
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var syncPeriod time.Duration
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
+	var leaderElectionNamespace string
+	var metricsSecure bool
+	var metricsCertDir string
+	var metricsAuth bool
+	var gracefulShutdownTimeout time.Duration
+	var mode string
+	var proxyImage string
+	var toolsImage string
+	var tlsProxyImage string
+	var offline bool
+	var imageMirrors string
+	var outboundOpts outbound.Options
+	var adminOpts admin.Options
+	var statusAPIOpts statusapi.Options
+	var slackOpts chatops.SlackOptions
+	var slackAllowedUsers string
+	var auditEvents bool
+	var auditWebhookURL string
+	var auditWebhookTokenFile string
+	var prometheusURL string
+	var networksConfigMap string
+	var historyOpts statushistory.Options
+	var peerListOpts peerlist.Options
+	var migrateStorageVersions bool
+	var migrateOnly bool
+	var nodeClientOpts nodeclient.Options
+	featureGates := featuregate.New()
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"The duration non-leader candidates wait before forcing to acquire leadership.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"The duration the leader retries refreshing leadership before giving it up. Must be less than the lease duration.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"The duration candidates wait between tries of acquiring or renewing leadership.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "",
+		"The namespace of the leader election lease. Defaults to the namespace the operator runs in.")
+	flag.BoolVar(&metricsSecure, "metrics-secure", false,
+		"Serve the metrics endpoint over HTTPS instead of HTTP.")
+	flag.StringVar(&metricsCertDir, "metrics-cert-dir", "",
+		"The directory holding the tls.crt and tls.key of the secure metrics endpoint. A self-signed certificate is used when empty.")
+	flag.BoolVar(&metricsAuth, "metrics-auth", false,
+		"Only serve metrics to bearer tokens whose identity may get the metrics path, checked with TokenReviews and SubjectAccessReviews. Requires --metrics-secure.")
+	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second,
+		"The duration running reconciles are given to finish when the operator stops.")
 	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Minute, "The minimum frequency at which watched resources are reconciled.")
+	flag.StringVar(&mode, "mode", "standalone",
+		"Deployment mode: standalone, hub (also manages AxelarNodes placed in remote clusters) "+
+			"or agent (only runs the AxelarNode controller for a hub).")
+	flag.StringVar(&proxyImage, "rpc-proxy-image", controller.DefaultProxyImage,
+		"The image of the RPC proxy sidecar injected into autoscaled nodes.")
+	flag.StringVar(&toolsImage, "tools-image", controller.DefaultToolsImage,
+		"The image running the snapshot downloader, address book tools and backup encryption.")
+	flag.StringVar(&tlsProxyImage, "tls-proxy-image", controller.DefaultTLSProxyImage,
+		"The image of the TLS terminating proxy sidecar injected into nodes serving TLS.")
+	flag.BoolVar(&offline, "offline", false,
+		"Run in an air-gapped cluster: never fetch snapshot indexes, the chain registry or genesis files from the Internet. "+
+			"Nodes must take them from ConfigMaps and volumes instead.")
+	flag.StringVar(&imageMirrors, "image-mirrors", "",
+		"Comma-separated registry=mirror pairs, like docker.io=registry.internal/dockerhub, rewriting the images of every pod the operator creates.")
+	flag.StringVar(&outboundOpts.HTTPProxy, "http-proxy", "",
+		"The proxy of outbound HTTP requests. Defaults to the HTTP_PROXY environment variable.")
+	flag.StringVar(&outboundOpts.HTTPSProxy, "https-proxy", "",
+		"The proxy of outbound HTTPS requests. Defaults to the HTTPS_PROXY environment variable.")
+	flag.StringVar(&outboundOpts.NoProxy, "no-proxy", "",
+		"Comma-separated hosts, domains and CIDRs reached without the proxy. Defaults to the NO_PROXY environment variable.")
+	flag.StringVar(&outboundOpts.CAFile, "ca-bundle-file", "",
+		"The file, usually mounted from a Secret, holding PEM certificates outbound requests trust besides the system roots.")
+	flag.StringVar(&adminOpts.BindAddress, "admin-bind-address", "",
+		"The address the admin API binds to. The admin API is disabled when empty.")
+	flag.StringVar(&adminOpts.TokenFile, "admin-token-file", "",
+		"The file holding the bearer token admin API clients must present.")
+	flag.StringVar(&adminOpts.CertFile, "admin-tls-cert-file", "", "The TLS certificate of the admin API.")
+	flag.StringVar(&adminOpts.KeyFile, "admin-tls-key-file", "", "The TLS key of the admin API.")
+	flag.StringVar(&statusAPIOpts.BindAddress, "status-api-bind-address", "",
+		"The address the read-only node status API binds to. The status API is disabled when empty.")
+	flag.StringVar(&statusAPIOpts.TokenFile, "status-api-token-file", "",
+		"The file holding the bearer token status API clients must present. Clients are not authenticated when empty.")
+	flag.StringVar(&statusAPIOpts.CertFile, "status-api-tls-cert-file", "", "The TLS certificate of the status API.")
+	flag.StringVar(&statusAPIOpts.KeyFile, "status-api-tls-key-file", "", "The TLS key of the status API.")
+	flag.StringVar(&slackOpts.BindAddress, "slack-bind-address", "",
+		"The address Slack slash commands are received on. Slack commands are disabled when empty.")
+	flag.StringVar(&slackOpts.SigningSecretFile, "slack-signing-secret-file", "",
+		"The file holding the signing secret of the Slack app.")
+	flag.StringVar(&slackAllowedUsers, "slack-allowed-users", "",
+		"Comma-separated Slack user IDs allowed to run commands other than status. Everyone is allowed when empty.")
+
+	flag.BoolVar(&auditEvents, "audit-events", true,
+		"Record every mutation made by the operator as an event on the resource that caused it.")
+	flag.StringVar(&auditWebhookURL, "audit-webhook-url", "",
+		"The URL audit entries are posted to as JSON. The audit webhook is disabled when empty.")
+	flag.StringVar(&auditWebhookTokenFile, "audit-webhook-token-file", "",
+		"The file holding the bearer token sent to the audit webhook.")
+	flag.StringVar(&prometheusURL, "prometheus-url", "",
+		"The Prometheus server the usage history of node containers is read from for resource recommendations. Disabled when empty.")
+	flag.StringVar(&networksConfigMap, "networks-configmap", "",
+		"The namespace/name of the ConfigMap registering networks besides mainnet and testnet, one profile per key. Disabled when empty.")
+	flag.DurationVar(&historyOpts.Interval, "status-history-interval", time.Minute,
+		"The interval between two samples of the status history.")
+	flag.StringVar(&historyOpts.RemoteWriteURL, "status-history-remote-write-url", "",
+		"The Prometheus remote write endpoint the status history of nodes is sent to.")
+	flag.StringVar(&historyOpts.RemoteWriteTokenFile, "status-history-remote-write-token-file", "",
+		"The file holding the bearer token sent to the status history remote write endpoint.")
+	flag.StringVar(&historyOpts.ConfigMap, "status-history-configmap", "",
+		"The namespace/name of the ConfigMap the recent status history of nodes is kept in.")
+	flag.IntVar(&historyOpts.MaxSamples, "status-history-max-samples", 5000,
+		"The number of samples the status history ConfigMap keeps.")
+	flag.StringVar(&peerListOpts.ConfigMap, "peer-list-configmap", "",
+		"The namespace/name of the ConfigMap the node IDs and external addresses of running nodes are published in, per network. Disabled when empty.")
+	flag.DurationVar(&peerListOpts.Interval, "peer-list-interval", time.Minute,
+		"The interval between two refreshes of the peer list.")
+	flag.Float64Var(&nodeClientOpts.QPS, "node-client-qps", nodeclient.DefaultOptions.QPS,
+		"The sustained rate of requests per second the operator sends to the RPC and REST APIs of each node.")
+	flag.IntVar(&nodeClientOpts.Burst, "node-client-burst", nodeclient.DefaultOptions.Burst,
+		"The number of requests to each node allowed above --node-client-qps.")
+	flag.DurationVar(&nodeClientOpts.Timeout, "node-client-timeout", nodeclient.DefaultOptions.Timeout,
+		"The timeout of the requests sent to nodes, unless spec.monitoring.statusSource sets one.")
+	flag.IntVar(&nodeClientOpts.MaxConnsPerNode, "node-client-max-conns", nodeclient.DefaultOptions.MaxConnsPerNode,
+		"The number of connections the operator keeps open to each node endpoint.")
+	flag.IntVar(&nodeClientOpts.FailureThreshold, "node-client-failure-threshold", nodeclient.DefaultOptions.FailureThreshold,
+		"The number of consecutive failed requests to a node after which the operator stops querying it for --node-client-open-duration.")
+	flag.DurationVar(&nodeClientOpts.OpenDuration, "node-client-open-duration", nodeclient.DefaultOptions.OpenDuration,
+		"How long the operator stops querying a failing node before probing it again.")
+	flag.BoolVar(&migrateStorageVersions, "migrate-storage-versions", true,
+		"Rewrite the custom resources stored in an older version of their CRD in its storage version at startup.")
+	flag.BoolVar(&migrateOnly, "migrate-only", false,
+		"Migrate the stored versions of the custom resources, print the result and exit, without running the controllers.")
+
+	flag.Var(featureGates, "feature-gates",
+		"A comma-separated list of Feature=bool pairs enabling or disabling experimental capabilities. Options are:\n"+
+			strings.Join(featureGates.Known(), "\n"))
 
 	opts := zap.Options{
 		Development: true,
@@ -48,41 +247,301 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	setupLog.Info("feature gates", "enabled", featureGates.EnabledFeatures())
+
+	if metricsAuth && !metricsSecure {
+		setupLog.Error(nil, "--metrics-auth requires --metrics-secure, so bearer tokens are not sent in clear text")
+		os.Exit(1)
+	}
+
+	mirrors, err := airgap.ParseMirrors(imageMirrors)
+	if err != nil {
+		setupLog.Error(err, "invalid --image-mirrors")
+		os.Exit(1)
+	}
+	airgap.Configure(airgap.Options{Offline: offline, Mirrors: mirrors})
+	if err := outbound.Configure(outboundOpts); err != nil {
+		setupLog.Error(err, "invalid outbound proxy settings")
+		os.Exit(1)
+	}
+	nodeclient.Configure(nodeClientOpts)
+	proxyImage, toolsImage, tlsProxyImage = airgap.Image(proxyImage), airgap.Image(toolsImage), airgap.Image(tlsProxyImage)
+	if offline {
+		setupLog.Info("offline mode, outbound fetches are disabled")
+	}
+
+	metricsOpts := server.Options{
+		BindAddress:   metricsAddr,
+		SecureServing: metricsSecure,
+		CertDir:       metricsCertDir,
+	}
+	if metricsAuth {
+		metricsOpts.FilterProvider = metricsauth.WithAuthenticationAndAuthorization
+	}
+
+	if enableLeaderElection && (renewDeadline >= leaseDuration || retryPeriod >= renewDeadline) {
+		setupLog.Error(nil, "leader election requires retry period < renew deadline < lease duration",
+			"leaseDuration", leaseDuration, "renewDeadline", renewDeadline, "retryPeriod", retryPeriod)
+		os.Exit(1)
+	}
+
+	var clusters *remote.Clusters
+	switch mode {
+	case "standalone", "agent":
+	case "hub":
+		clusters = remote.NewClusters(scheme)
+	default:
+		setupLog.Error(nil, "unknown mode", "mode", mode)
+		os.Exit(1)
+	}
+
+	if migrateOnly {
+		c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create a client")
+			os.Exit(1)
+		}
+		results, err := migration.New(c, ctrl.Log.WithName("migration")).Run(ctrl.SetupSignalHandler())
+		message, _ := json.Marshal(results)
+		fmt.Println(string(message))
+		if err != nil {
+			setupLog.Error(err, "unable to migrate the stored versions")
+			os.Exit(1)
+		}
+		return
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		Metrics: server.Options{
-			BindAddress: metricsAddr,
-		},
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "axelar-operator-leader-election",
+		Scheme:                  scheme,
+		Metrics:                 metricsOpts,
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        "axelar-operator-leader-election",
+		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
+		// Only the ConfigMaps and Secrets written by the operator are cached
 		Cache: cache.Options{
 			SyncPeriod: &syncPeriod,
+			ByObject:   caching.ByObject(),
 		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	if err := caching.SetupIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to set up field indexes")
+		os.Exit(1)
+	}
+
+	// Networks registered by administrators are known before nodes are rendered
+	if networksConfigMap != "" {
+		namespace, name, ok := strings.Cut(networksConfigMap, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "--networks-configmap must be namespace/name", "networksConfigMap", networksConfigMap)
+			os.Exit(1)
+		}
+		loader := &networks.Loader{
+			Reader:    mgr.GetAPIReader(),
+			ConfigMap: types.NamespacedName{Namespace: namespace, Name: name},
+			Interval:  time.Minute,
+			Log:       ctrl.Log.WithName("networks"),
+		}
+		if err := mgr.Add(loader); err != nil {
+			setupLog.Error(err, "unable to set up networks")
+			os.Exit(1)
+		}
+	}
+
+	// Every mutation made through the operator clients is audited
+	sinks := []audit.Sink{audit.LogSink{Log: ctrl.Log.WithName("audit")}}
+	if auditEvents {
+		sinks = append(sinks, audit.EventSink{Recorder: mgr.GetEventRecorderFor("axelar-operator-audit")})
+	}
+	if auditWebhookURL != "" {
+		webhook := audit.NewWebhookSink(auditWebhookURL, auditWebhookTokenFile, ctrl.Log.WithName("audit-webhook"))
+		if err := mgr.Add(webhook); err != nil {
+			setupLog.Error(err, "unable to set up audit webhook")
+			os.Exit(1)
+		}
+		sinks = append(sinks, webhook)
+	}
+	auditor := audit.NewAuditor(sinks...)
+	// and carries the tenant and recommended labels of the resource it was made for
+	auditedClient := auditor.Client(tenancy.Client(naming.Client(caching.Client(mgr.GetClient()))), "")
+	if clusters != nil {
+		clusters.Reader = mgr.GetAPIReader()
+		clusters.Wrap = func(c client.Client, cluster string) client.Client {
+			return auditor.Client(tenancy.Client(naming.Client(caching.Client(c))), cluster)
+		}
+	}
 
 	// Setup AxelarNode controller
+	var usageClient *usage.Client
+	if prometheusURL != "" {
+		usageClient = usage.NewClient(strings.TrimSuffix(prometheusURL, "/"))
+	}
 	if err = (&controller.AxelarNodeReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("AxelarNode"),
+		Client:        auditedClient,
+		Scheme:        mgr.GetScheme(),
+		Log:           ctrl.Log.WithName("controllers").WithName("AxelarNode"),
+		Clusters:      clusters,
+		ProxyImage:    proxyImage,
+		ToolsImage:    toolsImage,
+		TLSProxyImage: tlsProxyImage,
+		Recorder:      mgr.GetEventRecorderFor("axelarnode-controller"),
+		Usage:         usageClient,
+		Features:      featureGates,
+		RESTConfig:    mgr.GetConfig(),
+		APIReader:     mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AxelarNode")
 		os.Exit(1)
 	}
 
-	// Setup AxelarNetwork controller
-	if err = (&controller.AxelarNetworkReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("AxelarNetwork"),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "AxelarNetwork")
+	// Networks and fleets are coordinated by the hub in agent mode
+	if mode != "agent" {
+		// Setup AxelarNetwork controller
+		if err = (&controller.AxelarNetworkReconciler{
+			Client:   auditedClient,
+			Scheme:   mgr.GetScheme(),
+			Log:      ctrl.Log.WithName("controllers").WithName("AxelarNetwork"),
+			Clusters: clusters,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AxelarNetwork")
+			os.Exit(1)
+		}
+
+		// Setup AxelarRPCFleet controller
+		if err = (&controller.AxelarRPCFleetReconciler{
+			Client:     auditedClient,
+			Scheme:     mgr.GetScheme(),
+			Log:        ctrl.Log.WithName("controllers").WithName("AxelarRPCFleet"),
+			ProxyImage: proxyImage,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AxelarRPCFleet")
+			os.Exit(1)
+		}
+
+		// Setup AxelarNodeKeyBackup controller
+		if err = (&controller.AxelarNodeKeyBackupReconciler{
+			Client:     auditedClient,
+			Scheme:     mgr.GetScheme(),
+			Log:        ctrl.Log.WithName("controllers").WithName("AxelarNodeKeyBackup"),
+			ToolsImage: toolsImage,
+			Recorder:   mgr.GetEventRecorderFor("axelarnodekeybackup-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AxelarNodeKeyBackup")
+			os.Exit(1)
+		}
+
+		// Setup AxelarValidatorOnboarding controller
+		if err = (&controller.AxelarValidatorOnboardingReconciler{
+			Client:     auditedClient,
+			Scheme:     mgr.GetScheme(),
+			Log:        ctrl.Log.WithName("controllers").WithName("AxelarValidatorOnboarding"),
+			Recorder:   mgr.GetEventRecorderFor("axelarvalidatoronboarding-controller"),
+			RESTConfig: mgr.GetConfig(),
+			APIReader:  mgr.GetAPIReader(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AxelarValidatorOnboarding")
+			os.Exit(1)
+		}
+
+		// Setup TofndCluster controller
+		if err = (&controller.TofndClusterReconciler{
+			Client:     auditedClient,
+			Scheme:     mgr.GetScheme(),
+			Log:        ctrl.Log.WithName("controllers").WithName("TofndCluster"),
+			ToolsImage: toolsImage,
+			Recorder:   mgr.GetEventRecorderFor("tofndcluster-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TofndCluster")
+			os.Exit(1)
+		}
+	}
+
+	// Setup the admin API
+	if adminOpts.BindAddress != "" {
+		if adminOpts.TokenFile == "" {
+			setupLog.Error(nil, "the admin API requires --admin-token-file")
+			os.Exit(1)
+		}
+		if err := mgr.Add(admin.New(adminOpts, auditedClient, ctrl.Log.WithName("admin"))); err != nil {
+			setupLog.Error(err, "unable to set up admin API")
+			os.Exit(1)
+		}
+	}
+
+	// Setup the status API
+	if statusAPIOpts.BindAddress != "" {
+		if err := mgr.Add(statusapi.New(statusAPIOpts, mgr.GetClient(), ctrl.Log.WithName("statusapi"))); err != nil {
+			setupLog.Error(err, "unable to set up status API")
+			os.Exit(1)
+		}
+	}
+
+	// Setup Slack commands
+	if slackOpts.BindAddress != "" {
+		if slackOpts.SigningSecretFile == "" {
+			setupLog.Error(nil, "Slack commands require --slack-signing-secret-file")
+			os.Exit(1)
+		}
+		if slackAllowedUsers != "" {
+			slackOpts.AllowedUsers = strings.Split(slackAllowedUsers, ",")
+		}
+		slack := chatops.NewSlack(slackOpts, auditedClient, mgr.GetEventRecorderFor("axelar-chatops"),
+			ctrl.Log.WithName("chatops"))
+		if err := mgr.Add(slack); err != nil {
+			setupLog.Error(err, "unable to set up Slack commands")
+			os.Exit(1)
+		}
+	}
+
+	// Setup the status history
+	if historyOpts.Enabled() {
+		exporter, err := statushistory.New(historyOpts, caching.Client(mgr.GetClient()), ctrl.Log.WithName("statushistory"))
+		if err == nil {
+			err = mgr.Add(exporter)
+		}
+		if err != nil {
+			setupLog.Error(err, "unable to set up the status history")
+			os.Exit(1)
+		}
+	}
+
+	// Setup the peer list
+	if peerListOpts.ConfigMap != "" {
+		publisher, err := peerlist.New(peerListOpts, caching.Client(mgr.GetClient()), ctrl.Log.WithName("peerlist"))
+		if err == nil {
+			err = mgr.Add(publisher)
+		}
+		if err != nil {
+			setupLog.Error(err, "unable to set up the peer list")
+			os.Exit(1)
+		}
+	}
+
+	// Setup the storage version migration
+	if migrateStorageVersions {
+		if err := mgr.Add(migration.New(mgr.GetClient(), ctrl.Log.WithName("migration"))); err != nil {
+			setupLog.Error(err, "unable to set up the storage version migration")
+			os.Exit(1)
+		}
+	}
+
+	// ConfigMaps and Secrets written before they were labeled are adopted into the cache
+	var adopted []types.NamespacedName
+	for _, configMap := range []string{peerListOpts.ConfigMap, historyOpts.ConfigMap} {
+		if namespace, name, ok := strings.Cut(configMap, "/"); ok {
+			adopted = append(adopted, types.NamespacedName{Namespace: namespace, Name: name})
+		}
+	}
+	if err := caching.Adopt(context.Background(), mgr.GetAPIReader(), mgr.GetClient(), adopted...); err != nil {
+		setupLog.Error(err, "unable to label the ConfigMaps and Secrets of the operator")
 		os.Exit(1)
 	}
 
@@ -96,9 +555,169 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("starting Axelar Kubernetes Operator")
+	setupLog.Info("starting Axelar Kubernetes Operator", "mode", mode)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// runRPCProxy runs the RPC proxy sidecar injected into autoscaled nodes
+func runRPCProxy(args []string) {
+	fs := flag.NewFlagSet("rpc-proxy", flag.ExitOnError)
+	var proxyOpts rpcproxy.Options
+	proxyOpts.BindFlags(fs)
+	opts := zap.Options{}
+	opts.BindFlags(fs)
+	fs.Parse(args)
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	log := ctrl.Log.WithName("rpc-proxy")
+
+	log.Info("starting RPC proxy", "listen", proxyOpts.Listen, "upstream", proxyOpts.Upstream)
+	if err := rpcproxy.New(proxyOpts, log).Run(ctrl.SetupSignalHandler()); err != nil {
+		log.Error(err, "problem running RPC proxy")
+		os.Exit(1)
+	}
+}
+
+// runGateway runs the RPC gateway sidecar authenticating public RPC clients
+func runGateway(args []string) {
+	fs := flag.NewFlagSet("gateway", flag.ExitOnError)
+	var gatewayOpts gateway.Options
+	gatewayOpts.BindFlags(fs)
+	opts := zap.Options{}
+	opts.BindFlags(fs)
+	fs.Parse(args)
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	log := ctrl.Log.WithName("gateway")
+
+	log.Info("starting RPC gateway", "listen", gatewayOpts.Listen, "upstream", gatewayOpts.Upstream)
+	if err := gateway.New(gatewayOpts, log).Run(ctrl.SetupSignalHandler()); err != nil {
+		log.Error(err, "problem running RPC gateway")
+		os.Exit(1)
+	}
+}
+
+// runSnapshotDownload runs the init container bootstrapping a node data volume
+func runSnapshotDownload(args []string) {
+	fs := flag.NewFlagSet("snapshot-download", flag.ExitOnError)
+	var downloadOpts snapshot.DownloadOptions
+	downloadOpts.BindFlags(fs)
+	opts := zap.Options{}
+	opts.BindFlags(fs)
+	fs.Parse(args)
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	log := ctrl.Log.WithName("snapshot-download")
+
+	if err := snapshot.NewDownloader(downloadOpts, log).Run(ctrl.SetupSignalHandler()); err != nil {
+		log.Error(err, "problem downloading snapshot")
+		// Reported by the operator in the SnapshotBootstrapped condition
+		os.WriteFile("/dev/termination-log", []byte(err.Error()), 0o644)
+		os.Exit(1)
+	}
+}
+
+// runAddrbook seeds the address book of an empty node, or serves it to the
+// operator for backups
+func runAddrbook(mode string, args []string) {
+	fs := flag.NewFlagSet("addrbook", flag.ExitOnError)
+	var addrbookOpts addrbook.Options
+	addrbookOpts.BindFlags(fs)
+	opts := zap.Options{}
+	opts.BindFlags(fs)
+	fs.Parse(args)
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	log := ctrl.Log.WithName("addrbook")
+
+	ctx := ctrl.SetupSignalHandler()
+	var err error
+	switch mode {
+	case "seed":
+		err = addrbook.Seed(ctx, addrbookOpts, log)
+	case "serve":
+		err = addrbook.Serve(ctx, addrbookOpts, log)
+	default:
+		err = fmt.Errorf("unknown mode %q, expected seed or serve", mode)
+	}
+	if err != nil {
+		log.Error(err, "problem running addrbook", "mode", mode)
+		os.Exit(1)
+	}
+}
+
+// runBackupCrypt encrypts or decrypts a backup archive from stdin to stdout
+func runBackupCrypt(mode string, args []string) {
+	fs := flag.NewFlagSet("backup-crypt", flag.ExitOnError)
+	var cryptOpts backup.Options
+	cryptOpts.BindFlags(fs)
+	fs.Parse(args)
+
+	ctx := ctrl.SetupSignalHandler()
+	out := bufio.NewWriterSize(os.Stdout, 1<<20)
+	var err error
+	switch mode {
+	case "encrypt":
+		var w io.WriteCloser
+		if w, err = backup.Encrypt(ctx, out, cryptOpts); err == nil {
+			if _, err = io.Copy(w, bufio.NewReaderSize(os.Stdin, 1<<20)); err == nil {
+				err = w.Close()
+			}
+		}
+	case "decrypt":
+		var r io.Reader
+		if r, err = backup.Decrypt(ctx, bufio.NewReaderSize(os.Stdin, 1<<20), cryptOpts); err == nil {
+			_, err = io.Copy(out, r)
+		}
+	default:
+		err = fmt.Errorf("unknown mode %q, expected encrypt or decrypt", mode)
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "backup-crypt %s: %v\n", mode, err)
+		os.Exit(1)
+	}
+}
+
+// runKeyEscrow encrypts the key files given as arguments and uploads them to
+// object storage. The result is the termination message, read by the operator.
+func runKeyEscrow(args []string) {
+	fs := flag.NewFlagSet("key-escrow", flag.ExitOnError)
+	var escrowOpts backup.EscrowOptions
+	escrowOpts.BindFlags(fs)
+	fs.Parse(args)
+
+	result, err := backup.Escrow(ctrl.SetupSignalHandler(), fs.Args(), escrowOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "key-escrow: %v\n", err)
+		os.WriteFile("/dev/termination-log", []byte(err.Error()), 0o644)
+		os.Exit(1)
+	}
+	message, _ := json.Marshal(result)
+	fmt.Println(string(message))
+	os.WriteFile("/dev/termination-log", message, 0o644)
+}
+
+// runIntegrityCheck compares the chain data of a node with a trusted RPC. The
+// result is the termination message, read by the operator.
+func runIntegrityCheck(args []string) {
+	fs := flag.NewFlagSet("integrity-check", flag.ExitOnError)
+	var checkOpts integrity.Options
+	checkOpts.BindFlags(fs)
+	fs.Parse(args)
+
+	result, err := integrity.Check(ctrl.SetupSignalHandler(), checkOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "integrity-check: %v\n", err)
+		os.WriteFile("/dev/termination-log", []byte(err.Error()), 0o644)
+		os.Exit(1)
+	}
+	message, _ := json.Marshal(result)
+	fmt.Println(string(message))
+	os.WriteFile("/dev/termination-log", message, 0o644)
+}
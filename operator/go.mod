@@ -9,6 +9,16 @@ require (
 	sigs.k8s.io/controller-runtime v0.16.0
 	github.com/go-logr/logr v1.2.4
 	github.com/prometheus/client_golang v1.16.0
+	github.com/robfig/cron/v3 v3.0.1
+	github.com/klauspost/compress v1.17.4
+	github.com/pierrec/lz4/v4 v4.1.18
+	filippo.io/age v1.1.1
+	gocloud.dev v0.34.0
+	golang.org/x/time v0.3.0
+	golang.org/x/net v0.13.0
+	github.com/BurntSushi/toml v1.3.2
+	google.golang.org/protobuf v1.30.0
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -44,15 +54,12 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	go.uber.org/zap v1.24.0 // indirect
-	golang.org/x/net v0.13.0 // indirect
 	golang.org/x/oauth2 v0.8.0 // indirect
 	golang.org/x/sys v0.10.0 // indirect
 	golang.org/x/term v0.10.0 // indirect
 	golang.org/x/text v0.11.0 // indirect
-	golang.org/x/time v0.3.0 // indirect
 	gomodules.xyz/jsonpatch/v2 v2.4.0 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
-	google.golang.org/protobuf v1.30.0 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -63,5 +70,4 @@ require (
 	k8s.io/utils v0.0.0-20230726121419-3b25d923346b // indirect
 	sigs.k8s.io/json v0.0.0-20221116044647-bc3834ca7abd // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.3.0 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
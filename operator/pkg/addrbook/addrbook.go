@@ -0,0 +1,285 @@
+// Package addrbook seeds and serves the address book of known peers a node
+// keeps in addrbook.json, so re-provisioned nodes find peers without waiting
+// on the seed nodes.
+package addrbook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// bucketTypeOld marks addresses the node has successfully connected to
+const bucketTypeOld = 2
+
+// maxFetchBytes bounds the size of a downloaded address book
+const maxFetchBytes = 16 << 20
+
+// Options configures seeding and serving the address book
+type Options struct {
+	// File is the addrbook.json of the node
+	File string
+	// SeedFile is copied to File when File does not exist
+	SeedFile string
+	// SeedURL is downloaded to File when File does not exist and SeedFile is unavailable
+	SeedURL string
+	// Listen is the address the address book is served on
+	Listen string
+	// MaxBytes bounds the size of the served address book
+	MaxBytes int
+	// BlockedPeers is a comma separated list of node IDs removed from the address book
+	BlockedPeers string
+}
+
+// BindFlags registers the options on fs
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.File, "file", "/home/axelard/.axelar/config/addrbook.json", "The address book of the node.")
+	fs.StringVar(&o.SeedFile, "seed-file", "", "The address book an empty node is seeded from.")
+	fs.StringVar(&o.SeedURL, "seed-url", "", "The URL of the address book an empty node is seeded from when the seed file is unavailable.")
+	fs.StringVar(&o.Listen, "listen", ":26671", "The address the address book is served on.")
+	fs.IntVar(&o.MaxBytes, "max-bytes", 512<<10, "The maximum size of the served address book.")
+	fs.StringVar(&o.BlockedPeers, "blocked-peers", "", "Comma separated node IDs removed from the address book when seeding.")
+}
+
+// book is the layout of addrbook.json. Addresses are kept as they are, only
+// the fields needed to rank them are decoded.
+type book struct {
+	Key   string            `json:"key"`
+	Addrs []json.RawMessage `json:"addrs"`
+}
+
+// knownAddress holds the fields addresses are ranked and filtered by
+type knownAddress struct {
+	Addr struct {
+		ID string `json:"id"`
+	} `json:"addr"`
+	BucketType  int       `json:"bucket_type"`
+	LastSuccess time.Time `json:"last_success"`
+}
+
+// Parse checks an address book and returns the number of addresses it holds
+func Parse(data []byte) (int, error) {
+	b := book{}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return 0, fmt.Errorf("invalid address book: %w", err)
+	}
+	return len(b.Addrs), nil
+}
+
+// Trim keeps the addresses the node connected to most recently, within
+// maxBytes. Addresses the node has connected to come before those it has only
+// heard of.
+func Trim(data []byte, maxBytes int) ([]byte, error) {
+	b := book{}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid address book: %w", err)
+	}
+
+	ranks := make([]knownAddress, len(b.Addrs))
+	for i, addr := range b.Addrs {
+		json.Unmarshal(addr, &ranks[i])
+	}
+	order := make([]int, len(b.Addrs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, c := ranks[order[i]], ranks[order[j]]
+		if (a.BucketType == bucketTypeOld) != (c.BucketType == bucketTypeOld) {
+			return a.BucketType == bucketTypeOld
+		}
+		return a.LastSuccess.After(c.LastSuccess)
+	})
+
+	trimmed := book{Key: b.Key, Addrs: make([]json.RawMessage, 0, len(b.Addrs))}
+	size := len(b.Key) + 32
+	for _, i := range order {
+		size += len(b.Addrs[i]) + 1
+		if maxBytes > 0 && size > maxBytes {
+			break
+		}
+		trimmed.Addrs = append(trimmed.Addrs, b.Addrs[i])
+	}
+	return json.Marshal(trimmed)
+}
+
+// Drop removes the addresses of the blocked node IDs and returns the number
+// of addresses removed
+func Drop(data []byte, blocked map[string]bool) ([]byte, int, error) {
+	b := book{}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, 0, fmt.Errorf("invalid address book: %w", err)
+	}
+	kept := book{Key: b.Key, Addrs: make([]json.RawMessage, 0, len(b.Addrs))}
+	for _, addr := range b.Addrs {
+		known := knownAddress{}
+		json.Unmarshal(addr, &known)
+		if !blocked[known.Addr.ID] {
+			kept.Addrs = append(kept.Addrs, addr)
+		}
+	}
+	if len(kept.Addrs) == len(b.Addrs) {
+		return data, 0, nil
+	}
+	out, err := json.Marshal(kept)
+	return out, len(b.Addrs) - len(kept.Addrs), err
+}
+
+// blockedPeers parses the blocked node IDs of the options
+func blockedPeers(opts Options) map[string]bool {
+	blocked := map[string]bool{}
+	for _, id := range strings.Split(opts.BlockedPeers, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			blocked[id] = true
+		}
+	}
+	return blocked
+}
+
+// Seed writes the seed address book to the node when it has none. An
+// unavailable seed is not an error, the node then discovers peers through its
+// seed nodes. Blocked peers are removed from the seed, and from the address
+// book the node already has.
+func Seed(ctx context.Context, opts Options, log logr.Logger) error {
+	blocked := blockedPeers(opts)
+	if current, err := os.ReadFile(opts.File); err == nil {
+		if len(blocked) == 0 {
+			log.Info("Address book present, not seeding", "file", opts.File)
+			return nil
+		}
+		pruned, removed, err := Drop(current, blocked)
+		if err != nil {
+			log.Error(err, "Unable to remove the blocked peers", "file", opts.File)
+			return nil
+		}
+		if removed > 0 {
+			log.Info("Removed blocked peers from the address book", "file", opts.File, "addresses", removed)
+			return writeFile(opts.File, pruned)
+		}
+		return nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	data, source := []byte(nil), ""
+	if opts.SeedFile != "" {
+		seed, err := os.ReadFile(opts.SeedFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if len(seed) > 0 {
+			data, source = seed, opts.SeedFile
+		}
+	}
+	if data == nil && opts.SeedURL != "" {
+		seed, err := Fetch(ctx, opts.SeedURL)
+		if err != nil {
+			log.Error(err, "Unable to download the seed address book", "url", opts.SeedURL)
+		} else {
+			data, source = seed, opts.SeedURL
+		}
+	}
+	if data == nil {
+		log.Info("No seed address book available")
+		return nil
+	}
+
+	data, _, err := Drop(data, blocked)
+	if err != nil {
+		log.Error(err, "Ignoring the seed address book", "source", source)
+		return nil
+	}
+	count, _ := Parse(data)
+	if err := writeFile(opts.File, data); err != nil {
+		return err
+	}
+	log.Info("Seeded the address book", "source", source, "addresses", count)
+	return nil
+}
+
+// writeFile replaces file with data
+func writeFile(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// Fetch downloads an address book
+func Fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("address book %s returned %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFetchBytes {
+		return nil, fmt.Errorf("address book %s is larger than %d bytes", url, maxFetchBytes)
+	}
+	if _, err := Parse(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Serve serves the trimmed address book of the node on /addrbook until ctx
+// is done
+func Serve(ctx context.Context, opts Options, log logr.Logger) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/addrbook", func(w http.ResponseWriter, r *http.Request) {
+		data, err := os.ReadFile(opts.File)
+		if errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		trimmed, err := Trim(data, opts.MaxBytes)
+		if err != nil {
+			// The node may be rewriting the file
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(trimmed)
+	})
+
+	server := &http.Server{Addr: opts.Listen, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	log.Info("Serving the address book", "file", opts.File, "listen", opts.Listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
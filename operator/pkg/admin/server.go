@@ -0,0 +1,290 @@
+// Package admin implements the operator admin API, an authenticated HTTP
+// endpoint exposing node status and imperative operations to runbooks and
+// ChatOps without kubectl access. Operations are translated into the same
+// request annotations users can set themselves.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/caching"
+)
+
+// Options configures the admin API
+type Options struct {
+	// BindAddress is the address the API is served on; empty disables it
+	BindAddress string
+	// TokenFile holds the bearer token clients authenticate with. It is read
+	// on every request, so a mounted Secret can be rotated in place.
+	TokenFile string
+	// CertFile and KeyFile enable TLS when both are set
+	CertFile string
+	KeyFile  string
+}
+
+// Server serves the admin API
+type Server struct {
+	opts   Options
+	client client.Client
+	log    logr.Logger
+}
+
+// NodeSummary is the status of a node returned by the API
+type NodeSummary struct {
+	Name       string                              `json:"name"`
+	Namespace  string                              `json:"namespace"`
+	NodeType   string                              `json:"nodeType"`
+	Network    string                              `json:"network"`
+	Phase      string                              `json:"phase"`
+	Height     int64                               `json:"height"`
+	CatchingUp bool                                `json:"catchingUp"`
+	Peers      int32                               `json:"peers"`
+	Paused     bool                                `json:"paused"`
+	LastBackup *metav1.Time                        `json:"lastBackup,omitempty"`
+	Operation  *blockchainv1alpha1.OperationStatus `json:"operation,omitempty"`
+	Conditions []metav1.Condition                  `json:"conditions,omitempty"`
+}
+
+// operationResponse acknowledges an accepted operation
+type operationResponse struct {
+	Node      string `json:"node"`
+	Namespace string `json:"namespace"`
+	Operation string `json:"operation"`
+	Message   string `json:"message"`
+}
+
+// ErrUnknownOperation is returned for operations the API does not provide
+var ErrUnknownOperation = errors.New("unknown operation")
+
+// operations maps operation names to the annotation change they make. The
+// argument is the archive of a restore.
+var operations = map[string]func(annotations map[string]string, arg string){
+	"backup": func(a map[string]string, arg string) {
+		a[blockchainv1alpha1.BackupAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	},
+	"restore": func(a map[string]string, arg string) {
+		if arg == "" {
+			arg = "latest"
+		}
+		a[blockchainv1alpha1.RestoreAnnotation] = arg
+	},
+	"resync": func(a map[string]string, arg string) {
+		a[blockchainv1alpha1.ResyncAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	},
+	"pause": func(a map[string]string, arg string) {
+		a[blockchainv1alpha1.PausedAnnotation] = "true"
+	},
+	"resume": func(a map[string]string, arg string) {
+		delete(a, blockchainv1alpha1.PausedAnnotation)
+	},
+}
+
+// Request records an operation on the node by setting its request annotation
+func Request(ctx context.Context, c client.Client, name types.NamespacedName, operation, arg string) error {
+	apply, ok := operations[operation]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownOperation, operation)
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node := &blockchainv1alpha1.AxelarNode{}
+		if err := c.Get(ctx, name, node); err != nil {
+			return err
+		}
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		apply(node.Annotations, arg)
+		return c.Update(ctx, node)
+	})
+}
+
+// New creates the admin API server
+func New(opts Options, c client.Client, log logr.Logger) *Server {
+	return &Server{opts: opts, client: c, log: log}
+}
+
+// NeedLeaderElection lets every operator replica serve the API, since
+// operations are only recorded as annotations
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the API until ctx is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/v1/nodes", s.authenticate(http.HandlerFunc(s.listNodes)))
+	mux.Handle("/v1/nodes/", s.authenticate(http.HandlerFunc(s.serveNode)))
+
+	server := &http.Server{Addr: s.opts.BindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() {
+		var err error
+		if s.opts.CertFile != "" && s.opts.KeyFile != "" {
+			err = server.ListenAndServeTLS(s.opts.CertFile, s.opts.KeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+	s.log.Info("Serving admin API", "address", s.opts.BindAddress)
+
+	select {
+	case <-ctx.Done():
+	case err := <-errs:
+		return err
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdown)
+}
+
+// authenticate rejects requests without the configured bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := os.ReadFile(s.opts.TokenFile)
+		if err != nil {
+			s.log.Error(err, "Unable to read admin token")
+			http.Error(w, "admin token unavailable", http.StatusInternalServerError)
+			return
+		}
+		expected := strings.TrimSpace(string(token))
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || expected == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(expected)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// listNodes serves GET /v1/nodes, optionally filtered by ?namespace=,
+// ?network= and ?nodeType=
+func (s *Server) listNodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	fields := client.MatchingFields{}
+	if network := query.Get("network"); network != "" {
+		fields[caching.NetworkIndex] = network
+	}
+	if nodeType := query.Get("nodeType"); nodeType != "" {
+		fields[caching.NodeTypeIndex] = nodeType
+	}
+	nodes := &blockchainv1alpha1.AxelarNodeList{}
+	if err := s.client.List(r.Context(), nodes, client.InNamespace(query.Get("namespace")), fields); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]NodeSummary, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		summaries = append(summaries, Summarize(&nodes.Items[i]))
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// serveNode serves GET /v1/nodes/{namespace}/{name} and
+// POST /v1/nodes/{namespace}/{name}/{operation}
+func (s *Server) serveNode(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/nodes/"), "/"), "/")
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		s.getNode(w, r, types.NamespacedName{Namespace: parts[0], Name: parts[1]})
+	case len(parts) == 3 && r.Method == http.MethodPost:
+		s.runOperation(w, r, types.NamespacedName{Namespace: parts[0], Name: parts[1]}, parts[2])
+	case len(parts) == 2 || len(parts) == 3:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// getNode returns the status of a single node
+func (s *Server) getNode(w http.ResponseWriter, r *http.Request, name types.NamespacedName) {
+	node := &blockchainv1alpha1.AxelarNode{}
+	if err := s.client.Get(r.Context(), name, node); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Summarize(node))
+}
+
+// runOperation records an operation request on the node
+func (s *Server) runOperation(w http.ResponseWriter, r *http.Request, name types.NamespacedName, operation string) {
+	ctx := audit.WithInitiator(r.Context(), "admin-api "+r.RemoteAddr)
+	ctx = audit.WithReason(ctx, operation)
+	if err := Request(ctx, s.client, name, operation, r.URL.Query().Get("archive")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.log.Info("Accepted admin operation", "operation", operation, "node", name.String(), "remote", r.RemoteAddr)
+	writeJSON(w, http.StatusAccepted, operationResponse{
+		Node:      name.Name,
+		Namespace: name.Namespace,
+		Operation: operation,
+		Message:   fmt.Sprintf("%s requested, follow the node status for progress", operation),
+	})
+}
+
+// Summarize returns the API view of a node
+func Summarize(node *blockchainv1alpha1.AxelarNode) NodeSummary {
+	return NodeSummary{
+		Name:       node.Name,
+		Namespace:  node.Namespace,
+		NodeType:   node.Spec.NodeType,
+		Network:    node.Spec.Network,
+		Phase:      node.Status.Phase,
+		Height:     node.Status.SyncInfo.CurrentHeight,
+		CatchingUp: node.Status.SyncInfo.CatchingUp,
+		Peers:      node.Status.NetworkInfo.Peers,
+		Paused:     node.Annotations[blockchainv1alpha1.PausedAnnotation] == "true",
+		LastBackup: node.Status.LastBackup,
+		Operation:  node.Status.Operation,
+		Conditions: node.Status.Conditions,
+	}
+}
+
+// writeError maps API errors to HTTP status codes
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUnknownOperation), apierrors.IsNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case apierrors.IsConflict(err):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
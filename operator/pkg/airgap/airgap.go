@@ -0,0 +1,84 @@
+// Package airgap adapts the operator to clusters without Internet access.
+// Offline, neither the operator nor the pods it creates fetch anything from
+// outside the cluster, and images are pulled from registry mirrors.
+package airgap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrOffline is returned for fetches refused in offline mode
+var ErrOffline = errors.New("outbound fetches are disabled in offline mode")
+
+// defaultRegistry is the registry of image references naming none
+const defaultRegistry = "docker.io"
+
+// Options configures the air-gapped mode
+type Options struct {
+	// Offline disables all fetches from outside the cluster
+	Offline bool
+	// Mirrors maps registries, like docker.io, to the mirrors their images
+	// are pulled from, like registry.internal/dockerhub
+	Mirrors map[string]string
+}
+
+var (
+	mu      sync.RWMutex
+	current Options
+)
+
+// Configure sets the air-gapped mode of the operator
+func Configure(opts Options) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = opts
+}
+
+// Offline reports whether fetches from outside the cluster are disabled
+func Offline() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.Offline
+}
+
+// Image returns the reference image is pulled from, on the mirror of its
+// registry if one is configured
+func Image(image string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(current.Mirrors) == 0 || image == "" {
+		return image
+	}
+
+	registry, path := defaultRegistry, image
+	if first, rest, ok := strings.Cut(image, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, path = first, rest
+	} else if !ok {
+		path = "library/" + image
+	}
+	mirror, ok := current.Mirrors[registry]
+	if !ok {
+		return image
+	}
+	return strings.TrimSuffix(mirror, "/") + "/" + path
+}
+
+// ParseMirrors parses comma-separated registry=mirror pairs
+func ParseMirrors(value string) (map[string]string, error) {
+	mirrors := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		registry, mirror, ok := strings.Cut(pair, "=")
+		if !ok || registry == "" || mirror == "" {
+			return nil, fmt.Errorf("invalid image mirror %q, expected registry=mirror", pair)
+		}
+		mirrors[registry] = mirror
+	}
+	return mirrors, nil
+}
@@ -0,0 +1,50 @@
+// Package alert delivers operator alerts to the channels configured in the
+// monitoring spec of a node.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+)
+
+// slackMessage is the payload of a Slack incoming webhook
+type slackMessage struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+// Send posts text to the alert channels of the spec. Nothing is sent when
+// alerts are disabled or no channel is configured.
+func Send(ctx context.Context, spec blockchainv1alpha1.AlertsSpec, text string) error {
+	if !spec.Enabled || spec.Slack.Webhook == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(slackMessage{Channel: spec.Slack.Channel, Text: text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, spec.Slack.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := outbound.Client(10 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,791 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NetworkLabel is the label an AxelarNode carries to join an AxelarNetwork
+const NetworkLabel = "blockchain.axelar.network/network"
+
+// NetworkNamespaceLabel is the namespace of the AxelarNetwork an AxelarNode
+// joins from another namespace
+const NetworkNamespaceLabel = "blockchain.axelar.network/network-namespace"
+
+// TenantLabel identifies the tenant owning a resource. The operator copies it
+// from the reconciled resource to every object it writes.
+const TenantLabel = "blockchain.axelar.network/tenant"
+
+// ManagedByLabel marks the objects written by the operator with ManagedBy
+const ManagedByLabel = "app.kubernetes.io/managed-by"
+
+// ManagedBy is the value of ManagedByLabel on the objects written by the operator
+const ManagedBy = "axelar-operator"
+
+// NameLabel names the application an object generated by the operator belongs to
+const NameLabel = "app.kubernetes.io/name"
+
+// InstanceLabel names the resource an object generated by the operator belongs to
+const InstanceLabel = "app.kubernetes.io/instance"
+
+// ComponentLabel names the part of the application an object generated by
+// the operator belongs to
+const ComponentLabel = "app.kubernetes.io/component"
+
+// VersionLabel is the version of the application an object generated by the
+// operator belongs to
+const VersionLabel = "app.kubernetes.io/version"
+
+// AxelarNetworkSpec defines the desired state of AxelarNetwork
+type AxelarNetworkSpec struct {
+	// NetworkName specifies which Axelar network this is: mainnet, testnet or
+	// a network registered in the networks ConfigMap of the operator
+	// +kubebuilder:validation:MinLength=1
+	NetworkName string `json:"networkName"`
+
+	// ChainID is the chain identifier of the network
+	ChainID string `json:"chainId"`
+
+	// Genesis configuration
+	Genesis GenesisSpec `json:"genesis,omitempty"`
+
+	// Seeds list
+	Seeds []PeerSpec `json:"seeds,omitempty"`
+
+	// PersistentPeers list
+	PersistentPeers []PeerSpec `json:"persistentPeers,omitempty"`
+
+	// Upgrades scheduled on the network
+	Upgrades []NetworkUpgradeSpec `json:"upgrades,omitempty"`
+
+	// Monitoring configuration
+	Monitoring NetworkMonitoringSpec `json:"monitoring,omitempty"`
+
+	// Image is rolled out to every member node when set
+	Image *ImageSpec `json:"image,omitempty"`
+
+	// Rollout configures how image changes reach the member nodes
+	Rollout RolloutSpec `json:"rollout,omitempty"`
+
+	// HA configures active-passive validator failover
+	HA *ValidatorHASpec `json:"ha,omitempty"`
+
+	// Replicas is the number of sentry or observer members created from
+	// nodeTemplate, adjusted by kubectl scale or an HPA
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// NodeTemplate describes the members created to reach replicas
+	NodeTemplate *NetworkNodeTemplate `json:"nodeTemplate,omitempty"`
+
+	// NodeDefaults is the common spec of the members created by the network.
+	// The node template and member overrides are merged over it.
+	NodeDefaults *AxelarNodeSpec `json:"nodeDefaults,omitempty"`
+
+	// Members overrides the spec of individual members created by the network
+	Members []NetworkMemberOverride `json:"members,omitempty"`
+
+	// MemberNamespaces are the namespaces, besides the network's own, that
+	// AxelarNodes may join the network from. They must belong to the tenant
+	// of the network.
+	MemberNamespaces []string `json:"memberNamespaces,omitempty"`
+
+	// Scaling configures how members are removed on scale-down
+	Scaling NetworkScalingSpec `json:"scaling,omitempty"`
+}
+
+// NetworkNodeTemplate describes the AxelarNodes created by a scaled network
+type NetworkNodeTemplate struct {
+	// Labels added to the created nodes
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Spec of the created nodes, which must be sentries or observers
+	Spec AxelarNodeSpec `json:"spec"`
+}
+
+// NetworkMemberOverride overrides the spec of a member created by the network
+type NetworkMemberOverride struct {
+	// Name of the member
+	Name string `json:"name"`
+
+	// Resources of the member
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// StorageClass of the member volumes, used when they are created
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Zone the member is pinned to, used when it is created
+	Zone string `json:"zone,omitempty"`
+
+	// ImageTag pins the member to an image tag, excluding it from rollouts
+	ImageTag string `json:"imageTag,omitempty"`
+}
+
+// NetworkScalingSpec defines how a scaled network removes members
+type NetworkScalingSpec struct {
+	// DrainPeriod a member stops accepting peers for before it is deleted
+	// +kubebuilder:default="2m"
+	DrainPeriod metav1.Duration `json:"drainPeriod,omitempty"`
+}
+
+// ScaledMemberLabel marks the nodes and pods created by a scaled network with its name
+const ScaledMemberLabel = "blockchain.axelar.network/scaled-member"
+
+// DrainAnnotation marks a member being removed on scale-down with the time
+// draining started
+const DrainAnnotation = "blockchain.axelar.network/draining"
+
+// ValidatorHASpec defines an active-passive validator pair
+type ValidatorHASpec struct {
+	// Enabled turns on active-passive failover
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Nodes are the two validator member nodes, in order of preference
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	Nodes []HAMemberSpec `json:"nodes"`
+
+	// KeySecret is the Secret holding priv_validator_key.json
+	KeySecret string `json:"keySecret"`
+
+	// AutoFailover fails over when the armed node stays unhealthy
+	AutoFailover bool `json:"autoFailover,omitempty"`
+
+	// FailureThreshold is how long the armed node may be unhealthy before failover
+	// +kubebuilder:default="5m"
+	FailureThreshold metav1.Duration `json:"failureThreshold,omitempty"`
+
+	// SafetyMargin is added to the replicated signing height when arming a node
+	// +kubebuilder:default=2
+	SafetyMargin int64 `json:"safetyMargin,omitempty"`
+}
+
+// HAMemberSpec defines a member of the validator pair
+type HAMemberSpec struct {
+	// Name of the AxelarNode
+	Name string `json:"name"`
+
+	// Zone the node is placed in
+	Zone string `json:"zone"`
+}
+
+// FailoverAnnotation requests a failover to the named HA node. The value
+// "force" confirms that an unreachable armed node is fenced.
+const FailoverAnnotation = "blockchain.axelar.network/failover"
+
+// GenesisSpec defines genesis configuration
+type GenesisSpec struct {
+	// URL to download the genesis file from
+	URL string `json:"url,omitempty"`
+
+	// Checksum of the genesis file
+	Checksum string `json:"checksum,omitempty"`
+
+	// AutoUpdate enables automatic genesis updates
+	AutoUpdate bool `json:"autoUpdate,omitempty"`
+}
+
+// PeerSpec defines a seed or persistent peer
+type PeerSpec struct {
+	// ID is the Tendermint node ID
+	ID string `json:"id,omitempty"`
+
+	// Address is the host:port of the peer
+	Address string `json:"address,omitempty"`
+
+	// Provider operating the peer
+	Provider string `json:"provider,omitempty"`
+}
+
+// NetworkUpgradeSpec defines a scheduled network upgrade
+type NetworkUpgradeSpec struct {
+	// Name of the upgrade
+	Name string `json:"name,omitempty"`
+
+	// Height at which the upgrade takes place
+	Height int64 `json:"height,omitempty"`
+
+	// Version of the node software after the upgrade
+	Version string `json:"version,omitempty"`
+
+	// Info about the upgrade
+	Info string `json:"info,omitempty"`
+
+	// Scheduled indicates if the upgrade is scheduled
+	Scheduled bool `json:"scheduled,omitempty"`
+}
+
+// NetworkMonitoringSpec defines network-wide monitoring configuration
+type NetworkMonitoringSpec struct {
+	// HealthCheck configuration
+	HealthCheck HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// Metrics configuration
+	Metrics MetricsSpec `json:"metrics,omitempty"`
+}
+
+// HealthCheckSpec defines health check configuration
+type HealthCheckSpec struct {
+	// Enabled indicates if health checks are enabled
+	// +kubebuilder:default=true
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval between health checks
+	// +kubebuilder:default="30s"
+	Interval string `json:"interval,omitempty"`
+
+	// Endpoints to check
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// MetricsSpec defines metrics configuration
+type MetricsSpec struct {
+	// Aggregation enables metrics aggregation
+	// +kubebuilder:default=true
+	Aggregation bool `json:"aggregation,omitempty"`
+
+	// Retention period for metrics
+	// +kubebuilder:default="30d"
+	Retention string `json:"retention,omitempty"`
+}
+
+// RolloutSpec defines how image changes are rolled out to member nodes
+type RolloutSpec struct {
+	// Canary configuration
+	Canary CanarySpec `json:"canary,omitempty"`
+}
+
+// CanarySpec defines canary upgrade configuration
+type CanarySpec struct {
+	// Enabled upgrades a single sentry or observer first
+	// +kubebuilder:default=true
+	Enabled bool `json:"enabled,omitempty"`
+
+	// SoakTime the canary must stay synced before the rollout continues
+	// +kubebuilder:default="30m"
+	SoakTime metav1.Duration `json:"soakTime,omitempty"`
+
+	// Timeout after which an unsynced canary aborts the rollout
+	// +kubebuilder:default="2h"
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// AxelarNetworkStatus defines the observed state of AxelarNetwork
+type AxelarNetworkStatus struct {
+	// Phase represents the current phase of the network
+	// +kubebuilder:validation:Enum=Initializing;Active;Upgrading;Degraded
+	Phase string `json:"phase,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// NetworkStats contains aggregated member information
+	NetworkStats NetworkStats `json:"networkStats,omitempty"`
+
+	// LastUpgrade contains the last completed upgrade
+	LastUpgrade *NetworkUpgradeStatus `json:"lastUpgrade,omitempty"`
+
+	// Rollout contains the progress of the current image rollout
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// HA contains the active-passive validator state
+	HA *HAStatus `json:"ha,omitempty"`
+
+	// Governance lists the proposals open for voting, as last reported by a member
+	Governance *GovernanceStatus `json:"governance,omitempty"`
+
+	// Validators reports the signing state of the validator members
+	Validators []ValidatorSigningStatus `json:"validators,omitempty"`
+
+	// Replicas is the number of created members that are not draining
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Selector is the label selector of the pods of created members
+	Selector string `json:"selector,omitempty"`
+}
+
+// HA phases of an AxelarNetwork validator pair
+const (
+	HAPhaseArmed   = "Armed"
+	HAPhaseFencing = "Fencing"
+)
+
+// HAStatus contains the active-passive validator state
+type HAStatus struct {
+	// ArmedNode is the node holding the signing lease
+	ArmedNode string `json:"armedNode,omitempty"`
+
+	// Phase of the validator pair
+	// +kubebuilder:validation:Enum=Armed;Fencing
+	Phase string `json:"phase,omitempty"`
+
+	// FailoverTarget is the node being armed during a failover
+	FailoverTarget string `json:"failoverTarget,omitempty"`
+
+	// SigningHeight is the last replicated signing height
+	SigningHeight int64 `json:"signingHeight,omitempty"`
+
+	// UnhealthySince is when the armed node was first seen unhealthy
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+
+	// LastFailover is when the last failover completed
+	LastFailover *metav1.Time `json:"lastFailover,omitempty"`
+
+	// Message describes the HA state
+	Message string `json:"message,omitempty"`
+}
+
+// NetworkStats contains aggregated member information
+type NetworkStats struct {
+	// TotalNodes is the number of member nodes
+	TotalNodes int32 `json:"totalNodes,omitempty"`
+
+	// ActiveValidators is the number of running validators
+	ActiveValidators int32 `json:"activeValidators,omitempty"`
+
+	// CurrentHeight is the highest block height among members
+	CurrentHeight int64 `json:"currentHeight,omitempty"`
+
+	// AverageBlockTime across the network
+	AverageBlockTime string `json:"averageBlockTime,omitempty"`
+
+	// Phases counts the members by phase
+	Phases map[string]int32 `json:"phases,omitempty"`
+
+	// SyncedNodes is the number of members with the Synced condition
+	SyncedNodes int32 `json:"syncedNodes,omitempty"`
+
+	// LowestHeight is the lowest block height among members reporting one
+	LowestHeight int64 `json:"lowestHeight,omitempty"`
+
+	// MaxSyncLag is the largest number of blocks a member is behind the
+	// highest height known to the network
+	MaxSyncLag int64 `json:"maxSyncLag,omitempty"`
+}
+
+// ValidatorSigningStatus is the signing state of a validator member
+type ValidatorSigningStatus struct {
+	// Node is the name of the AxelarNode
+	Node string `json:"node"`
+
+	// Armed is true when the node runs the signing stack
+	Armed bool `json:"armed"`
+
+	// Signing is true when an armed node is synced with healthy vald and tofnd
+	Signing bool `json:"signing"`
+
+	// MissedBlocks is the number of missed blocks reported by the node
+	MissedBlocks int32 `json:"missedBlocks,omitempty"`
+
+	// LastSignedHeight is the last block height the node signed
+	LastSignedHeight int64 `json:"lastSignedHeight,omitempty"`
+
+	// Message explains why an armed node is not signing
+	Message string `json:"message,omitempty"`
+}
+
+// ConditionMemberNamespaces is true while every member namespace may join the network
+const ConditionMemberNamespaces = "MemberNamespaces"
+
+// ConditionNetworkReady is true while every member is synced and every armed validator signs
+const ConditionNetworkReady = "Ready"
+
+// NetworkUpgradeStatus contains the result of an upgrade
+type NetworkUpgradeStatus struct {
+	// Name of the upgrade
+	Name string `json:"name,omitempty"`
+
+	// Height at which the upgrade happened
+	Height int64 `json:"height,omitempty"`
+
+	// Timestamp of the upgrade
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+
+	// Success indicates if the upgrade succeeded
+	Success bool `json:"success,omitempty"`
+}
+
+// Rollout stages of an AxelarNetwork image rollout
+const (
+	RolloutStageCanary     = "Canary"
+	RolloutStageSentries   = "Sentries"
+	RolloutStageValidators = "Validators"
+	RolloutStageCompleted  = "Completed"
+	RolloutStageAborted    = "Aborted"
+)
+
+// RolloutStatus contains the progress of an image rollout
+type RolloutStatus struct {
+	// TargetTag is the image tag being rolled out
+	TargetTag string `json:"targetTag,omitempty"`
+
+	// PreviousTag is the image tag members ran before the rollout
+	PreviousTag string `json:"previousTag,omitempty"`
+
+	// Stage of the rollout
+	// +kubebuilder:validation:Enum=Canary;Sentries;Validators;Completed;Aborted
+	Stage string `json:"stage,omitempty"`
+
+	// CanaryNode is the name of the node upgraded first
+	CanaryNode string `json:"canaryNode,omitempty"`
+
+	// StartedAt is when the rollout started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CanarySyncedAt is when the canary was first seen synced on the target tag
+	CanarySyncedAt *metav1.Time `json:"canarySyncedAt,omitempty"`
+
+	// Message describes the current rollout state
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=axnet;axnetwork,categories=axelar;blockchain
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="Network",type="string",JSONPath=".spec.networkName"
+// +kubebuilder:printcolumn:name="Chain-ID",type="string",JSONPath=".spec.chainId"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.image.tag"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=="Ready")].status"
+// +kubebuilder:printcolumn:name="Nodes",type="integer",JSONPath=".status.networkStats.totalNodes"
+// +kubebuilder:printcolumn:name="Validators",type="integer",JSONPath=".status.networkStats.activeValidators"
+// +kubebuilder:printcolumn:name="Height",type="integer",JSONPath=".status.networkStats.currentHeight"
+// +kubebuilder:printcolumn:name="Rollout",type="string",JSONPath=".status.rollout.stage"
+
+// AxelarNetwork is the Schema for the axelarnetworks API
+type AxelarNetwork struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AxelarNetworkSpec   `json:"spec,omitempty"`
+	Status AxelarNetworkStatus `json:"status,omitempty"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *AxelarNetwork) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarNetwork.
+func (in *AxelarNetwork) DeepCopy() *AxelarNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarNetwork) DeepCopyInto(out *AxelarNetwork) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// +kubebuilder:object:root=true
+
+// AxelarNetworkList contains a list of AxelarNetwork
+type AxelarNetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AxelarNetwork `json:"items"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *AxelarNetworkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarNetworkList.
+func (in *AxelarNetworkList) DeepCopy() *AxelarNetworkList {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarNetworkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarNetworkList) DeepCopyInto(out *AxelarNetworkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AxelarNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarNetworkSpec) DeepCopyInto(out *AxelarNetworkSpec) {
+	*out = *in
+	if in.Seeds != nil {
+		in, out := &in.Seeds, &out.Seeds
+		*out = make([]PeerSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.PersistentPeers != nil {
+		in, out := &in.PersistentPeers, &out.PersistentPeers
+		*out = make([]PeerSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgrades != nil {
+		in, out := &in.Upgrades, &out.Upgrades
+		*out = make([]NetworkUpgradeSpec, len(*in))
+		copy(*out, *in)
+	}
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(ImageSpec)
+		**out = **in
+	}
+	out.Rollout = in.Rollout
+	if in.HA != nil {
+		in, out := &in.HA, &out.HA
+		*out = new(ValidatorHASpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeTemplate != nil {
+		in, out := &in.NodeTemplate, &out.NodeTemplate
+		*out = new(NetworkNodeTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeDefaults != nil {
+		in, out := &in.NodeDefaults, &out.NodeDefaults
+		*out = new(AxelarNodeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]NetworkMemberOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Scaling = in.Scaling
+	if in.MemberNamespaces != nil {
+		in, out := &in.MemberNamespaces, &out.MemberNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkMemberOverride) DeepCopyInto(out *NetworkMemberOverride) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkNodeTemplate) DeepCopyInto(out *NetworkNodeTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidatorHASpec) DeepCopyInto(out *ValidatorHASpec) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]HAMemberSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidatorHASpec.
+func (in *ValidatorHASpec) DeepCopy() *ValidatorHASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidatorHASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarNetworkSpec.
+func (in *AxelarNetworkSpec) DeepCopy() *AxelarNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkMonitoringSpec) DeepCopyInto(out *NetworkMonitoringSpec) {
+	*out = *in
+	if in.HealthCheck.Endpoints != nil {
+		in, out := &in.HealthCheck.Endpoints, &out.HealthCheck.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Metrics = in.Metrics
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkMonitoringSpec.
+func (in *NetworkMonitoringSpec) DeepCopy() *NetworkMonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkMonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarNetworkStatus) DeepCopyInto(out *AxelarNetworkStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.NetworkStats.DeepCopyInto(&out.NetworkStats)
+	if in.LastUpgrade != nil {
+		in, out := &in.LastUpgrade, &out.LastUpgrade
+		*out = new(NetworkUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HA != nil {
+		in, out := &in.HA, &out.HA
+		*out = new(HAStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Governance != nil {
+		in, out := &in.Governance, &out.Governance
+		*out = new(GovernanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validators != nil {
+		in, out := &in.Validators, &out.Validators
+		*out = make([]ValidatorSigningStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkStats) DeepCopyInto(out *NetworkStats) {
+	*out = *in
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAStatus) DeepCopyInto(out *HAStatus) {
+	*out = *in
+	if in.UnhealthySince != nil {
+		in, out := &in.UnhealthySince, &out.UnhealthySince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailover != nil {
+		in, out := &in.LastFailover, &out.LastFailover
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAStatus.
+func (in *HAStatus) DeepCopy() *HAStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HAStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarNetworkStatus.
+func (in *AxelarNetworkStatus) DeepCopy() *AxelarNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkUpgradeStatus) DeepCopyInto(out *NetworkUpgradeStatus) {
+	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkUpgradeStatus.
+func (in *NetworkUpgradeStatus) DeepCopy() *NetworkUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CanarySyncedAt != nil {
+		in, out := &in.CanarySyncedAt, &out.CanarySyncedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
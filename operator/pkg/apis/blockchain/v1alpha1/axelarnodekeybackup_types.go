@@ -0,0 +1,222 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Keys an AxelarNodeKeyBackup escrows
+const (
+	// EscrowKeyTofnd is the tofnd mnemonic the key shares are derived from
+	EscrowKeyTofnd = "tofnd"
+	// EscrowKeyBroadcaster is the keyring holding the vald broadcaster key
+	EscrowKeyBroadcaster = "broadcaster"
+	// EscrowKeyNode is the node key and the consensus key of the validator
+	EscrowKeyNode = "node"
+)
+
+// AxelarNodeKeyBackupSpec defines the desired state of AxelarNodeKeyBackup
+type AxelarNodeKeyBackupSpec struct {
+	// NodeRef names the AxelarNode in the same namespace whose keys are escrowed
+	NodeRef corev1.LocalObjectReference `json:"nodeRef"`
+
+	// Keys escrowed, all of them by default
+	// +kubebuilder:validation:items:Enum=tofnd;broadcaster;node
+	Keys []string `json:"keys,omitempty"`
+
+	// Encryption of the archive. Only the holder of the age identity or of
+	// the KMS key can read the keys back.
+	Encryption BackupEncryptionSpec `json:"encryption"`
+
+	// Destination the encrypted archive is uploaded to
+	Destination KeyBackupDestination `json:"destination"`
+
+	// Schedule is a cron schedule repeating the backup. The keys are backed up
+	// once per change of the spec when empty.
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// KeyBackupDestination is the object storage bucket key archives are uploaded to
+type KeyBackupDestination struct {
+	// BucketURL is the bucket, such as s3://bucket?region=eu-west-1,
+	// gs://bucket or azblob://container
+	// +kubebuilder:validation:MinLength=1
+	BucketURL string `json:"bucketURL"`
+
+	// Prefix of the uploaded objects
+	Prefix string `json:"prefix,omitempty"`
+
+	// CredentialsSecret is a Secret whose keys are set as the environment of
+	// the backup Job, such as AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// ServiceAccountName of the backup Job, for workload identity
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// Phases of an AxelarNodeKeyBackup
+const (
+	KeyBackupPending   = "Pending"
+	KeyBackupRunning   = "Running"
+	KeyBackupSucceeded = "Succeeded"
+	KeyBackupFailed    = "Failed"
+)
+
+// AxelarNodeKeyBackupStatus defines the observed state of AxelarNodeKeyBackup
+type AxelarNodeKeyBackupStatus struct {
+	// Phase of the current or last backup
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	Phase string `json:"phase,omitempty"`
+
+	// ObservedGeneration is the generation of the spec last backed up
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Job running the current or last backup
+	Job string `json:"job,omitempty"`
+
+	// StartedAt is when the current or last backup started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// LastBackup is when the keys were last escrowed
+	LastBackup *metav1.Time `json:"lastBackup,omitempty"`
+
+	// Object is the URL of the last uploaded archive
+	Object string `json:"object,omitempty"`
+
+	// Fingerprint is the SHA-256 of the last uploaded archive, to check the
+	// archive held in escrow is the one backed up
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Files in the last uploaded archive
+	Files []string `json:"files,omitempty"`
+
+	// Message explains the phase
+	Message string `json:"message,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=axkeybackup,categories=axelar;blockchain
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Last Backup",type="date",JSONPath=".status.lastBackup"
+// +kubebuilder:printcolumn:name="Fingerprint",type="string",JSONPath=".status.fingerprint",priority=1
+
+// AxelarNodeKeyBackup is the Schema for the axelarnodekeybackups API
+type AxelarNodeKeyBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AxelarNodeKeyBackupSpec   `json:"spec,omitempty"`
+	Status AxelarNodeKeyBackupStatus `json:"status,omitempty"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *AxelarNodeKeyBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarNodeKeyBackup.
+func (in *AxelarNodeKeyBackup) DeepCopy() *AxelarNodeKeyBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarNodeKeyBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarNodeKeyBackup) DeepCopyInto(out *AxelarNodeKeyBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// +kubebuilder:object:root=true
+
+// AxelarNodeKeyBackupList contains a list of AxelarNodeKeyBackup
+type AxelarNodeKeyBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AxelarNodeKeyBackup `json:"items"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *AxelarNodeKeyBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarNodeKeyBackupList.
+func (in *AxelarNodeKeyBackupList) DeepCopy() *AxelarNodeKeyBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarNodeKeyBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarNodeKeyBackupList) DeepCopyInto(out *AxelarNodeKeyBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AxelarNodeKeyBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarNodeKeyBackupSpec) DeepCopyInto(out *AxelarNodeKeyBackupSpec) {
+	*out = *in
+	out.NodeRef = in.NodeRef
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Encryption.DeepCopyInto(&out.Encryption)
+	out.Destination = in.Destination
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarNodeKeyBackupStatus) DeepCopyInto(out *AxelarNodeKeyBackupStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastBackup != nil {
+		in, out := &in.LastBackup, &out.LastBackup
+		*out = (*in).DeepCopy()
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
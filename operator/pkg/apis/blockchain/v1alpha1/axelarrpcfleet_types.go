@@ -0,0 +1,179 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AxelarRPCFleetSpec defines the desired state of AxelarRPCFleet
+// +kubebuilder:validation:XValidation:rule="!(self.network == 'mainnet' && has(self.networking) && has(self.networking.rpc) && has(self.networking.rpc.unsafe) && self.networking.rpc.unsafe)",message="unsafe RPC commands are not allowed on mainnet"
+type AxelarRPCFleetSpec struct {
+	// Network specifies which Axelar network to connect to: mainnet, testnet
+	// or a network registered in the networks ConfigMap of the operator
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:default=testnet
+	Network string `json:"network"`
+
+	// Replicas is the number of RPC nodes, managed by the HPA while autoscaling
+	// +kubebuilder:default=2
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Image configuration for the RPC nodes
+	Image ImageSpec `json:"image,omitempty"`
+
+	// Resources defines the compute resources for each RPC node
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Storage configuration for each RPC node
+	Storage StorageSpec `json:"storage,omitempty"`
+
+	// Pruning strategy of the RPC nodes
+	// +kubebuilder:validation:Enum=default;everything;nothing
+	// +kubebuilder:default=everything
+	Pruning string `json:"pruning,omitempty"`
+
+	// Networking configuration
+	Networking NetworkingSpec `json:"networking,omitempty"`
+
+	// Monitoring configuration
+	Monitoring MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Autoscaling configuration
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+}
+
+// AxelarRPCFleetStatus defines the observed state of AxelarRPCFleet
+type AxelarRPCFleetStatus struct {
+	// Replicas is the number of RPC nodes
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of synced RPC nodes serving traffic
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Selector is the label selector of the RPC node pods
+	Selector string `json:"selector,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// FleetLabel marks pods belonging to an AxelarRPCFleet
+const FleetLabel = "blockchain.axelar.network/rpc-fleet"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=axfleet,categories=axelar;blockchain
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="Network",type="string",JSONPath=".spec.network"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.image.tag"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas"
+
+// AxelarRPCFleet is the Schema for the axelarrpcfleets API
+type AxelarRPCFleet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AxelarRPCFleetSpec   `json:"spec,omitempty"`
+	Status AxelarRPCFleetStatus `json:"status,omitempty"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *AxelarRPCFleet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarRPCFleet.
+func (in *AxelarRPCFleet) DeepCopy() *AxelarRPCFleet {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarRPCFleet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarRPCFleet) DeepCopyInto(out *AxelarRPCFleet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// +kubebuilder:object:root=true
+
+// AxelarRPCFleetList contains a list of AxelarRPCFleet
+type AxelarRPCFleetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AxelarRPCFleet `json:"items"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *AxelarRPCFleetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarRPCFleetList.
+func (in *AxelarRPCFleetList) DeepCopy() *AxelarRPCFleetList {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarRPCFleetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarRPCFleetList) DeepCopyInto(out *AxelarRPCFleetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AxelarRPCFleet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarRPCFleetSpec) DeepCopyInto(out *AxelarRPCFleetSpec) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Networking.DeepCopyInto(&out.Networking)
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarRPCFleetStatus) DeepCopyInto(out *AxelarRPCFleetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
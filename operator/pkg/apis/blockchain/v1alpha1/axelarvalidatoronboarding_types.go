@@ -0,0 +1,218 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AxelarValidatorOnboardingSpec defines the desired state of AxelarValidatorOnboarding
+type AxelarValidatorOnboardingSpec struct {
+	// NodeRef names the validator AxelarNode in the same namespace
+	NodeRef corev1.LocalObjectReference `json:"nodeRef"`
+
+	// OperatorAddress is the axelarvaloper address of the validator. Defaults
+	// to spec.validator.profile.operatorAddress of the node.
+	OperatorAddress string `json:"operatorAddress,omitempty"`
+
+	// BroadcasterAddress is the axelar address vald broadcasts from. Defaults
+	// to spec.validator.heartbeat.broadcasterAddress of the node.
+	BroadcasterAddress string `json:"broadcasterAddress,omitempty"`
+
+	// MinOperatorBalance in uaxl the operator account needs before onboarding continues
+	// +kubebuilder:default="1000000"
+	MinOperatorBalance string `json:"minOperatorBalance,omitempty"`
+
+	// MinBroadcasterBalance in uaxl the broadcaster account needs to pay for
+	// the transactions of vald
+	// +kubebuilder:default="5000000"
+	MinBroadcasterBalance string `json:"minBroadcasterBalance,omitempty"`
+
+	// Chains the validator registers as a chain maintainer of, such as Ethereum
+	Chains []string `json:"chains,omitempty"`
+
+	// OperatorKeySecretRef references the mnemonic of the operator account,
+	// which registers the broadcaster. Defaults to the transaction key of the node.
+	OperatorKeySecretRef *corev1.SecretKeySelector `json:"operatorKeySecretRef,omitempty"`
+
+	// BroadcasterKeySecretRef references the mnemonic of the broadcaster
+	// account, which registers the chain maintainers. The registration is
+	// left to the operator of the validator when unset.
+	BroadcasterKeySecretRef *corev1.SecretKeySelector `json:"broadcasterKeySecretRef,omitempty"`
+}
+
+// Onboarding steps, in order. Each one is reported as a condition of the same type.
+const (
+	OnboardingNodeSynced            = "NodeSynced"
+	OnboardingFunded                = "Funded"
+	OnboardingTofndKeysGenerated    = "TofndKeysGenerated"
+	OnboardingValidatorCreated      = "ValidatorCreated"
+	OnboardingProxyRegistered       = "ProxyRegistered"
+	OnboardingMaintainersRegistered = "ChainMaintainersRegistered"
+)
+
+// OnboardingSteps lists the onboarding steps in the order they are taken
+var OnboardingSteps = []string{
+	OnboardingNodeSynced,
+	OnboardingFunded,
+	OnboardingTofndKeysGenerated,
+	OnboardingValidatorCreated,
+	OnboardingProxyRegistered,
+	OnboardingMaintainersRegistered,
+}
+
+// Phases of an AxelarValidatorOnboarding
+const (
+	OnboardingInProgress = "InProgress"
+	OnboardingCompleted  = "Completed"
+)
+
+// AxelarValidatorOnboardingStatus defines the observed state of AxelarValidatorOnboarding
+type AxelarValidatorOnboardingStatus struct {
+	// Phase of the onboarding
+	// +kubebuilder:validation:Enum=InProgress;Completed
+	Phase string `json:"phase,omitempty"`
+
+	// Step is the first step not completed yet
+	Step string `json:"step,omitempty"`
+
+	// Job sending the transaction of the current step
+	Job string `json:"job,omitempty"`
+
+	// TxHashes of the transactions sent by the operator, keyed by step
+	TxHashes map[string]string `json:"txHashes,omitempty"`
+
+	// CompletedAt is when every step was first completed
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Conditions report each step
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=axonboard,categories=axelar;blockchain
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Step",type="string",JSONPath=".status.step"
+
+// AxelarValidatorOnboarding is the Schema for the axelarvalidatoronboardings API
+type AxelarValidatorOnboarding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AxelarValidatorOnboardingSpec   `json:"spec,omitempty"`
+	Status AxelarValidatorOnboardingStatus `json:"status,omitempty"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *AxelarValidatorOnboarding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarValidatorOnboarding.
+func (in *AxelarValidatorOnboarding) DeepCopy() *AxelarValidatorOnboarding {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarValidatorOnboarding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarValidatorOnboarding) DeepCopyInto(out *AxelarValidatorOnboarding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// +kubebuilder:object:root=true
+
+// AxelarValidatorOnboardingList contains a list of AxelarValidatorOnboarding
+type AxelarValidatorOnboardingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AxelarValidatorOnboarding `json:"items"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *AxelarValidatorOnboardingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxelarValidatorOnboardingList.
+func (in *AxelarValidatorOnboardingList) DeepCopy() *AxelarValidatorOnboardingList {
+	if in == nil {
+		return nil
+	}
+	out := new(AxelarValidatorOnboardingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarValidatorOnboardingList) DeepCopyInto(out *AxelarValidatorOnboardingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AxelarValidatorOnboarding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarValidatorOnboardingSpec) DeepCopyInto(out *AxelarValidatorOnboardingSpec) {
+	*out = *in
+	out.NodeRef = in.NodeRef
+	if in.Chains != nil {
+		in, out := &in.Chains, &out.Chains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OperatorKeySecretRef != nil {
+		in, out := &in.OperatorKeySecretRef, &out.OperatorKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BroadcasterKeySecretRef != nil {
+		in, out := &in.BroadcasterKeySecretRef, &out.BroadcasterKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxelarValidatorOnboardingStatus) DeepCopyInto(out *AxelarValidatorOnboardingStatus) {
+	*out = *in
+	if in.TxHashes != nil {
+		in, out := &in.TxHashes, &out.TxHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
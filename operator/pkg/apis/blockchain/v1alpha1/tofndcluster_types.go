@@ -0,0 +1,259 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// TofndClusterSpec defines the desired state of TofndCluster
+type TofndClusterSpec struct {
+	// Image of tofnd
+	// +kubebuilder:default="axelarnet/tofnd:v0.10.1"
+	Image string `json:"image,omitempty"`
+
+	// PasswordSecretRef references the Secret key holding the tofnd password
+	PasswordSecretRef corev1.SecretKeySelector `json:"passwordSecretRef"`
+
+	// Storage of the tofnd mnemonic and key shares
+	Storage TofndStorageSpec `json:"storage,omitempty"`
+
+	// Resources defines the compute resources of tofnd
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Probes of tofnd. By default its liveness checks the gRPC port accepts
+	// connections, and readiness is disabled.
+	Probes SidecarProbesSpec `json:"probes,omitempty"`
+
+	// SecurityContext of the tofnd pod
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+
+	// Backup escrows the mnemonic and key shares on a schedule
+	Backup *TofndBackupSpec `json:"backup,omitempty"`
+
+	// NetworkPolicy only admits the validators referencing the cluster to tofnd
+	// +kubebuilder:default=true
+	NetworkPolicy *bool `json:"networkPolicy,omitempty"`
+}
+
+// TofndStorageSpec configures the volume of tofnd
+type TofndStorageSpec struct {
+	// Size of the volume
+	// +kubebuilder:default="10Gi"
+	Size string `json:"size,omitempty"`
+
+	// StorageClass of the volume, the cluster default when empty
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// TofndBackupSpec schedules the escrow of the tofnd volume
+type TofndBackupSpec struct {
+	// Schedule is a cron schedule of the backups
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Encryption of the archive. Only the holder of the age identity or of
+	// the KMS key can read the keys back.
+	Encryption BackupEncryptionSpec `json:"encryption"`
+
+	// Destination the encrypted archive is uploaded to
+	Destination KeyBackupDestination `json:"destination"`
+}
+
+// Phases of a TofndCluster
+const (
+	TofndClusterPending = "Pending"
+	TofndClusterRunning = "Running"
+)
+
+// ConditionTofndReady is true while tofnd is running and ready
+const ConditionTofndReady = "Ready"
+
+// TofndClusterStatus defines the observed state of TofndCluster
+type TofndClusterStatus struct {
+	// Phase of tofnd
+	// +kubebuilder:validation:Enum=Pending;Running
+	Phase string `json:"phase,omitempty"`
+
+	// Endpoint is the address vald dials tofnd on
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Validators lists the AxelarNodes signing with the cluster
+	Validators []string `json:"validators,omitempty"`
+
+	// Backup reports the current or last backup
+	Backup TofndBackupStatus `json:"backup,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// TofndBackupStatus reports a backup of a TofndCluster
+type TofndBackupStatus struct {
+	// Phase of the current or last backup
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	Phase string `json:"phase,omitempty"`
+
+	// Job running the current or last backup
+	Job string `json:"job,omitempty"`
+
+	// StartedAt is when the current or last backup started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// LastBackup is when the volume was last escrowed
+	LastBackup *metav1.Time `json:"lastBackup,omitempty"`
+
+	// Object is the URL of the last uploaded archive
+	Object string `json:"object,omitempty"`
+
+	// Fingerprint is the SHA-256 of the last uploaded archive
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Message explains the phase
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=axtofnd,categories=axelar;blockchain
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".status.endpoint"
+// +kubebuilder:printcolumn:name="Last Backup",type="date",JSONPath=".status.backup.lastBackup"
+
+// TofndCluster is the Schema for the tofndclusters API
+type TofndCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TofndClusterSpec   `json:"spec,omitempty"`
+	Status TofndClusterStatus `json:"status,omitempty"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *TofndCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofndCluster.
+func (in *TofndCluster) DeepCopy() *TofndCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(TofndCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofndCluster) DeepCopyInto(out *TofndCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// +kubebuilder:object:root=true
+
+// TofndClusterList contains a list of TofndCluster
+type TofndClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TofndCluster `json:"items"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *TofndClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofndClusterList.
+func (in *TofndClusterList) DeepCopy() *TofndClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(TofndClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofndClusterList) DeepCopyInto(out *TofndClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TofndCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofndClusterSpec) DeepCopyInto(out *TofndClusterSpec) {
+	*out = *in
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Probes.DeepCopyInto(&out.Probes)
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(TofndBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofndBackupSpec) DeepCopyInto(out *TofndBackupSpec) {
+	*out = *in
+	in.Encryption.DeepCopyInto(&out.Encryption)
+	out.Destination = in.Destination
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofndClusterStatus) DeepCopyInto(out *TofndClusterStatus) {
+	*out = *in
+	if in.Validators != nil {
+		in, out := &in.Validators, &out.Validators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Backup.DeepCopyInto(&out.Backup)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofndBackupStatus) DeepCopyInto(out *TofndBackupStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastBackup != nil {
+		in, out := &in.LastBackup, &out.LastBackup
+		*out = (*in).DeepCopy()
+	}
+}
@@ -0,0 +1,147 @@
+// Package audit records every mutation the operator performs on the cluster,
+// with the reconciled object that caused it, so validator operations teams can
+// account for each change in compliance reviews.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Verbs of audited mutations
+const (
+	VerbCreate      = "create"
+	VerbUpdate      = "update"
+	VerbPatch       = "patch"
+	VerbDelete      = "delete"
+	VerbDeleteAllOf = "deletecollection"
+)
+
+// DefaultReason is recorded for mutations made by a plain reconcile
+const DefaultReason = "reconcile"
+
+// maxChanges bounds the changed paths recorded per entry
+const maxChanges = 20
+
+// Entry is a single audited mutation
+type Entry struct {
+	// Time of the mutation
+	Time time.Time `json:"time"`
+	// Verb of the mutation
+	Verb string `json:"verb"`
+	// Kind, Namespace and Name identify the mutated object
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// Cluster is the remote cluster the object lives in, empty for the local one
+	Cluster string `json:"cluster,omitempty"`
+	// Actor is the reconciled object or user that initiated the mutation
+	Actor string `json:"actor"`
+	// Reason describes why the mutation was made
+	Reason string `json:"reason"`
+	// Changes lists the paths of the fields that changed
+	Changes []string `json:"changes,omitempty"`
+	// Error is set when the API server rejected the mutation
+	Error string `json:"error,omitempty"`
+}
+
+// Sink receives audit entries. actor is the reconciled object that initiated
+// the mutation, or nil, and target the mutated object.
+type Sink interface {
+	Record(entry Entry, actor, target client.Object)
+}
+
+// Auditor fans entries out to its sinks
+type Auditor struct {
+	sinks []Sink
+}
+
+// NewAuditor creates an auditor recording to sinks
+func NewAuditor(sinks ...Sink) *Auditor {
+	return &Auditor{sinks: sinks}
+}
+
+// record sends the entry to every sink
+func (a *Auditor) record(entry Entry, actor, target client.Object) {
+	for _, sink := range a.sinks {
+		sink.Record(entry, actor, target)
+	}
+}
+
+type actorKey struct{}
+type initiatorKey struct{}
+type reasonKey struct{}
+
+// WithActor attributes mutations made with ctx to the reconciled object
+func WithActor(ctx context.Context, actor client.Object) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// WithInitiator attributes mutations made with ctx to a user or system outside
+// the reconcilers, such as an admin API client
+func WithInitiator(ctx context.Context, initiator string) context.Context {
+	return context.WithValue(ctx, initiatorKey{}, initiator)
+}
+
+// WithReason records why mutations made with ctx happen
+func WithReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// actorFrom returns the actor object and reason carried by ctx
+func actorFrom(ctx context.Context) (client.Object, string, string) {
+	actor, _ := ctx.Value(actorKey{}).(client.Object)
+	initiator, _ := ctx.Value(initiatorKey{}).(string)
+	reason, _ := ctx.Value(reasonKey{}).(string)
+	if reason == "" {
+		reason = DefaultReason
+	}
+	return actor, initiator, reason
+}
+
+// changedPaths returns the paths of the fields that differ between two
+// unstructured objects, descending into maps up to depth levels
+func changedPaths(prefix string, old, new map[string]interface{}, depth int) []string {
+	keys := map[string]bool{}
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+
+	var paths []string
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		o, n := old[k], new[k]
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		om, oIsMap := o.(map[string]interface{})
+		nm, nIsMap := n.(map[string]interface{})
+		// Descend into maps, including ones that were added or removed
+		if depth > 1 && (oIsMap || o == nil) && (nIsMap || n == nil) {
+			paths = append(paths, changedPaths(path, om, nm, depth-1)...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// truncate bounds the recorded paths
+func truncate(paths []string) []string {
+	if len(paths) <= maxChanges {
+		return paths
+	}
+	return append(paths[:maxChanges], fmt.Sprintf("... %d more", len(paths)-maxChanges))
+}
@@ -0,0 +1,166 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// diffDepth is how deep changed paths are reported, e.g. spec.template.spec.containers
+const diffDepth = 4
+
+// ignoredMetadata are metadata fields maintained by the API server
+var ignoredMetadata = []string{"resourceVersion", "managedFields", "generation", "uid", "creationTimestamp", "selfLink"}
+
+// auditedClient records the mutations made through the wrapped client. Status
+// writes are not audited; they only report what the operator observed.
+type auditedClient struct {
+	client.Client
+	auditor *Auditor
+	cluster string
+}
+
+// Client wraps c so its mutations are audited. cluster names the remote
+// cluster c talks to, or is empty for the local cluster.
+func (a *Auditor) Client(c client.Client, cluster string) client.Client {
+	return &auditedClient{Client: c, auditor: a, cluster: cluster}
+}
+
+// Create creates obj and records it
+func (c *auditedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	c.record(ctx, VerbCreate, obj, nil, err)
+	return err
+}
+
+// Update updates obj and records the fields it changed. The stored object is
+// compared with the one the API server returns, so defaulted fields do not
+// show up as changes and updates changing nothing are not recorded.
+func (c *auditedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	old, ok := obj.DeepCopyObject().(client.Object)
+	compared := ok && c.Client.Get(ctx, client.ObjectKeyFromObject(obj), old) == nil
+
+	err := c.Client.Update(ctx, obj, opts...)
+
+	var changes []string
+	if compared {
+		changes = diff(old, obj)
+		if err == nil && len(changes) == 0 {
+			return nil
+		}
+	}
+	c.record(ctx, VerbUpdate, obj, changes, err)
+	return err
+}
+
+// Patch patches obj and records the fields the patch sets
+func (c *auditedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	// The patch is computed from obj, which the response overwrites
+	changes := patchPaths(obj, patch)
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	c.record(ctx, VerbPatch, obj, changes, err)
+	return err
+}
+
+// Delete deletes obj and records it
+func (c *auditedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	c.record(ctx, VerbDelete, obj, nil, err)
+	return err
+}
+
+// DeleteAllOf deletes the objects of obj's kind and records it
+func (c *auditedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	c.record(ctx, VerbDeleteAllOf, obj, nil, err)
+	return err
+}
+
+// record sends an entry describing the mutation to the auditor
+func (c *auditedClient) record(ctx context.Context, verb string, obj client.Object, changes []string, err error) {
+	actor, initiator, reason := actorFrom(ctx)
+
+	entry := Entry{
+		Time:      time.Now().UTC(),
+		Verb:      verb,
+		Kind:      c.kind(obj),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Cluster:   c.cluster,
+		Reason:    reason,
+		Changes:   truncate(changes),
+	}
+	switch {
+	case initiator != "":
+		entry.Actor = initiator
+	case actor != nil:
+		entry.Actor = fmt.Sprintf("%s %s", c.kind(actor), types.NamespacedName{Namespace: actor.GetNamespace(), Name: actor.GetName()})
+	default:
+		entry.Actor = "operator"
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	c.auditor.record(entry, actor, obj)
+}
+
+// kind returns the kind of obj, which typed objects often leave unset
+func (c *auditedClient) kind(obj runtime.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return fmt.Sprintf("%T", obj)
+	}
+	return gvk.Kind
+}
+
+// diff returns the paths of the fields changed between old and new, ignoring
+// the status and server-maintained metadata
+func diff(old, new client.Object) []string {
+	o, err := runtime.DefaultUnstructuredConverter.ToUnstructured(old)
+	if err != nil {
+		return nil
+	}
+	n, err := runtime.DefaultUnstructuredConverter.ToUnstructured(new)
+	if err != nil {
+		return nil
+	}
+	for _, u := range []map[string]interface{}{o, n} {
+		delete(u, "status")
+		delete(u, "apiVersion")
+		delete(u, "kind")
+		if metadata, ok := u["metadata"].(map[string]interface{}); ok {
+			for _, field := range ignoredMetadata {
+				delete(metadata, field)
+			}
+		}
+	}
+	return changedPaths("", o, n, diffDepth)
+}
+
+// patchPaths returns the paths set by a merge patch
+func patchPaths(obj client.Object, patch client.Patch) []string {
+	switch patch.Type() {
+	case types.MergePatchType, types.StrategicMergePatchType:
+	default:
+		return nil
+	}
+	data, err := patch.Data(obj)
+	if err != nil {
+		return nil
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "resourceVersion")
+	}
+	return changedPaths("", nil, fields, diffDepth)
+}
@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+)
+
+// LogSink writes entries to the operator log
+type LogSink struct {
+	Log logr.Logger
+}
+
+// Record logs the entry
+func (s LogSink) Record(entry Entry, _, _ client.Object) {
+	s.Log.Info("Audit", "verb", entry.Verb, "kind", entry.Kind, "namespace", entry.Namespace, "name", entry.Name,
+		"cluster", entry.Cluster, "actor", entry.Actor, "reason", entry.Reason, "changes", entry.Changes, "error", entry.Error)
+}
+
+// EventSink records entries as Kubernetes events on the reconciled object
+// that caused them, so they show up when describing it
+type EventSink struct {
+	Recorder record.EventRecorder
+}
+
+// Record emits an event for the entry. Mutations without a reconciled object
+// are recorded on the mutated object, unless it is in a remote cluster.
+func (s EventSink) Record(entry Entry, actor, target client.Object) {
+	subject := actor
+	if subject == nil {
+		if entry.Cluster != "" {
+			return
+		}
+		subject = target
+	}
+
+	eventType := corev1.EventTypeNormal
+	message := fmt.Sprintf("%s %s %s", entry.Verb, entry.Kind, entry.Name)
+	if entry.Cluster != "" {
+		message += " in cluster " + entry.Cluster
+	}
+	if len(entry.Changes) > 0 {
+		message += fmt.Sprintf(" (%s)", strings.Join(entry.Changes, ", "))
+	}
+	message += ": " + entry.Reason
+	if entry.Error != "" {
+		eventType = corev1.EventTypeWarning
+		message += " failed: " + entry.Error
+	}
+	s.Recorder.Event(subject, eventType, "Audit", message)
+}
+
+// webhookBatchSize bounds the entries posted at once
+const webhookBatchSize = 100
+
+// WebhookSink posts entries as JSON arrays to an HTTP endpoint. Entries are
+// buffered and sent in the background; they are dropped, with a log line,
+// when the endpoint cannot keep up.
+type WebhookSink struct {
+	url       string
+	tokenFile string
+	client    *http.Client
+	entries   chan Entry
+	log       logr.Logger
+}
+
+// NewWebhookSink creates a sink posting to url. When tokenFile is set its
+// content is sent as a bearer token.
+func NewWebhookSink(url, tokenFile string, log logr.Logger) *WebhookSink {
+	return &WebhookSink{
+		url:       url,
+		tokenFile: tokenFile,
+		client:    outbound.Client(10 * time.Second),
+		entries:   make(chan Entry, 1000),
+		log:       log,
+	}
+}
+
+// Record queues the entry
+func (s *WebhookSink) Record(entry Entry, _, _ client.Object) {
+	select {
+	case s.entries <- entry:
+	default:
+		s.log.Info("Audit webhook queue is full, dropping entry", "verb", entry.Verb, "kind", entry.Kind, "name", entry.Name)
+	}
+}
+
+// NeedLeaderElection lets every operator replica deliver its own entries
+func (s *WebhookSink) NeedLeaderElection() bool {
+	return false
+}
+
+// Start delivers queued entries until ctx is cancelled
+func (s *WebhookSink) Start(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var batch []Entry
+	for {
+		select {
+		case <-ctx.Done():
+			if len(batch) > 0 {
+				s.deliver(context.Background(), batch)
+			}
+			return nil
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) < webhookBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		s.deliver(ctx, batch)
+		batch = nil
+	}
+}
+
+// deliver posts a batch, retrying with backoff before dropping it
+func (s *WebhookSink) deliver(ctx context.Context, batch []Entry) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		s.log.Error(err, "Unable to encode audit entries")
+		return
+	}
+
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err = s.post(ctx, body)
+		if err == nil {
+			return
+		}
+		if attempt == 3 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	s.log.Error(err, "Unable to deliver audit entries, dropping them", "entries", len(batch))
+}
+
+// post sends one request to the webhook
+func (s *WebhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.tokenFile != "" {
+		token, err := os.ReadFile(s.tokenFile)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
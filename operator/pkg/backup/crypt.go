@@ -0,0 +1,150 @@
+// Package backup encrypts node backup archives client-side, inside the Job
+// that creates them, so chain state and address books never reach the backup
+// storage in the clear.
+//
+// Archives are age files. They are encrypted either to an age recipient, or
+// with a file key wrapped by a cloud KMS key and stored in an age stanza of
+// its own. Key escrow archives encrypt the validator keys the same way before
+// they are uploaded to object storage.
+package backup
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"gocloud.dev/secrets"
+	// KMS providers supported by KMSKeyURL
+	_ "gocloud.dev/secrets/awskms"
+	_ "gocloud.dev/secrets/azurekeyvault"
+	_ "gocloud.dev/secrets/gcpkms"
+)
+
+// Extension is appended to the name of encrypted archives
+const Extension = ".age"
+
+// kmsStanzaType identifies the age stanza holding a file key wrapped by a KMS key
+const kmsStanzaType = "axelar-kms"
+
+// kmsTimeout bounds a single KMS call
+const kmsTimeout = 30 * time.Second
+
+// Options selects the keys archives are encrypted and decrypted with
+type Options struct {
+	// AgeRecipient is the age public key archives are encrypted to
+	AgeRecipient string
+	// AgeIdentityFile holds the age identities archives are decrypted with
+	AgeIdentityFile string
+	// KMSKeyURL identifies the KMS key wrapping the file keys
+	KMSKeyURL string
+}
+
+// BindFlags registers the options on fs
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.AgeRecipient, "age-recipient", "", "The age recipient archives are encrypted to.")
+	fs.StringVar(&o.AgeIdentityFile, "age-identity-file", "", "The file holding the age identities archives are decrypted with.")
+	fs.StringVar(&o.KMSKeyURL, "kms-key-url", "", "The URL of the KMS key wrapping archive keys.")
+}
+
+// ValidateRecipient checks an age recipient
+func ValidateRecipient(recipient string) error {
+	_, err := age.ParseX25519Recipient(recipient)
+	return err
+}
+
+// Encrypt returns a writer encrypting to w. The archive is only complete once
+// the writer is closed.
+func Encrypt(ctx context.Context, w io.Writer, opts Options) (io.WriteCloser, error) {
+	var recipients []age.Recipient
+	if opts.AgeRecipient != "" {
+		recipient, err := age.ParseX25519Recipient(opts.AgeRecipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if opts.KMSKeyURL != "" {
+		keeper, err := secrets.OpenKeeper(ctx, opts.KMSKeyURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open KMS key: %w", err)
+		}
+		recipients = append(recipients, &kmsKey{ctx: ctx, keeper: keeper})
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("no age recipient or KMS key configured")
+	}
+	return age.Encrypt(w, recipients...)
+}
+
+// Decrypt returns a reader decrypting r
+func Decrypt(ctx context.Context, r io.Reader, opts Options) (io.Reader, error) {
+	var identities []age.Identity
+	if opts.AgeIdentityFile != "" {
+		f, err := os.Open(opts.AgeIdentityFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		parsed, err := age.ParseIdentities(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age identity file: %w", err)
+		}
+		identities = append(identities, parsed...)
+	}
+	if opts.KMSKeyURL != "" {
+		keeper, err := secrets.OpenKeeper(ctx, opts.KMSKeyURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open KMS key: %w", err)
+		}
+		identities = append(identities, &kmsKey{ctx: ctx, keeper: keeper})
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("no age identity or KMS key configured")
+	}
+	return age.Decrypt(r, identities...)
+}
+
+// kmsKey wraps and unwraps age file keys with a KMS key
+type kmsKey struct {
+	ctx    context.Context
+	keeper *secrets.Keeper
+}
+
+// Wrap encrypts the file key with the KMS key
+func (k *kmsKey) Wrap(fileKey []byte) ([]*age.Stanza, error) {
+	ctx, cancel := context.WithTimeout(k.ctx, kmsTimeout)
+	defer cancel()
+	wrapped, err := k.keeper.Encrypt(ctx, fileKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wrap the archive key: %w", err)
+	}
+	return []*age.Stanza{{Type: kmsStanzaType, Body: wrapped}}, nil
+}
+
+// Unwrap decrypts the file key of an archive encrypted with a KMS key
+func (k *kmsKey) Unwrap(stanzas []*age.Stanza) ([]byte, error) {
+	for _, stanza := range stanzas {
+		if stanza.Type != kmsStanzaType {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(k.ctx, kmsTimeout)
+		defer cancel()
+		fileKey, err := k.keeper.Decrypt(ctx, stanza.Body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unwrap the archive key: %w", err)
+		}
+		return fileKey, nil
+	}
+	return nil, age.ErrIncorrectIdentity
+}
+
+// Encrypted reports whether an archive name is that of an encrypted archive
+func Encrypted(archive string) bool {
+	return strings.HasSuffix(archive, Extension)
+}
@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gocloud.dev/blob"
+	// Object stores supported by BucketURL
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+// EscrowOptions selects the key files escrowed and where they are uploaded
+type EscrowOptions struct {
+	Options
+
+	// BucketURL is the gocloud URL of the bucket, such as s3://bucket?region=eu-west-1
+	BucketURL string
+	// Object is the key the archive is uploaded to
+	Object string
+	// Root the archived paths are relative to
+	Root string
+}
+
+// BindFlags registers the options on fs
+func (o *EscrowOptions) BindFlags(fs *flag.FlagSet) {
+	o.Options.BindFlags(fs)
+	fs.StringVar(&o.BucketURL, "bucket-url", "", "The URL of the bucket the archive is uploaded to.")
+	fs.StringVar(&o.Object, "object", "", "The key the archive is uploaded to.")
+	fs.StringVar(&o.Root, "root", "/home/axelard", "The directory the archived paths are relative to.")
+}
+
+// EscrowResult describes an uploaded key archive
+type EscrowResult struct {
+	// Object is the URL of the uploaded archive
+	Object string `json:"object"`
+	// Fingerprint is the SHA-256 of the encrypted archive, as sha256:<hex>
+	Fingerprint string `json:"fingerprint"`
+	// Files archived, relative to the root
+	Files []string `json:"files"`
+}
+
+// Escrow archives the key files at paths, encrypts the archive and uploads it
+// to the bucket. Paths are files or directories below the root; a missing
+// path fails the escrow, so an archive never silently lacks a key.
+func Escrow(ctx context.Context, paths []string, opts EscrowOptions) (*EscrowResult, error) {
+	if opts.BucketURL == "" || opts.Object == "" {
+		return nil, errors.New("a bucket URL and an object are required")
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no key files to escrow")
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(opts.Root, path)); err != nil {
+			return nil, fmt.Errorf("key file %s: %w", path, err)
+		}
+	}
+
+	bucket, err := blob.OpenBucket(ctx, opts.BucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bucket: %w", err)
+	}
+	defer bucket.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	upload, err := bucket.NewWriter(ctx, opts.Object, &blob.WriterOptions{ContentType: "application/octet-stream"})
+	if err != nil {
+		return nil, fmt.Errorf("unable to upload %s: %w", opts.Object, err)
+	}
+	hash := sha256.New()
+	encrypted, err := Encrypt(ctx, io.MultiWriter(upload, hash), opts.Options)
+	if err != nil {
+		cancel()
+		upload.Close()
+		return nil, err
+	}
+
+	files, err := writeKeyArchive(encrypted, opts.Root, paths)
+	if err == nil {
+		err = encrypted.Close()
+	}
+	if err != nil {
+		// Cancelling before Close aborts the upload
+		cancel()
+		upload.Close()
+		return nil, err
+	}
+	if err := upload.Close(); err != nil {
+		return nil, fmt.Errorf("unable to upload %s: %w", opts.Object, err)
+	}
+	return &EscrowResult{
+		Object:      strings.TrimSuffix(strings.SplitN(opts.BucketURL, "?", 2)[0], "/") + "/" + opts.Object,
+		Fingerprint: "sha256:" + hex.EncodeToString(hash.Sum(nil)),
+		Files:       files,
+	}, nil
+}
+
+// writeKeyArchive writes a gzipped tarball of the paths to w and returns the
+// files it holds
+func writeKeyArchive(w io.Writer, root string, paths []string) ([]string, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(filepath.Join(root, path), func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(root, name)
+			if err != nil {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = rel
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err := io.Copy(tw, f); err != nil {
+				return err
+			}
+			files = append(files, rel)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to archive %s: %w", path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return files, gz.Close()
+}
@@ -0,0 +1,77 @@
+package caching
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// adoptPageSize is the number of objects listed per request while adopting
+const adoptPageSize = 500
+
+// Adopt labels the ConfigMaps and Secrets written by earlier operator
+// versions, which the cache would otherwise not see. These are the objects
+// controlled by a resource of the operator API group, and the objects named
+// in extra, such as the peer list ConfigMap. It reads through reader, the API
+// server, and runs before the manager starts.
+func Adopt(ctx context.Context, reader client.Reader, writer client.Client, extra ...types.NamespacedName) error {
+	named := map[types.NamespacedName]bool{}
+	for _, name := range extra {
+		named[name] = true
+	}
+	for _, list := range []client.ObjectList{&corev1.ConfigMapList{}, &corev1.SecretList{}} {
+		if err := adoptList(ctx, reader, writer, list, named); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adoptList labels the objects of one kind, a page at a time
+func adoptList(ctx context.Context, reader client.Reader, writer client.Client, list client.ObjectList, named map[types.NamespacedName]bool) error {
+	for {
+		if err := reader.List(ctx, list, client.Limit(adoptPageSize), client.Continue(list.GetContinue())); err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || obj.GetLabels()[blockchainv1alpha1.ManagedByLabel] == blockchainv1alpha1.ManagedBy {
+				continue
+			}
+			if !controlledByOperator(obj) && !named[client.ObjectKeyFromObject(obj)] {
+				continue
+			}
+			patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+			label(obj)
+			if err := writer.Patch(ctx, obj, patch); err != nil {
+				return fmt.Errorf("failed to label %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+			}
+		}
+		if list.GetContinue() == "" {
+			return nil
+		}
+	}
+}
+
+// controlledByOperator reports whether the controller of obj is a resource
+// of the operator API group
+func controlledByOperator(obj client.Object) bool {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return false
+	}
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	return err == nil && gv.Group == blockchainv1alpha1.SchemeGroupVersion.Group
+}
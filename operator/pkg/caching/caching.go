@@ -0,0 +1,78 @@
+// Package caching scopes the caches of the operator manager. The ConfigMaps
+// and Secrets the operator writes are labeled, and only those are cached, so
+// clusters with thousands of unrelated ConfigMaps and Secrets do not fill the
+// operator memory. The ConfigMaps and Secrets users reference, such as
+// kubeconfigs and status source credentials, are read from the API server.
+// AxelarNodes are indexed by the fields they are listed by.
+package caching
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// managedSelector selects the objects labeled as written by the operator
+var managedSelector = labels.SelectorFromSet(labels.Set{blockchainv1alpha1.ManagedByLabel: blockchainv1alpha1.ManagedBy})
+
+// ByObject restricts the informers of ConfigMaps and Secrets to the objects
+// written by the operator
+func ByObject() map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.ConfigMap{}: {Label: managedSelector},
+		&corev1.Secret{}:    {Label: managedSelector},
+	}
+}
+
+// label marks the ConfigMaps and Secrets written by the operator
+func label(obj client.Object) {
+	switch obj.(type) {
+	case *corev1.ConfigMap, *corev1.Secret:
+	default:
+		return
+	}
+	labels := obj.GetLabels()
+	if labels[blockchainv1alpha1.ManagedByLabel] == blockchainv1alpha1.ManagedBy {
+		return
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[blockchainv1alpha1.ManagedByLabel] = blockchainv1alpha1.ManagedBy
+	obj.SetLabels(labels)
+}
+
+// managedClient labels the ConfigMaps and Secrets written through the wrapped client
+type managedClient struct {
+	client.Client
+}
+
+// Client wraps c so the ConfigMaps and Secrets it creates, updates and
+// patches are cached by the manager
+func Client(c client.Client) client.Client {
+	return &managedClient{Client: c}
+}
+
+// Create labels obj and creates it
+func (c *managedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	label(obj)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// Update labels obj and updates it
+func (c *managedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	label(obj)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// Patch labels obj and patches it. Merge patches computed from obj include
+// the label.
+func (c *managedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	label(obj)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
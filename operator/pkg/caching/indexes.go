@@ -0,0 +1,67 @@
+package caching
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+)
+
+// Field indexes of AxelarNodes, usable with client.MatchingFields on the
+// manager client
+const (
+	// NetworkIndex indexes AxelarNodes by spec.network
+	NetworkIndex = "spec.network"
+
+	// NodeTypeIndex indexes AxelarNodes by spec.nodeType
+	NodeTypeIndex = "spec.nodeType"
+
+	// MemberIndex indexes AxelarNodes by the AxelarNetwork they join, as
+	// returned by MemberKey
+	MemberIndex = "axelarNetwork"
+)
+
+// MemberKey is the MemberIndex key of the members of an AxelarNetwork. Nodes
+// name the network in a label, so long names are matched as label values.
+func MemberKey(namespace, name string) string {
+	return namespace + "/" + naming.LabelValue(name)
+}
+
+// memberOf returns the MemberIndex key of the AxelarNetwork joined by node.
+// Nodes join a network of their own namespace unless they name another one.
+func memberOf(node *blockchainv1alpha1.AxelarNode) []string {
+	network, ok := node.Labels[blockchainv1alpha1.NetworkLabel]
+	if !ok {
+		return nil
+	}
+	namespace := node.Namespace
+	if joins, ok := node.Labels[blockchainv1alpha1.NetworkNamespaceLabel]; ok {
+		namespace = joins
+	}
+	return []string{MemberKey(namespace, network)}
+}
+
+// SetupIndexes registers the field indexes of AxelarNodes with indexer
+func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	indexes := map[string]func(*blockchainv1alpha1.AxelarNode) []string{
+		NetworkIndex:  func(node *blockchainv1alpha1.AxelarNode) []string { return []string{node.Spec.Network} },
+		NodeTypeIndex: func(node *blockchainv1alpha1.AxelarNode) []string { return []string{node.Spec.NodeType} },
+		MemberIndex:   memberOf,
+	}
+	for field, extract := range indexes {
+		extract := extract
+		err := indexer.IndexField(ctx, &blockchainv1alpha1.AxelarNode{}, field, func(obj client.Object) []string {
+			node, ok := obj.(*blockchainv1alpha1.AxelarNode)
+			if !ok {
+				return nil
+			}
+			return extract(node)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
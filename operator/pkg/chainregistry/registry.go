@@ -0,0 +1,101 @@
+// Package chainregistry reads the peers of a chain from its chain.json in the
+// Cosmos chain registry.
+package chainregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+)
+
+// Registry URLs of the chain.json of the Axelar networks
+const (
+	MainnetURL = "https://raw.githubusercontent.com/cosmos/chain-registry/master/axelar/chain.json"
+	TestnetURL = "https://raw.githubusercontent.com/cosmos/chain-registry/master/testnets/axelartestnet/chain.json"
+)
+
+// maxFetchBytes bounds the size of a downloaded chain.json
+const maxFetchBytes = 4 << 20
+
+// Peers are the peers of a chain, as id@host:port addresses
+type Peers struct {
+	Seeds           []string
+	PersistentPeers []string
+}
+
+// peer is a peer entry of chain.json
+type peer struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	Provider string `json:"provider"`
+}
+
+// chain is the part of chain.json holding the peers
+type chain struct {
+	Peers struct {
+		Seeds           []peer `json:"seeds"`
+		PersistentPeers []peer `json:"persistent_peers"`
+	} `json:"peers"`
+}
+
+// FetchPeers downloads chain.json from url and returns its peers
+func FetchPeers(ctx context.Context, url string) (*Peers, error) {
+	if airgap.Offline() {
+		return nil, fmt.Errorf("fetching %s: %w", url, airgap.ErrOffline)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := (&http.Client{Transport: outbound.Transport()}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchBytes))
+	if err != nil {
+		return nil, err
+	}
+	peers, err := ParsePeers(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", url, err)
+	}
+	return peers, nil
+}
+
+// ParsePeers returns the peers of a chain.json document
+func ParsePeers(document []byte) (*Peers, error) {
+	data := &chain{}
+	if err := json.Unmarshal(document, data); err != nil {
+		return nil, err
+	}
+	return &Peers{
+		Seeds:           addresses(data.Peers.Seeds),
+		PersistentPeers: addresses(data.Peers.PersistentPeers),
+	}, nil
+}
+
+// addresses formats the peers as id@host:port, skipping incomplete entries
+func addresses(peers []peer) []string {
+	result := []string{}
+	for _, p := range peers {
+		if p.ID == "" || p.Address == "" {
+			continue
+		}
+		result = append(result, p.ID+"@"+p.Address)
+	}
+	return result
+}
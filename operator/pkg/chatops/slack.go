@@ -0,0 +1,277 @@
+// Package chatops lets on-call engineers operate nodes from Slack. Slash
+// commands are translated into the same request annotations as the admin API,
+// and every accepted command is recorded as an event on the node.
+package chatops
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/admin"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+)
+
+// maxRequestAge bounds the Slack request timestamp, rejecting replays
+const maxRequestAge = 5 * time.Minute
+
+// maxRequestSize bounds the slash command payload
+const maxRequestSize = 64 << 10
+
+// usage lists the supported commands
+const usage = "Usage: `/axelar status|backup|restore|resync|pause|resume <node> [archive]`. " +
+	"Nodes are given as `name` or `namespace/name`."
+
+// SlackOptions configures the Slack command handler
+type SlackOptions struct {
+	// BindAddress is the address slash commands are received on; empty disables them
+	BindAddress string
+	// SigningSecretFile holds the Slack app signing secret. It is read on
+	// every request, so a mounted Secret can be rotated in place.
+	SigningSecretFile string
+	// AllowedUsers are the Slack user IDs allowed to run commands other than
+	// status; empty allows every member of the workspace
+	AllowedUsers []string
+}
+
+// Slack serves Slack slash commands
+type Slack struct {
+	opts     SlackOptions
+	client   client.Client
+	recorder record.EventRecorder
+	log      logr.Logger
+}
+
+// slackResponse is the message returned to Slack
+type slackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// NewSlack creates the Slack command handler
+func NewSlack(opts SlackOptions, c client.Client, recorder record.EventRecorder, log logr.Logger) *Slack {
+	return &Slack{opts: opts, client: c, recorder: recorder, log: log}
+}
+
+// NeedLeaderElection lets every operator replica serve commands, since
+// operations are only recorded as annotations
+func (s *Slack) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves slash commands until ctx is cancelled
+func (s *Slack) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/commands", s.serveCommand)
+
+	server := &http.Server{Addr: s.opts.BindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+	s.log.Info("Serving Slack commands", "address", s.opts.BindAddress)
+
+	select {
+	case <-ctx.Done():
+	case err := <-errs:
+		return err
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdown)
+}
+
+// serveCommand verifies and runs a slash command
+func (s *Slack) serveCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.verify(r.Header, body, time.Now()); err != nil {
+		s.log.Info("Rejected Slack request", "error", err.Error(), "remote", r.RemoteAddr)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := s.run(r.Context(), form.Get("user_id"), form.Get("user_name"), strings.Fields(form.Get("text")))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// verify checks the Slack request signature, see
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func (s *Slack) verify(header http.Header, body []byte, now time.Time) error {
+	data, err := os.ReadFile(s.opts.SigningSecretFile)
+	if err != nil {
+		return fmt.Errorf("signing secret unavailable: %w", err)
+	}
+	// Anyone can sign with an empty secret
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return fmt.Errorf("signing secret is empty")
+	}
+
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("request timestamp is %s old", age)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// run executes a command on behalf of a Slack user
+func (s *Slack) run(ctx context.Context, userID, userName string, args []string) slackResponse {
+	if len(args) < 2 {
+		return ephemeral(usage)
+	}
+	command, target := args[0], args[1]
+	arg := ""
+	if len(args) > 2 {
+		arg = args[2]
+	}
+
+	node, err := s.resolve(ctx, target)
+	if err != nil {
+		return ephemeral(err.Error())
+	}
+	name := types.NamespacedName{Namespace: node.Namespace, Name: node.Name}
+
+	if command == "status" {
+		return ephemeral(statusText(admin.Summarize(node)))
+	}
+
+	if !s.allowed(userID) {
+		s.log.Info("Refused Slack command", "command", command, "node", name.String(), "user", userName, "userID", userID)
+		return ephemeral(fmt.Sprintf("You are not allowed to run `%s`", command))
+	}
+
+	ctx = audit.WithInitiator(ctx, fmt.Sprintf("slack %s (%s)", userName, userID))
+	ctx = audit.WithReason(ctx, command)
+	if err := admin.Request(ctx, s.client, name, command, arg); err != nil {
+		if errors.Is(err, admin.ErrUnknownOperation) {
+			return ephemeral(usage)
+		}
+		return ephemeral(fmt.Sprintf("Unable to request %s on %s: %s", command, name, err))
+	}
+
+	s.log.Info("Accepted Slack command", "command", command, "node", name.String(), "user", userName, "userID", userID)
+	s.recorder.Eventf(node, corev1.EventTypeNormal, "ChatOpsCommand",
+		"%s requested from Slack by %s (%s)", command, userName, userID)
+	return slackResponse{
+		ResponseType: "in_channel",
+		Text:         fmt.Sprintf("<@%s> requested `%s` on `%s`", userID, command, name),
+	}
+}
+
+// resolve finds the node named by a command. A bare name must be unique
+// across namespaces.
+func (s *Slack) resolve(ctx context.Context, target string) (*blockchainv1alpha1.AxelarNode, error) {
+	if namespace, name, ok := strings.Cut(target, "/"); ok {
+		node := &blockchainv1alpha1.AxelarNode{}
+		err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, node)
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("node `%s` not found", target)
+		}
+		return node, err
+	}
+
+	nodes := &blockchainv1alpha1.AxelarNodeList{}
+	if err := s.client.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	var found []*blockchainv1alpha1.AxelarNode
+	for i := range nodes.Items {
+		if nodes.Items[i].Name == target {
+			found = append(found, &nodes.Items[i])
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("node `%s` not found", target)
+	case 1:
+		return found[0], nil
+	}
+	return nil, fmt.Errorf("node `%s` exists in several namespaces, use `namespace/%s`", target, target)
+}
+
+// allowed reports whether the user may run mutating commands
+func (s *Slack) allowed(userID string) bool {
+	if len(s.opts.AllowedUsers) == 0 {
+		return true
+	}
+	for _, allowed := range s.opts.AllowedUsers {
+		if allowed == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// statusText renders a node summary for Slack
+func statusText(summary admin.NodeSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s/%s* (%s, %s)\n", summary.Namespace, summary.Name, summary.NodeType, summary.Network)
+	fmt.Fprintf(&b, "Phase: %s, height %d, %d peers", summary.Phase, summary.Height, summary.Peers)
+	if summary.CatchingUp {
+		b.WriteString(", catching up")
+	}
+	if summary.Paused {
+		b.WriteString(", reconciliation paused")
+	}
+	if summary.LastBackup != nil {
+		fmt.Fprintf(&b, "\nLast backup: %s", summary.LastBackup.UTC().Format(time.RFC3339))
+	}
+	if op := summary.Operation; op != nil && op.Type != "" {
+		fmt.Fprintf(&b, "\n%s %s: %s", op.Type, strings.ToLower(op.Phase), op.Message)
+	}
+	return b.String()
+}
+
+// ephemeral returns a response only shown to the user who ran the command
+func ephemeral(text string) slackResponse {
+	return slackResponse{ResponseType: "ephemeral", Text: text}
+}
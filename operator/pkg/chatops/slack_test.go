@@ -0,0 +1,55 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// signedHeader signs body with secret like Slack does
+func signedHeader(secret string, body []byte, now time.Time) http.Header {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte("command=/axelar&text=status+validator")
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  http.Header
+		wantErr bool
+	}{
+		{"valid signature", "s3cret\n", signedHeader("s3cret", body, now), false},
+		{"wrong secret", "s3cret", signedHeader("other", body, now), true},
+		{"stale request", "s3cret", signedHeader("s3cret", body, now.Add(-time.Hour)), true},
+		{"empty secret", "", signedHeader("", body, now), true},
+		{"whitespace secret", " \n", signedHeader("", body, now), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "signing-secret")
+			if err := os.WriteFile(file, []byte(tt.secret), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			s := &Slack{opts: SlackOptions{SigningSecretFile: file}}
+			if err := s.verify(tt.header, body, now); (err != nil) != tt.wantErr {
+				t.Fatalf("verify() error = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
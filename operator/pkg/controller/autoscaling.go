@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Ports of the RPC proxy sidecar
+const (
+	rpcProxyPort        = 26667
+	rpcProxyMetricsPort = 26668
+)
+
+// DefaultProxyImage is the image providing the RPC proxy sidecar
+const DefaultProxyImage = "axelarnet/axelar-k8s-operator:latest"
+
+// DefaultToolsImage is the image providing the snapshot downloader and backup encryption
+const DefaultToolsImage = DefaultProxyImage
+
+// Custom metrics autoscalers scale on, published by the RPC proxy sidecar
+// and served through a custom metrics adapter
+const (
+	requestRateMetric = "axelar_rpc_requests_per_second"
+	syncLagMetric     = "axelar_sync_lag_blocks"
+)
+
+// addRPCProxy fronts the node RPC with the proxy sidecar publishing the
+// autoscaling metrics. The pod Prometheus port moves to the proxy, which
+// includes the node metrics in its own while monitoring is enabled.
+func addRPCProxy(podSpec *corev1.PodSpec, image string, rpcPort int32, monitoring blockchainv1alpha1.MonitoringSpec) {
+	if image == "" {
+		image = airgap.Image(DefaultProxyImage)
+	}
+	args := []string{
+		"rpc-proxy",
+		fmt.Sprintf("--listen=:%d", rpcProxyPort),
+		fmt.Sprintf("--metrics-listen=:%d", rpcProxyMetricsPort),
+		fmt.Sprintf("--upstream=http://127.0.0.1:%d", rpcPort),
+	}
+	if monitoring.Enabled {
+		args = append(args, fmt.Sprintf("--upstream-metrics=http://127.0.0.1:%d%s", monitoring.Prometheus.Port, monitoring.Prometheus.Path))
+	}
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:  "rpc-proxy",
+		Image: image,
+		Args:  args,
+		Ports: []corev1.ContainerPort{
+			{Name: "rpc-proxy", ContainerPort: rpcProxyPort},
+			{Name: "proxy-metrics", ContainerPort: rpcProxyMetricsPort},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+	})
+}
+
+// reconcileAutoscaler creates, updates or removes the HPA named after owner
+// that scales target according to spec
+func reconcileAutoscaler(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object,
+	target autoscalingv2.CrossVersionObjectReference, spec *blockchainv1alpha1.AutoscalingSpec) error {
+	found := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := c.Get(ctx, types.NamespacedName{Name: owner.GetName(), Namespace: owner.GetNamespace()}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if spec == nil || !spec.Enabled {
+		if exists && metav1.IsControlledBy(found, owner) {
+			return c.Delete(ctx, found)
+		}
+		return nil
+	}
+
+	targets := []struct {
+		metric string
+		target *resource.Quantity
+	}{
+		{requestRateMetric, spec.TargetRequestRate},
+		{syncLagMetric, spec.TargetSyncLag},
+	}
+
+	metrics := []autoscalingv2.MetricSpec{}
+	for _, t := range targets {
+		if t.target == nil {
+			continue
+		}
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: t.metric},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: t.target,
+				},
+			},
+		})
+	}
+	if len(metrics) == 0 {
+		return fmt.Errorf("autoscaling requires targetRequestRate or targetSyncLag")
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      owner.GetName(),
+			Namespace: owner.GetNamespace(),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: target,
+			MinReplicas:    spec.MinReplicas,
+			MaxReplicas:    spec.MaxReplicas,
+			Metrics:        metrics,
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(owner, hpa, scheme); err != nil {
+		return err
+	}
+
+	if !exists {
+		return c.Create(ctx, hpa)
+	}
+	found.Spec = hpa.Spec
+	return c.Update(ctx, found)
+}
+
+// nodeAutoscaled reports whether the node is scaled out by an HPA. Validators
+// are never scaled, since replicas would double-sign.
+func nodeAutoscaled(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	autoscaling := axelarNode.Spec.Autoscaling
+	if autoscaling == nil || !autoscaling.Enabled {
+		return false
+	}
+	return !isValidatorNode(axelarNode)
+}
+
+// scaleOutPodSpec prepares the pod of a scaled out node to run as one of
+// many replicas. Each replica syncs into its own ephemeral data volume and
+// only receives traffic once synced. On autoscaled nodes the RPC proxy
+// publishes the metrics the HPA scales on.
+func (r *AxelarNodeReconciler) scaleOutPodSpec(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	claim := r.createPVC(axelarNode, "data", axelarNode.Spec.Storage.Size)
+	for i := range podSpec.Volumes {
+		switch podSpec.Volumes[i].Name {
+		case "data":
+			podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{
+				Ephemeral: &corev1.EphemeralVolumeSource{
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{Spec: claim.Spec},
+				},
+			}
+		case "shared":
+			podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		}
+	}
+	r.ephemeralVolumes(axelarNode, podSpec)
+
+	podSpec.Containers[0].ReadinessProbe = syncedReadinessProbe(axelarNode.Spec.Networking.RPC.Port)
+	if nodeAutoscaled(axelarNode) {
+		addRPCProxy(podSpec, r.ProxyImage, axelarNode.Spec.Networking.RPC.Port, axelarNode.Spec.Monitoring)
+	}
+}
+
+// reconcileNodeAutoscaler manages the HPA scaling the node Deployment
+func (r *AxelarNodeReconciler) reconcileNodeAutoscaler(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	spec := axelarNode.Spec.Autoscaling
+	requested := spec != nil && spec.Enabled
+
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionAutoscaling,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Disabled",
+		Message:            "Autoscaling is disabled",
+		ObservedGeneration: axelarNode.Generation,
+	}
+	switch {
+	case requested && !nodeAutoscaled(axelarNode):
+		condition.Reason = "ValidatorNotScalable"
+		condition.Message = "Validators cannot be scaled out without double-signing"
+		spec = nil
+	case requested && spec.TargetRequestRate == nil && spec.TargetSyncLag == nil:
+		condition.Reason = "InvalidConfiguration"
+		condition.Message = "Autoscaling requires targetRequestRate or targetSyncLag"
+		spec = nil
+	case requested:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Enabled"
+		condition.Message = fmt.Sprintf("Scaling between %d and %d replicas", ptrInt32(spec.MinReplicas, 1), spec.MaxReplicas)
+	}
+	if requested || meta.FindStatusCondition(axelarNode.Status.Conditions, condition.Type) != nil {
+		meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	}
+
+	return reconcileAutoscaler(ctx, r.Client, r.Scheme, axelarNode, autoscalingv2.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       deploymentName(axelarNode),
+	}, spec)
+}
+
+// ptrInt32 dereferences p, or returns def when p is nil
+func ptrInt32(p *int32, def int32) int32 {
+	if p == nil {
+		return def
+	}
+	return *p
+}
@@ -2,11 +2,25 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/remote"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tenancy"
 )
 
 // AxelarNetworkReconciler reconciles an AxelarNetwork object
@@ -14,25 +28,377 @@ type AxelarNetworkReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+
+	// Clusters is set in hub mode to reach members placed in remote clusters
+	Clusters *remote.Clusters
 }
 
 // +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarnetworks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarnetworks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarnetworks/finalizers,verbs=update
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarnodes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=selfsubjectaccessreviews,verbs=create
 
 // Reconcile handles AxelarNetwork reconciliation
 func (r *AxelarNetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("axelarnetwork", req.NamespacedName)
 
-	// TODO: Implement AxelarNetwork reconciliation logic
-	log.Info("AxelarNetwork reconciliation not yet implemented")
+	// Fetch the AxelarNetwork instance
+	network := &blockchainv1alpha1.AxelarNetwork{}
+	err := r.Get(ctx, req.NamespacedName, network)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("AxelarNetwork resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get AxelarNetwork")
+		return ctrl.Result{}, err
+	}
+	ctx = audit.WithActor(ctx, network)
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(network))
+	ctx = naming.WithInstance(ctx, naming.Instance{Owner: network.UID, Name: "axelar-network", Instance: network.Name, Component: "network"})
+
+	members, err := r.listMembers(ctx, network)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if network.Status.Phase == "" {
+		network.Status.Phase = "Initializing"
+	}
+	aggregateMembers(network, members)
+
+	scaling, err := r.reconcileScale(ctx, network, members)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	requeue, err := r.reconcileRollout(ctx, network, rolloutMembers(network, members))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	requeue = requeue || scaling
+
+	if err := r.reconcileHA(ctx, network, members); err != nil {
+		return ctrl.Result{}, err
+	}
+	network.Status.Governance = latestGovernance(members)
+
+	if err := r.Status().Update(ctx, network); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if requeue || (network.Spec.HA != nil && network.Spec.HA.Enabled) {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+}
+
+// listMembers returns the AxelarNodes labeled as members of the network in
+// the namespaces they may join it from, sorted by name. Members of another
+// tenant are left out.
+func (r *AxelarNetworkReconciler) listMembers(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork) ([]blockchainv1alpha1.AxelarNode, error) {
+	namespaces, err := r.memberNamespaces(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []blockchainv1alpha1.AxelarNode
+	for _, namespace := range namespaces {
+		nodes, err := r.listNamespaceMembers(ctx, network, namespace)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, nodes...)
+	}
+	return r.isolateMembers(ctx, network, members)
+}
+
+// reconcileRollout drives the canary rollout of spec.image to the members.
+// It returns true while a rollout is in progress.
+func (r *AxelarNetworkReconciler) reconcileRollout(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode) (bool, error) {
+	ctx = audit.WithReason(ctx, "canary-rollout")
+	if network.Spec.Image == nil || network.Spec.Image.Tag == "" {
+		if network.Status.Phase != "Degraded" {
+			network.Status.Phase = "Active"
+		}
+		return false, nil
+	}
+
+	target := network.Spec.Image.Tag
+	rollout := network.Status.Rollout
 
-	return ctrl.Result{}, nil
+	// Start a new rollout whenever the target tag changes
+	if rollout == nil || rollout.TargetTag != target {
+		pending := membersNotAt(members, target)
+		if len(pending) == 0 {
+			network.Status.Phase = "Active"
+			return false, nil
+		}
+
+		rollout = &blockchainv1alpha1.RolloutStatus{
+			TargetTag:   target,
+			PreviousTag: pending[0].Spec.Image.Tag,
+			Stage:       blockchainv1alpha1.RolloutStageSentries,
+			StartedAt:   &metav1.Time{Time: time.Now()},
+		}
+		if network.Spec.Rollout.Canary.Enabled {
+			if canary := selectCanary(pending); canary != nil {
+				rollout.Stage = blockchainv1alpha1.RolloutStageCanary
+				rollout.CanaryNode = canary.Name
+				rollout.PreviousTag = canary.Spec.Image.Tag
+			}
+		}
+		network.Status.Rollout = rollout
+		r.setRolloutCondition(network, metav1.ConditionTrue, "RolloutStarted",
+			fmt.Sprintf("Rolling out %s", target))
+	}
+
+	switch rollout.Stage {
+	case blockchainv1alpha1.RolloutStageCompleted:
+		network.Status.Phase = "Active"
+		return false, nil
+
+	case blockchainv1alpha1.RolloutStageAborted:
+		network.Status.Phase = "Degraded"
+		return false, nil
+
+	case blockchainv1alpha1.RolloutStageCanary:
+		network.Status.Phase = "Upgrading"
+		return true, r.progressCanary(ctx, network, members)
+
+	case blockchainv1alpha1.RolloutStageSentries:
+		network.Status.Phase = "Upgrading"
+		done, err := r.upgradeMembers(ctx, network, members, false)
+		if err != nil || !done {
+			return true, err
+		}
+		rollout.Stage = blockchainv1alpha1.RolloutStageValidators
+		rollout.Message = "Upgrading validators"
+		return true, nil
+
+	case blockchainv1alpha1.RolloutStageValidators:
+		network.Status.Phase = "Upgrading"
+		done, err := r.upgradeMembers(ctx, network, members, true)
+		if err != nil || !done {
+			return true, err
+		}
+		rollout.Stage = blockchainv1alpha1.RolloutStageCompleted
+		rollout.Message = fmt.Sprintf("All members running %s", target)
+		network.Status.Phase = "Active"
+		network.Status.LastUpgrade = &blockchainv1alpha1.NetworkUpgradeStatus{
+			Name:      target,
+			Height:    maxHeight(members),
+			Timestamp: &metav1.Time{Time: time.Now()},
+			Success:   true,
+		}
+		r.setRolloutCondition(network, metav1.ConditionFalse, "RolloutCompleted", rollout.Message)
+		return false, nil
+	}
+
+	return false, nil
+}
+
+// progressCanary upgrades the canary node and waits for it to soak
+func (r *AxelarNetworkReconciler) progressCanary(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode) error {
+	rollout := network.Status.Rollout
+	canarySpec := network.Spec.Rollout.Canary
+
+	canary := findMember(members, rollout.CanaryNode)
+	if canary == nil {
+		r.abortRollout(network, fmt.Sprintf("Canary node %s no longer exists", rollout.CanaryNode))
+		return nil
+	}
+
+	if canary.Spec.Image.Tag != rollout.TargetTag {
+		rollout.Message = fmt.Sprintf("Upgrading canary %s", canary.Name)
+		return r.setNodeImage(ctx, canary, network.Spec.Image, "AxelarNetwork/"+network.Name+" canary")
+	}
+
+	// The canary timeout only starts once its maintenance window allows the upgrade
+	if meta.IsStatusConditionTrue(canary.Status.Conditions, blockchainv1alpha1.ConditionRolloutDeferred) {
+		rollout.StartedAt = &metav1.Time{Time: time.Now()}
+		rollout.Message = fmt.Sprintf("Canary %s waiting for its maintenance window", canary.Name)
+		return nil
+	}
+
+	if canary.Status.Phase == "Failed" {
+		return r.abortCanary(ctx, network, canary, fmt.Sprintf("Canary %s failed", canary.Name))
+	}
+
+	if !nodeSynced(canary) {
+		rollout.CanarySyncedAt = nil
+		if time.Since(rollout.StartedAt.Time) > canarySpec.Timeout.Duration {
+			return r.abortCanary(ctx, network, canary,
+				fmt.Sprintf("Canary %s did not sync within %s", canary.Name, canarySpec.Timeout.Duration))
+		}
+		rollout.Message = fmt.Sprintf("Waiting for canary %s to sync", canary.Name)
+		return nil
+	}
+
+	if rollout.CanarySyncedAt == nil {
+		rollout.CanarySyncedAt = &metav1.Time{Time: time.Now()}
+	}
+	soaked := time.Since(rollout.CanarySyncedAt.Time)
+	if soaked < canarySpec.SoakTime.Duration {
+		rollout.Message = fmt.Sprintf("Canary %s soaking (%s of %s)",
+			canary.Name, soaked.Round(time.Second), canarySpec.SoakTime.Duration)
+		return nil
+	}
+
+	rollout.Stage = blockchainv1alpha1.RolloutStageSentries
+	rollout.Message = "Canary healthy, upgrading remaining non-validator nodes"
+	return nil
+}
+
+// upgradeMembers moves the validator or non-validator members to the target image
+// and reports whether all of them are synced on it
+func (r *AxelarNetworkReconciler) upgradeMembers(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode, validators bool) (bool, error) {
+	done := true
+	for i := range members {
+		node := &members[i]
+		if isValidator(node) != validators {
+			continue
+		}
+		if node.Spec.Image.Tag != network.Spec.Image.Tag {
+			if err := r.setNodeImage(ctx, node, network.Spec.Image, "AxelarNetwork/"+network.Name); err != nil {
+				return false, err
+			}
+			done = false
+			continue
+		}
+		if !nodeSynced(node) {
+			done = false
+		}
+	}
+	return done, nil
+}
+
+// abortCanary reverts the canary to its previous image and aborts the rollout
+func (r *AxelarNetworkReconciler) abortCanary(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, canary *blockchainv1alpha1.AxelarNode, reason string) error {
+	r.abortRollout(network, reason)
+
+	previous := *network.Spec.Image
+	previous.Tag = network.Status.Rollout.PreviousTag
+	return r.setNodeImage(ctx, canary, &previous, "AxelarNetwork/"+network.Name+" rollback")
+}
+
+// abortRollout marks the rollout as aborted
+func (r *AxelarNetworkReconciler) abortRollout(network *blockchainv1alpha1.AxelarNetwork, reason string) {
+	r.Log.WithValues("axelarnetwork", network.Name).Info("Aborting rollout", "reason", reason)
+
+	network.Status.Rollout.Stage = blockchainv1alpha1.RolloutStageAborted
+	network.Status.Rollout.Message = reason
+	network.Status.Phase = "Degraded"
+	r.setRolloutCondition(network, metav1.ConditionFalse, "RolloutAborted", reason)
+}
+
+// setNodeImage patches the image of a member node, annotating it with the
+// trigger recorded in its upgrade history
+func (r *AxelarNetworkReconciler) setNodeImage(ctx context.Context, node *blockchainv1alpha1.AxelarNode, image *blockchainv1alpha1.ImageSpec, trigger string) error {
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[blockchainv1alpha1.UpgradeTriggerAnnotation] = trigger + "=" + image.Tag
+	node.Spec.Image.Tag = image.Tag
+	if image.Repository != "" {
+		node.Spec.Image.Repository = image.Repository
+	}
+	return r.Patch(ctx, node, patch)
+}
+
+// setRolloutCondition records the rollout state as a condition
+func (r *AxelarNetworkReconciler) setRolloutCondition(network *blockchainv1alpha1.AxelarNetwork, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&network.Status.Conditions, metav1.Condition{
+		Type:               "Progressing",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: network.Generation,
+	})
+}
+
+// selectCanary picks the first sentry or observer among the pending members
+// that is not being drained
+func selectCanary(pending []*blockchainv1alpha1.AxelarNode) *blockchainv1alpha1.AxelarNode {
+	for _, nodeType := range []string{"sentry", "observer"} {
+		for _, node := range pending {
+			if node.Spec.NodeType == nodeType && !isValidator(node) && !memberDraining(node) {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// membersNotAt returns the members not running the given tag
+func membersNotAt(members []blockchainv1alpha1.AxelarNode, tag string) []*blockchainv1alpha1.AxelarNode {
+	var pending []*blockchainv1alpha1.AxelarNode
+	for i := range members {
+		if members[i].Spec.Image.Tag != tag {
+			pending = append(pending, &members[i])
+		}
+	}
+	return pending
+}
+
+// findMember returns the member with the given name
+func findMember(members []blockchainv1alpha1.AxelarNode, name string) *blockchainv1alpha1.AxelarNode {
+	for i := range members {
+		if members[i].Name == name {
+			return &members[i]
+		}
+	}
+	return nil
+}
+
+// maxHeight returns the highest block height among the members
+func maxHeight(members []blockchainv1alpha1.AxelarNode) int64 {
+	var height int64
+	for i := range members {
+		if members[i].Status.SyncInfo.CurrentHeight > height {
+			height = members[i].Status.SyncInfo.CurrentHeight
+		}
+	}
+	return height
+}
+
+// isValidator reports whether the node signs blocks
+func isValidator(node *blockchainv1alpha1.AxelarNode) bool {
+	return node.Spec.NodeType == "validator" || (node.Spec.Validator != nil && node.Spec.Validator.Enabled)
+}
+
+// nodeSynced reports whether the node is synced on its current spec
+func nodeSynced(node *blockchainv1alpha1.AxelarNode) bool {
+	cond := meta.FindStatusCondition(node.Status.Conditions, blockchainv1alpha1.ConditionSynced)
+	return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == node.Generation
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AxelarNetworkReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// TODO: Add AxelarNetwork type when it's defined
-	// For now, just return nil to avoid compilation errors
-	return nil
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&blockchainv1alpha1.AxelarNetwork{}).
+		Watches(&blockchainv1alpha1.AxelarNode{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, obj client.Object) []reconcile.Request {
+				// Scaled members are controlled by their network, whose name
+				// may be hashed in the label
+				if owner := metav1.GetControllerOf(obj); owner != nil && owner.Kind == "AxelarNetwork" {
+					return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: owner.Name, Namespace: obj.GetNamespace()}}}
+				}
+				network, ok := obj.GetLabels()[blockchainv1alpha1.NetworkLabel]
+				if !ok {
+					return nil
+				}
+				namespace := obj.GetNamespace()
+				if joins, ok := obj.GetLabels()[blockchainv1alpha1.NetworkNamespaceLabel]; ok {
+					namespace = joins
+				}
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{
+					Name:      network,
+					Namespace: namespace,
+				}}}
+			})).
+		Complete(r)
 }
@@ -0,0 +1,407 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+)
+
+// forceFailover confirms that an unreachable armed node is fenced
+const forceFailover = "force"
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// reconcileHA keeps exactly one node of the validator pair armed for signing.
+// A failover first disarms the armed node and waits until none of its pods can
+// sign and the signing lease is free before arming the other node.
+func (r *AxelarNetworkReconciler) reconcileHA(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode) error {
+	ha := network.Spec.HA
+	if ha == nil || !ha.Enabled {
+		return nil
+	}
+	ctx = audit.WithReason(ctx, "validator-ha")
+	log := r.Log.WithValues("axelarnetwork", network.Name)
+
+	if len(ha.Nodes) != 2 || ha.Nodes[0].Name == ha.Nodes[1].Name {
+		r.setHACondition(network, metav1.ConditionFalse, "InvalidConfiguration", "HA requires two distinct nodes")
+		return nil
+	}
+	for _, member := range ha.Nodes {
+		if findMember(members, member.Name) == nil {
+			r.setHACondition(network, metav1.ConditionFalse, "MemberMissing",
+				fmt.Sprintf("HA node %s is not a member of the network", member.Name))
+			return nil
+		}
+	}
+
+	st := network.Status.HA
+	if st == nil {
+		var err error
+		if st, err = r.initialHAStatus(ctx, network, members); err != nil {
+			return err
+		}
+		log.Info("Starting validator HA", "phase", st.Phase, "armed", st.ArmedNode, "target", st.FailoverTarget)
+		network.Status.HA = st
+	}
+
+	armed := findMember(members, st.ArmedNode)
+	request := network.Annotations[blockchainv1alpha1.FailoverAnnotation]
+
+	switch st.Phase {
+	case blockchainv1alpha1.HAPhaseArmed:
+		healthy := armed != nil && nodeSynced(armed)
+		if healthy {
+			st.UnhealthySince = nil
+			if height := armed.Status.SyncInfo.CurrentHeight; height > st.SigningHeight {
+				st.SigningHeight = height
+			}
+			if err := r.replicateSigningState(ctx, network); err != nil {
+				return err
+			}
+			if err := r.updateSignerLease(ctx, network, st.ArmedNode, ha.FailureThreshold.Duration); err != nil {
+				return err
+			}
+			st.Message = fmt.Sprintf("%s is armed for signing", st.ArmedNode)
+		} else if st.UnhealthySince == nil {
+			st.UnhealthySince = &metav1.Time{Time: time.Now()}
+		}
+
+		target := ""
+		if request != "" && request != forceFailover {
+			if err := r.clearFailoverRequest(ctx, network); err != nil {
+				return err
+			}
+			if request != st.ArmedNode && (request == ha.Nodes[0].Name || request == ha.Nodes[1].Name) {
+				target = request
+			}
+		} else if ha.AutoFailover && !healthy && time.Since(st.UnhealthySince.Time) > ha.FailureThreshold.Duration {
+			target = otherHANode(ha, st.ArmedNode)
+			if standby := findMember(members, target); !nodeSynced(standby) {
+				st.Message = fmt.Sprintf("%s is unhealthy but %s is not synced, cannot fail over", st.ArmedNode, target)
+				target = ""
+			}
+		}
+
+		if target != "" {
+			log.Info("Starting validator failover", "from", st.ArmedNode, "to", target)
+			st.Phase = blockchainv1alpha1.HAPhaseFencing
+			st.FailoverTarget = target
+			st.Message = fmt.Sprintf("Fencing %s before arming %s", st.ArmedNode, target)
+			if healthy {
+				// A healthy signer hands over its lease immediately
+				if err := r.updateSignerLease(ctx, network, "", ha.FailureThreshold.Duration); err != nil {
+					return err
+				}
+			}
+		}
+
+	case blockchainv1alpha1.HAPhaseFencing:
+		fenced, err := r.nodeFenced(ctx, armed)
+		if err != nil {
+			return err
+		}
+		if !fenced && request == forceFailover {
+			log.Info("Failover fencing confirmed by operator", "node", st.ArmedNode)
+			fenced = true
+		}
+		if !fenced {
+			st.Message = fmt.Sprintf("Waiting for %s to stop signing; set %s=%s once it is confirmed down",
+				st.ArmedNode, blockchainv1alpha1.FailoverAnnotation, forceFailover)
+			break
+		}
+
+		free, err := r.signerLeaseFree(ctx, network)
+		if err != nil || !free {
+			st.Message = "Waiting for the signing lease to expire"
+			return err
+		}
+
+		if request == forceFailover {
+			if err := r.clearFailoverRequest(ctx, network); err != nil {
+				return err
+			}
+		}
+
+		// The target has followed the chain while disarmed, so its height
+		// bounds anything the fenced node can have signed
+		if target := findMember(members, st.FailoverTarget); target != nil {
+			if height := target.Status.SyncInfo.CurrentHeight; height > st.SigningHeight {
+				st.SigningHeight = height
+			}
+		}
+		if err := r.replicateSigningState(ctx, network); err != nil {
+			return err
+		}
+		if err := r.updateSignerLease(ctx, network, st.FailoverTarget, ha.FailureThreshold.Duration); err != nil {
+			return err
+		}
+
+		log.Info("Validator failover completed", "armed", st.FailoverTarget)
+		st.ArmedNode = st.FailoverTarget
+		st.FailoverTarget = ""
+		st.Phase = blockchainv1alpha1.HAPhaseArmed
+		st.UnhealthySince = nil
+		st.LastFailover = &metav1.Time{Time: time.Now()}
+		st.Message = fmt.Sprintf("%s is armed for signing", st.ArmedNode)
+	}
+
+	// Apply the desired signer state to both nodes
+	for _, member := range ha.Nodes {
+		node := findMember(members, member.Name)
+		desired := blockchainv1alpha1.SignerSpec{
+			Armed:                 st.Phase == blockchainv1alpha1.HAPhaseArmed && member.Name == st.ArmedNode,
+			KeySecret:             ha.KeySecret,
+			SigningStateConfigMap: network.Name + "-signing-state",
+			SafetyMargin:          ha.SafetyMargin,
+		}
+		if err := r.setNodeSigner(ctx, node, member.Zone, desired); err != nil {
+			return err
+		}
+	}
+
+	r.setHACondition(network, metav1.ConditionTrue, st.Phase, st.Message)
+	return nil
+}
+
+// initialHAStatus derives the HA state when the network has none, after its
+// creation or the loss of its status. The node armed in the spec of the
+// members is kept armed when the signing lease agrees. When the members and
+// the lease disagree, the HA starts by fencing, so a node that may still be
+// signing is never armed next to another one. A network where no node is
+// armed or holds the lease arms its first node.
+func (r *AxelarNetworkReconciler) initialHAStatus(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode) (*blockchainv1alpha1.HAStatus, error) {
+	ha := network.Spec.HA
+	holder, err := r.signerLeaseHolder(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+
+	var armed []string
+	for _, member := range ha.Nodes {
+		if node := findMember(members, member.Name); node != nil && signerArmed(node) {
+			armed = append(armed, member.Name)
+		}
+	}
+	switch {
+	case len(armed) == 0 && holder == "":
+		return &blockchainv1alpha1.HAStatus{
+			ArmedNode: ha.Nodes[0].Name,
+			Phase:     blockchainv1alpha1.HAPhaseArmed,
+		}, nil
+	case len(armed) == 1 && (holder == "" || holder == armed[0]):
+		return &blockchainv1alpha1.HAStatus{
+			ArmedNode: armed[0],
+			Phase:     blockchainv1alpha1.HAPhaseArmed,
+		}, nil
+	}
+
+	// The lease holder was signing last, and is armed once the other node is fenced
+	target := ha.Nodes[0].Name
+	switch {
+	case holder == ha.Nodes[0].Name || holder == ha.Nodes[1].Name:
+		target = holder
+	case len(armed) == 1:
+		target = armed[0]
+	}
+	return &blockchainv1alpha1.HAStatus{
+		ArmedNode:      otherHANode(ha, target),
+		FailoverTarget: target,
+		Phase:          blockchainv1alpha1.HAPhaseFencing,
+		Message:        fmt.Sprintf("The armed node is ambiguous, fencing %s before arming %s", otherHANode(ha, target), target),
+	}, nil
+}
+
+// signerArmed reports whether the spec of the node arms its signer
+func signerArmed(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	validator := axelarNode.Spec.Validator
+	return validator != nil && validator.Enabled && validator.Signer != nil && validator.Signer.Armed
+}
+
+// setNodeSigner patches the zone and signer configuration of an HA node
+func (r *AxelarNetworkReconciler) setNodeSigner(ctx context.Context, node *blockchainv1alpha1.AxelarNode, zone string, signer blockchainv1alpha1.SignerSpec) error {
+	if node.Spec.Zone == zone && node.Spec.Validator != nil && node.Spec.Validator.Enabled &&
+		node.Spec.Validator.Signer != nil && *node.Spec.Validator.Signer == signer {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Spec.Zone = zone
+	if node.Spec.Validator == nil {
+		node.Spec.Validator = &blockchainv1alpha1.ValidatorSpec{}
+	}
+	node.Spec.Validator.Enabled = true
+	node.Spec.Validator.Signer = &signer
+	return r.Patch(ctx, node, patch)
+}
+
+// nodeFenced reports whether no pod of the node can still be signing
+func (r *AxelarNetworkReconciler) nodeFenced(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	if axelarNode == nil {
+		// Without the node its pods cannot be located, require a forced failover
+		return false, nil
+	}
+	// Pods of a remote node are checked in its own cluster
+	podClient, namespace, err := clusterClient(ctx, r.Client, r.Clusters, axelarNode)
+	if err != nil {
+		return false, err
+	}
+
+	pods := &corev1.PodList{}
+	if err := podClient.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels(nodeSelector(axelarNode))); err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Annotations[armedAnnotation] != "false" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// replicateSigningState stores the last known signing height for the signer guard
+func (r *AxelarNetworkReconciler) replicateSigningState(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      network.Name + "-signing-state",
+			Namespace: network.Namespace,
+		},
+		Data: map[string]string{
+			"height": strconv.FormatInt(network.Status.HA.SigningHeight, 10),
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(network, configMap, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, configMap)
+	} else if err != nil {
+		return err
+	}
+
+	// Never lower the replicated height
+	if previous, _ := strconv.ParseInt(found.Data["height"], 10, 64); previous >= network.Status.HA.SigningHeight {
+		network.Status.HA.SigningHeight = previous
+		return nil
+	}
+	found.Data = configMap.Data
+	return r.Update(ctx, found)
+}
+
+// updateSignerLease records the holder of the signing lease
+func (r *AxelarNetworkReconciler) updateSignerLease(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, holder string, duration time.Duration) error {
+	seconds := int32(duration.Seconds())
+	now := metav1.NewMicroTime(time.Now())
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      network.Name + "-signer",
+			Namespace: network.Namespace,
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &seconds,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(network, lease, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &coordinationv1.Lease{}
+	err := r.Get(ctx, types.NamespacedName{Name: lease.Name, Namespace: lease.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, lease)
+	} else if err != nil {
+		return err
+	}
+
+	if found.Spec.HolderIdentity != nil && *found.Spec.HolderIdentity == holder {
+		lease.Spec.AcquireTime = found.Spec.AcquireTime
+	}
+	found.Spec = lease.Spec
+	return r.Update(ctx, found)
+}
+
+// signerLeaseFree reports whether the signing lease is released or expired
+func (r *AxelarNetworkReconciler) signerLeaseFree(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork) (bool, error) {
+	holder, err := r.signerLeaseHolder(ctx, network)
+	if err != nil {
+		return false, err
+	}
+	return holder == "", nil
+}
+
+// signerLeaseHolder returns the holder of the signing lease, or an empty
+// string when it is released or expired
+func (r *AxelarNetworkReconciler) signerLeaseHolder(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork) (string, error) {
+	lease := &coordinationv1.Lease{}
+	err := r.Get(ctx, types.NamespacedName{Name: network.Name + "-signer", Namespace: network.Namespace}, lease)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return "", nil
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return "", nil
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	if time.Now().After(expiry) {
+		return "", nil
+	}
+	return *lease.Spec.HolderIdentity, nil
+}
+
+// clearFailoverRequest removes the failover annotation
+func (r *AxelarNetworkReconciler) clearFailoverRequest(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork) error {
+	status := network.Status.DeepCopy()
+	delete(network.Annotations, blockchainv1alpha1.FailoverAnnotation)
+	if err := r.Update(ctx, network); err != nil {
+		return err
+	}
+	network.Status = *status
+	return nil
+}
+
+// setHACondition records the HA state as a condition
+func (r *AxelarNetworkReconciler) setHACondition(network *blockchainv1alpha1.AxelarNetwork, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&network.Status.Conditions, metav1.Condition{
+		Type:               "ValidatorHA",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: network.Generation,
+	})
+}
+
+// otherHANode returns the HA node that is not name
+func otherHANode(ha *blockchainv1alpha1.ValidatorHASpec, name string) string {
+	if ha.Nodes[0].Name == name {
+		return ha.Nodes[1].Name
+	}
+	return ha.Nodes[0].Name
+}
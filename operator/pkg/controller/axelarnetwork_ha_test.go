@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// haMember returns a member of the network, armed or not
+func haMember(name string, armed bool) blockchainv1alpha1.AxelarNode {
+	return blockchainv1alpha1.AxelarNode{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "axelar"},
+		Spec: blockchainv1alpha1.AxelarNodeSpec{
+			Validator: &blockchainv1alpha1.ValidatorSpec{
+				Enabled: true,
+				Signer:  &blockchainv1alpha1.SignerSpec{Armed: armed},
+			},
+		},
+	}
+}
+
+func TestInitialHAStatus(t *testing.T) {
+	network := &blockchainv1alpha1.AxelarNetwork{
+		ObjectMeta: metav1.ObjectMeta{Name: "mainnet", Namespace: "axelar"},
+		Spec: blockchainv1alpha1.AxelarNetworkSpec{
+			HA: &blockchainv1alpha1.ValidatorHASpec{
+				Enabled: true,
+				Nodes:   []blockchainv1alpha1.HAMemberSpec{{Name: "validator-a"}, {Name: "validator-b"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		armedA     bool
+		armedB     bool
+		holder     string
+		wantPhase  string
+		wantArmed  string
+		wantTarget string
+	}{
+		{"new network", false, false, "", blockchainv1alpha1.HAPhaseArmed, "validator-a", ""},
+		{"second node armed", false, true, "", blockchainv1alpha1.HAPhaseArmed, "validator-b", ""},
+		{"armed node holds the lease", false, true, "validator-b", blockchainv1alpha1.HAPhaseArmed, "validator-b", ""},
+		{"lease held by the disarmed node", true, false, "validator-b", blockchainv1alpha1.HAPhaseFencing, "validator-a", "validator-b"},
+		{"both nodes armed", true, true, "", blockchainv1alpha1.HAPhaseFencing, "validator-b", "validator-a"},
+		{"no node armed, lease held", false, false, "validator-b", blockchainv1alpha1.HAPhaseFencing, "validator-a", "validator-b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []client.Object{}
+			if tt.holder != "" {
+				seconds := int32(300)
+				now := metav1.NewMicroTime(time.Now())
+				objects = append(objects, &coordinationv1.Lease{
+					ObjectMeta: metav1.ObjectMeta{Name: "mainnet-signer", Namespace: "axelar"},
+					Spec: coordinationv1.LeaseSpec{
+						HolderIdentity:       &tt.holder,
+						LeaseDurationSeconds: &seconds,
+						RenewTime:            &now,
+					},
+				})
+			}
+			r := &AxelarNetworkReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(objects...).Build()}
+			members := []blockchainv1alpha1.AxelarNode{haMember("validator-a", tt.armedA), haMember("validator-b", tt.armedB)}
+
+			st, err := r.initialHAStatus(context.Background(), network, members)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if st.Phase != tt.wantPhase || st.ArmedNode != tt.wantArmed || st.FailoverTarget != tt.wantTarget {
+				t.Fatalf("status = %s armed=%s target=%s, want %s armed=%s target=%s",
+					st.Phase, st.ArmedNode, st.FailoverTarget, tt.wantPhase, tt.wantArmed, tt.wantTarget)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+)
+
+// strategicMerge merges overlay into base. Objects are merged key by key,
+// lists of objects with a name are merged by name, and any other value in
+// overlay replaces the one in base. Nulls, empty strings and zero durations
+// in overlay are fields it leaves unset, so base is kept.
+func strategicMerge(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		if value == nil || value == "" || value == "0s" {
+			continue
+		}
+		switch over := value.(type) {
+		case map[string]interface{}:
+			if under, ok := merged[key].(map[string]interface{}); ok {
+				merged[key] = strategicMerge(under, over)
+				continue
+			}
+		case []interface{}:
+			if under, ok := merged[key].([]interface{}); ok && namedList(under) && namedList(over) {
+				merged[key] = mergeNamedList(under, over)
+				continue
+			}
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// namedList reports whether every item of a list is an object with a name
+func namedList(items []interface{}) bool {
+	for _, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := object["name"].(string); !ok {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+// mergeNamedList merges the items of overlay into those of base with the same
+// name, and appends the others
+func mergeNamedList(base, overlay []interface{}) []interface{} {
+	merged := append([]interface{}{}, base...)
+	for _, item := range overlay {
+		over := item.(map[string]interface{})
+		found := false
+		for i, existing := range merged {
+			under := existing.(map[string]interface{})
+			if under["name"] == over["name"] {
+				merged[i] = strategicMerge(under, over)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, over)
+		}
+	}
+	return merged
+}
+
+// mergeNodeSpecs returns overlay merged over base with strategicMerge
+func mergeNodeSpecs(base, overlay *blockchainv1alpha1.AxelarNodeSpec) (*blockchainv1alpha1.AxelarNodeSpec, error) {
+	if base == nil {
+		return overlay.DeepCopy(), nil
+	}
+	if overlay == nil {
+		return base.DeepCopy(), nil
+	}
+	toMap := func(spec *blockchainv1alpha1.AxelarNodeSpec) (map[string]interface{}, error) {
+		data, err := json.Marshal(spec)
+		if err != nil {
+			return nil, err
+		}
+		fields := map[string]interface{}{}
+		return fields, json.Unmarshal(data, &fields)
+	}
+	under, err := toMap(base)
+	if err != nil {
+		return nil, err
+	}
+	over, err := toMap(overlay)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(strategicMerge(under, over))
+	if err != nil {
+		return nil, err
+	}
+	merged := &blockchainv1alpha1.AxelarNodeSpec{}
+	return merged, json.Unmarshal(data, merged)
+}
+
+// memberOverride returns the override of the named member, or nil
+func memberOverride(network *blockchainv1alpha1.AxelarNetwork, name string) *blockchainv1alpha1.NetworkMemberOverride {
+	for i := range network.Spec.Members {
+		if network.Spec.Members[i].Name == name {
+			return &network.Spec.Members[i]
+		}
+	}
+	return nil
+}
+
+// applyOverride applies the override of a member to its spec
+func applyOverride(spec *blockchainv1alpha1.AxelarNodeSpec, override *blockchainv1alpha1.NetworkMemberOverride) {
+	if override == nil {
+		return
+	}
+	if override.Resources != nil {
+		spec.Resources = *override.Resources.DeepCopy()
+	}
+	if override.StorageClass != "" {
+		spec.Storage.StorageClass = override.StorageClass
+	}
+	if override.Zone != "" {
+		spec.Zone = override.Zone
+	}
+	if override.ImageTag != "" {
+		spec.Image.Tag = override.ImageTag
+	}
+}
+
+// memberTemplate returns the spec members are created from: the node
+// template merged over the node defaults, or nil when neither is set
+func memberTemplate(network *blockchainv1alpha1.AxelarNetwork) (*blockchainv1alpha1.AxelarNodeSpec, error) {
+	var template *blockchainv1alpha1.AxelarNodeSpec
+	if network.Spec.NodeTemplate != nil {
+		template = &network.Spec.NodeTemplate.Spec
+	}
+	if template == nil && network.Spec.NodeDefaults == nil {
+		return nil, nil
+	}
+	return mergeNodeSpecs(network.Spec.NodeDefaults, template)
+}
+
+// rolloutMembers returns the members image rollouts apply to. Members pinned
+// to an image tag by an override are left out.
+func rolloutMembers(network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode) []blockchainv1alpha1.AxelarNode {
+	var included []blockchainv1alpha1.AxelarNode
+	for i := range members {
+		if override := memberOverride(network, members[i].Name); override != nil && override.ImageTag != "" {
+			continue
+		}
+		included = append(included, members[i])
+	}
+	return included
+}
+
+// syncOverrides keeps the resources and the pinned image tag of the members
+// created by the network in line with the defaults, template and overrides.
+// The zone and storage class only apply when a member is created, since its
+// volumes cannot move.
+func (r *AxelarNetworkReconciler) syncOverrides(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, template *blockchainv1alpha1.AxelarNodeSpec, members []blockchainv1alpha1.AxelarNode) error {
+	for i := range members {
+		node := &members[i]
+		if node.Labels[blockchainv1alpha1.ScaledMemberLabel] != naming.LabelValue(network.Name) || node.DeletionTimestamp != nil {
+			continue
+		}
+		desired := template.DeepCopy()
+		override := memberOverride(network, node.Name)
+		applyOverride(desired, override)
+
+		patch := client.MergeFrom(node.DeepCopy())
+		changed := false
+		if !equality.Semantic.DeepEqual(node.Spec.Resources, desired.Resources) {
+			node.Spec.Resources = desired.Resources
+			changed = true
+		}
+		if override != nil && override.ImageTag != "" && node.Spec.Image.Tag != override.ImageTag {
+			node.Spec.Image.Tag = override.ImageTag
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		r.Log.WithValues("axelarnetwork", network.Name).Info("Updating network member from overrides", "axelarnode", node.Name)
+		if err := r.Patch(ctx, node, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
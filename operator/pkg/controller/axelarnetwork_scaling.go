@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+)
+
+// defaultDrainPeriod applies when the spec leaves the drain period unset
+const defaultDrainPeriod = 2 * time.Minute
+
+// scaledNodeType returns the node type of the members created by the network,
+// or an empty string when the template cannot be scaled
+func scaledNodeType(template *blockchainv1alpha1.AxelarNodeSpec) string {
+	if template == nil || (template.Validator != nil && template.Validator.Enabled) {
+		return ""
+	}
+	switch template.NodeType {
+	case "", "sentry":
+		return "sentry"
+	case "observer":
+		return "observer"
+	}
+	return ""
+}
+
+// memberOrdinal returns the ordinal of a member created by the network, or -1
+// when its name does not carry one
+func memberOrdinal(network *blockchainv1alpha1.AxelarNetwork, nodeType string, node *blockchainv1alpha1.AxelarNode) int {
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(node.Name, network.Name+"-"+nodeType+"-"))
+	if err != nil || ordinal < 0 || node.Name != fmt.Sprintf("%s-%s-%d", network.Name, nodeType, ordinal) {
+		return -1
+	}
+	return ordinal
+}
+
+// memberDraining reports whether the member is being removed on scale-down
+func memberDraining(node *blockchainv1alpha1.AxelarNode) bool {
+	_, draining := node.Annotations[blockchainv1alpha1.DrainAnnotation]
+	return draining
+}
+
+// reconcileScale creates and removes sentry or observer members until the
+// network has spec.replicas of them. A removed member is drained first: it
+// stops accepting peers for the drain period, then is deleted and shuts down
+// gracefully. It returns true while members are being created or drained.
+func (r *AxelarNetworkReconciler) reconcileScale(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode) (bool, error) {
+	ctx = audit.WithReason(ctx, "scale")
+	log := r.Log.WithValues("axelarnetwork", network.Name)
+	network.Status.Selector = labels.SelectorFromSet(map[string]string{blockchainv1alpha1.ScaledMemberLabel: naming.LabelValue(network.Name)}).String()
+
+	if network.Spec.Replicas == nil {
+		network.Status.Replicas = 0
+		meta.RemoveStatusCondition(&network.Status.Conditions, "Scaling")
+		return false, nil
+	}
+	desired := int(*network.Spec.Replicas)
+
+	template, err := memberTemplate(network)
+	if err != nil {
+		r.setScalingCondition(network, metav1.ConditionFalse, "InvalidNodeTemplate", err.Error())
+		return false, nil
+	}
+	nodeType := scaledNodeType(template)
+	if nodeType == "" {
+		r.setScalingCondition(network, metav1.ConditionFalse, "InvalidNodeTemplate",
+			"spec.nodeDefaults and spec.nodeTemplate must describe sentry or observer nodes")
+		return false, nil
+	}
+
+	drainPeriod := network.Spec.Scaling.DrainPeriod.Duration
+	if drainPeriod <= 0 {
+		drainPeriod = defaultDrainPeriod
+	}
+
+	created := map[int]*blockchainv1alpha1.AxelarNode{}
+	var surplus []*blockchainv1alpha1.AxelarNode
+	for i := range members {
+		node := &members[i]
+		if node.Labels[blockchainv1alpha1.ScaledMemberLabel] != naming.LabelValue(network.Name) {
+			continue
+		}
+		ordinal := memberOrdinal(network, nodeType, node)
+		if ordinal < 0 || ordinal >= desired {
+			surplus = append(surplus, node)
+			continue
+		}
+		created[ordinal] = node
+	}
+
+	progressing := false
+	for ordinal := 0; ordinal < desired; ordinal++ {
+		node, ok := created[ordinal]
+		if !ok {
+			log.Info("Creating network member", "ordinal", ordinal)
+			if err := r.createMember(ctx, network, template, nodeType, ordinal); err != nil && !errors.IsAlreadyExists(err) {
+				return false, err
+			}
+			progressing = true
+			continue
+		}
+		// A member scaled back up before it was deleted stays
+		if memberDraining(node) {
+			log.Info("Cancelling drain of network member", "axelarnode", node.Name)
+			patch := client.MergeFrom(node.DeepCopy())
+			delete(node.Annotations, blockchainv1alpha1.DrainAnnotation)
+			if err := r.Patch(ctx, node, patch); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if err := r.syncOverrides(ctx, network, template, members); err != nil {
+		return false, err
+	}
+
+	for _, node := range surplus {
+		progressing = true
+		if node.DeletionTimestamp != nil {
+			continue
+		}
+		if !memberDraining(node) {
+			log.Info("Draining network member", "axelarnode", node.Name, "drainPeriod", drainPeriod)
+			patch := client.MergeFrom(node.DeepCopy())
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+			node.Annotations[blockchainv1alpha1.DrainAnnotation] = time.Now().UTC().Format(time.RFC3339)
+			if err := r.Patch(ctx, node, patch); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		since, err := time.Parse(time.RFC3339, node.Annotations[blockchainv1alpha1.DrainAnnotation])
+		if err == nil && time.Since(since) < drainPeriod {
+			continue
+		}
+		log.Info("Deleting drained network member", "axelarnode", node.Name)
+		if err := r.Delete(ctx, node); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	network.Status.Replicas = int32(desired)
+	if progressing {
+		r.setScalingCondition(network, metav1.ConditionTrue, "Scaling",
+			fmt.Sprintf("Scaling to %d %s members, %d draining", desired, nodeType, len(surplus)))
+	} else {
+		r.setScalingCondition(network, metav1.ConditionFalse, "AtDesiredReplicas",
+			fmt.Sprintf("Running %d %s members", desired, nodeType))
+	}
+	return progressing, nil
+}
+
+// createMember creates the member of a scaled network with the given ordinal
+func (r *AxelarNetworkReconciler) createMember(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, template *blockchainv1alpha1.AxelarNodeSpec, nodeType string, ordinal int) error {
+	name := fmt.Sprintf("%s-%s-%d", network.Name, nodeType, ordinal)
+
+	nodeLabels := map[string]string{}
+	if network.Spec.NodeTemplate != nil {
+		for key, value := range network.Spec.NodeTemplate.Labels {
+			nodeLabels[key] = value
+		}
+	}
+	nodeLabels[blockchainv1alpha1.NetworkLabel] = naming.LabelValue(network.Name)
+	nodeLabels[blockchainv1alpha1.ScaledMemberLabel] = naming.LabelValue(network.Name)
+
+	node := &blockchainv1alpha1.AxelarNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: network.Namespace,
+			Labels:    nodeLabels,
+		},
+		Spec: *template.DeepCopy(),
+	}
+	node.Spec.NodeType = nodeType
+	if node.Spec.Moniker == "" {
+		node.Spec.Moniker = name
+	} else {
+		node.Spec.Moniker = fmt.Sprintf("%s-%d", node.Spec.Moniker, ordinal)
+	}
+
+	// New members join on the image the network last rolled out, unless an
+	// override pins them to another
+	override := memberOverride(network, name)
+	if rollout := network.Status.Rollout; rollout != nil && rollout.Stage == blockchainv1alpha1.RolloutStageCompleted &&
+		network.Spec.Image != nil && network.Spec.Image.Tag == rollout.TargetTag {
+		node.Spec.Image.Tag = network.Spec.Image.Tag
+		if network.Spec.Image.Repository != "" {
+			node.Spec.Image.Repository = network.Spec.Image.Repository
+		}
+	}
+	applyOverride(&node.Spec, override)
+
+	if err := controllerutil.SetControllerReference(network, node, r.Scheme); err != nil {
+		return err
+	}
+	return r.Create(ctx, node)
+}
+
+// setScalingCondition records the scaling state as a condition
+func (r *AxelarNetworkReconciler) setScalingCondition(network *blockchainv1alpha1.AxelarNetwork, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&network.Status.Conditions, metav1.Condition{
+		Type:               "Scaling",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: network.Generation,
+	})
+}
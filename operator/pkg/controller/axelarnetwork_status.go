@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// validatorSigningStatus returns the signing state of a validator member
+func validatorSigningStatus(node *blockchainv1alpha1.AxelarNode) blockchainv1alpha1.ValidatorSigningStatus {
+	status := blockchainv1alpha1.ValidatorSigningStatus{
+		Node:  node.Name,
+		Armed: validatorSigning(node),
+	}
+	if info := node.Status.ValidatorInfo; info != nil {
+		status.MissedBlocks = info.MissedBlocks
+		status.LastSignedHeight = info.LastSignedHeight
+	}
+	if !status.Armed {
+		return status
+	}
+
+	var problems []string
+	if !nodeSynced(node) {
+		problems = append(problems, "not synced")
+	}
+	for _, condition := range []string{blockchainv1alpha1.ConditionValdHealthy, blockchainv1alpha1.ConditionTofndHealthy} {
+		if meta.IsStatusConditionFalse(node.Status.Conditions, condition) {
+			problems = append(problems, condition+" is False")
+		}
+	}
+	status.Signing = len(problems) == 0
+	status.Message = strings.Join(problems, ", ")
+	return status
+}
+
+// aggregateMembers rolls up the health of the members into the network status
+// and its Ready condition
+func aggregateMembers(network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode) {
+	networkHeight := maxHeight(members)
+	for i := range members {
+		if latest := members[i].Status.SyncInfo.LatestHeight; latest > networkHeight {
+			networkHeight = latest
+		}
+	}
+
+	stats := blockchainv1alpha1.NetworkStats{
+		TotalNodes:       int32(len(members)),
+		CurrentHeight:    maxHeight(members),
+		AverageBlockTime: network.Status.NetworkStats.AverageBlockTime,
+	}
+	var validators []blockchainv1alpha1.ValidatorSigningStatus
+	var notReady, notSigning []string
+	for i := range members {
+		node := &members[i]
+		phase := node.Status.Phase
+		if phase == "" {
+			phase = "Pending"
+		}
+		if stats.Phases == nil {
+			stats.Phases = map[string]int32{}
+		}
+		stats.Phases[phase]++
+
+		if nodeSynced(node) {
+			stats.SyncedNodes++
+		} else if !memberDraining(node) {
+			notReady = append(notReady, node.Name)
+		}
+		if height := node.Status.SyncInfo.CurrentHeight; height > 0 {
+			if stats.LowestHeight == 0 || height < stats.LowestHeight {
+				stats.LowestHeight = height
+			}
+			if lag := networkHeight - height; lag > stats.MaxSyncLag {
+				stats.MaxSyncLag = lag
+			}
+		}
+
+		if !isValidator(node) {
+			continue
+		}
+		signing := validatorSigningStatus(node)
+		if signing.Signing {
+			stats.ActiveValidators++
+		} else if signing.Armed {
+			notSigning = append(notSigning, fmt.Sprintf("%s (%s)", node.Name, signing.Message))
+		}
+		validators = append(validators, signing)
+	}
+	network.Status.NetworkStats = stats
+	network.Status.Validators = validators
+
+	ready := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionNetworkReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "MembersReady",
+		Message:            fmt.Sprintf("%d members synced at height %d or above", stats.SyncedNodes, stats.LowestHeight),
+		ObservedGeneration: network.Generation,
+	}
+	switch {
+	case len(members) == 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "NoMembers"
+		ready.Message = "No AxelarNode carries the network label"
+	case len(notSigning) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "ValidatorsNotSigning"
+		ready.Message = "Armed validators not signing: " + strings.Join(notSigning, "; ")
+	case len(notReady) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "MembersNotSynced"
+		ready.Message = "Members not synced: " + strings.Join(notReady, ", ")
+	}
+	meta.SetStatusCondition(&network.Status.Conditions, ready)
+}
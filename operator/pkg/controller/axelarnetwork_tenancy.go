@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/caching"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tenancy"
+)
+
+// memberVerbs are the verbs the operator needs on the AxelarNodes of a member namespace
+var memberVerbs = []string{"list", "watch", "patch", "create", "delete"}
+
+// memberNamespaces returns the namespaces members may join the network from:
+// its own, and those of spec.memberNamespaces that belong to the tenant of
+// the network and where the operator may manage AxelarNodes. Refused
+// namespaces are reported in the MemberNamespaces condition.
+func (r *AxelarNetworkReconciler) memberNamespaces(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork) ([]string, error) {
+	allowed := []string{network.Namespace}
+	if len(network.Spec.MemberNamespaces) == 0 {
+		meta.RemoveStatusCondition(&network.Status.Conditions, blockchainv1alpha1.ConditionMemberNamespaces)
+		return allowed, nil
+	}
+
+	// Access reviews are repeated when the spec changes or a namespace was refused
+	previous := meta.FindStatusCondition(network.Status.Conditions, blockchainv1alpha1.ConditionMemberNamespaces)
+	reviewAccess := previous == nil || previous.Status != metav1.ConditionTrue || previous.ObservedGeneration != network.Generation
+
+	var refused []string
+	for _, name := range network.Spec.MemberNamespaces {
+		if name == network.Namespace {
+			continue
+		}
+		namespace := &corev1.Namespace{}
+		err := r.Get(ctx, types.NamespacedName{Name: name}, namespace)
+		if errors.IsNotFound(err) {
+			refused = append(refused, fmt.Sprintf("%s does not exist", name))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if tenancy.Of(namespace) != tenancy.Of(network) {
+			refused = append(refused, fmt.Sprintf("%s belongs to tenant %q", name, tenancy.Of(namespace)))
+			continue
+		}
+		if reviewAccess {
+			denied, err := r.deniedVerbs(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			if len(denied) > 0 {
+				refused = append(refused, fmt.Sprintf("%s does not allow %s on axelarnodes", name, strings.Join(denied, ", ")))
+				continue
+			}
+		}
+		allowed = append(allowed, name)
+	}
+
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionMemberNamespaces,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Allowed",
+		Message:            fmt.Sprintf("Members may join from %s", strings.Join(allowed, ", ")),
+		ObservedGeneration: network.Generation,
+	}
+	if len(refused) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Refused"
+		condition.Message = "Ignoring member namespaces: " + strings.Join(refused, "; ")
+	}
+	meta.SetStatusCondition(&network.Status.Conditions, condition)
+	return allowed, nil
+}
+
+// deniedVerbs returns the member verbs the operator may not use on the
+// AxelarNodes of a namespace
+func (r *AxelarNetworkReconciler) deniedVerbs(ctx context.Context, namespace string) ([]string, error) {
+	var denied []string
+	for _, verb := range memberVerbs {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Group:     blockchainv1alpha1.SchemeGroupVersion.Group,
+					Resource:  "axelarnodes",
+				},
+			},
+		}
+		if err := r.Create(ctx, review); err != nil {
+			return nil, err
+		}
+		if !review.Status.Allowed {
+			denied = append(denied, verb)
+		}
+	}
+	return denied, nil
+}
+
+// listNamespaceMembers returns the AxelarNodes joining the network from a
+// namespace. Nodes of the network's namespace may join a namesake network
+// elsewhere, which the member index tells apart.
+func (r *AxelarNetworkReconciler) listNamespaceMembers(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, namespace string) ([]blockchainv1alpha1.AxelarNode, error) {
+	nodes := &blockchainv1alpha1.AxelarNodeList{}
+	err := r.List(ctx, nodes, client.InNamespace(namespace),
+		client.MatchingFields{caching.MemberIndex: caching.MemberKey(network.Namespace, network.Name)})
+	if err != nil {
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+// isolateMembers leaves out the members of another tenant, and labels those
+// without a tenant with the tenant of the network
+func (r *AxelarNetworkReconciler) isolateMembers(ctx context.Context, network *blockchainv1alpha1.AxelarNetwork, members []blockchainv1alpha1.AxelarNode) ([]blockchainv1alpha1.AxelarNode, error) {
+	tenant := tenancy.Of(network)
+	isolated := members[:0]
+	for i := range members {
+		node := &members[i]
+		switch tenancy.Of(node) {
+		case tenant:
+		case "":
+			patch := client.MergeFrom(node.DeepCopy())
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[blockchainv1alpha1.TenantLabel] = tenant
+			if err := r.Patch(ctx, node, patch); err != nil {
+				return nil, err
+			}
+		default:
+			r.Log.WithValues("axelarnetwork", network.Name).Info("Ignoring a member of another tenant",
+				"axelarnode", types.NamespacedName{Namespace: node.Namespace, Name: node.Name}, "tenant", tenancy.Of(node))
+			continue
+		}
+		isolated = append(isolated, *node)
+	}
+
+	sort.Slice(isolated, func(i, j int) bool {
+		if isolated[i].Name != isolated[j].Name {
+			return isolated[i].Name < isolated[j].Name
+		}
+		return isolated[i].Namespace < isolated[j].Namespace
+	})
+	return isolated, nil
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/addrbook"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// addrbookPort is where the address book sidecar serves the address book
+const addrbookPort = 26671
+
+// addrbookKey is the ConfigMap key holding the address book
+const addrbookKey = "addrbook.json"
+
+// defaultAddrbookBackupInterval applies when the spec leaves the interval unset
+const defaultAddrbookBackupInterval = time.Hour
+
+// addrbookConfigMapName names the ConfigMap address books are backed up to
+func addrbookConfigMapName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "addrbook")
+}
+
+// addAddressBook seeds the address book of an empty data volume before the
+// node starts, and serves it to the operator when backups are enabled
+func (r *AxelarNodeReconciler) addAddressBook(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	spec, blocked := axelarNode.Spec.Networking.P2P.AddressBook, axelarNode.Spec.Networking.P2P.BlockedPeerIDs
+	if spec == nil {
+		if len(blocked) == 0 {
+			return
+		}
+		// Only prune the blocked peers from the address book
+		spec = &blockchainv1alpha1.AddressBookSpec{}
+	}
+
+	seed := corev1.Container{
+		Name:  "addrbook-seed",
+		Image: r.ToolsImage,
+		Args:  []string{"addrbook", "seed", "--file=/home/axelard/.axelar/config/addrbook.json"},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: "/home/axelard/.axelar"},
+		},
+	}
+	// An explicit seed takes precedence over the backups of the node
+	source := spec.ConfigMapRef
+	if source == nil && spec.Backup {
+		optional := true
+		source = &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: addrbookConfigMapName(axelarNode)},
+			Key:                  addrbookKey,
+			Optional:             &optional,
+		}
+	}
+	if source != nil {
+		seed.Args = append(seed.Args, "--seed-file=/addrbook-seed/"+addrbookKey)
+		seed.VolumeMounts = append(seed.VolumeMounts, corev1.VolumeMount{Name: "addrbook-seed", MountPath: "/addrbook-seed", ReadOnly: true})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "addrbook-seed",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: source.LocalObjectReference,
+					Items:                []corev1.KeyToPath{{Key: source.Key, Path: addrbookKey}},
+					Optional:             source.Optional,
+				},
+			},
+		})
+	}
+	if spec.URL != "" {
+		seed.Args = append(seed.Args, "--seed-url="+spec.URL)
+	}
+	if len(blocked) > 0 {
+		seed.Args = append(seed.Args, "--blocked-peers="+strings.Join(blocked, ","))
+	}
+	podSpec.InitContainers = append(podSpec.InitContainers, seed)
+
+	if spec.Backup {
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{
+			Name:  "addrbook",
+			Image: r.ToolsImage,
+			Args: []string{
+				"addrbook", "serve",
+				"--file=/home/axelard/.axelar/config/addrbook.json",
+				fmt.Sprintf("--listen=:%d", addrbookPort),
+			},
+			Ports: []corev1.ContainerPort{
+				{Name: "addrbook", ContainerPort: addrbookPort, Protocol: corev1.ProtocolTCP},
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "data", MountPath: "/home/axelard/.axelar", ReadOnly: true},
+			},
+		})
+	}
+}
+
+// reconcileAddressBookBackup periodically saves the address book of a running
+// pod to a ConfigMap. The ConfigMap is not owned by the node, so it seeds the
+// node again when it is re-created.
+func (r *AxelarNodeReconciler) reconcileAddressBookBackup(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	spec := axelarNode.Spec.Networking.P2P.AddressBook
+	if spec == nil || !spec.Backup {
+		return nil
+	}
+	interval := defaultAddrbookBackupInterval
+	if spec.BackupInterval.Duration > 0 {
+		interval = spec.BackupInterval.Duration
+	}
+	if status := axelarNode.Status.AddressBook; status != nil && status.LastBackup != nil && time.Since(status.LastBackup.Time) < interval {
+		return nil
+	}
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(axelarNode.Namespace), client.MatchingLabels(nodeSelector(axelarNode))); err != nil {
+		return err
+	}
+	var book []byte
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		data, err := addrbook.Fetch(ctx, podURL(pod.Status.PodIP, addrbookPort)+"/addrbook")
+		if err != nil {
+			log.V(1).Info("Unable to fetch the address book", "pod", pod.Name, "error", err.Error())
+			continue
+		}
+		book = data
+		break
+	}
+	if book == nil {
+		return nil
+	}
+	// Never replace a backup with an empty address book
+	count, _ := addrbook.Parse(book)
+	if count == 0 {
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: addrbookConfigMapName(axelarNode), Namespace: axelarNode.Namespace}, configMap)
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      addrbookConfigMapName(axelarNode),
+				Namespace: axelarNode.Namespace,
+				Labels:    nodeLabels(axelarNode, componentNode),
+			},
+			Data: map[string]string{addrbookKey: string(book)},
+		}
+		err = r.Create(ctx, configMap)
+	} else if err == nil {
+		configMap.Data = map[string]string{addrbookKey: string(book)}
+		err = r.Update(ctx, configMap)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.V(1).Info("Backed up the address book", "addresses", count)
+	axelarNode.Status.AddressBook = &blockchainv1alpha1.AddressBookStatus{
+		LastBackup: &metav1.Time{Time: time.Now()},
+		Addresses:  int32(count),
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// adoptionRequested reports whether the node asks the operator to take over
+// pre-existing objects
+func adoptionRequested(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Annotations[blockchainv1alpha1.AdoptAnnotation] == "true" ||
+		axelarNode.Annotations[blockchainv1alpha1.AdoptSelectorAnnotation] != ""
+}
+
+// deploymentName returns the Deployment running the node
+func deploymentName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if adoption := axelarNode.Status.Adoption; adoption != nil && adoption.Deployment != "" {
+		return adoption.Deployment
+	}
+	return axelarNode.Name
+}
+
+// serviceName returns the Service exposing the node
+func serviceName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if adoption := axelarNode.Status.Adoption; adoption != nil && adoption.Service != "" {
+		return adoption.Service
+	}
+	return childName(axelarNode, "service")
+}
+
+// discoverAdoption matches the objects of the adopt selector annotation once
+// and records them in status.adoption, which names the Deployment, Service
+// and data PVC of the node from then on. An object is only matched when no
+// controller owns it, and the selector must match at most one of each.
+func (r *AxelarNodeReconciler) discoverAdoption(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	value := axelarNode.Annotations[blockchainv1alpha1.AdoptSelectorAnnotation]
+	if value == "" {
+		return nil
+	}
+	if axelarNode.Status.Adoption != nil {
+		return nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", blockchainv1alpha1.AdoptSelectorAnnotation, err)
+	}
+	opts := []client.ListOption{client.InNamespace(axelarNode.Namespace), client.MatchingLabelsSelector{Selector: selector}}
+	adoption := &blockchainv1alpha1.AdoptionStatus{Selector: value}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, opts...); err != nil {
+		return err
+	}
+	var deployment *appsv1.Deployment
+	for i := range deployments.Items {
+		if !adoptable(&deployments.Items[i]) {
+			continue
+		}
+		if deployment != nil {
+			return fmt.Errorf("adopt selector %q matches Deployments %s and %s", value, deployment.Name, deployments.Items[i].Name)
+		}
+		deployment = &deployments.Items[i]
+	}
+	if deployment != nil {
+		adoption.Deployment = deployment.Name
+		adoption.DataClaim = mountedClaim(deployment, "/home/axelard/.axelar")
+	}
+
+	if adoption.DataClaim == "" {
+		claims := &corev1.PersistentVolumeClaimList{}
+		if err := r.List(ctx, claims, opts...); err != nil {
+			return err
+		}
+		for i := range claims.Items {
+			if !adoptable(&claims.Items[i]) {
+				continue
+			}
+			if adoption.DataClaim != "" {
+				return fmt.Errorf("adopt selector %q matches PVCs %s and %s, set spec.storage.existingClaim", value, adoption.DataClaim, claims.Items[i].Name)
+			}
+			adoption.DataClaim = claims.Items[i].Name
+		}
+	}
+
+	// Only the Service exposing the RPC port is the node Service, others such
+	// as a NodePort for P2P are left alone
+	services := &corev1.ServiceList{}
+	if err := r.List(ctx, services, opts...); err != nil {
+		return err
+	}
+	for i := range services.Items {
+		service := &services.Items[i]
+		if !adoptable(service) || !exposesPort(service, axelarNode.Spec.Networking.RPC.Port) {
+			continue
+		}
+		if adoption.Service != "" {
+			return fmt.Errorf("adopt selector %q matches Services %s and %s exposing the RPC port", value, adoption.Service, service.Name)
+		}
+		adoption.Service = service.Name
+	}
+
+	axelarNode.Status.Adoption = adoption
+	r.Log.Info("Matched objects to adopt", "axelarnode", axelarNode.Name, "selector", value,
+		"deployment", adoption.Deployment, "service", adoption.Service, "dataClaim", adoption.DataClaim)
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, corev1.EventTypeNormal, "AdoptionMatched", fmt.Sprintf("Adopting Deployment %q, Service %q and PVC %q",
+			adoption.Deployment, adoption.Service, adoption.DataClaim))
+	}
+
+	// The names must be written before the objects are adopted, or a failed
+	// write could let the next reconcile create the objects the node was
+	// meant to adopt
+	return r.patchStatus(ctx, axelarNode)
+}
+
+// adoptable reports whether obj is not controlled by anything, including
+// the objects the operator created itself
+func adoptable(obj client.Object) bool {
+	return metav1.GetControllerOf(obj) == nil
+}
+
+// mountedClaim returns the PVC a container of the Deployment mounts at path
+func mountedClaim(deployment *appsv1.Deployment, path string) string {
+	spec := deployment.Spec.Template.Spec
+	for _, container := range spec.Containers {
+		for _, mount := range container.VolumeMounts {
+			if mount.MountPath != path {
+				continue
+			}
+			for _, volume := range spec.Volumes {
+				if volume.Name == mount.Name && volume.PersistentVolumeClaim != nil {
+					return volume.PersistentVolumeClaim.ClaimName
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// exposesPort reports whether the Service has a port or target port
+func exposesPort(service *corev1.Service, port int32) bool {
+	for _, p := range service.Spec.Ports {
+		if p.Port == port || p.TargetPort.IntValue() == int(port) {
+			return true
+		}
+	}
+	return false
+}
+
+// adoptObject makes the node the controller of an existing child object it
+// did not create, when the node carries the adopt annotation. The object is
+// updated in place, never recreated. Objects controlled by something else
+// are left alone.
+func (r *AxelarNodeReconciler) adoptObject(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, obj client.Object, kind string) error {
+	if metav1.IsControlledBy(obj, axelarNode) {
+		return nil
+	}
+	// Another node whose generated names collide with those of this one
+	// keeps its objects until one of the nodes is renamed
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		return terminal("NameCollision", fmt.Errorf("%s %s is controlled by %s %s", kind, obj.GetName(), owner.Kind, owner.Name))
+	}
+	if !adoptionRequested(axelarNode) {
+		return fmt.Errorf("%s %s already exists and is not managed by the operator, set the %s annotation to adopt it",
+			kind, obj.GetName(), blockchainv1alpha1.AdoptAnnotation)
+	}
+
+	if err := controllerutil.SetControllerReference(axelarNode, obj, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Update(ctx, obj); err != nil {
+		return err
+	}
+	r.Log.Info("Adopted existing object", "axelarnode", axelarNode.Name, "kind", kind, "name", obj.GetName())
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, corev1.EventTypeNormal, "Adopted", fmt.Sprintf("Adopted %s %s", kind, obj.GetName()))
+	}
+	return nil
+}
+
+// keepSelector carries the selector of an existing Deployment over to the
+// desired one, since selectors are immutable. The pod template is labelled
+// to match it, so an adopted Deployment keeps selecting its pods.
+func keepSelector(found, deployment *appsv1.Deployment) {
+	if found.Spec.Selector == nil {
+		return
+	}
+	deployment.Spec.Selector = found.Spec.Selector.DeepCopy()
+	if deployment.Spec.Template.Labels == nil {
+		deployment.Spec.Template.Labels = map[string]string{}
+	}
+	for key, value := range found.Spec.Selector.MatchLabels {
+		deployment.Spec.Template.Labels[key] = value
+	}
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// snapshotVolumeMountPath is where the snapshot volume is mounted in the
+// bootstrap container
+const snapshotVolumeMountPath = "/snapshots"
+
+// nodeImage returns the image of the node, pulled from its registry mirror
+func nodeImage(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return airgap.Image(fmt.Sprintf("%s:%s", axelarNode.Spec.Image.Repository, axelarNode.Spec.Image.Tag))
+}
+
+// userReader reads the ConfigMaps and Secrets referenced by users.
+// Reconcilers built without an APIReader, such as renderers, read through
+// their client.
+func (r *AxelarNodeReconciler) userReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// configMapValue reads a key of a ConfigMap in namespace
+func (r *AxelarNodeReconciler) configMapValue(ctx context.Context, namespace string, selector *corev1.ConfigMapKeySelector) ([]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.userReader().Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, configMap); err != nil {
+		return nil, err
+	}
+	if value, ok := configMap.Data[selector.Key]; ok {
+		return []byte(value), nil
+	}
+	if value, ok := configMap.BinaryData[selector.Key]; ok {
+		return value, nil
+	}
+	return nil, fmt.Errorf("ConfigMap %s has no key %s", selector.Name, selector.Key)
+}
+
+// addGenesis mounts the genesis file of spec.genesisFrom next to the node
+// configuration, in place of the download from the genesis URL
+func addGenesis(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	source := axelarNode.Spec.GenesisFrom
+	if source == nil {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "genesis",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: source.Name},
+				Items:                []corev1.KeyToPath{{Key: source.Key, Path: "genesis.json"}},
+			},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      "genesis",
+		MountPath: "/home/axelard/config/genesis.json",
+		SubPath:   "genesis.json",
+		ReadOnly:  true,
+	})
+}
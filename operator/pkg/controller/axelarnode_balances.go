@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// balanceCheckInterval is how often the balances are queried. vald spends
+// little per block, so a few minutes leave time to top an account up.
+const balanceCheckInterval = 5 * time.Minute
+
+// defaultBalanceDenom applies when the spec leaves the denom unset
+const defaultBalanceDenom = "uaxl"
+
+// Names of the accounts the operator knows from the spec
+const (
+	broadcasterAccount = "broadcaster"
+	withdrawAccount    = "withdraw"
+)
+
+// Balances are exported on the operator metrics endpoint
+var (
+	accountBalance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "axelar_node_account_balance",
+		Help: "Balance of an account of the validator, in the smallest unit of the denom",
+	}, []string{"namespace", "name", "account", "address", "denom"})
+	accountBalanceLow = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "axelar_node_account_balance_low",
+		Help: "Whether an account of the validator holds less than its minimum balance",
+	}, []string{"namespace", "name", "account", "address", "denom"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(accountBalance, accountBalanceLow)
+}
+
+// monitoredAccount is an account whose balance is queried, with the minimum
+// below which an alert is sent, or 0 for none
+type monitoredAccount struct {
+	name, address string
+	min           int64
+}
+
+// monitoredAccounts returns the accounts of the validator the operator knows
+// about: the broadcaster of the heartbeats, the withdraw address of the
+// rewards and the accounts of spec.validator.balances
+func monitoredAccounts(axelarNode *blockchainv1alpha1.AxelarNode) (string, []monitoredAccount) {
+	if !isValidatorNode(axelarNode) {
+		return "", nil
+	}
+	validator := axelarNode.Spec.Validator
+	spec := validator.Balances
+	if spec == nil {
+		spec = &blockchainv1alpha1.BalancesSpec{}
+	}
+	denom := spec.Denom
+	if denom == "" {
+		denom = defaultBalanceDenom
+	}
+	var accounts []monitoredAccount
+	if heartbeat := validator.Heartbeat; heartbeat != nil && heartbeat.BroadcasterAddress != "" {
+		accounts = append(accounts, monitoredAccount{broadcasterAccount, heartbeat.BroadcasterAddress,
+			minBalance(spec.MinBroadcasterBalance, defaultMinBroadcasterBalance)})
+	}
+	if rewards := validator.Rewards; rewards != nil && rewards.WithdrawAddress != "" {
+		accounts = append(accounts, monitoredAccount{withdrawAccount, rewards.WithdrawAddress, minBalance(spec.MinWithdrawBalance, 0)})
+	}
+	for _, account := range spec.Accounts {
+		accounts = append(accounts, monitoredAccount{account.Name, account.Address, minBalance(account.MinBalance, 0)})
+	}
+	return denom, accounts
+}
+
+// reconcileBalances exports the balances of the accounts of the validator.
+// An alert is sent when an account drops below its minimum, and again once
+// it is topped up.
+func (r *AxelarNodeReconciler) reconcileBalances(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	denom, accounts := monitoredAccounts(axelarNode)
+	if len(accounts) == 0 {
+		axelarNode.Status.Balances = nil
+		deleteBalanceMetrics(axelarNode.Namespace, axelarNode.Name)
+		return nil
+	}
+	previous := axelarNode.Status.Balances
+	if previous != nil && previous.LastChecked != nil && time.Since(previous.LastChecked.Time) < balanceCheckInterval {
+		return nil
+	}
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	_, api, err := r.statusClients(ctx, axelarNode)
+	if err != nil {
+		log.V(1).Info("Unable to reach the status source", "error", err.Error())
+		return nil
+	}
+	// Query every account before reporting any, so a failed query leaves
+	// the last report in place
+	now := metav1.Now()
+	status := &blockchainv1alpha1.BalancesStatus{LastChecked: &now}
+	for _, account := range accounts {
+		amount, err := api.Balance(ctx, account.address, denom)
+		if err != nil {
+			log.V(1).Info("Unable to query the balance", "account", account.name, "error", err.Error())
+			return nil
+		}
+		status.Accounts = append(status.Accounts, blockchainv1alpha1.AccountBalance{
+			Name:    account.name,
+			Address: account.address,
+			Amount:  amount,
+			Denom:   denom,
+			Low:     account.min > 0 && amount < account.min,
+		})
+	}
+
+	wasLow := map[string]bool{}
+	if previous != nil {
+		for _, balance := range previous.Accounts {
+			wasLow[balance.Name+"/"+balance.Address] = balance.Low
+		}
+	}
+	// Accounts removed from the spec must not linger in the metrics
+	deleteBalanceMetrics(axelarNode.Namespace, axelarNode.Name)
+	for i, balance := range status.Accounts {
+		labels := []string{axelarNode.Namespace, axelarNode.Name, balance.Name, balance.Address, denom}
+		accountBalance.WithLabelValues(labels...).Set(float64(balance.Amount))
+		low := 0.0
+		if balance.Low {
+			low = 1
+		}
+		accountBalanceLow.WithLabelValues(labels...).Set(low)
+
+		switch was := wasLow[balance.Name+"/"+balance.Address]; {
+		case balance.Low && !was:
+			r.notifyBalance(ctx, axelarNode, corev1.EventTypeWarning, "BalanceLow",
+				fmt.Sprintf(":money_with_wings: The %s account %s holds %d%s, below the minimum of %d%s",
+					balance.Name, balance.Address, balance.Amount, denom, accounts[i].min, denom))
+		case !balance.Low && was:
+			r.notifyBalance(ctx, axelarNode, corev1.EventTypeNormal, "BalanceRestored",
+				fmt.Sprintf(":moneybag: The %s account %s holds %d%s again", balance.Name, balance.Address, balance.Amount, denom))
+		}
+	}
+	axelarNode.Status.Balances = status
+	return nil
+}
+
+// deleteBalanceMetrics removes the balances of the node namespace/name from
+// the metrics
+func deleteBalanceMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	accountBalance.DeletePartialMatch(labels)
+	accountBalanceLow.DeletePartialMatch(labels)
+}
+
+// notifyBalance records a balance event and sends it to the alert channels
+func (r *AxelarNodeReconciler) notifyBalance(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, eventType, reason, text string) {
+	r.Log.Info("Balance notification", "axelarnode", axelarNode.Name, "message", text)
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, eventType, reason, text)
+	}
+	if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+		r.Log.Error(err, "Unable to send balance alert", "axelarnode", axelarNode.Name)
+	}
+}
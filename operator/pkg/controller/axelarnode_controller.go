@@ -2,30 +2,85 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/featuregate"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/maintenance"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/nodeclient"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/remote"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tenancy"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/usage"
 )
 
+// configHashAnnotation records the hash of the rendered configuration on the pod template
+const configHashAnnotation = "blockchain.axelar.network/config-hash"
+
 // AxelarNodeReconciler reconciles an AxelarNode object
 type AxelarNodeReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+
+	// Clusters is set in hub mode to materialize nodes in remote clusters
+	Clusters *remote.Clusters
+
+	// ProxyImage is the image of the RPC proxy sidecar
+	ProxyImage string
+
+	// ToolsImage is the image running the snapshot downloader and backup encryption
+	ToolsImage string
+
+	// TLSProxyImage is the image of the TLS terminating proxy sidecar
+	TLSProxyImage string
+
+	// Recorder emits events on nodes
+	Recorder record.EventRecorder
+
+	// Usage reads the usage history of containers for resource
+	// recommendations. Recommendations after OOM kills are still made when nil.
+	Usage *usage.Client
+
+	// Features gates the experimental subsystems. Nil leaves them at their defaults.
+	Features *featuregate.Gate
+
+	// RESTConfig reaches the Kubernetes API for the Exec status source
+	RESTConfig *rest.Config
+
+	// APIReader reads the ConfigMaps and Secrets referenced by users, which
+	// the manager does not cache
+	APIReader client.Reader
 }
 
 // +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarnodes,verbs=get;list;watch;create;update;patch;delete
@@ -33,13 +88,24 @@ type AxelarNodeReconciler struct {
 // +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarnodes/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes;grpcroutes;httproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cilium.io,resources=ciliumnetworkpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-// Reconcile handles AxelarNode reconciliation
+// Reconcile handles AxelarNode reconciliation. The status and metadata
+// changes of a reconcile are written once when it ends, even when it fails.
+// Failures are classified so only transient ones are retried with backoff.
 func (r *AxelarNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("axelarnode", req.NamespacedName)
 
@@ -48,30 +114,76 @@ func (r *AxelarNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	err := r.Get(ctx, req.NamespacedName, axelarNode)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			log.Info("AxelarNode resource not found. Ignoring since object must be deleted")
+			r.nodeGone(req)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to get AxelarNode")
 		return ctrl.Result{}, err
 	}
+	return r.reconcileFetched(ctx, axelarNode)
+}
 
-	// Handle deletion
+// nodeGone releases what the operator keeps in memory for a deleted node
+func (r *AxelarNodeReconciler) nodeGone(req ctrl.Request) {
+	r.Log.WithValues("axelarnode", req.NamespacedName).Info("AxelarNode resource not found. Ignoring since object must be deleted")
+	nodeclient.Forget(req.Namespace, req.Name)
+	deleteBalanceMetrics(req.Namespace, req.Name)
+}
+
+// reconcileFetched reconciles a node read from the cluster
+func (r *AxelarNodeReconciler) reconcileFetched(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (ctrl.Result, error) {
+	log := r.Log.WithValues("axelarnode", client.ObjectKeyFromObject(axelarNode))
+	ctx = audit.WithActor(ctx, axelarNode)
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(axelarNode))
+	ctx = naming.WithInstance(ctx, nodeInstance(axelarNode))
+
+	original := axelarNode.DeepCopy()
+	result, err := r.reconcileNode(ctx, axelarNode)
+	result, err = r.classifyFailure(original, axelarNode, result, err)
+	if writeErr := r.writeNode(ctx, original, axelarNode); writeErr != nil {
+		if err == nil {
+			return ctrl.Result{}, writeErr
+		}
+		log.Error(writeErr, "Failed to write the AxelarNode status")
+	}
+	return result, err
+}
+
+// reconcileNode reconciles the resources of the node and builds its status
+func (r *AxelarNodeReconciler) reconcileNode(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (ctrl.Result, error) {
+	log := r.Log.WithValues("axelarnode", client.ObjectKeyFromObject(axelarNode))
+
+	// Handle deletion. Protected nodes keep running until the protection is removed.
 	if axelarNode.DeletionTimestamp != nil {
-		return r.handleDeletion(ctx, axelarNode)
+		if !r.blockDeletion(axelarNode) {
+			return r.handleDeletion(ctx, axelarNode)
+		}
+		log.Info("Deletion refused, the node is protected")
 	}
 
 	// Add finalizer if not present
-	if !controllerutil.ContainsFinalizer(axelarNode, "axelarnode.blockchain.axelar.network/finalizer") {
-		controllerutil.AddFinalizer(axelarNode, "axelarnode.blockchain.axelar.network/finalizer")
-		return ctrl.Result{}, r.Update(ctx, axelarNode)
+	controllerutil.AddFinalizer(axelarNode, "axelarnode.blockchain.axelar.network/finalizer")
+
+	// Nodes placed in a remote cluster are managed by the agent operator there
+	if axelarNode.Spec.Cluster != nil {
+		return r.reconcileRemote(ctx, axelarNode)
+	}
+
+	// Paused nodes are left as they are until resumed
+	setPausedCondition(axelarNode)
+	if nodePaused(axelarNode) {
+		log.Info("Reconciliation is paused")
+		return ctrl.Result{}, nil
 	}
 
 	// Update status phase
 	if axelarNode.Status.Phase == "" {
 		axelarNode.Status.Phase = "Initializing"
-		if err := r.Status().Update(ctx, axelarNode); err != nil {
-			return ctrl.Result{}, err
-		}
+	}
+
+	// An invalid spec is reported rather than turned into broken manifests
+	if !r.checkNodeSpec(axelarNode) {
+		return ctrl.Result{}, nil
 	}
 
 	// Reconcile resources
@@ -83,6 +195,10 @@ func (r *AxelarNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	if err := r.discoverAdoption(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if err := r.reconcilePVC(ctx, axelarNode); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -91,125 +207,286 @@ func (r *AxelarNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileDebugService(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileP2PService(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileTLS(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// A blue/green switchover manages the Deployments itself while in progress
+	switching, err := r.reconcileSwitchover(ctx, axelarNode)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if switching {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	// So does a backup, restore or resync
+	operating, err := r.reconcileOperation(ctx, axelarNode)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if operating {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	// Without its bootstrap snapshot a new node would sync from genesis
+	if !r.reconcileSnapshot(ctx, axelarNode) {
+		err := r.Get(ctx, types.NamespacedName{Name: deploymentName(axelarNode), Namespace: axelarNode.Namespace}, &appsv1.Deployment{})
+		if errors.IsNotFound(err) {
+			log.Info("Waiting for a bootstrap snapshot")
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	if err := r.reconcileDeployment(ctx, axelarNode); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileNodeAutoscaler(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileSplitSigner(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileStandby(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcilePublicService(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileGatewayRoutes(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileEgressPolicy(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileBackupVerification(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileIntegrityCheck(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileIndexing(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileAddressBookBackup(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileValidatorProfile(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileRewards(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileGovernance(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileVotes(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileHeartbeat(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileBalances(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileKeyShares(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileKeyBackups(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.collectGarbage(ctx, axelarNode); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Update status based on deployment
 	if err := r.updateStatus(ctx, axelarNode); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Follow the snapshot download, restore drills, integrity checks, pending
+	// halts and the sync of the public endpoints closely
+	if snapshotBootstrapping(axelarNode) || verificationRunning(axelarNode) || integrityCheckRunning(axelarNode) || haltPending(axelarNode) || publicEnabled(axelarNode) {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
 	// Schedule next reconciliation
 	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
 }
 
-// handleDeletion handles resource cleanup
+// handleDeletion tears the node down and removes its finalizer once done
 func (r *AxelarNodeReconciler) handleDeletion(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (ctrl.Result, error) {
 	log := r.Log.WithValues("axelarnode", axelarNode.Name)
 
-	// Perform cleanup operations here
-	log.Info("Cleaning up AxelarNode resources")
+	if !controllerutil.ContainsFinalizer(axelarNode, "axelarnode.blockchain.axelar.network/finalizer") {
+		return ctrl.Result{}, nil
+	}
+	ctx = audit.WithReason(ctx, "deletion")
+
+	if axelarNode.Spec.Cluster != nil {
+		if err := r.deleteRemote(ctx, axelarNode); err != nil {
+			log.Error(err, "Failed to delete AxelarNode from remote cluster")
+			return ctrl.Result{}, err
+		}
+	} else {
+		drained, err := r.drainNode(ctx, axelarNode)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !drained {
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	}
+
+	// The pooled clients of the node and their breakers, and its balance
+	// metrics, are released before the node goes, whichever controller sees
+	// it gone
+	nodeclient.Forget(axelarNode.Namespace, axelarNode.Name)
+	deleteBalanceMetrics(axelarNode.Namespace, axelarNode.Name)
 
 	// Remove finalizer
 	controllerutil.RemoveFinalizer(axelarNode, "axelarnode.blockchain.axelar.network/finalizer")
-	return ctrl.Result{}, r.Update(ctx, axelarNode)
+	return ctrl.Result{}, nil
 }
 
-// reconcileConfigMap creates or updates the ConfigMap
+// reconcileConfigMap creates the immutable ConfigMap of the rendered
+// configuration version and prunes the versions beyond the history limit.
+// The versions the node Deployments run are kept until they are rolled.
 func (r *AxelarNodeReconciler) reconcileConfigMap(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
-	configMap := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      axelarNode.Name + "-config",
-			Namespace: axelarNode.Namespace,
-		},
-		Data: r.generateConfigMapData(axelarNode),
+	keep := []string{}
+	for _, name := range []string{deploymentName(axelarNode), childName(axelarNode, "standby")} {
+		deployment := &appsv1.Deployment{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, deployment)
+		if err == nil {
+			keep = append(keep, deployment.Spec.Template.Annotations[configHashAnnotation])
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
 	}
 
-	if err := controllerutil.SetControllerReference(axelarNode, configMap, r.Scheme); err != nil {
-		return err
+	limit := int(axelarNode.Spec.Config.HistoryLimit)
+	if limit == 0 {
+		limit = defaultConfigHistoryLimit
 	}
 
-	found := &corev1.ConfigMap{}
-	err := r.Get(ctx, types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, configMap)
-	} else if err != nil {
+	if pinned := axelarNode.Spec.Config.Version; pinned != "" {
+		exists, err := configVersionExists(ctx, r.Client, axelarNode, pinned)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if r.Recorder != nil {
+				r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "ConfigVersionMissing",
+					fmt.Sprintf("Configuration version %s of spec.config.version is not retained", pinned))
+			}
+			return fmt.Errorf("configuration version %s of spec.config.version is not retained", pinned)
+		}
+		keep = append(keep, pinned)
+	}
+
+	data, err := renderConfig(axelarNode)
+	if err != nil {
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "InvalidConfig", err.Error())
+		}
 		return err
 	}
 
-	// Update if needed
-	found.Data = configMap.Data
-	return r.Update(ctx, found)
+	axelarNode.Status.ConfigVersion = r.nodeConfigVersion(axelarNode)
+	return reconcileConfigVersion(ctx, r.Client, r.Scheme, axelarNode, r.configVersion(axelarNode), data, limit, keep...)
 }
 
-// generateConfigMapData generates configuration data
+// configVersion returns the version of the configuration rendered from the
+// spec. Peers refreshed from the registry only make a new version when the
+// node restarts for them.
+func (r *AxelarNodeReconciler) configVersion(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	data := r.generateConfigMapData(withoutRegistryPeers(axelarNode))
+	if refreshed := peersRefreshed(axelarNode); refreshed != "" {
+		data[peersRefreshedAnnotation] = refreshed
+	}
+	return configHash(data)
+}
+
+// nodeConfigVersion returns the configuration version the node runs: the
+// version pinned in the spec, or the rendered one
+func (r *AxelarNodeReconciler) nodeConfigVersion(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if pinned := axelarNode.Spec.Config.Version; pinned != "" {
+		return pinned
+	}
+	return r.configVersion(axelarNode)
+}
+
+// generateConfigMapData generates configuration data. Rendering errors are
+// reported by reconcileConfigMap, before any ConfigMap is written.
 func (r *AxelarNodeReconciler) generateConfigMapData(axelarNode *blockchainv1alpha1.AxelarNode) map[string]string {
-	chainId := "axelar-testnet-lisbon-3"
-	if axelarNode.Spec.Network == "mainnet" {
-		chainId = "axelar-dojo-1"
-	}
-
-	return map[string]string{
-		"app.toml": fmt.Sprintf(`
-# Axelar Node Configuration
-minimum-gas-prices = "0.007uaxl"
-pruning = "default"
-halt-height = 0
-
-[telemetry]
-enabled = %t
-prometheus-retention-time = 60
-
-[api]
-enable = %t
-address = "tcp://0.0.0.0:%d"
-
-[grpc]
-enable = true
-address = "0.0.0.0:9090"
-`, axelarNode.Spec.Monitoring.Enabled, axelarNode.Spec.Networking.API.Enabled, axelarNode.Spec.Networking.API.Port),
-
-		"config.toml": fmt.Sprintf(`
-# Tendermint Configuration
-moniker = "%s"
-fast_sync = true
-db_backend = "goleveldb"
-log_level = "info"
-log_format = "json"
-
-[rpc]
-laddr = "tcp://0.0.0.0:%d"
-cors_allowed_origins = []
-unsafe = false
-
-[p2p]
-laddr = "tcp://0.0.0.0:%d"
-external_address = "%s"
-persistent_peers = "%s"
-seeds = "%s"
-max_num_inbound_peers = 40
-max_num_outbound_peers = 10
-
-[instrumentation]
-prometheus = %t
-prometheus_listen_addr = ":%d"
-`, axelarNode.Spec.Moniker, axelarNode.Spec.Networking.RPC.Port, 
-   axelarNode.Spec.Networking.P2P.Port, axelarNode.Spec.Networking.P2P.ExternalAddress,
-   joinStrings(axelarNode.Spec.Networking.P2P.PersistentPeers), 
-   joinStrings(axelarNode.Spec.Networking.P2P.Seeds),
-   axelarNode.Spec.Monitoring.Enabled, axelarNode.Spec.Monitoring.Prometheus.Port),
-
-		"chain-id": chainId,
-		"network":  axelarNode.Spec.Network,
+	data, err := renderConfig(axelarNode)
+	if err != nil {
+		return map[string]string{}
 	}
+	return data
 }
 
 // reconcileSecret creates or updates secrets
 func (r *AxelarNodeReconciler) reconcileSecret(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	// A Secret named in the spec is provided by the user, for example through External Secrets
+	if axelarNode.Spec.Security.SecretManagement.SecretName != "" {
+		return nil
+	}
+
+	secret := r.createSecret(axelarNode)
+	if err := controllerutil.SetControllerReference(axelarNode, secret, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, secret)
+	} else if err != nil {
+		return err
+	}
+
+	// Existing passwords are kept; the tofnd password follows the validator
+	if !mergeSecretData(found, secret.Data) {
+		return nil
+	}
+	return r.Update(ctx, found)
+}
+
+// createSecret creates the passwords secret object
+func (r *AxelarNodeReconciler) createSecret(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Secret {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      axelarNode.Name + "-secrets",
+			Name:      childName(axelarNode, "secrets"),
 			Namespace: axelarNode.Namespace,
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -218,40 +495,29 @@ func (r *AxelarNodeReconciler) reconcileSecret(ctx context.Context, axelarNode *
 		},
 	}
 
-	if axelarNode.Spec.Validator != nil && axelarNode.Spec.Validator.Enabled {
+	if isValidatorNode(axelarNode) {
 		secret.Data["tofnd-password"] = []byte("default-tofnd-password-change-me")
 	}
-
-	if err := controllerutil.SetControllerReference(axelarNode, secret, r.Scheme); err != nil {
-		return err
-	}
-
-	found := &corev1.Secret{}
-	err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, secret)
-	}
-	return err
+	setManagedKeys(secret, map[string]bool{"keyring-password": true, "tofnd-password": isValidatorNode(axelarNode)})
+	return secret
 }
 
 // reconcilePVC creates persistent volume claims
 func (r *AxelarNodeReconciler) reconcilePVC(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
 	// Main data PVC
-	pvc := r.createPVC(axelarNode, "data", axelarNode.Spec.Storage.Size)
-	if err := r.createOrUpdatePVC(ctx, pvc); err != nil {
+	if err := r.reconcileDataClaim(ctx, axelarNode, activeSlot(axelarNode)); err != nil {
 		return err
 	}
 
-	// Shared data PVC
-	sharedPVC := r.createPVC(axelarNode, "shared", "10Gi")
-	return r.createOrUpdatePVC(ctx, sharedPVC)
+	// Shared and additional PVCs
+	return r.reconcileVolumes(ctx, axelarNode)
 }
 
 // createPVC creates a PVC object
 func (r *AxelarNodeReconciler) createPVC(axelarNode *blockchainv1alpha1.AxelarNode, suffix, size string) *corev1.PersistentVolumeClaim {
 	pvc := &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%s", axelarNode.Name, suffix),
+			Name:      childName(axelarNode, suffix),
 			Namespace: axelarNode.Namespace,
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
@@ -272,11 +538,39 @@ func (r *AxelarNodeReconciler) createPVC(axelarNode *blockchainv1alpha1.AxelarNo
 	return pvc
 }
 
+// reconcileDataClaim creates the PVC of a data volume slot, or checks that
+// the existing claim of the slot is there. Existing PVCs are adopted when the
+// node asks for it.
+func (r *AxelarNodeReconciler) reconcileDataClaim(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, slot string) error {
+	name := dataClaimName(axelarNode, slot)
+	found := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if name == axelarNode.Spec.Storage.ExistingClaim {
+			return fmt.Errorf("existing claim %s of node %s not found", name, axelarNode.Name)
+		}
+		pvc := r.createPVC(axelarNode, dataVolumeSuffix(slot), axelarNode.Spec.Storage.Size)
+		if err := r.checkStorageClass(ctx, pvc); err != nil {
+			return err
+		}
+		return r.Create(ctx, pvc)
+	} else if err != nil {
+		return err
+	}
+	if adoptionRequested(axelarNode) {
+		return r.adoptObject(ctx, axelarNode, found, "PersistentVolumeClaim")
+	}
+	return nil
+}
+
 // createOrUpdatePVC creates or updates a PVC
 func (r *AxelarNodeReconciler) createOrUpdatePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim) error {
 	found := &corev1.PersistentVolumeClaim{}
 	err := r.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
+		if err := r.checkStorageClass(ctx, pvc); err != nil {
+			return err
+		}
 		return r.Create(ctx, pvc)
 	}
 	return err
@@ -284,25 +578,56 @@ func (r *AxelarNodeReconciler) createOrUpdatePVC(ctx context.Context, pvc *corev
 
 // reconcileService creates or updates the service
 func (r *AxelarNodeReconciler) reconcileService(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	service := r.createService(axelarNode)
+
+	if err := controllerutil.SetControllerReference(axelarNode, service, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if service.Spec.Selector, err = r.deploymentSelector(ctx, axelarNode, deploymentName(axelarNode), componentNode); err != nil {
+			return err
+		}
+		return r.Create(ctx, service)
+	} else if err != nil {
+		return err
+	}
+	if err := r.adoptObject(ctx, axelarNode, found, "Service"); err != nil {
+		return err
+	}
+
+	// Update service
+	found.Spec.Ports = service.Spec.Ports
+	applyIPFamilies(axelarNode.Spec.Networking, &found.Spec)
+	found.Annotations = service.Annotations
+	mergeLabels(found, service.Labels)
+	return r.Update(ctx, found)
+}
+
+// createService creates the node service object
+func (r *AxelarNodeReconciler) createService(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Service {
+	rpcTarget, prometheusTarget := axelarNode.Spec.Networking.RPC.Port, axelarNode.Spec.Monitoring.Prometheus.Port
+	if nodeAutoscaled(axelarNode) {
+		rpcTarget, prometheusTarget = rpcProxyPort, rpcProxyMetricsPort
+	}
+
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      axelarNode.Name + "-service",
+			Name:      serviceName(axelarNode),
 			Namespace: axelarNode.Namespace,
-			Annotations: map[string]string{
-				"prometheus.io/scrape": "true",
-				"prometheus.io/port":   fmt.Sprintf("%d", axelarNode.Spec.Monitoring.Prometheus.Port),
-				"prometheus.io/path":   axelarNode.Spec.Monitoring.Prometheus.Path,
-			},
+			Labels:    nodeLabels(axelarNode, componentNode),
+			Annotations: prometheusAnnotations(axelarNode.Spec.Monitoring, nodeAutoscaled(axelarNode),
+				axelarNode.Spec.Monitoring.Prometheus.Port),
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: map[string]string{
-				"app": axelarNode.Name,
-			},
+			Selector: podSelector(axelarNode, componentNode),
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "rpc",
 					Port:       axelarNode.Spec.Networking.RPC.Port,
-					TargetPort: intstr.FromInt(int(axelarNode.Spec.Networking.RPC.Port)),
+					TargetPort: intstr.FromInt(int(rpcTarget)),
 				},
 				{
 					Name:       "p2p",
@@ -314,86 +639,177 @@ func (r *AxelarNodeReconciler) reconcileService(ctx context.Context, axelarNode
 					Port:       axelarNode.Spec.Networking.API.Port,
 					TargetPort: intstr.FromInt(int(axelarNode.Spec.Networking.API.Port)),
 				},
-				{
-					Name:       "prometheus",
-					Port:       axelarNode.Spec.Monitoring.Prometheus.Port,
-					TargetPort: intstr.FromInt(int(axelarNode.Spec.Monitoring.Prometheus.Port)),
-				},
 			},
 		},
 	}
-
-	if err := controllerutil.SetControllerReference(axelarNode, service, r.Scheme); err != nil {
-		return err
+	if metricsScraped(axelarNode.Spec.Monitoring, nodeAutoscaled(axelarNode)) {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:       "prometheus",
+			Port:       axelarNode.Spec.Monitoring.Prometheus.Port,
+			TargetPort: intstr.FromInt(int(prometheusTarget)),
+		})
 	}
-
-	found := &corev1.Service{}
-	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, service)
-	} else if err != nil {
-		return err
+	service.Spec.Ports = append(service.Spec.Ports, gatewayServicePorts(axelarNode)...)
+	service.Spec.Ports = append(service.Spec.Ports, tlsServicePorts(axelarNode)...)
+	service.Spec.Ports = append(service.Spec.Ports, gatewayAPIServicePorts(axelarNode)...)
+
+	// A member drained on scale-down stops accepting new peers
+	if _, draining := axelarNode.Annotations[blockchainv1alpha1.DrainAnnotation]; draining {
+		ports := service.Spec.Ports[:0]
+		for _, port := range service.Spec.Ports {
+			if port.Name != "p2p" {
+				ports = append(ports, port)
+			}
+		}
+		service.Spec.Ports = ports
 	}
-
-	// Update service
-	found.Spec.Ports = service.Spec.Ports
-	found.Annotations = service.Annotations
-	return r.Update(ctx, found)
+	applyIPFamilies(axelarNode.Spec.Networking, &service.Spec)
+	return service
 }
 
 // reconcileDeployment creates or updates the deployment
 func (r *AxelarNodeReconciler) reconcileDeployment(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	r.checkValidatorGuardrails(axelarNode)
 	deployment := r.createDeployment(axelarNode)
 
 	if err := controllerutil.SetControllerReference(axelarNode, deployment, r.Scheme); err != nil {
 		return err
 	}
 
+	dryRun := nodeDryRun(axelarNode)
+	if !dryRun {
+		axelarNode.Status.DryRun = nil
+	}
+
 	found := &appsv1.Deployment{}
 	err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
+		if dryRun {
+			axelarNode.Status.ConfigVersion = ""
+			return r.previewChanges(ctx, axelarNode, nil, deployment)
+		}
 		return r.Create(ctx, deployment)
 	} else if err != nil {
 		return err
 	}
+	if !dryRun {
+		if err := r.adoptObject(ctx, axelarNode, found, "Deployment"); err != nil {
+			return err
+		}
+	}
+	if mergeLabels(found, deployment.Labels) {
+		if err := r.Update(ctx, found); err != nil {
+			return err
+		}
+	}
+	keepSelector(found, deployment)
+
+	// The HPA owns the replica count of autoscaled nodes
+	if nodeAutoscaled(axelarNode) {
+		deployment.Spec.Replicas = found.Spec.Replicas
+	}
 
-	// Update deployment if needed
+	// In dry-run, changes are previewed in the status instead of applied
+	if dryRun {
+		axelarNode.Status.ConfigVersion = found.Spec.Template.Annotations[configHashAnnotation]
+		if err := r.previewChanges(ctx, axelarNode, found, deployment); err != nil {
+			return err
+		}
+	}
+
+	// Update deployment if needed. Arming changes are never deferred or held
+	// back by a dry-run since they fence the signer during failovers.
 	if !r.deploymentEqual(found, deployment) {
+		armingChanged := found.Spec.Template.Annotations[armedAnnotation] != deployment.Spec.Template.Annotations[armedAnnotation]
+		if dryRun && !armingChanged {
+			return nil
+		}
+		allowed, err := r.rolloutAllowed(ctx, axelarNode)
+		if err != nil || (!allowed && !armingChanged) {
+			return err
+		}
+		from := nodeImageTag(found)
 		found.Spec = deployment.Spec
-		return r.Update(ctx, found)
+		if err := r.Update(ctx, found); err != nil {
+			return err
+		}
+		r.recordUpgrade(axelarNode, from, nodeImageTag(found))
 	}
 
 	return nil
 }
 
+// rolloutAllowed checks the maintenance window before a disruptive Deployment
+// change. Outside the window the change is deferred unless the emergency
+// rollout annotation is set, which is consumed once the change is applied.
+func (r *AxelarNodeReconciler) rolloutAllowed(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	window := axelarNode.Spec.Upgrade.MaintenanceWindow
+	if window == nil {
+		return true, nil
+	}
+
+	deferred := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionRolloutDeferred,
+		Status:             metav1.ConditionFalse,
+		Reason:             "InsideMaintenanceWindow",
+		Message:            "Changes are applied immediately",
+		ObservedGeneration: axelarNode.Generation,
+	}
+
+	open, next, err := maintenance.Open(window, time.Now())
+	if err != nil {
+		deferred.Status = metav1.ConditionTrue
+		deferred.Reason = "InvalidMaintenanceWindow"
+		deferred.Message = err.Error()
+		meta.SetStatusCondition(&axelarNode.Status.Conditions, deferred)
+		return false, nil
+	}
+
+	if !open {
+		if _, emergency := axelarNode.Annotations[blockchainv1alpha1.EmergencyRolloutAnnotation]; !emergency {
+			deferred.Status = metav1.ConditionTrue
+			deferred.Reason = "OutsideMaintenanceWindow"
+			deferred.Message = fmt.Sprintf("Changes deferred until the next maintenance window at %s", next.Format(time.RFC3339))
+			meta.SetStatusCondition(&axelarNode.Status.Conditions, deferred)
+			return false, nil
+		}
+
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Applying emergency rollout outside maintenance window")
+		deferred.Reason = "EmergencyRollout"
+		deferred.Message = "Changes applied outside the maintenance window"
+		delete(axelarNode.Annotations, blockchainv1alpha1.EmergencyRolloutAnnotation)
+	}
+
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, deferred)
+	return true, nil
+}
+
 // createDeployment creates a deployment object
 func (r *AxelarNodeReconciler) createDeployment(axelarNode *blockchainv1alpha1.AxelarNode) *appsv1.Deployment {
-	replicas := int32(1)
+	replicas := desiredReplicas(axelarNode)
+
+	prometheusPort := axelarNode.Spec.Monitoring.Prometheus.Port
+	if nodeAutoscaled(axelarNode) {
+		prometheusPort = rpcProxyMetricsPort
+	}
 	
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      axelarNode.Name,
+			Name:      deploymentName(axelarNode),
 			Namespace: axelarNode.Namespace,
+			Labels:    nodeLabels(axelarNode, componentNode),
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
-			Strategy: appsv1.DeploymentStrategy{
-				Type: appsv1.RecreateDeploymentStrategyType,
-			},
+			Strategy: desiredStrategy(axelarNode),
 			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app": axelarNode.Name,
-				},
+				MatchLabels: podSelector(axelarNode, componentNode),
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app": axelarNode.Name,
-					},
+					Labels: nodeLabels(axelarNode, componentNode),
 					Annotations: map[string]string{
-						"prometheus.io/scrape": "true",
-						"prometheus.io/port":   fmt.Sprintf("%d", axelarNode.Spec.Monitoring.Prometheus.Port),
-						"prometheus.io/path":   axelarNode.Spec.Monitoring.Prometheus.Path,
+						configHashAnnotation: r.nodeConfigVersion(axelarNode),
 					},
 				},
 				Spec: r.createPodSpec(axelarNode),
@@ -401,15 +817,100 @@ func (r *AxelarNodeReconciler) createDeployment(axelarNode *blockchainv1alpha1.A
 		},
 	}
 
+	for key, value := range prometheusAnnotations(axelarNode.Spec.Monitoring, nodeAutoscaled(axelarNode), prometheusPort) {
+		deployment.Spec.Template.Annotations[key] = value
+	}
+	if signer := signerSpec(axelarNode); signer != nil {
+		deployment.Spec.Template.Annotations[armedAnnotation] = strconv.FormatBool(signer.Armed)
+	}
+	if snap := bootstrapSnapshot(axelarNode); snap != nil {
+		deployment.Spec.Template.Annotations[snapshotAnnotation] = snap.Checksum
+	}
+	if proxy := proxyHash(axelarNode); proxy != "" {
+		deployment.Spec.Template.Annotations[proxyAnnotation] = proxy
+	}
+	if psql := psqlIndexing(axelarNode); psql != nil {
+		deployment.Spec.Template.Annotations[indexerAnnotation] = psqlSchemaHash(psql)
+	}
+	if refreshed := peersRefreshed(axelarNode); refreshed != "" {
+		deployment.Spec.Template.Annotations[peersRefreshedAnnotation] = refreshed
+	}
+	// The pods of a scaled network's members are selected by its scale subresource
+	if network := axelarNode.Labels[blockchainv1alpha1.ScaledMemberLabel]; network != "" {
+		deployment.Spec.Template.Labels[blockchainv1alpha1.ScaledMemberLabel] = network
+	}
+	if tenant := tenancy.Of(axelarNode); tenant != "" {
+		deployment.Spec.Template.Labels[blockchainv1alpha1.TenantLabel] = tenant
+	}
+	setTemplateHash(deployment)
+
 	return deployment
 }
 
+// isValidatorNode reports whether the node signs blocks
+func isValidatorNode(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Spec.Validator != nil && axelarNode.Spec.Validator.Enabled
+}
+
+// desiredReplicas returns the replica count of the node Deployment. Validators
+// run exactly one replica, since a second would double-sign.
+func desiredReplicas(axelarNode *blockchainv1alpha1.AxelarNode) int32 {
+	if isValidatorNode(axelarNode) || axelarNode.Spec.Replicas == nil {
+		return 1
+	}
+	return *axelarNode.Spec.Replicas
+}
+
+// desiredStrategy returns the Deployment strategy of the node. Validators are
+// always recreated, so the old pod stops signing before the new one starts.
+func desiredStrategy(axelarNode *blockchainv1alpha1.AxelarNode) appsv1.DeploymentStrategy {
+	if isValidatorNode(axelarNode) || axelarNode.Spec.Strategy == nil {
+		return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	}
+	return *axelarNode.Spec.Strategy.DeepCopy()
+}
+
+// nodeScaledOut reports whether the node runs more than one replica
+func nodeScaledOut(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return nodeAutoscaled(axelarNode) || desiredReplicas(axelarNode) > 1
+}
+
+// checkValidatorGuardrails reports replica and strategy settings refused on validators
+func (r *AxelarNodeReconciler) checkValidatorGuardrails(axelarNode *blockchainv1alpha1.AxelarNode) {
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionSpecRejected,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Accepted",
+		Message:            "The spec is applied as requested",
+		ObservedGeneration: axelarNode.Generation,
+	}
+
+	if isValidatorNode(axelarNode) {
+		if axelarNode.Spec.Replicas != nil && *axelarNode.Spec.Replicas > 1 {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "ValidatorReplicas"
+			condition.Message = fmt.Sprintf("Refusing %d replicas for a validator, running 1 to avoid double-signing", *axelarNode.Spec.Replicas)
+		} else if axelarNode.Spec.Strategy != nil && axelarNode.Spec.Strategy.Type == appsv1.RollingUpdateDeploymentStrategyType {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "ValidatorStrategy"
+			condition.Message = "Refusing RollingUpdate for a validator, using Recreate to avoid double-signing"
+		}
+	}
+
+	if condition.Status == metav1.ConditionTrue {
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Spec refused by validator guardrail", "reason", condition.Reason)
+	}
+	if condition.Status == metav1.ConditionTrue || meta.FindStatusCondition(axelarNode.Status.Conditions, condition.Type) != nil {
+		meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	}
+}
+
 // createPodSpec creates the pod specification
 func (r *AxelarNodeReconciler) createPodSpec(axelarNode *blockchainv1alpha1.AxelarNode) corev1.PodSpec {
 	containers := []corev1.Container{
 		{
 			Name:  "axelar-node",
-			Image: fmt.Sprintf("%s:%s", axelarNode.Spec.Image.Repository, axelarNode.Spec.Image.Tag),
+			Image: nodeImage(axelarNode),
 			ImagePullPolicy: axelarNode.Spec.Image.PullPolicy,
 			Command: []string{"startNodeProc"},
 			Env: []corev1.EnvVar{
@@ -421,7 +922,7 @@ func (r *AxelarNodeReconciler) createPodSpec(axelarNode *blockchainv1alpha1.Axel
 					ValueFrom: &corev1.EnvVarSource{
 						SecretKeyRef: &corev1.SecretKeySelector{
 							LocalObjectReference: corev1.LocalObjectReference{
-								Name: axelarNode.Name + "-secrets",
+								Name: passwordsSecretName(axelarNode),
 							},
 							Key: "keyring-password",
 						},
@@ -432,7 +933,6 @@ func (r *AxelarNodeReconciler) createPodSpec(axelarNode *blockchainv1alpha1.Axel
 				{Name: "rpc", ContainerPort: axelarNode.Spec.Networking.RPC.Port},
 				{Name: "p2p", ContainerPort: axelarNode.Spec.Networking.P2P.Port},
 				{Name: "api", ContainerPort: axelarNode.Spec.Networking.API.Port},
-				{Name: "prometheus", ContainerPort: axelarNode.Spec.Monitoring.Prometheus.Port},
 			},
 			Resources: axelarNode.Spec.Resources,
 			VolumeMounts: []corev1.VolumeMount{
@@ -440,42 +940,26 @@ func (r *AxelarNodeReconciler) createPodSpec(axelarNode *blockchainv1alpha1.Axel
 				{Name: "shared", MountPath: "/home/axelard/shared"},
 				{Name: "config", MountPath: "/home/axelard/config"},
 			},
-			LivenessProbe: &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: "/health",
-						Port: intstr.FromInt(int(axelarNode.Spec.Monitoring.Prometheus.Port)),
-					},
-				},
-				InitialDelaySeconds: 120,
-				PeriodSeconds:       30,
-			},
-			ReadinessProbe: &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: "/health",
-						Port: intstr.FromInt(int(axelarNode.Spec.Monitoring.Prometheus.Port)),
-					},
-				},
-				InitialDelaySeconds: 60,
-				PeriodSeconds:       10,
-			},
+			LivenessProbe:  livenessProbe(axelarNode),
+			ReadinessProbe: readinessProbe(axelarNode),
 		},
 	}
-
-	// Add validator containers if enabled
-	if axelarNode.Spec.Validator != nil && axelarNode.Spec.Validator.Enabled {
-		containers = append(containers, r.createValidatorContainers(axelarNode)...)
+	if axelarNode.Spec.Monitoring.Enabled {
+		containers[0].Ports = append(containers[0].Ports,
+			corev1.ContainerPort{Name: "prometheus", ContainerPort: axelarNode.Spec.Monitoring.Prometheus.Port})
 	}
 
-	return corev1.PodSpec{
+	// Add validator containers if enabled, unless they run on their own
+	containers = append(containers, r.sidecarContainers(axelarNode)...)
+
+	podSpec := corev1.PodSpec{
 		Containers: containers,
 		Volumes: []corev1.Volume{
 			{
 				Name: "data",
 				VolumeSource: corev1.VolumeSource{
 					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: axelarNode.Name + "-data",
+						ClaimName: dataClaimName(axelarNode, activeSlot(axelarNode)),
 					},
 				},
 			},
@@ -483,7 +967,7 @@ func (r *AxelarNodeReconciler) createPodSpec(axelarNode *blockchainv1alpha1.Axel
 				Name: "shared",
 				VolumeSource: corev1.VolumeSource{
 					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: axelarNode.Name + "-shared",
+						ClaimName: childName(axelarNode, "shared"),
 					},
 				},
 			},
@@ -492,7 +976,7 @@ func (r *AxelarNodeReconciler) createPodSpec(axelarNode *blockchainv1alpha1.Axel
 				VolumeSource: corev1.VolumeSource{
 					ConfigMap: &corev1.ConfigMapVolumeSource{
 						LocalObjectReference: corev1.LocalObjectReference{
-							Name: axelarNode.Name + "-config",
+							Name: configMapName(axelarNode.Name, r.nodeConfigVersion(axelarNode)),
 						},
 					},
 				},
@@ -500,6 +984,31 @@ func (r *AxelarNodeReconciler) createPodSpec(axelarNode *blockchainv1alpha1.Axel
 		},
 		SecurityContext: axelarNode.Spec.Security.PodSecurityContext,
 	}
+
+	if axelarNode.Spec.Zone != "" {
+		podSpec.NodeSelector = map[string]string{"topology.kubernetes.io/zone": axelarNode.Spec.Zone}
+	}
+
+	addVolumes(axelarNode, &podSpec)
+	addValdRecovery(axelarNode, &podSpec)
+	addKeyringBackend(axelarNode, &podSpec)
+	addPasswordFiles(axelarNode, &podSpec)
+	addGracefulShutdown(axelarNode, &podSpec)
+	addGenesis(axelarNode, &podSpec)
+	r.addSnapshotBootstrap(axelarNode, &podSpec)
+	r.addAddressBook(axelarNode, &podSpec)
+	addPprofPort(axelarNode, &podSpec)
+	r.addGateway(axelarNode, &podSpec)
+	r.addTLSProxy(axelarNode, &podSpec)
+	r.addSignerGuard(axelarNode, &podSpec)
+	addProxy(axelarNode, &podSpec)
+	addIndexer(axelarNode, &podSpec)
+
+	if nodeScaledOut(axelarNode) {
+		r.scaleOutPodSpec(axelarNode, &podSpec)
+	}
+
+	return podSpec
 }
 
 // createValidatorContainers creates validator-specific containers
@@ -507,8 +1016,8 @@ func (r *AxelarNodeReconciler) createValidatorContainers(axelarNode *blockchainv
 	return []corev1.Container{
 		{
 			Name:  "vald",
-			Image: fmt.Sprintf("%s:%s", axelarNode.Spec.Image.Repository, axelarNode.Spec.Image.Tag),
-			Command: []string{"sh", "-c", "sleep 60 && exec vald-start"},
+			Image: nodeImage(axelarNode),
+			Command: []string{"sh", "-c", valdStartScript(axelarNode)},
 			Env: []corev1.EnvVar{
 				{Name: "HOME", Value: "/home/axelard"},
 				{
@@ -516,7 +1025,7 @@ func (r *AxelarNodeReconciler) createValidatorContainers(axelarNode *blockchainv
 					ValueFrom: &corev1.EnvVarSource{
 						SecretKeyRef: &corev1.SecretKeySelector{
 							LocalObjectReference: corev1.LocalObjectReference{
-								Name: axelarNode.Name + "-secrets",
+								Name: passwordsSecretName(axelarNode),
 							},
 							Key: "keyring-password",
 						},
@@ -527,10 +1036,12 @@ func (r *AxelarNodeReconciler) createValidatorContainers(axelarNode *blockchainv
 				{Name: "data", MountPath: "/home/axelard/.axelar"},
 				{Name: "shared", MountPath: "/home/axelard/shared"},
 			},
+			LivenessProbe:  valdLivenessProbe(axelarNode),
+			ReadinessProbe: valdReadinessProbe(axelarNode),
 		},
 		{
 			Name:  "tofnd",
-			Image: "axelarnet/tofnd:v0.10.1",
+			Image: tofndImage(axelarNode),
 			Command: []string{"tofnd"},
 			Args: []string{
 				"-m", "/home/axelard/shared/tofnd.txt",
@@ -542,7 +1053,7 @@ func (r *AxelarNodeReconciler) createValidatorContainers(axelarNode *blockchainv
 					ValueFrom: &corev1.EnvVarSource{
 						SecretKeyRef: &corev1.SecretKeySelector{
 							LocalObjectReference: corev1.LocalObjectReference{
-								Name: axelarNode.Name + "-secrets",
+								Name: passwordsSecretName(axelarNode),
 							},
 							Key: "tofnd-password",
 						},
@@ -550,20 +1061,22 @@ func (r *AxelarNodeReconciler) createValidatorContainers(axelarNode *blockchainv
 				},
 			},
 			Ports: []corev1.ContainerPort{
-				{Name: "tofnd", ContainerPort: 50051},
+				{Name: "tofnd", ContainerPort: tofndPort},
 			},
 			VolumeMounts: []corev1.VolumeMount{
 				{Name: "shared", MountPath: "/home/axelard/shared"},
 			},
+			LivenessProbe:  tofndLivenessProbe(validatorProbes(axelarNode).Tofnd),
+			ReadinessProbe: tofndReadinessProbe(validatorProbes(axelarNode).Tofnd),
 		},
 	}
 }
 
-// updateStatus updates the AxelarNode status
+// updateStatus builds the AxelarNode status from the Deployment and the node
 func (r *AxelarNodeReconciler) updateStatus(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
 	// Get deployment status
 	deployment := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{Name: axelarNode.Name, Namespace: axelarNode.Namespace}, deployment)
+	err := r.Get(ctx, types.NamespacedName{Name: deploymentName(axelarNode), Namespace: axelarNode.Namespace}, deployment)
 	if err != nil {
 		return err
 	}
@@ -577,27 +1090,221 @@ func (r *AxelarNodeReconciler) updateStatus(ctx context.Context, axelarNode *blo
 		axelarNode.Status.Phase = "Pending"
 	}
 
-	// TODO: Get actual metrics from the node
-	axelarNode.Status.SyncInfo = blockchainv1alpha1.SyncInfo{
-		CurrentHeight: 12345,
-		LatestHeight:  12345,
-		CatchingUp:    false,
-		LastSyncTime:  &metav1.Time{Time: time.Now()},
+	// Query the node RPC for sync and peer information
+	r.collectNodeStatus(ctx, axelarNode)
+	r.reportSnapshotProgress(ctx, axelarNode)
+	if err := r.reportValidatorHealth(ctx, axelarNode); err != nil {
+		return err
+	}
+	if err := r.reportVersions(ctx, axelarNode); err != nil {
+		return err
+	}
+	if err := r.reportPodHealth(ctx, axelarNode); err != nil {
+		return err
+	}
+	r.trackUpgrade(ctx, axelarNode, deployment)
+	if err := r.reportHalt(ctx, axelarNode); err != nil {
+		return err
+	}
+	r.remediatePeers(ctx, axelarNode)
+	r.trackSLO(ctx, axelarNode)
+
+	synced := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionSynced,
+		Status:             metav1.ConditionFalse,
+		Reason:             "CatchingUp",
+		Message:            "Node is catching up with the network",
+		ObservedGeneration: axelarNode.Generation,
+	}
+	syncInfo := axelarNode.Status.SyncInfo
+	switch {
+	case axelarNode.Status.Phase != "Running":
+		synced.Reason = "NotRunning"
+		synced.Message = "Node is not running"
+	case syncInfo.CatchingUp:
+	case syncInfo.SyncLag > syncLagThreshold:
+		synced.Reason = "Behind"
+		synced.Message = fmt.Sprintf("Behind by %d blocks at height %d", syncInfo.SyncLag, syncInfo.CurrentHeight)
+	case len(referenceRPCs(axelarNode)) > 0 && syncInfo.HeadTime == nil:
+		synced.Status = metav1.ConditionUnknown
+		synced.Reason = "ReferenceUnavailable"
+		synced.Message = fmt.Sprintf("No reference RPC answered, the chain head is unknown at height %d", syncInfo.CurrentHeight)
+	default:
+		synced.Status = metav1.ConditionTrue
+		synced.Reason = "Synced"
+		synced.Message = fmt.Sprintf("Node is synced at height %d", syncInfo.CurrentHeight)
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, synced)
+	return nil
+}
+
+// collectNodeStatus fills sync and network information from the node RPC
+func (r *AxelarNodeReconciler) collectNodeStatus(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) {
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	rpc, _, err := r.statusClients(ctx, axelarNode)
+	if err != nil {
+		log.V(1).Info("Unable to reach the status source", "error", err.Error())
+		axelarNode.Status.SyncInfo.CatchingUp = true
+		axelarNode.Status.SyncInfo.HeadTime = nil
+		return
 	}
 
-	axelarNode.Status.NetworkInfo = blockchainv1alpha1.NetworkInfo{
-		Peers:   10,
-		NodeID:  "mock-node-id",
-		Network: axelarNode.Spec.Network,
+	status, err := rpc.Status(ctx)
+	if err != nil {
+		log.V(1).Info("Unable to query node status", "error", err.Error())
+		axelarNode.Status.SyncInfo.CatchingUp = true
+		axelarNode.Status.SyncInfo.HeadTime = nil
+		return
+	}
+
+	axelarNode.Status.SyncInfo = blockchainv1alpha1.SyncInfo{
+		CurrentHeight: status.SyncInfo.Height(),
+		LatestHeight:  status.SyncInfo.Height(),
+		CatchingUp:    status.SyncInfo.CatchingUp,
+		LastSyncTime:  &metav1.Time{Time: status.SyncInfo.LatestBlockTime},
+	}
+	r.reportSyncLag(ctx, axelarNode)
+	axelarNode.Status.NetworkInfo.NodeID = status.NodeInfo.ID
+	axelarNode.Status.NetworkInfo.Network = axelarNode.Spec.Network
+	axelarNode.Status.NetworkInfo.ExternalAddress = p2pExternalAddress(axelarNode)
+
+	if info, err := rpc.ABCIInfo(ctx); err != nil {
+		log.V(1).Info("Unable to query node version", "error", err.Error())
+	} else if info.Response.Version != "" {
+		runningVersions(axelarNode).Axelard = info.Response.Version
 	}
 
-	return r.Status().Update(ctx, axelarNode)
+	netInfo, err := rpc.NetInfo(ctx)
+	if err != nil {
+		log.V(1).Info("Unable to query node peers", "error", err.Error())
+		return
+	}
+	axelarNode.Status.NetworkInfo.Peers = netInfo.Peers()
 }
 
-// deploymentEqual compares two deployments
+// deploymentEqual compares two deployments. The pod templates are compared by
+// the hash stamped on the desired one, since the API server fills in defaults
+// the desired template lacks.
 func (r *AxelarNodeReconciler) deploymentEqual(a, b *appsv1.Deployment) bool {
-	// Simplified comparison - in production, you'd want more thorough comparison
-	return a.Spec.Template.Spec.Containers[0].Image == b.Spec.Template.Spec.Containers[0].Image
+	return *a.Spec.Replicas == *b.Spec.Replicas &&
+		equality.Semantic.DeepEqual(a.Spec.Strategy, b.Spec.Strategy) &&
+		a.Spec.Template.Annotations[templateHashAnnotation] == b.Spec.Template.Annotations[templateHashAnnotation]
+}
+
+// setTemplateHash stamps the hash of the pod template on it, once the
+// template is complete. Any change to the containers, volumes or annotations
+// of the template rolls the Deployment.
+func setTemplateHash(deployment *appsv1.Deployment) {
+	template := deployment.Spec.Template.DeepCopy()
+	delete(template.Annotations, templateHashAnnotation)
+	data, _ := json.Marshal(template)
+	sum := sha256.Sum256(data)
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.Annotations[templateHashAnnotation] = hex.EncodeToString(sum[:])[:16]
+}
+
+// configHash returns a stable hash of the rendered configuration, used to
+// restart the node when its configuration changes
+func configHash(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(data[k]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// corsAllowedOrigins returns the origins allowed to call the RPC. Enabling
+// CORS without origins allows all of them.
+func corsAllowedOrigins(axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	rpc := axelarNode.Spec.Networking.RPC
+	if len(rpc.CORSAllowedOrigins) > 0 {
+		return rpc.CORSAllowedOrigins
+	}
+	if rpc.CORS {
+		return []string{"*"}
+	}
+	return nil
+}
+
+// broadcastTxCommitTimeout returns how long broadcast_tx_commit waits for a block
+func broadcastTxCommitTimeout(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if timeout := axelarNode.Spec.Networking.RPC.TimeoutBroadcastTxCommit.Duration; timeout > 0 {
+		return timeout.String()
+	}
+	return "10s"
+}
+
+// apiReadTimeoutSeconds returns the API read timeout in whole seconds
+func apiReadTimeoutSeconds(axelarNode *blockchainv1alpha1.AxelarNode) int64 {
+	if timeout := axelarNode.Spec.Networking.API.RPCReadTimeout.Duration; timeout >= time.Second {
+		return int64(timeout / time.Second)
+	}
+	return 10
+}
+
+// grpcMaxRecvMsgSize returns the largest message the gRPC server accepts
+func grpcMaxRecvMsgSize(axelarNode *blockchainv1alpha1.AxelarNode) int64 {
+	if size := axelarNode.Spec.Networking.GRPC.MaxRecvMsgSize; size > 0 {
+		return size
+	}
+	return 10 << 20
+}
+
+// wasmConfig returns the [wasm] section of app.toml, with the wasmd defaults
+// for unset values
+func wasmConfig(axelarNode *blockchainv1alpha1.AxelarNode) wasmTOML {
+	wasm := axelarNode.Spec.Config.Wasm
+	config := wasmTOML{
+		QueryGasLimit:      wasm.QueryGasLimit,
+		MemoryCacheSize:    wasm.MemoryCacheSize,
+		SimulationGasLimit: wasm.SimulationGasLimit,
+	}
+	if config.QueryGasLimit <= 0 {
+		config.QueryGasLimit = 3000000
+	}
+	if config.MemoryCacheSize <= 0 {
+		config.MemoryCacheSize = 100
+	}
+	if wasm.DisableMemoryCache {
+		config.MemoryCacheSize = 0
+	}
+	return config
+}
+
+// tendermintSettings returns the mempool and consensus configuration, with
+// the Tendermint defaults for unset values
+func tendermintSettings(axelarNode *blockchainv1alpha1.AxelarNode) (blockchainv1alpha1.MempoolSpec, blockchainv1alpha1.ConsensusSpec) {
+	mempool := axelarNode.Spec.Config.Tendermint.Mempool
+	if mempool.Size <= 0 {
+		mempool.Size = 5000
+	}
+	if mempool.CacheSize <= 0 {
+		mempool.CacheSize = 10000
+	}
+	if mempool.MaxTxsBytes <= 0 {
+		mempool.MaxTxsBytes = 1 << 30
+	}
+	if mempool.MaxTxBytes <= 0 {
+		mempool.MaxTxBytes = 1 << 20
+	}
+
+	consensus := axelarNode.Spec.Config.Tendermint.Consensus
+	if consensus.TimeoutCommit.Duration <= 0 {
+		consensus.TimeoutCommit.Duration = 5 * time.Second
+	}
+	return mempool, consensus
 }
 
 // joinStrings joins string slice with commas
@@ -612,14 +1319,35 @@ func joinStrings(strs []string) string {
 	return result
 }
 
-// SetupWithManager sets up the controller with the Manager
+// SetupWithManager sets up the controllers of validators and of the other
+// nodes with the Manager
 func (r *AxelarNodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.setupNodeController(mgr, "axelarnode-validator", true); err != nil {
+		return err
+	}
+	return r.setupNodeController(mgr, "axelarnode", false)
+}
+
+// setupNodeController sets up the controller of the validators, or of the other nodes
+func (r *AxelarNodeReconciler) setupNodeController(mgr ctrl.Manager, name string, validators bool) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&blockchainv1alpha1.AxelarNode{}).
+		Named(name).
+		For(&blockchainv1alpha1.AxelarNode{}, builder.WithPredicates(nodeClass(validators))).
+		WithOptions(controller.Options{RateLimiter: nodeRateLimiter()}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		Owns(&batchv1.CronJob{}).
+		Owns(&corev1.Pod{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		// Container restarts of the node and signer pods are reconciled as they happen
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.nodeForPod),
+			builder.WithPredicates(podHealthChanged)).
+		// and so are the key backups escrowing their keys
+		Watches(&blockchainv1alpha1.AxelarNodeKeyBackup{}, handler.EnqueueRequestsFromMapFunc(nodeForKeyBackup)).
+		Complete(&nodeClassReconciler{AxelarNodeReconciler: r, validators: validators})
 }
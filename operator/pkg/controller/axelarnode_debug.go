@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// defaultPprofPort applies when the spec leaves the pprof port unset
+const defaultPprofPort = 6060
+
+// pprofPort returns the port pprof is served on, or 0 when it is disabled
+func pprofPort(axelarNode *blockchainv1alpha1.AxelarNode) int32 {
+	pprof := axelarNode.Spec.Debug.Pprof
+	if !pprof.Enabled {
+		return 0
+	}
+	if pprof.Port > 0 {
+		return pprof.Port
+	}
+	return defaultPprofPort
+}
+
+// pprofAddress returns the pprof_laddr of config.toml, empty when pprof is disabled
+func pprofAddress(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	port := pprofPort(axelarNode)
+	if port == 0 {
+		return ""
+	}
+	return listenAddress(axelarNode.Spec.Networking, port)
+}
+
+// addPprofPort declares the pprof port on the node container
+func addPprofPort(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	if port := pprofPort(axelarNode); port != 0 {
+		podSpec.Containers[0].Ports = append(podSpec.Containers[0].Ports,
+			corev1.ContainerPort{Name: "pprof", ContainerPort: port, Protocol: corev1.ProtocolTCP})
+	}
+}
+
+// reconcileDebugService exposes pprof on the internal <node>-debug Service,
+// never on the node Service, and removes it once pprof is disabled
+func (r *AxelarNodeReconciler) reconcileDebugService(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	name := childName(axelarNode, "debug")
+	port := pprofPort(axelarNode)
+	if port == 0 {
+		found := &corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, found)
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return r.Delete(ctx, found)
+	}
+
+	service := createDebugService(axelarNode, port)
+	if err := controllerutil.SetControllerReference(axelarNode, service, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if service.Spec.Selector, err = r.deploymentSelector(ctx, axelarNode, deploymentName(axelarNode), componentNode); err != nil {
+			return err
+		}
+		return r.Create(ctx, service)
+	} else if err != nil {
+		return err
+	}
+	found.Spec.Ports = service.Spec.Ports
+	applyIPFamilies(axelarNode.Spec.Networking, &found.Spec)
+	return r.Update(ctx, found)
+}
+
+// createDebugService creates the debug service object exposing pprof on port
+func createDebugService(axelarNode *blockchainv1alpha1.AxelarNode, port int32) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      childName(axelarNode, "debug"),
+			Namespace: axelarNode.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: podSelector(axelarNode, componentNode),
+			Ports: []corev1.ServicePort{
+				{Name: "pprof", Port: port, TargetPort: intstr.FromInt(int(port))},
+			},
+		},
+	}
+	applyIPFamilies(axelarNode.Spec.Networking, &service.Spec)
+	return service
+}
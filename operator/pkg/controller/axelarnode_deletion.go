@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// defaultDeletionTimeout applies to nodes created before the deletion timeout existed
+const defaultDeletionTimeout = 15 * time.Minute
+
+// finalBackupJobName returns the name of the Job taking the final backup of a deleted node
+func finalBackupJobName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "final-backup")
+}
+
+// deletionTimedOut reports whether the teardown of a deleted node has run
+// past its timeout
+func deletionTimedOut(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	d := axelarNode.Status.Deletion
+	if d == nil || d.StartedAt == nil {
+		return false
+	}
+	timeout := axelarNode.Spec.Shutdown.DeletionTimeout.Duration
+	if timeout <= 0 {
+		timeout = defaultDeletionTimeout
+	}
+	return time.Since(d.StartedAt.Time) > timeout
+}
+
+// deletionProtected reports whether the deletion of the node is refused
+func deletionProtected(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Spec.DeletionProtection || axelarNode.Annotations[blockchainv1alpha1.ProtectedAnnotation] == "true"
+}
+
+// blockDeletion records whether the deletion of a deleted node is refused by
+// its protection, and reports it. A teardown already under way is not
+// interrupted.
+func (r *AxelarNodeReconciler) blockDeletion(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionDeletionBlocked,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Unprotected",
+		Message:            "The node is being deleted",
+		ObservedGeneration: axelarNode.Generation,
+	}
+	blocked := deletionProtected(axelarNode) && axelarNode.Status.Deletion == nil
+	if blocked {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Protected"
+		condition.Message = fmt.Sprintf("Deletion is refused until spec.deletionProtection and the %s annotation are removed",
+			blockchainv1alpha1.ProtectedAnnotation)
+		if !meta.IsStatusConditionTrue(axelarNode.Status.Conditions, condition.Type) {
+			r.recordDeletionEvent(axelarNode, corev1.EventTypeWarning, "DeletionBlocked", condition.Message)
+		}
+	} else if meta.FindStatusCondition(axelarNode.Status.Conditions, condition.Type) == nil {
+		return false
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	return blocked
+}
+
+// drainNode tears a deleted node down before its finalizer is removed. The
+// standby stops first, then the active node, whose preStop hook stops axelard
+// only after vald and tofnd. The final backup is taken from the stopped
+// node, and the load balancers and DNS records of the node are released
+// last. It returns true once the teardown is done.
+func (r *AxelarNodeReconciler) drainNode(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	d := axelarNode.Status.Deletion
+	if d == nil {
+		log.Info("Draining the deleted node")
+		d = &blockchainv1alpha1.DeletionStatus{
+			Phase:     blockchainv1alpha1.DeletionStoppingSigner,
+			StartedAt: &metav1.Time{Time: time.Now()},
+			Message:   "Stopping the standby node",
+		}
+		axelarNode.Status.Deletion = d
+	}
+
+	timedOut := deletionTimedOut(axelarNode)
+	if timedOut && d.Phase != blockchainv1alpha1.DeletionReleasing {
+		log.Info("Teardown timed out, releasing the node resources", "phase", d.Phase)
+		r.recordDeletionEvent(axelarNode, corev1.EventTypeWarning, "DeletionTimeout",
+			fmt.Sprintf("Teardown timed out in phase %s, releasing the node resources", d.Phase))
+		d.Phase = blockchainv1alpha1.DeletionReleasing
+	}
+
+	switch d.Phase {
+	case blockchainv1alpha1.DeletionStoppingSigner:
+		stopped := true
+		for _, name := range []string{valdName(axelarNode), tofndName(axelarNode), childName(axelarNode, "standby")} {
+			gone, err := r.stopDeployment(ctx, axelarNode, name)
+			if err != nil {
+				return false, err
+			}
+			stopped = stopped && gone
+		}
+		if !stopped {
+			return false, nil
+		}
+		d.Phase = blockchainv1alpha1.DeletionStoppingNode
+		d.Message = "Stopping the node"
+		return false, nil
+
+	case blockchainv1alpha1.DeletionStoppingNode:
+		stopped, err := r.stopValidatorPods(ctx, axelarNode)
+		if err != nil || !stopped {
+			return false, err
+		}
+		if !axelarNode.Spec.Shutdown.FinalBackup {
+			d.Phase = blockchainv1alpha1.DeletionReleasing
+			d.Message = "Releasing load balancers and DNS records"
+			return false, nil
+		}
+		return false, r.startFinalBackup(ctx, axelarNode)
+
+	case blockchainv1alpha1.DeletionBackingUp:
+		return false, r.checkFinalBackup(ctx, axelarNode)
+
+	case blockchainv1alpha1.DeletionReleasing:
+		released, err := r.releaseExternalResources(ctx, axelarNode)
+		if err != nil {
+			return false, err
+		}
+		if !released && !timedOut {
+			d.Message = "Waiting for load balancers and DNS records to be released"
+			return false, nil
+		}
+		log.Info("Deleted node drained")
+		return true, nil
+	}
+
+	return false, fmt.Errorf("unknown deletion phase %q", d.Phase)
+}
+
+// startFinalBackup starts the backup of the stopped node: a volume snapshot,
+// or a Job archiving the data volume to the backup volume. Backups the node
+// cannot take are skipped.
+func (r *AxelarNodeReconciler) startFinalBackup(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	d := axelarNode.Status.Deletion
+	op := &blockchainv1alpha1.OperationStatus{
+		Type:    blockchainv1alpha1.OperationBackup,
+		Archive: backupArchiveName(axelarNode),
+	}
+	if refusal := operationRefusal(axelarNode, op); refusal != "" {
+		r.recordDeletionEvent(axelarNode, corev1.EventTypeWarning, "FinalBackupSkipped", refusal)
+		d.Phase = blockchainv1alpha1.DeletionReleasing
+		d.Message = fmt.Sprintf("Final backup skipped: %s", refusal)
+		return nil
+	}
+
+	if volumeSnapshotBackups(axelarNode) {
+		if err := r.createVolumeSnapshot(ctx, axelarNode, op.Archive); err != nil {
+			return err
+		}
+	} else {
+		if err := r.createOrUpdatePVC(ctx, r.createPVC(axelarNode, "backup", axelarNode.Spec.Storage.Size)); err != nil {
+			return err
+		}
+		job := r.volumeJob(axelarNode, op, finalBackupJobName(axelarNode), dataClaimName(axelarNode, activeSlot(axelarNode)))
+		if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	d.Phase = blockchainv1alpha1.DeletionBackingUp
+	d.BackupArchive = op.Archive
+	d.Message = fmt.Sprintf("Taking final backup %s", op.Archive)
+	return nil
+}
+
+// checkFinalBackup waits for the final backup. The backup volume is released
+// from the node once it holds the archive, so it outlives the node; volume
+// snapshots are never owned by the node. A failed backup is reported and the
+// teardown goes on.
+func (r *AxelarNodeReconciler) checkFinalBackup(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	d := axelarNode.Status.Deletion
+	failure := ""
+	if volumeSnapshotBackups(axelarNode) {
+		cut, _, reason, err := r.volumeSnapshotState(ctx, axelarNode.Namespace, d.BackupArchive)
+		switch {
+		case errors.IsNotFound(err):
+			failure = "the volume snapshot was deleted"
+		case err != nil:
+			return err
+		case reason != "":
+			failure = reason
+		case !cut:
+			return nil
+		}
+	} else {
+		job := &batchv1.Job{}
+		err := r.Get(ctx, types.NamespacedName{Name: finalBackupJobName(axelarNode), Namespace: axelarNode.Namespace}, job)
+		switch {
+		case errors.IsNotFound(err):
+			failure = "the backup Job was deleted"
+		case err != nil:
+			return err
+		case job.Status.Succeeded > 0:
+			if err := r.releasePVC(ctx, axelarNode, childName(axelarNode, "backup")); err != nil {
+				return err
+			}
+		case job.Status.Failed > 0:
+			failure = "the backup Job failed"
+		default:
+			return nil
+		}
+		if err := r.deleteJob(ctx, axelarNode, finalBackupJobName(axelarNode)); err != nil {
+			return err
+		}
+	}
+
+	if failure != "" {
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Final backup failed", "archive", d.BackupArchive, "reason", failure)
+		r.recordDeletionEvent(axelarNode, corev1.EventTypeWarning, "FinalBackupFailed", failure)
+		d.BackupArchive = ""
+		d.Message = fmt.Sprintf("Final backup failed: %s", failure)
+	} else {
+		r.recordDeletionEvent(axelarNode, corev1.EventTypeNormal, "FinalBackup", fmt.Sprintf("Took final backup %s", d.BackupArchive))
+		d.Message = fmt.Sprintf("Took final backup %s, releasing load balancers and DNS records", d.BackupArchive)
+	}
+	d.Phase = blockchainv1alpha1.DeletionReleasing
+	return nil
+}
+
+// releasePVC removes the owner reference of the node from a PVC, so it is
+// not garbage collected with the node
+func (r *AxelarNodeReconciler) releasePVC(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, name string) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	patch := client.MergeFrom(pvc.DeepCopy())
+	var refs []metav1.OwnerReference
+	for _, ref := range pvc.OwnerReferences {
+		if ref.UID != axelarNode.UID {
+			refs = append(refs, ref)
+		}
+	}
+	pvc.OwnerReferences = refs
+	return r.Patch(ctx, pvc, patch)
+}
+
+// releaseExternalResources deletes the LoadBalancer Services of the node and
+// the Services published by external-dns, so the cloud load balancers and
+// DNS records are gone before the node is. It reports whether the Services
+// are gone, which only happens once the cloud provider has released the
+// load balancers.
+func (r *AxelarNodeReconciler) releaseExternalResources(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	services := &corev1.ServiceList{}
+	if err := r.List(ctx, services, client.InNamespace(axelarNode.Namespace)); err != nil {
+		return false, err
+	}
+
+	released := true
+	for i := range services.Items {
+		service := &services.Items[i]
+		if !metav1.IsControlledBy(service, axelarNode) {
+			continue
+		}
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer && service.Annotations[externalDNSHostnameAnnotation] == "" {
+			continue
+		}
+		released = false
+		if service.DeletionTimestamp != nil {
+			continue
+		}
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Releasing Service", "service", service.Name)
+		if err := r.Delete(ctx, service); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return released, nil
+}
+
+// recordDeletionEvent records a teardown event when a recorder is configured
+func (r *AxelarNodeReconciler) recordDeletionEvent(axelarNode *blockchainv1alpha1.AxelarNode, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, eventType, reason, message)
+	}
+}
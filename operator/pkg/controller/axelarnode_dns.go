@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// external-dns annotations of the published Services
+const (
+	externalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+	externalDNSTTLAnnotation      = "external-dns.alpha.kubernetes.io/ttl"
+)
+
+// defaultDNSTTL applies when the spec leaves the TTL unset
+const defaultDNSTTL = 300
+
+// p2pServiceName returns the name of the LoadBalancer Service of the P2P port
+func p2pServiceName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "p2p")
+}
+
+// p2pHostname returns the hostname peers dial, empty when none is set
+func p2pHostname(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if dns := axelarNode.Spec.Networking.DNS; dns != nil {
+		return dns.P2P
+	}
+	return ""
+}
+
+// rpcHostname returns the hostname of the public Service, empty when none is set
+func rpcHostname(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if dns := axelarNode.Spec.Networking.DNS; dns != nil {
+		return dns.RPC
+	}
+	return ""
+}
+
+// p2pExternalAddress returns the external_address of config.toml. An explicit
+// address wins over the DNS hostname.
+func p2pExternalAddress(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	p2p := axelarNode.Spec.Networking.P2P
+	if p2p.ExternalAddress != "" {
+		return p2p.ExternalAddress
+	}
+	if hostname := p2pHostname(axelarNode); hostname != "" {
+		return net.JoinHostPort(hostname, fmt.Sprintf("%d", p2p.Port))
+	}
+	return ""
+}
+
+// dnsAnnotations returns the external-dns annotations publishing hostname,
+// merged over the annotations of the spec
+func dnsAnnotations(axelarNode *blockchainv1alpha1.AxelarNode, hostname string, annotations map[string]string) map[string]string {
+	if hostname == "" {
+		return annotations
+	}
+	ttl := axelarNode.Spec.Networking.DNS.TTL
+	if ttl <= 0 {
+		ttl = defaultDNSTTL
+	}
+	merged := make(map[string]string, len(annotations)+2)
+	for key, value := range annotations {
+		merged[key] = value
+	}
+	merged[externalDNSHostnameAnnotation] = hostname
+	merged[externalDNSTTLAnnotation] = fmt.Sprintf("%d", ttl)
+	return merged
+}
+
+// reconcileP2PService exposes the P2P port on the <node>-p2p LoadBalancer
+// Service, published by external-dns under the P2P hostname, and removes it
+// once the hostname is unset
+func (r *AxelarNodeReconciler) reconcileP2PService(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	name := p2pServiceName(axelarNode)
+	hostname := p2pHostname(axelarNode)
+	if hostname == "" {
+		found := &corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, found)
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return r.Delete(ctx, found)
+	}
+
+	service := createP2PService(axelarNode, hostname)
+	if err := controllerutil.SetControllerReference(axelarNode, service, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if service.Spec.Selector, err = r.deploymentSelector(ctx, axelarNode, deploymentName(axelarNode), componentNode); err != nil {
+			return err
+		}
+		return r.Create(ctx, service)
+	} else if err != nil {
+		return err
+	}
+	found.Annotations = service.Annotations
+	found.Spec.Ports = service.Spec.Ports
+	applyIPFamilies(axelarNode.Spec.Networking, &found.Spec)
+	return r.Update(ctx, found)
+}
+
+// createP2PService creates the P2P service object published under hostname
+func createP2PService(axelarNode *blockchainv1alpha1.AxelarNode, hostname string) *corev1.Service {
+	port := axelarNode.Spec.Networking.P2P.Port
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        p2pServiceName(axelarNode),
+			Namespace:   axelarNode.Namespace,
+			Annotations: dnsAnnotations(axelarNode, hostname, nil),
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeLoadBalancer,
+			Selector: podSelector(axelarNode, componentNode),
+			Ports: []corev1.ServicePort{
+				{Name: "p2p", Port: port, TargetPort: intstr.FromInt(int(port))},
+			},
+		},
+	}
+	applyIPFamilies(axelarNode.Spec.Networking, &service.Spec)
+	return service
+}
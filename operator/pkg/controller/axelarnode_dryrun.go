@@ -0,0 +1,302 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// maxPreviewValue bounds the length of a value shown in a dry-run change
+const maxPreviewValue = 120
+
+// nodeDryRun reports whether changes to the node are previewed rather than applied
+func nodeDryRun(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Annotations[blockchainv1alpha1.DryRunAnnotation] == "true"
+}
+
+// previewValue formats a value of a dry-run change
+func previewValue(value interface{}) string {
+	if value == nil {
+		return "(unset)"
+	}
+	var text string
+	if s, ok := value.(string); ok {
+		text = s
+	} else if data, err := json.Marshal(value); err == nil {
+		text = string(data)
+	} else {
+		text = fmt.Sprintf("%v", value)
+	}
+	if len(text) > maxPreviewValue {
+		text = text[:maxPreviewValue] + "..."
+	}
+	return text
+}
+
+// fieldChange returns the change of a field, or nothing when it is unchanged
+func fieldChange(path string, before, after interface{}) []string {
+	b, a := previewValue(before), previewValue(after)
+	if b == a {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: %s -> %s", path, b, a)}
+}
+
+// flattenTOML returns the settings of a TOML file by dotted key
+func flattenTOML(data string) map[string]interface{} {
+	parsed := map[string]interface{}{}
+	if _, err := toml.Decode(data, &parsed); err != nil {
+		return map[string]interface{}{"(unparsable)": data}
+	}
+	flat := map[string]interface{}{}
+	var flatten func(prefix string, values map[string]interface{})
+	flatten = func(prefix string, values map[string]interface{}) {
+		for key, value := range values {
+			if table, ok := value.(map[string]interface{}); ok {
+				flatten(prefix+key+".", table)
+				continue
+			}
+			flat[prefix+key] = value
+		}
+	}
+	flatten("", parsed)
+	return flat
+}
+
+// configChanges compares the running configuration files to the rendered ones
+func configChanges(running, desired map[string]string) []string {
+	files := map[string]bool{}
+	for file := range running {
+		files[file] = true
+	}
+	for file := range desired {
+		files[file] = true
+	}
+	names := make([]string, 0, len(files))
+	for file := range files {
+		names = append(names, file)
+	}
+	sort.Strings(names)
+
+	changes := []string{}
+	for _, file := range names {
+		if !strings.HasSuffix(file, ".toml") {
+			before, hadBefore := running[file]
+			after, hasAfter := desired[file]
+			if hadBefore != hasAfter || before != after {
+				changes = append(changes, fieldChange(file, optional(before, hadBefore), optional(after, hasAfter))...)
+			}
+			continue
+		}
+		before, after := flattenTOML(running[file]), flattenTOML(desired[file])
+		keys := map[string]bool{}
+		for key := range before {
+			keys[key] = true
+		}
+		for key := range after {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			changes = append(changes, fieldChange(file+" "+key, before[key], after[key])...)
+		}
+	}
+	return changes
+}
+
+// optional returns value, or nil when it is not set
+func optional(value string, set bool) interface{} {
+	if !set {
+		return nil
+	}
+	return value
+}
+
+// volumeSource summarizes where a volume comes from
+func volumeSource(volume corev1.Volume) string {
+	switch {
+	case volume.PersistentVolumeClaim != nil:
+		return "pvc:" + volume.PersistentVolumeClaim.ClaimName
+	case volume.ConfigMap != nil:
+		return "configMap:" + volume.ConfigMap.Name
+	case volume.Secret != nil:
+		return "secret:" + volume.Secret.SecretName
+	case volume.EmptyDir != nil:
+		return "emptyDir"
+	case volume.Ephemeral != nil:
+		return "ephemeral"
+	default:
+		return "other"
+	}
+}
+
+// containerPorts summarizes the ports of a container
+func containerPorts(container corev1.Container) []string {
+	ports := []string{}
+	for _, port := range container.Ports {
+		ports = append(ports, fmt.Sprintf("%s:%d", port.Name, port.ContainerPort))
+	}
+	return ports
+}
+
+// containerChanges compares the containers of two pods by name
+func containerChanges(kind string, running, desired []corev1.Container) []string {
+	changes := []string{}
+	before := map[string]corev1.Container{}
+	for _, container := range running {
+		before[container.Name] = container
+	}
+	for _, container := range desired {
+		path := fmt.Sprintf("%s[%s]", kind, container.Name)
+		found, ok := before[container.Name]
+		delete(before, container.Name)
+		if !ok {
+			changes = append(changes, path+": added")
+			continue
+		}
+		changes = append(changes, fieldChange(path+".image", found.Image, container.Image)...)
+		changes = append(changes, fieldChange(path+".command", found.Command, container.Command)...)
+		changes = append(changes, fieldChange(path+".args", found.Args, container.Args)...)
+		changes = append(changes, fieldChange(path+".env", found.Env, container.Env)...)
+		changes = append(changes, fieldChange(path+".volumeMounts", found.VolumeMounts, container.VolumeMounts)...)
+		changes = append(changes, fieldChange(path+".resources", found.Resources, container.Resources)...)
+		changes = append(changes, fieldChange(path+".ports", containerPorts(found), containerPorts(container))...)
+		changes = append(changes, fieldChange(path+".livenessProbe", found.LivenessProbe, container.LivenessProbe)...)
+		changes = append(changes, fieldChange(path+".readinessProbe", found.ReadinessProbe, container.ReadinessProbe)...)
+	}
+	for _, container := range running {
+		if _, ok := before[container.Name]; ok {
+			changes = append(changes, fmt.Sprintf("%s[%s]: removed", kind, container.Name))
+		}
+	}
+	return changes
+}
+
+// deploymentChanges compares the running Deployment to the desired one
+func deploymentChanges(running, desired *appsv1.Deployment) []string {
+	changes := []string{}
+	changes = append(changes, fieldChange("replicas", running.Spec.Replicas, desired.Spec.Replicas)...)
+	changes = append(changes, fieldChange("strategy", running.Spec.Strategy, desired.Spec.Strategy)...)
+
+	keys := map[string]bool{}
+	for key := range running.Spec.Template.Annotations {
+		keys[key] = true
+	}
+	for key := range desired.Spec.Template.Annotations {
+		keys[key] = true
+	}
+	// The template hash changes along with the fields listed below
+	delete(keys, templateHashAnnotation)
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		before, hadBefore := running.Spec.Template.Annotations[key]
+		after, hasAfter := desired.Spec.Template.Annotations[key]
+		changes = append(changes, fieldChange("annotations["+key+"]", optional(before, hadBefore), optional(after, hasAfter))...)
+	}
+
+	runningPod, desiredPod := running.Spec.Template.Spec, desired.Spec.Template.Spec
+	changes = append(changes, containerChanges("initContainers", runningPod.InitContainers, desiredPod.InitContainers)...)
+	changes = append(changes, containerChanges("containers", runningPod.Containers, desiredPod.Containers)...)
+
+	volumes := map[string]string{}
+	for _, volume := range runningPod.Volumes {
+		volumes[volume.Name] = volumeSource(volume)
+	}
+	for _, volume := range desiredPod.Volumes {
+		before, ok := volumes[volume.Name]
+		delete(volumes, volume.Name)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("volumes[%s]: added %s", volume.Name, volumeSource(volume)))
+			continue
+		}
+		changes = append(changes, fieldChange("volumes["+volume.Name+"]", before, volumeSource(volume))...)
+	}
+	for _, volume := range runningPod.Volumes {
+		if _, ok := volumes[volume.Name]; ok {
+			changes = append(changes, fmt.Sprintf("volumes[%s]: removed", volume.Name))
+		}
+	}
+	changes = append(changes, fieldChange("nodeSelector", runningPod.NodeSelector, desiredPod.NodeSelector)...)
+	changes = append(changes, fieldChange("terminationGracePeriodSeconds", runningPod.TerminationGracePeriodSeconds, desiredPod.TerminationGracePeriodSeconds)...)
+	return changes
+}
+
+// runningConfig returns the configuration files mounted by a Deployment
+func (r *AxelarNodeReconciler) runningConfig(ctx context.Context, deployment *appsv1.Deployment) (map[string]string, error) {
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Name != "config" || volume.ConfigMap == nil {
+			continue
+		}
+		configMap := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Name: volume.ConfigMap.Name, Namespace: deployment.Namespace}, configMap)
+		if errors.IsNotFound(err) {
+			return map[string]string{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return configMap.Data, nil
+	}
+	return map[string]string{}, nil
+}
+
+// previewChanges records in status.dryRun what applying the spec would
+// change in the configuration and the Deployment of the node
+func (r *AxelarNodeReconciler) previewChanges(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, running, desired *appsv1.Deployment) error {
+	preview := &blockchainv1alpha1.DryRunStatus{
+		ComputedAt:    metav1.Now(),
+		ConfigVersion: r.nodeConfigVersion(axelarNode),
+	}
+	if running == nil {
+		preview.Changes = []string{"Deployment " + desired.Name + ": created"}
+	} else {
+		current, err := r.runningConfig(ctx, running)
+		if err != nil {
+			return err
+		}
+		rendered, err := renderConfig(axelarNode)
+		if err != nil {
+			return err
+		}
+		if pinned := axelarNode.Spec.Config.Version; pinned != "" {
+			configMap := &corev1.ConfigMap{}
+			if err := r.Get(ctx, types.NamespacedName{Name: configMapName(axelarNode.Name, pinned), Namespace: axelarNode.Namespace}, configMap); err != nil {
+				return err
+			}
+			rendered = configMap.Data
+		}
+		preview.Changes = append(configChanges(current, rendered), deploymentChanges(running, desired)...)
+	}
+
+	// An unchanged preview keeps its timestamp so the status stays stable
+	if previous := axelarNode.Status.DryRun; previous != nil && previous.ConfigVersion == preview.ConfigVersion &&
+		strings.Join(previous.Changes, "\n") == strings.Join(preview.Changes, "\n") {
+		preview.ComputedAt = previous.ComputedAt
+	} else {
+		r.Log.Info("Previewed changes", "axelarnode", axelarNode.Name, "changes", len(preview.Changes))
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeNormal, "DryRun",
+				fmt.Sprintf("Applying the spec would make %d changes, see status.dryRun", len(preview.Changes)))
+		}
+	}
+	axelarNode.Status.DryRun = preview
+	return nil
+}
@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+)
+
+// Egress policy providers
+const (
+	egressProviderKubernetes = "Kubernetes"
+	egressProviderCilium     = "Cilium"
+)
+
+// defaultEgressPort is allowed to endpoints without ports, for HTTPS and
+// secure websockets
+const defaultEgressPort = 443
+
+// dnsPort is always allowed, so the pods resolve the endpoints
+const dnsPort = 53
+
+// ciliumPolicyGVK is handled as an unstructured object, so the operator runs
+// in clusters without Cilium
+var ciliumPolicyGVK = schema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumNetworkPolicy"}
+
+// egressProvider returns the provider of the egress policy, or an empty
+// string when the egress of the node is not restricted
+func egressProvider(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	egress := axelarNode.Spec.Egress
+	if egress == nil {
+		return ""
+	}
+	if egress.Provider == "" {
+		return egressProviderKubernetes
+	}
+	return egress.Provider
+}
+
+// egressPolicyName names the egress policy of the node
+func egressPolicyName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "egress")
+}
+
+// egressPodSelector selects the pods of the node, the standby and the split
+// vald and tofnd. Jobs, such as the snapshot downloads, are left alone.
+func egressPodSelector(axelarNode *blockchainv1alpha1.AxelarNode) metav1.LabelSelector {
+	return metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			blockchainv1alpha1.NameLabel:     nodeAppName,
+			blockchainv1alpha1.InstanceLabel: naming.LabelValue(axelarNode.Name),
+		},
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      blockchainv1alpha1.ComponentLabel,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{componentNode, componentStandby, componentVald, componentTofnd},
+		}},
+	}
+}
+
+// egressPeerPorts returns the ports peers outside the cluster are dialed on
+func egressPeerPorts(axelarNode *blockchainv1alpha1.AxelarNode) []int32 {
+	egress := axelarNode.Spec.Egress
+	if egress.DisablePeers {
+		return nil
+	}
+	if len(egress.PeerPorts) > 0 {
+		return egress.PeerPorts
+	}
+	return []int32{axelarNode.Spec.Networking.P2P.Port}
+}
+
+// endpointPorts returns the ports of an endpoint
+func endpointPorts(endpoint blockchainv1alpha1.EgressEndpoint) []int32 {
+	if len(endpoint.Ports) > 0 {
+		return endpoint.Ports
+	}
+	return []int32{defaultEgressPort}
+}
+
+// reconcileEgressPolicy restricts the egress of the node pods with the policy
+// of the provider, and removes the policies no longer asked for
+func (r *AxelarNodeReconciler) reconcileEgressPolicy(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	provider := egressProvider(axelarNode)
+	if provider != egressProviderKubernetes {
+		policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: egressPolicyName(axelarNode), Namespace: axelarNode.Namespace}}
+		if err := r.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	if provider != egressProviderCilium {
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(ciliumPolicyGVK)
+		policy.SetName(egressPolicyName(axelarNode))
+		policy.SetNamespace(axelarNode.Namespace)
+		if err := r.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+
+	switch provider {
+	case egressProviderKubernetes:
+		return r.applyEgressNetworkPolicy(ctx, axelarNode)
+	case egressProviderCilium:
+		return r.applyEgressCiliumPolicy(ctx, axelarNode)
+	}
+	return nil
+}
+
+// applyEgressNetworkPolicy creates or updates the NetworkPolicy allowing DNS,
+// the pods of the namespace, the peers and the CIDRs of the endpoints.
+// NetworkPolicies cannot match FQDNs, which validation reports.
+func (r *AxelarNodeReconciler) applyEgressNetworkPolicy(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{Ports: networkPolicyPorts([]int32{dnsPort}, corev1.ProtocolUDP, corev1.ProtocolTCP)},
+		{To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+	}
+	if ports := egressPeerPorts(axelarNode); len(ports) > 0 {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{Ports: networkPolicyPorts(ports, corev1.ProtocolTCP)})
+	}
+	for _, endpoint := range axelarNode.Spec.Egress.Endpoints {
+		if endpoint.CIDR == "" {
+			continue
+		}
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			Ports: networkPolicyPorts(endpointPorts(endpoint), corev1.ProtocolTCP),
+			To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: endpoint.CIDR}}},
+		})
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      egressPolicyName(axelarNode),
+			Namespace: axelarNode.Namespace,
+			Labels:    nodeLabels(axelarNode, componentNode),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: egressPodSelector(axelarNode),
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		},
+	}
+	if err := controllerutil.SetControllerReference(axelarNode, policy, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, policy)
+	} else if err != nil {
+		return err
+	}
+	found.Spec = policy.Spec
+	return r.Update(ctx, found)
+}
+
+// networkPolicyPorts returns the ports with each protocol
+func networkPolicyPorts(ports []int32, protocols ...corev1.Protocol) []networkingv1.NetworkPolicyPort {
+	var out []networkingv1.NetworkPolicyPort
+	for _, port := range ports {
+		for _, protocol := range protocols {
+			port, protocol := intstr.FromInt(int(port)), protocol
+			out = append(out, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+		}
+	}
+	return out
+}
+
+// applyEgressCiliumPolicy creates or updates the CiliumNetworkPolicy allowing
+// DNS, the pods of the namespace, the peers and the CIDRs and FQDNs of the
+// endpoints. DNS goes through the Cilium proxy, which learns the addresses
+// of the FQDNs.
+func (r *AxelarNodeReconciler) applyEgressCiliumPolicy(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	selector := egressPodSelector(axelarNode)
+	matchLabels := map[string]interface{}{}
+	for key, value := range selector.MatchLabels {
+		matchLabels[key] = value
+	}
+	components := make([]interface{}, 0, len(selector.MatchExpressions[0].Values))
+	for _, component := range selector.MatchExpressions[0].Values {
+		components = append(components, component)
+	}
+
+	egress := []interface{}{
+		map[string]interface{}{
+			"toEndpoints": []interface{}{
+				map[string]interface{}{"matchLabels": map[string]interface{}{
+					"k8s:io.kubernetes.pod.namespace": "kube-system",
+					"k8s:k8s-app":                     "kube-dns",
+				}},
+			},
+			"toPorts": []interface{}{
+				map[string]interface{}{
+					"ports": ciliumPorts([]int32{dnsPort}, "ANY"),
+					"rules": map[string]interface{}{
+						"dns": []interface{}{map[string]interface{}{"matchPattern": "*"}},
+					},
+				},
+			},
+		},
+		map[string]interface{}{"toEndpoints": []interface{}{map[string]interface{}{}}},
+	}
+	if ports := egressPeerPorts(axelarNode); len(ports) > 0 {
+		egress = append(egress, map[string]interface{}{
+			"toEntities": []interface{}{"world"},
+			"toPorts":    []interface{}{map[string]interface{}{"ports": ciliumPorts(ports, "TCP")}},
+		})
+	}
+	for _, endpoint := range axelarNode.Spec.Egress.Endpoints {
+		rule := map[string]interface{}{
+			"toPorts": []interface{}{map[string]interface{}{"ports": ciliumPorts(endpointPorts(endpoint), "TCP")}},
+		}
+		switch {
+		case endpoint.CIDR != "":
+			rule["toCIDR"] = []interface{}{endpoint.CIDR}
+		case strings.Contains(endpoint.FQDN, "*"):
+			rule["toFQDNs"] = []interface{}{map[string]interface{}{"matchPattern": endpoint.FQDN}}
+		case endpoint.FQDN != "":
+			rule["toFQDNs"] = []interface{}{map[string]interface{}{"matchName": endpoint.FQDN}}
+		default:
+			continue
+		}
+		egress = append(egress, rule)
+	}
+	spec := map[string]interface{}{
+		"endpointSelector": map[string]interface{}{
+			"matchLabels": matchLabels,
+			"matchExpressions": []interface{}{
+				map[string]interface{}{
+					"key":      blockchainv1alpha1.ComponentLabel,
+					"operator": string(metav1.LabelSelectorOpIn),
+					"values":   components,
+				},
+			},
+		},
+		"egress": egress,
+	}
+
+	name := egressPolicyName(axelarNode)
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(ciliumPolicyGVK)
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		object := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		object.SetGroupVersionKind(ciliumPolicyGVK)
+		object.SetName(name)
+		object.SetNamespace(axelarNode.Namespace)
+		object.SetLabels(nodeLabels(axelarNode, componentNode))
+		if err := controllerutil.SetControllerReference(axelarNode, object, r.Scheme); err != nil {
+			return err
+		}
+		return r.Create(ctx, object)
+	} else if err != nil {
+		return err
+	}
+	found.Object["spec"] = spec
+	return r.Update(ctx, found)
+}
+
+// ciliumPorts returns the ports of a Cilium rule with a protocol
+func ciliumPorts(ports []int32, protocol string) []interface{} {
+	out := make([]interface{}, 0, len(ports))
+	for _, port := range ports {
+		out = append(out, map[string]interface{}{"port": strconv.Itoa(int(port)), "protocol": protocol})
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/backup"
+)
+
+// backupIdentityPath is where the age identity is mounted in restore Jobs
+const backupIdentityPath = "/etc/backup-identity"
+
+// encryptedBackupScript archives and encrypts the chain data of the stopped
+// node with the operator tools, so the archive is never written in the clear
+const encryptedBackupScript = `set -eo pipefail
+cd /home/axelard/.axelar
+tar -cz --exclude=config/priv_validator_key.json --exclude=keyring-file data config |
+  /root/manager backup-crypt encrypt --age-recipient="$AGE_RECIPIENT" --kms-key-url="$KMS_KEY_URL" > "/backup/$ARCHIVE.tmp"
+mv "/backup/$ARCHIVE.tmp" "/backup/$ARCHIVE"
+sync
+`
+
+// encryptedRestoreScript restores an encrypted or plain archive, keeping the
+// current signing state like restoreScript
+const encryptedRestoreScript = `set -eo pipefail
+if [ "$ARCHIVE" = latest ]; then
+  archive=$(ls -t /backup/*.tar.gz /backup/*.tar.gz.age 2>/dev/null | head -n 1) || true
+else
+  archive="/backup/$ARCHIVE"
+fi
+test -f "$archive"
+cd /home/axelard/.axelar
+if [ -f data/priv_validator_state.json ]; then cp data/priv_validator_state.json /tmp/priv_validator_state.json; fi
+rm -rf data
+case "$archive" in
+  *.age)
+    /root/manager backup-crypt decrypt --age-identity-file="$AGE_IDENTITY_FILE" --kms-key-url="$KMS_KEY_URL" < "$archive" |
+      tar -xzf - ${RESTORE_PATHS:-data} ;;
+  *) tar -xzf "$archive" ${RESTORE_PATHS:-data} ;;
+esac
+if [ -f /tmp/priv_validator_state.json ]; then cp /tmp/priv_validator_state.json data/priv_validator_state.json; fi
+sync
+`
+
+// backupEncryption returns the encryption of the node backups, or nil
+func backupEncryption(axelarNode *blockchainv1alpha1.AxelarNode) *blockchainv1alpha1.BackupEncryptionSpec {
+	return axelarNode.Spec.Storage.Backup.Encryption
+}
+
+// encryptionRefusal explains why a backup or restore cannot run with the
+// configured encryption, or returns an empty string
+func encryptionRefusal(axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus) string {
+	enc := backupEncryption(axelarNode)
+	if enc == nil {
+		if op.Type == blockchainv1alpha1.OperationRestore && backup.Encrypted(op.Archive) {
+			return "encrypted archives need spec.storage.backup.encryption"
+		}
+		return ""
+	}
+
+	if refusal := encryptionProblem(enc); refusal != "" {
+		return refusal
+	}
+	if op.Type == blockchainv1alpha1.OperationRestore && enc.Age != nil && enc.Age.IdentitySecretRef == nil {
+		return "restoring encrypted archives needs encryption.age.identitySecretRef"
+	}
+	return ""
+}
+
+// encryptionProblem explains why archives cannot be encrypted with enc, or
+// returns an empty string
+func encryptionProblem(enc *blockchainv1alpha1.BackupEncryptionSpec) string {
+	switch {
+	case (enc.Age == nil) == (enc.KMS == nil):
+		return "exactly one of encryption.age and encryption.kms must be set"
+	case enc.Age != nil:
+		if err := backup.ValidateRecipient(enc.Age.Recipient); err != nil {
+			return fmt.Sprintf("invalid age recipient: %v", err)
+		}
+	case enc.KMS.KeyURL == "":
+		return "encryption.kms.keyURL is required"
+	}
+	return ""
+}
+
+// addBackupEncryption runs a backup or restore Job with the operator tools,
+// encrypting or decrypting the archive with the configured keys
+func (r *AxelarNodeReconciler) addBackupEncryption(axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus, podSpec *corev1.PodSpec) {
+	enc := backupEncryption(axelarNode)
+	if enc == nil || op.Type == blockchainv1alpha1.OperationResync {
+		return
+	}
+
+	container := &podSpec.Containers[0]
+	container.Image = r.ToolsImage
+	container.Command = []string{"sh", "-c", encryptedBackupScript}
+	if op.Type == blockchainv1alpha1.OperationRestore {
+		container.Command = []string{"sh", "-c", encryptedRestoreScript}
+	}
+
+	if enc.Age != nil {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "AGE_RECIPIENT", Value: enc.Age.Recipient})
+		if ref := enc.Age.IdentitySecretRef; ref != nil {
+			container.Env = append(container.Env, corev1.EnvVar{Name: "AGE_IDENTITY_FILE", Value: backupIdentityPath + "/identity"})
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name: "backup-identity", MountPath: backupIdentityPath, ReadOnly: true,
+			})
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: "backup-identity",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: ref.Name,
+						Items:      []corev1.KeyToPath{{Key: ref.Key, Path: "identity"}},
+					},
+				},
+			})
+		}
+	}
+	if enc.KMS != nil {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "KMS_KEY_URL", Value: enc.KMS.KeyURL})
+		if enc.KMS.CredentialsSecret != "" {
+			container.EnvFrom = append(container.EnvFrom, corev1.EnvFromSource{
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: enc.KMS.CredentialsSecret}},
+			})
+		}
+		podSpec.ServiceAccountName = enc.KMS.ServiceAccountName
+	}
+}
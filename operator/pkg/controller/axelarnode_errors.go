@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+const (
+	// transientRetryBase and transientRetryMax bound the exponential backoff
+	// of a node whose reconcile keeps failing with transient errors
+	transientRetryBase = time.Second
+	transientRetryMax  = 5 * time.Minute
+
+	// terminalRetryInterval is how often a node failing with a terminal error
+	// is tried again, in case what it depends on was fixed outside the spec
+	terminalRetryInterval = 15 * time.Minute
+)
+
+// reconcileFailedReason is the reason of the Degraded condition set by a
+// terminal error
+const reconcileFailedReason = "ReconcileFailed"
+
+// nodeRateLimiter backs off the nodes whose reconcile fails, per node
+func nodeRateLimiter() workqueue.RateLimiter {
+	return workqueue.NewItemExponentialFailureRateLimiter(transientRetryBase, transientRetryMax)
+}
+
+// terminalError is an error retries cannot fix, only a change of the spec
+// or of the cluster
+type terminalError struct {
+	reason string
+	err    error
+}
+
+func (e *terminalError) Error() string { return e.err.Error() }
+func (e *terminalError) Unwrap() error { return e.err }
+
+// terminal marks an error as terminal
+func terminal(reason string, err error) error {
+	return &terminalError{reason: reason, err: err}
+}
+
+// terminalReason reports why an error is terminal, or "" for a transient
+// error. The API server refusing an object as invalid is terminal: sending
+// it again is refused again.
+func terminalReason(err error) string {
+	var t *terminalError
+	switch {
+	case goerrors.As(err, &t):
+		return t.reason
+	case errors.IsInvalid(err):
+		return "InvalidObject"
+	case errors.IsBadRequest(err):
+		return "BadRequest"
+	}
+	return ""
+}
+
+// classifyFailure turns the outcome of a reconcile into the result the node
+// is requeued with. Transient errors are returned, so the node is retried with
+// exponential backoff. Terminal errors set the Degraded condition and are
+// retried at a slow interval instead. A successful reconcile clears the
+// condition set by an earlier terminal error.
+func (r *AxelarNodeReconciler) classifyFailure(original, axelarNode *blockchainv1alpha1.AxelarNode, result ctrl.Result, err error) (ctrl.Result, error) {
+	previous := meta.FindStatusCondition(original.Status.Conditions, blockchainv1alpha1.ConditionDegraded)
+	if err == nil {
+		current := meta.FindStatusCondition(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionDegraded)
+		if current != nil && current.Status == metav1.ConditionTrue && current.Reason == reconcileFailedReason {
+			meta.SetStatusCondition(&axelarNode.Status.Conditions, metav1.Condition{
+				Type:               blockchainv1alpha1.ConditionDegraded,
+				Status:             metav1.ConditionFalse,
+				Reason:             "Reconciled",
+				Message:            "The node reconciled",
+				ObservedGeneration: axelarNode.Generation,
+			})
+		}
+		return result, nil
+	}
+
+	reason := terminalReason(err)
+	if reason == "" {
+		return result, err
+	}
+
+	message := fmt.Sprintf("%s: %v", reason, err)
+	r.Log.WithValues("axelarnode", axelarNode.Name).Info("Reconcile failed with a terminal error, retrying slowly",
+		"reason", reason, "error", err.Error())
+	if r.Recorder != nil && (previous == nil || previous.Status != metav1.ConditionTrue || previous.Message != message) {
+		r.Recorder.Event(axelarNode, corev1.EventTypeWarning, reconcileFailedReason, message)
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             reconcileFailedReason,
+		Message:            message,
+		ObservedGeneration: axelarNode.Generation,
+	})
+	// The spec check clears the condition before the failure sets it again,
+	// which must not move the time the node became degraded
+	if previous != nil && previous.Status == metav1.ConditionTrue {
+		meta.FindStatusCondition(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionDegraded).LastTransitionTime = previous.LastTransitionTime
+	}
+	return ctrl.Result{RequeueAfter: terminalRetryInterval}, nil
+}
+
+// checkStorageClass fails terminally when a PVC asks for a StorageClass the
+// cluster does not have, since it would stay pending forever
+func (r *AxelarNodeReconciler) checkStorageClass(ctx context.Context, pvc *corev1.PersistentVolumeClaim) error {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return nil
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, &storagev1.StorageClass{})
+	if errors.IsNotFound(err) {
+		return terminal("StorageClassNotFound", fmt.Errorf("PVC %s asks for StorageClass %q, which does not exist", pvc.Name, *pvc.Spec.StorageClassName))
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// defaultGatewayPort applies when the spec leaves the gateway port unset
+const defaultGatewayPort = 26672
+
+// gatewayPort returns the port the gateway serves the RPC on, or 0 when the
+// node has no gateway
+func gatewayPort(axelarNode *blockchainv1alpha1.AxelarNode) int32 {
+	spec := axelarNode.Spec.Networking.Gateway
+	if spec == nil {
+		return 0
+	}
+	if spec.Port > 0 {
+		return spec.Port
+	}
+	return defaultGatewayPort
+}
+
+// rpcUpstreamPort returns the port public RPC traffic is forwarded to. An
+// autoscaled node keeps serving it through the RPC proxy, which counts the
+// requests it scales on.
+func rpcUpstreamPort(axelarNode *blockchainv1alpha1.AxelarNode) int32 {
+	if nodeAutoscaled(axelarNode) {
+		return rpcProxyPort
+	}
+	return axelarNode.Spec.Networking.RPC.Port
+}
+
+// gatewayServicePorts returns the gateway port of the node Service
+func gatewayServicePorts(axelarNode *blockchainv1alpha1.AxelarNode) []corev1.ServicePort {
+	port := gatewayPort(axelarNode)
+	if port == 0 {
+		return nil
+	}
+	return []corev1.ServicePort{
+		{Name: "rpc-gateway", Port: port, TargetPort: intstr.FromInt(int(port))},
+	}
+}
+
+// addGateway fronts the RPC with the gateway sidecar. API keys and the JWT
+// secret are mounted, so rotating them does not restart the pod.
+func (r *AxelarNodeReconciler) addGateway(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	spec := axelarNode.Spec.Networking.Gateway
+	if spec == nil {
+		return
+	}
+	port := gatewayPort(axelarNode)
+	container := corev1.Container{
+		Name:  "rpc-gateway",
+		Image: r.ToolsImage,
+		Args: []string{
+			"gateway",
+			fmt.Sprintf("--listen=:%d", port),
+			fmt.Sprintf("--upstream=http://127.0.0.1:%d", rpcUpstreamPort(axelarNode)),
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "rpc-gateway", ContainerPort: port, Protocol: corev1.ProtocolTCP},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+	}
+	if spec.RateLimit > 0 {
+		container.Args = append(container.Args, fmt.Sprintf("--rate=%d", spec.RateLimit))
+	}
+	if spec.Burst > 0 {
+		container.Args = append(container.Args, fmt.Sprintf("--burst=%d", spec.Burst))
+	}
+	if len(spec.AllowedMethods) > 0 {
+		container.Args = append(container.Args, "--allow-methods="+strings.Join(spec.AllowedMethods, ","))
+	}
+	if len(spec.BlockedMethods) > 0 {
+		container.Args = append(container.Args, "--block-methods="+strings.Join(spec.BlockedMethods, ","))
+	}
+
+	if spec.APIKeysSecretRef != nil {
+		container.Args = append(container.Args, "--keys-dir=/etc/gateway/keys")
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "gateway-keys", MountPath: "/etc/gateway/keys", ReadOnly: true})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "gateway-keys",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: spec.APIKeysSecretRef.Name},
+			},
+		})
+	}
+	if spec.JWTSecretRef != nil {
+		container.Args = append(container.Args, "--jwt-secret-file=/etc/gateway/jwt/secret")
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "gateway-jwt", MountPath: "/etc/gateway/jwt", ReadOnly: true})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "gateway-jwt",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: spec.JWTSecretRef.Name,
+					Items:      []corev1.KeyToPath{{Key: spec.JWTSecretRef.Key, Path: "secret"}},
+					Optional:   spec.JWTSecretRef.Optional,
+				},
+			},
+		})
+	}
+	podSpec.Containers = append(podSpec.Containers, container)
+}
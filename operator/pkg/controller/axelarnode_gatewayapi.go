@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Gateway API route kinds. They are handled as unstructured objects, so the
+// operator runs in clusters without the Gateway API CRDs.
+var (
+	tcpRouteGVK  = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TCPRoute"}
+	grpcRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GRPCRoute"}
+	httpRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+)
+
+// gatewayRoute is a route the spec may ask for
+type gatewayRoute struct {
+	gvk  schema.GroupVersionKind
+	name string
+	spec *blockchainv1alpha1.GatewayRouteSpec
+	port int32
+}
+
+// gatewayRoutes returns the routes of the P2P, gRPC and API ports, with a nil
+// spec for those not asked for
+func gatewayRoutes(axelarNode *blockchainv1alpha1.AxelarNode) []gatewayRoute {
+	spec := axelarNode.Spec.Networking.GatewayAPI
+	if spec == nil {
+		spec = &blockchainv1alpha1.GatewayAPISpec{}
+	}
+	networking := axelarNode.Spec.Networking
+	return []gatewayRoute{
+		{tcpRouteGVK, childName(axelarNode, "p2p"), spec.P2P, networking.P2P.Port},
+		{grpcRouteGVK, childName(axelarNode, "grpc"), spec.GRPC, grpcPort},
+		{httpRouteGVK, childName(axelarNode, "api"), spec.API, networking.API.Port},
+	}
+}
+
+// gatewayAPIServicePorts returns the gRPC port of the node Service, which
+// only a GRPCRoute needs
+func gatewayAPIServicePorts(axelarNode *blockchainv1alpha1.AxelarNode) []corev1.ServicePort {
+	spec := axelarNode.Spec.Networking.GatewayAPI
+	if spec == nil || spec.GRPC == nil {
+		return nil
+	}
+	return []corev1.ServicePort{
+		{Name: "grpc", Port: grpcPort, TargetPort: intstr.FromInt(grpcPort)},
+	}
+}
+
+// reconcileGatewayRoutes attaches a route of each port the spec asks for to
+// the Gateway, pointing at the node Service, and removes the others
+func (r *AxelarNodeReconciler) reconcileGatewayRoutes(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	for _, route := range gatewayRoutes(axelarNode) {
+		if route.spec == nil {
+			if err := r.deleteGatewayRoute(ctx, axelarNode, route); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.applyGatewayRoute(ctx, axelarNode, route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteGatewayRoute removes a route, when the Gateway API is installed
+func (r *AxelarNodeReconciler) deleteGatewayRoute(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, route gatewayRoute) error {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(route.gvk)
+	object.SetName(route.name)
+	object.SetNamespace(axelarNode.Namespace)
+	if err := r.Delete(ctx, object); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+// applyGatewayRoute creates or updates a route
+func (r *AxelarNodeReconciler) applyGatewayRoute(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, route gatewayRoute) error {
+	gateway := axelarNode.Spec.Networking.GatewayAPI.GatewayRef
+	parent := map[string]interface{}{"name": gateway.Name}
+	if gateway.Namespace != "" {
+		parent["namespace"] = gateway.Namespace
+	}
+	if route.spec.SectionName != "" {
+		parent["sectionName"] = route.spec.SectionName
+	}
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parent},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": serviceName(axelarNode), "port": int64(route.port)},
+				},
+			},
+		},
+	}
+	if len(route.spec.Hostnames) > 0 && route.gvk != tcpRouteGVK {
+		hostnames := make([]interface{}, 0, len(route.spec.Hostnames))
+		for _, hostname := range route.spec.Hostnames {
+			hostnames = append(hostnames, hostname)
+		}
+		spec["hostnames"] = hostnames
+	}
+
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(route.gvk)
+	err := r.Get(ctx, types.NamespacedName{Name: route.name, Namespace: axelarNode.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		object := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		object.SetGroupVersionKind(route.gvk)
+		object.SetName(route.name)
+		object.SetNamespace(axelarNode.Namespace)
+		object.SetLabels(nodeLabels(axelarNode, componentNode))
+		if err := controllerutil.SetControllerReference(axelarNode, object, r.Scheme); err != nil {
+			return err
+		}
+		return r.Create(ctx, object)
+	} else if err != nil {
+		return err
+	}
+	found.Object["spec"] = spec
+	return r.Update(ctx, found)
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// gcKinds are the kinds of child resources checked for staleness. Jobs and
+// pods are left to the features running them.
+var gcKinds = []struct {
+	kind string
+	list func() client.ObjectList
+}{
+	{"Deployment", func() client.ObjectList { return &appsv1.DeploymentList{} }},
+	{"Service", func() client.ObjectList { return &corev1.ServiceList{} }},
+	{"ConfigMap", func() client.ObjectList { return &corev1.ConfigMapList{} }},
+	{"Secret", func() client.ObjectList { return &corev1.SecretList{} }},
+	{"PersistentVolumeClaim", func() client.ObjectList { return &corev1.PersistentVolumeClaimList{} }},
+	{"CronJob", func() client.ObjectList { return &batchv1.CronJobList{} }},
+}
+
+// desiredChildren returns the names of the child resources the spec asks for,
+// by kind. Both data volume slots and the volumes of running operations are
+// always kept.
+func desiredChildren(axelarNode *blockchainv1alpha1.AxelarNode) map[string]map[string]bool {
+	desired := map[string]map[string]bool{
+		"Deployment": {deploymentName(axelarNode): true},
+		"Service":    {serviceName(axelarNode): true},
+		"ConfigMap":  {addrbookConfigMapName(axelarNode): true},
+		"Secret":     {},
+		"PersistentVolumeClaim": {
+			dataClaimName(axelarNode, blockchainv1alpha1.SlotBlue):  true,
+			dataClaimName(axelarNode, blockchainv1alpha1.SlotGreen): true,
+			childName(axelarNode, "backup"):                         true,
+			childName(axelarNode, "restore"):                        true,
+			verifyName(axelarNode):                                  true,
+		},
+		"CronJob": {},
+	}
+
+	if standbyEnabled(axelarNode) {
+		desired["Deployment"][childName(axelarNode, "standby")] = true
+		desired["Service"][childName(axelarNode, "standby-service")] = true
+	}
+	if splitSigner(axelarNode) {
+		desired["Deployment"][valdName(axelarNode)] = true
+	}
+	if splitSigner(axelarNode) && ownTofnd(axelarNode) {
+		desired["Deployment"][tofndName(axelarNode)] = true
+		desired["Service"][tofndName(axelarNode)] = true
+	}
+	if pprofPort(axelarNode) != 0 {
+		desired["Service"][childName(axelarNode, "debug")] = true
+	}
+	if publicEnabled(axelarNode) {
+		desired["Service"][publicServiceName(axelarNode)] = true
+	}
+	if p2pHostname(axelarNode) != "" {
+		desired["Service"][p2pServiceName(axelarNode)] = true
+	}
+	if axelarNode.Spec.Networking.TLS != nil {
+		desired["ConfigMap"][tlsProxyConfigMapName(axelarNode)] = true
+	}
+	if axelarNode.Spec.Security.SecretManagement.SecretName == "" {
+		desired["Secret"][passwordsSecretName(axelarNode)] = true
+	}
+	if sharedVolumeType(axelarNode) == blockchainv1alpha1.SharedVolumePVC {
+		desired["PersistentVolumeClaim"][childName(axelarNode, "shared")] = true
+	}
+	for _, volume := range axelarNode.Spec.Storage.Volumes {
+		desired["PersistentVolumeClaim"][childName(axelarNode, extraVolumePrefix+volume.Name)] = true
+	}
+	if validator := axelarNode.Spec.Validator; validator != nil && validator.Enabled && validator.Rewards != nil {
+		desired["CronJob"][rewardsName(axelarNode)] = true
+	}
+	return desired
+}
+
+// collectGarbage reports the child resources owned by the node that the spec
+// no longer asks for, such as the Service of a renamed port or the PVC of a
+// removed volume. With spec.garbageCollection.prune, a resource is deleted
+// once it has been reported in an earlier reconcile.
+func (r *AxelarNodeReconciler) collectGarbage(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+	desired := desiredChildren(axelarNode)
+
+	reported := map[string]blockchainv1alpha1.StaleResource{}
+	for _, resource := range axelarNode.Status.StaleResources {
+		reported[resource.Kind+"/"+resource.Name] = resource
+	}
+
+	now := metav1.Now()
+	stale := []blockchainv1alpha1.StaleResource{}
+	for _, gc := range gcKinds {
+		list := gc.list()
+		if err := r.List(ctx, list, client.InNamespace(axelarNode.Namespace)); err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			object, ok := item.(client.Object)
+			if !ok || !metav1.IsControlledBy(object, axelarNode) || object.GetDeletionTimestamp() != nil || desired[gc.kind][object.GetName()] {
+				continue
+			}
+			// Configuration versions are pruned with their history limit
+			if _, versioned := object.GetLabels()[configVersionLabel]; versioned {
+				continue
+			}
+			// Claims are stale once no Deployment of the node mounts them
+			if gc.kind == "PersistentVolumeClaim" {
+				mounted, err := r.claimMounted(ctx, axelarNode, object.GetName())
+				if err != nil {
+					return err
+				}
+				if mounted {
+					continue
+				}
+			}
+
+			previous, seen := reported[gc.kind+"/"+object.GetName()]
+			if seen && axelarNode.Spec.GarbageCollection.Prune {
+				log.Info("Pruning a stale child resource", "kind", gc.kind, "name", object.GetName())
+				if err := r.Delete(ctx, object, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+					return err
+				}
+				r.recordGCEvent(axelarNode, "StaleResourcePruned", fmt.Sprintf("Deleted %s %s, which is no longer part of the spec", gc.kind, object.GetName()))
+				continue
+			}
+
+			resource := blockchainv1alpha1.StaleResource{Kind: gc.kind, Name: object.GetName(), Since: now}
+			if seen {
+				resource.Since = previous.Since
+			} else {
+				log.Info("Found a stale child resource", "kind", gc.kind, "name", object.GetName())
+				r.recordGCEvent(axelarNode, "StaleResource", fmt.Sprintf("%s %s is no longer part of the spec", gc.kind, object.GetName()))
+			}
+			stale = append(stale, resource)
+		}
+	}
+
+	if len(stale) == 0 {
+		stale = nil
+	}
+	axelarNode.Status.StaleResources = stale
+	return nil
+}
+
+// recordGCEvent records a garbage collection event when a recorder is configured
+func (r *AxelarNodeReconciler) recordGCEvent(axelarNode *blockchainv1alpha1.AxelarNode, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, corev1.EventTypeNormal, reason, message)
+	}
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/cosmos"
+)
+
+// governanceCheckInterval is how often the proposals are queried
+const governanceCheckInterval = 5 * time.Minute
+
+// defaultReminderBefore applies when the spec leaves the reminder unset
+const defaultReminderBefore = 24 * time.Hour
+
+// defaultProposalTypes are reported when the spec lists no proposal types
+var defaultProposalTypes = []string{
+	"SoftwareUpgradeProposal",
+	"CancelSoftwareUpgradeProposal",
+	"ParameterChangeProposal",
+}
+
+// proposalType returns the short type of a proposal, such as
+// SoftwareUpgradeProposal for /cosmos.upgrade.v1beta1.SoftwareUpgradeProposal
+func proposalType(typeURL string) string {
+	return typeURL[strings.LastIndex(typeURL, ".")+1:]
+}
+
+// proposalRelevant returns whether proposals of type t are reported
+func proposalRelevant(spec *blockchainv1alpha1.GovernanceSpec, t string) bool {
+	types := spec.ProposalTypes
+	if len(types) == 0 {
+		types = defaultProposalTypes
+	}
+	for _, allowed := range types {
+		if allowed == "*" || allowed == t {
+			return true
+		}
+	}
+	return false
+}
+
+// votingCountdown formats the time left to vote, such as 2d5h or 3h20m
+func votingCountdown(d time.Duration) string {
+	if d <= 0 {
+		return "ended"
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	if days > 0 {
+		return fmt.Sprintf("%dd%dh", days, hours)
+	}
+	return fmt.Sprintf("%dh%dm", hours, int(d%time.Hour/time.Minute))
+}
+
+// reconcileGovernance reports the relevant proposals open for voting. An
+// alert is sent when a proposal enters the voting period, and again before
+// voting ends unless the voter has voted.
+func (r *AxelarNodeReconciler) reconcileGovernance(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	spec := axelarNode.Spec.Monitoring.Governance
+	if spec == nil || !spec.Enabled {
+		axelarNode.Status.Governance = nil
+		return nil
+	}
+	previous := axelarNode.Status.Governance
+	if previous != nil && previous.LastChecked != nil && time.Since(previous.LastChecked.Time) < governanceCheckInterval {
+		return nil
+	}
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	_, api, err := r.statusClients(ctx, axelarNode)
+	if err != nil {
+		log.V(1).Info("Unable to reach the status source", "error", err.Error())
+		return nil
+	}
+	proposals, err := api.Proposals(ctx, cosmos.ProposalStatusVotingPeriod)
+	if err != nil {
+		log.V(1).Info("Unable to query governance proposals", "error", err.Error())
+		return nil
+	}
+
+	known := map[string]blockchainv1alpha1.GovernanceProposal{}
+	if previous != nil {
+		for _, proposal := range previous.ActiveProposals {
+			known[proposal.ID] = proposal
+		}
+	}
+	reminderBefore := spec.ReminderBefore.Duration
+	if reminderBefore == 0 {
+		reminderBefore = defaultReminderBefore
+	}
+
+	now := metav1.Now()
+	status := &blockchainv1alpha1.GovernanceStatus{LastChecked: &now}
+	for _, p := range proposals {
+		t := proposalType(p.Content.Type)
+		if !proposalRelevant(spec, t) {
+			continue
+		}
+		proposal := blockchainv1alpha1.GovernanceProposal{
+			ID:            p.ProposalID,
+			Type:          t,
+			Title:         p.Content.Title,
+			VotingEndTime: metav1.Time{Time: p.VotingEndTime},
+		}
+		if spec.VoterAddress != "" {
+			voted, err := api.Voted(ctx, p.ProposalID, spec.VoterAddress)
+			if err != nil {
+				log.V(1).Info("Unable to query the vote", "proposal", p.ProposalID, "error", err.Error())
+			} else {
+				proposal.Voted = &voted
+			}
+		}
+		left := time.Until(p.VotingEndTime)
+		pending := proposal.Voted == nil || !*proposal.Voted
+
+		if seen, ok := known[p.ProposalID]; !ok {
+			text := fmt.Sprintf(":ballot_box: Proposal #%s %q (%s) is open for voting until %s, %s left",
+				p.ProposalID, p.Content.Title, t, p.VotingEndTime.UTC().Format(time.RFC3339), votingCountdown(left))
+			r.notifyGovernance(ctx, axelarNode, text)
+			proposal.Reminded = left <= reminderBefore
+		} else {
+			proposal.Reminded = seen.Reminded
+		}
+		if !proposal.Reminded && pending && left <= reminderBefore {
+			text := fmt.Sprintf(":alarm_clock: Voting on proposal #%s %q ends in %s", p.ProposalID, p.Content.Title, votingCountdown(left))
+			if proposal.Voted != nil {
+				text += fmt.Sprintf("; %s has not voted", spec.VoterAddress)
+			}
+			r.notifyGovernance(ctx, axelarNode, text)
+			proposal.Reminded = true
+		}
+		status.ActiveProposals = append(status.ActiveProposals, proposal)
+	}
+	sort.Slice(status.ActiveProposals, func(i, j int) bool {
+		return status.ActiveProposals[i].VotingEndTime.Before(&status.ActiveProposals[j].VotingEndTime)
+	})
+	axelarNode.Status.Governance = status
+	return nil
+}
+
+// notifyGovernance records a proposal event and sends it to the alert channels
+func (r *AxelarNodeReconciler) notifyGovernance(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, text string) {
+	r.Log.Info("Governance notification", "axelarnode", axelarNode.Name, "message", text)
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, corev1.EventTypeNormal, "GovernanceProposal", text)
+	}
+	if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+		r.Log.Error(err, "Unable to send governance alert", "axelarnode", axelarNode.Name)
+	}
+}
+
+// latestGovernance returns the most recent proposals reported by the members
+func latestGovernance(members []blockchainv1alpha1.AxelarNode) *blockchainv1alpha1.GovernanceStatus {
+	var latest *blockchainv1alpha1.GovernanceStatus
+	for i := range members {
+		status := members[i].Status.Governance
+		if status == nil || status.LastChecked == nil {
+			continue
+		}
+		if latest == nil || latest.LastChecked.Before(status.LastChecked) {
+			latest = status
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	return latest.DeepCopy()
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// haltTime returns the halt-time of app.toml in Unix seconds, 0 when unset
+func haltTime(axelarNode *blockchainv1alpha1.AxelarNode) int64 {
+	if axelarNode.Spec.HaltTime == nil {
+		return 0
+	}
+	return axelarNode.Spec.HaltTime.Unix()
+}
+
+// haltConfigured reports whether a halt height or time is set
+func haltConfigured(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Spec.HaltHeight > 0 || axelarNode.Spec.HaltTime != nil
+}
+
+// haltPending reports whether the node is yet to reach its halt
+func haltPending(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return haltConfigured(axelarNode) && axelarNode.Status.Phase != "Halted"
+}
+
+// haltReached returns why the node has halted, or an empty string. The node
+// has halted once it reports the halt height or a block at the halt time.
+// axelard exits cleanly when it halts, so a restarted node container that
+// exited with code 0 also counts.
+func (r *AxelarNodeReconciler) haltReached(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (string, error) {
+	sync := axelarNode.Status.SyncInfo
+	if height := axelarNode.Spec.HaltHeight; height > 0 && sync.CurrentHeight >= height {
+		return fmt.Sprintf("Node halted at height %d", height), nil
+	}
+	if at := axelarNode.Spec.HaltTime; at != nil && sync.LastSyncTime != nil && !sync.LastSyncTime.Before(at) {
+		return fmt.Sprintf("Node halted at %s", at.UTC().Format(time.RFC3339)), nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(axelarNode.Namespace), client.MatchingLabels(nodeSelector(axelarNode))); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != "axelar-node" {
+				continue
+			}
+			for _, state := range []corev1.ContainerState{status.State, status.LastTerminationState} {
+				if state.Terminated != nil && state.Terminated.ExitCode == 0 {
+					return fmt.Sprintf("Node exited at height %d after reaching its halt configuration", sync.CurrentHeight), nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// reportHalt sets the Halted phase once the node has stopped at its halt
+// height or time. Clearing the halt changes the configuration, which restarts
+// the node.
+func (r *AxelarNodeReconciler) reportHalt(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if !haltConfigured(axelarNode) {
+		meta.RemoveStatusCondition(&axelarNode.Status.Conditions, blockchainv1alpha1.ConditionHalted)
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionHalted,
+		Status:             metav1.ConditionFalse,
+		Reason:             "HaltScheduled",
+		ObservedGeneration: axelarNode.Generation,
+	}
+	switch {
+	case axelarNode.Spec.HaltHeight > 0 && axelarNode.Spec.HaltTime != nil:
+		condition.Message = fmt.Sprintf("Node halts at height %d or at %s", axelarNode.Spec.HaltHeight, axelarNode.Spec.HaltTime.UTC().Format(time.RFC3339))
+	case axelarNode.Spec.HaltHeight > 0:
+		condition.Message = fmt.Sprintf("Node halts at height %d", axelarNode.Spec.HaltHeight)
+	default:
+		condition.Message = fmt.Sprintf("Node halts at %s", axelarNode.Spec.HaltTime.UTC().Format(time.RFC3339))
+	}
+
+	reason, err := r.haltReached(ctx, axelarNode)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		if previous := meta.FindStatusCondition(axelarNode.Status.Conditions, condition.Type); previous == nil || previous.Status != metav1.ConditionTrue {
+			r.Log.Info("Node halted", "axelarnode", axelarNode.Name, "reason", reason)
+			if r.Recorder != nil {
+				r.Recorder.Event(axelarNode, corev1.EventTypeNormal, "Halted", reason)
+			}
+		}
+		axelarNode.Status.Phase = "Halted"
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "HaltReached"
+		condition.Message = reason
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	return nil
+}
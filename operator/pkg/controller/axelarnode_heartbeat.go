@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// heartbeatCheckInterval is how often the heartbeats are queried. vald sends
+// one every 50 blocks, about five minutes.
+const heartbeatCheckInterval = time.Minute
+
+// defaultMaxBlocksSinceHeartbeat applies when the spec leaves the threshold unset
+const defaultMaxBlocksSinceHeartbeat = 100
+
+// blocksSinceHeartbeat is exported on the operator metrics endpoint
+var blocksSinceHeartbeat = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "axelar_node_blocks_since_heartbeat",
+	Help: "Blocks since the last heartbeat sent by vald",
+}, []string{"namespace", "name"})
+
+func init() {
+	metrics.Registry.MustRegister(blocksSinceHeartbeat)
+}
+
+// reconcileHeartbeat reports the blocks since vald last sent a heartbeat.
+// An alert is sent when they exceed the threshold, as validators missing
+// heartbeats are penalized, and again once heartbeats resume.
+func (r *AxelarNodeReconciler) reconcileHeartbeat(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	var spec *blockchainv1alpha1.HeartbeatSpec
+	if isValidatorNode(axelarNode) {
+		spec = axelarNode.Spec.Validator.Heartbeat
+	}
+	if spec == nil || spec.BroadcasterAddress == "" {
+		axelarNode.Status.Heartbeat = nil
+		blocksSinceHeartbeat.DeleteLabelValues(axelarNode.Namespace, axelarNode.Name)
+		return nil
+	}
+	previous := axelarNode.Status.Heartbeat
+	if previous != nil && previous.LastChecked != nil && time.Since(previous.LastChecked.Time) < heartbeatCheckInterval {
+		return nil
+	}
+	height := axelarNode.Status.SyncInfo.CurrentHeight
+	if height == 0 {
+		return nil
+	}
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	_, api, err := r.statusClients(ctx, axelarNode)
+	if err != nil {
+		log.V(1).Info("Unable to reach the status source", "error", err.Error())
+		return nil
+	}
+	last, err := api.LastHeartbeat(ctx, spec.BroadcasterAddress)
+	if err != nil {
+		log.V(1).Info("Unable to query heartbeats", "error", err.Error())
+		return nil
+	}
+	// Keep the last heartbeat found when it dropped out of the recent transactions
+	if last == 0 && previous != nil {
+		last = previous.LastHeight
+	}
+
+	now := metav1.Now()
+	status := &blockchainv1alpha1.HeartbeatStatus{LastChecked: &now, LastHeight: last}
+	if last > 0 && height > last {
+		status.BlocksSince = height - last
+	}
+	blocksSinceHeartbeat.WithLabelValues(axelarNode.Namespace, axelarNode.Name).Set(float64(status.BlocksSince))
+
+	max := spec.MaxBlocksSinceHeartbeat
+	if max == 0 {
+		max = defaultMaxBlocksSinceHeartbeat
+	}
+	// Without any heartbeat found the node may be new, so only a heartbeat
+	// seen before counts as missed
+	status.Missing = last > 0 && status.BlocksSince > max
+	wasMissing := previous != nil && previous.Missing
+	switch {
+	case status.Missing && !wasMissing:
+		r.notifyHeartbeat(ctx, axelarNode, corev1.EventTypeWarning, "HeartbeatMissed",
+			fmt.Sprintf(":heart: No heartbeat from %s for %d blocks, since height %d; vald may be down or unable to broadcast",
+				spec.BroadcasterAddress, status.BlocksSince, last))
+	case !status.Missing && wasMissing:
+		r.notifyHeartbeat(ctx, axelarNode, corev1.EventTypeNormal, "HeartbeatResumed",
+			fmt.Sprintf(":heartbeat: Heartbeats from %s resumed at height %d", spec.BroadcasterAddress, last))
+	}
+	axelarNode.Status.Heartbeat = status
+	return nil
+}
+
+// notifyHeartbeat records a heartbeat event and sends it to the alert channels
+func (r *AxelarNodeReconciler) notifyHeartbeat(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, eventType, reason, text string) {
+	r.Log.Info("Heartbeat notification", "axelarnode", axelarNode.Name, "message", text)
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, eventType, reason, text)
+	}
+	if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+		r.Log.Error(err, "Unable to send heartbeat alert", "axelarnode", axelarNode.Name)
+	}
+}
@@ -0,0 +1,323 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+)
+
+// indexerAnnotation holds a hash of the PostgreSQL indexer settings of the
+// pods, so changing the database rolls the node
+const indexerAnnotation = "blockchain.axelar.network/indexer"
+
+// defaultPSQLImage runs psql for the schema Job and the wait of the node
+const defaultPSQLImage = "postgres:16-alpine"
+
+// psqlSchemaRetryInterval is how long a failed schema Job waits before the
+// next attempt
+const psqlSchemaRetryInterval = 5 * time.Minute
+
+// axelard reads its configuration through viper, which overrides the keys of
+// config.toml with AXELARD_ variables. The connection string holds the
+// database password, so it is passed from its Secret rather than rendered.
+const (
+	psqlConnEnv = "AXELARD_TX_INDEX_PSQL_CONN"
+	indexerEnv  = "AXELARD_TX_INDEX_INDEXER"
+)
+
+// waitIndexerContainer holds the node until the schema of the indexer exists,
+// so no block is committed without being indexed
+const waitIndexerContainer = "wait-indexer-schema"
+
+// psqlSchema is the schema of the PostgreSQL event sink of CometBFT. Each
+// statement is idempotent, so the Job runs again safely on a database that
+// already has it.
+const psqlSchema = `CREATE TABLE IF NOT EXISTS blocks (
+  rowid      BIGSERIAL PRIMARY KEY,
+  height     BIGINT NOT NULL,
+  chain_id   VARCHAR NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  UNIQUE (height, chain_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocks_height_chain ON blocks(height, chain_id);
+
+CREATE TABLE IF NOT EXISTS tx_results (
+  rowid      BIGSERIAL PRIMARY KEY,
+  block_id   BIGINT NOT NULL REFERENCES blocks(rowid),
+  index      INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  tx_hash    VARCHAR NOT NULL,
+  tx_result  BYTEA NOT NULL,
+  UNIQUE (block_id, index)
+);
+
+CREATE TABLE IF NOT EXISTS events (
+  rowid    BIGSERIAL PRIMARY KEY,
+  block_id BIGINT NOT NULL REFERENCES blocks(rowid),
+  tx_id    BIGINT NULL REFERENCES tx_results(rowid),
+  type     VARCHAR NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS attributes (
+  event_id      BIGINT NOT NULL REFERENCES events(rowid),
+  key           VARCHAR NOT NULL,
+  composite_key VARCHAR NOT NULL,
+  value         VARCHAR NULL,
+  UNIQUE (event_id, key)
+);
+
+CREATE OR REPLACE VIEW event_attributes AS
+  SELECT block_id, tx_id, type, key, composite_key, value
+  FROM events LEFT JOIN attributes ON (events.rowid = attributes.event_id);
+
+CREATE OR REPLACE VIEW block_events AS
+  SELECT blocks.rowid as block_id, height, chain_id, type, key, composite_key, value
+  FROM blocks JOIN event_attributes ON (blocks.rowid = event_attributes.block_id)
+  WHERE event_attributes.tx_id IS NULL;
+
+CREATE OR REPLACE VIEW tx_events AS
+  SELECT height, index, chain_id, type, key, composite_key, value, tx_results.created_at
+  FROM blocks JOIN tx_results ON (blocks.rowid = tx_results.block_id)
+  JOIN event_attributes ON (tx_results.rowid = event_attributes.tx_id)
+  WHERE event_attributes.tx_id IS NOT NULL;
+`
+
+// psqlIndexing returns the PostgreSQL indexer of the node, or nil
+func psqlIndexing(axelarNode *blockchainv1alpha1.AxelarNode) *blockchainv1alpha1.PSQLIndexingSpec {
+	if indexing := axelarNode.Spec.Indexing; indexing != nil {
+		return indexing.PSQL
+	}
+	return nil
+}
+
+// psqlImage returns the image running psql
+func psqlImage(psql *blockchainv1alpha1.PSQLIndexingSpec) string {
+	if psql.Image != "" {
+		return psql.Image
+	}
+	return defaultPSQLImage
+}
+
+// psqlSchemaHash identifies the schema, the database and the image, so the
+// Job runs again when one of them changes
+func psqlSchemaHash(psql *blockchainv1alpha1.PSQLIndexingSpec) string {
+	h := sha256.New()
+	for _, value := range []string{psqlSchema, psql.ConnectionSecretRef.Name, psql.ConnectionSecretRef.Key, psqlImage(psql)} {
+		h.Write([]byte(value))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// txIndexConfig returns the [tx_index] section of config.toml, or nil to
+// keep the embedded index. The connection string is left empty for the
+// variable of addIndexer to fill.
+func txIndexConfig(axelarNode *blockchainv1alpha1.AxelarNode) *txIndexTOML {
+	if psqlIndexing(axelarNode) == nil {
+		return nil
+	}
+	return &txIndexTOML{Indexer: "psql"}
+}
+
+// psqlConnectionEnv passes the connection string from its Secret
+func psqlConnectionEnv(name string, psql *blockchainv1alpha1.PSQLIndexingSpec) corev1.EnvVar {
+	ref := psql.ConnectionSecretRef
+	return corev1.EnvVar{
+		Name:      name,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &ref},
+	}
+}
+
+// addIndexer passes the connection string of the PostgreSQL indexer to the
+// node, and holds it until the schema exists
+func addIndexer(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	psql := psqlIndexing(axelarNode)
+	if psql == nil {
+		return
+	}
+	container := &podSpec.Containers[0]
+	container.Env = append(container.Env, psqlConnectionEnv(psqlConnEnv, psql))
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:    waitIndexerContainer,
+		Image:   psqlImage(psql),
+		Command: []string{"sh", "-c", `until psql "$DATABASE_URL" -tAc 'SELECT 1 FROM blocks LIMIT 1' >/dev/null; do echo "Waiting for the indexer schema"; sleep 5; done`},
+		Env:     []corev1.EnvVar{psqlConnectionEnv("DATABASE_URL", psql)},
+	})
+}
+
+// removeIndexer keeps the standby on the embedded index, so the database
+// only receives the blocks of the active node
+func removeIndexer(podSpec *corev1.PodSpec) {
+	container := &podSpec.Containers[0]
+	env := container.Env[:0]
+	for _, v := range container.Env {
+		if v.Name != psqlConnEnv {
+			env = append(env, v)
+		}
+	}
+	container.Env = append(env, corev1.EnvVar{Name: indexerEnv, Value: "kv"})
+
+	initContainers := podSpec.InitContainers[:0]
+	for _, c := range podSpec.InitContainers {
+		if c.Name != waitIndexerContainer {
+			initContainers = append(initContainers, c)
+		}
+	}
+	podSpec.InitContainers = initContainers
+}
+
+// psqlSchemaName names the Job initializing the schema
+func psqlSchemaName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "psql-schema")
+}
+
+// reconcileIndexing initializes the schema of the PostgreSQL indexer with a
+// Job, once per schema and database. A failed Job is retried after
+// psqlSchemaRetryInterval; the node waits for the schema meanwhile.
+func (r *AxelarNodeReconciler) reconcileIndexing(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	psql := psqlIndexing(axelarNode)
+	if psql == nil {
+		axelarNode.Status.Indexing = nil
+		return nil
+	}
+	if psql.InitSchema != nil && !*psql.InitSchema {
+		axelarNode.Status.Indexing = &blockchainv1alpha1.IndexingStatus{
+			Phase:   blockchainv1alpha1.IndexingSchemaReady,
+			Message: "The schema is managed outside the operator",
+		}
+		return nil
+	}
+	ctx = audit.WithReason(ctx, "indexer-schema")
+	hash := psqlSchemaHash(psql)
+	status := axelarNode.Status.Indexing
+	if status != nil && status.SchemaHash == hash {
+		switch status.Phase {
+		case blockchainv1alpha1.IndexingSchemaReady:
+			return nil
+		case blockchainv1alpha1.IndexingSchemaInitializing:
+			return r.checkPSQLSchemaJob(ctx, axelarNode, status)
+		case blockchainv1alpha1.IndexingSchemaFailed:
+			if status.CompletedAt != nil && time.Since(status.CompletedAt.Time) < psqlSchemaRetryInterval {
+				return nil
+			}
+		}
+	}
+
+	job := r.createPSQLSchemaJob(axelarNode, psql)
+	if err := r.deleteJob(ctx, axelarNode, job.Name); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, job); err != nil {
+		if errors.IsAlreadyExists(err) {
+			// The Job of the last attempt is still being deleted
+			return nil
+		}
+		return err
+	}
+	r.Log.WithValues("axelarnode", axelarNode.Name).Info("Initializing the indexer schema", "secret", psql.ConnectionSecretRef.Name)
+	axelarNode.Status.Indexing = &blockchainv1alpha1.IndexingStatus{
+		Phase:      blockchainv1alpha1.IndexingSchemaInitializing,
+		SchemaHash: hash,
+		Job:        job.Name,
+		Message:    "Initializing the schema of the indexer",
+	}
+	return nil
+}
+
+// checkPSQLSchemaJob records the outcome of the schema Job once it is done
+func (r *AxelarNodeReconciler) checkPSQLSchemaJob(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, status *blockchainv1alpha1.IndexingStatus) error {
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: status.Job, Namespace: axelarNode.Namespace}, job)
+	if errors.IsNotFound(err) {
+		r.completePSQLSchema(axelarNode, status, false, "The indexer schema Job was deleted")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if job.Status.Succeeded > 0 {
+		r.completePSQLSchema(axelarNode, status, true, "The schema of the indexer is initialized")
+		return nil
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			output, err := r.txJobOutput(ctx, job)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				output = "the indexer schema Job failed, see its logs"
+			}
+			r.completePSQLSchema(axelarNode, status, false, fmt.Sprintf("Unable to initialize the indexer schema, retrying in %s: %s", psqlSchemaRetryInterval, output))
+			return nil
+		}
+	}
+	return nil
+}
+
+// completePSQLSchema records the outcome of the schema Job
+func (r *AxelarNodeReconciler) completePSQLSchema(axelarNode *blockchainv1alpha1.AxelarNode, status *blockchainv1alpha1.IndexingStatus, succeeded bool, message string) {
+	status.CompletedAt = &metav1.Time{Time: time.Now()}
+	status.Message = message
+	eventType, reason := corev1.EventTypeNormal, "IndexerSchemaReady"
+	status.Phase = blockchainv1alpha1.IndexingSchemaReady
+	if !succeeded {
+		eventType, reason = corev1.EventTypeWarning, "IndexerSchemaFailed"
+		status.Phase = blockchainv1alpha1.IndexingSchemaFailed
+	}
+	axelarNode.Status.Indexing = status
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, eventType, reason, message)
+	}
+}
+
+// createPSQLSchemaJob creates the Job applying the schema in one transaction.
+// It retries a few times, as the database may start with the node.
+func (r *AxelarNodeReconciler) createPSQLSchemaJob(axelarNode *blockchainv1alpha1.AxelarNode, psql *blockchainv1alpha1.PSQLIndexingSpec) *batchv1.Job {
+	name := psqlSchemaName(axelarNode)
+	backoffLimit := int32(4)
+	ttl := int32(24 * 60 * 60)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: axelarNode.Namespace,
+			Labels:    map[string]string{"app": name},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:    "psql-schema",
+						Image:   psqlImage(psql),
+						Command: []string{"sh", "-c", `printf '%s\n' "$SCHEMA" | psql "$DATABASE_URL" -v ON_ERROR_STOP=1 --single-transaction`},
+						Env: []corev1.EnvVar{
+							psqlConnectionEnv("DATABASE_URL", psql),
+							{Name: "SCHEMA", Value: psqlSchema},
+						},
+						// The errors of psql become the message of the status
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					}},
+					SecurityContext: axelarNode.Spec.Security.PodSecurityContext,
+				},
+			},
+		},
+	}
+	controllerutil.SetControllerReference(axelarNode, job, r.Scheme)
+	return job
+}
@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/integrity"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/maintenance"
+)
+
+// integrityCheckContainer is the container of the integrity check Job
+const integrityCheckContainer = "integrity-check"
+
+// defaultIntegrityCheckTimeout bounds a check when the spec leaves it unset
+const defaultIntegrityCheckTimeout = 30 * time.Minute
+
+// integrityCheckName names the Job of an integrity check
+func integrityCheckName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "integrity")
+}
+
+// integrityCheckRunning reports whether an integrity check is in progress
+func integrityCheckRunning(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	check := axelarNode.Status.IntegrityCheck
+	return check != nil && check.Phase == blockchainv1alpha1.IntegrityCheckRunning
+}
+
+// reconcileIntegrityCheck runs the requested and scheduled integrity checks.
+// A Job compares the latest blocks of the node and its committed app hash
+// with the trusted RPC while the node keeps running.
+func (r *AxelarNodeReconciler) reconcileIntegrityCheck(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	spec := axelarNode.Spec.Storage.IntegrityCheck
+	check := axelarNode.Status.IntegrityCheck
+	ctx = audit.WithReason(ctx, "integrity-check")
+
+	if !integrityCheckRunning(axelarNode) {
+		_, requested := axelarNode.Annotations[blockchainv1alpha1.IntegrityCheckAnnotation]
+		if spec == nil {
+			if requested {
+				delete(axelarNode.Annotations, blockchainv1alpha1.IntegrityCheckAnnotation)
+				refused := &blockchainv1alpha1.IntegrityCheckStatus{StartedAt: &metav1.Time{Time: time.Now()}}
+				if check != nil {
+					refused.LastSuccess = check.LastSuccess
+				}
+				r.completeIntegrityCheck(ctx, axelarNode, refused, blockchainv1alpha1.IntegrityCheckFailed,
+					"Integrity check refused: set spec.storage.integrityCheck.trustedRPC")
+			}
+			return nil
+		}
+		if !requested {
+			due, err := integrityCheckDue(axelarNode, spec)
+			if err != nil || !due {
+				return err
+			}
+		}
+		// The node must serve its RPC to be checked
+		if axelarNode.Status.Phase != "Running" {
+			return nil
+		}
+		job := r.createIntegrityCheckJob(axelarNode, spec)
+		if err := r.deleteJob(ctx, axelarNode, job.Name); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			if errors.IsAlreadyExists(err) {
+				// The Job of the last check is still being deleted
+				return nil
+			}
+			return err
+		}
+		delete(axelarNode.Annotations, blockchainv1alpha1.IntegrityCheckAnnotation)
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Starting integrity check", "trustedRPC", spec.TrustedRPC, "blocks", spec.Blocks)
+		next := &blockchainv1alpha1.IntegrityCheckStatus{
+			Phase:     blockchainv1alpha1.IntegrityCheckRunning,
+			Job:       job.Name,
+			StartedAt: &metav1.Time{Time: time.Now()},
+			Message:   fmt.Sprintf("Comparing the last %d blocks with %s", spec.Blocks, spec.TrustedRPC),
+		}
+		if check != nil {
+			next.LastSuccess = check.LastSuccess
+		}
+		axelarNode.Status.IntegrityCheck = next
+		return nil
+	}
+
+	timeout := defaultIntegrityCheckTimeout
+	if spec != nil && spec.Timeout.Duration > 0 {
+		timeout = spec.Timeout.Duration
+	}
+	if time.Since(check.StartedAt.Time) > timeout {
+		if err := r.deleteJob(ctx, axelarNode, check.Job); err != nil {
+			return err
+		}
+		r.completeIntegrityCheck(ctx, axelarNode, check, blockchainv1alpha1.IntegrityCheckFailed, fmt.Sprintf("Integrity check timed out after %s", timeout))
+		return nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: check.Job, Namespace: axelarNode.Namespace}, job)
+	if errors.IsNotFound(err) {
+		r.completeIntegrityCheck(ctx, axelarNode, check, blockchainv1alpha1.IntegrityCheckFailed, "The integrity check Job was deleted")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		return nil
+	}
+
+	output, err := r.txJobOutput(ctx, job)
+	if err != nil {
+		return err
+	}
+	if job.Status.Succeeded == 0 {
+		if output == "" {
+			output = "the integrity check Job failed, see its logs"
+		}
+		r.completeIntegrityCheck(ctx, axelarNode, check, blockchainv1alpha1.IntegrityCheckFailed, fmt.Sprintf("Integrity check failed: %s", output))
+		return nil
+	}
+	result := &integrity.Result{}
+	if err := json.Unmarshal([]byte(output), result); err != nil {
+		r.completeIntegrityCheck(ctx, axelarNode, check, blockchainv1alpha1.IntegrityCheckFailed, fmt.Sprintf("Unable to read the integrity check result %q", output))
+		return nil
+	}
+
+	check.FromHeight, check.ToHeight, check.AppHashHeight = result.FromHeight, result.ToHeight, result.AppHashHeight
+	check.MismatchedHeights = nil
+	for _, mismatch := range result.Mismatches {
+		check.MismatchedHeights = append(check.MismatchedHeights, mismatch.Height)
+	}
+	if result.Corrupted() {
+		r.completeIntegrityCheck(ctx, axelarNode, check, blockchainv1alpha1.IntegrityCheckCorrupted, corruptionMessage(result))
+		return nil
+	}
+	message := fmt.Sprintf("Blocks %d to %d match the trusted RPC", result.FromHeight, result.ToHeight)
+	if result.AppHashHeight > 0 {
+		message += fmt.Sprintf(", and so does the state committed at height %d", result.AppHashHeight)
+	}
+	r.completeIntegrityCheck(ctx, axelarNode, check, blockchainv1alpha1.IntegrityCheckPassed, message)
+	return nil
+}
+
+// integrityCheckDue reports whether the schedule calls for a check since the last one
+func integrityCheckDue(axelarNode *blockchainv1alpha1.AxelarNode, spec *blockchainv1alpha1.IntegrityCheckSpec) (bool, error) {
+	if spec.Schedule == "" {
+		return false, nil
+	}
+	schedule, err := maintenance.ParseSchedule(spec.Schedule)
+	if err != nil {
+		return false, err
+	}
+	last := axelarNode.CreationTimestamp.Time
+	if check := axelarNode.Status.IntegrityCheck; check != nil && check.StartedAt != nil {
+		last = check.StartedAt.Time
+	}
+	return !schedule.Next(last).After(time.Now()), nil
+}
+
+// corruptionMessage describes the data a check found differing from the trusted RPC
+func corruptionMessage(result *integrity.Result) string {
+	var problems []string
+	if result.AppHashMismatch {
+		problems = append(problems, fmt.Sprintf("the state committed at height %d differs", result.AppHashHeight))
+	}
+	for _, mismatch := range result.Mismatches {
+		problems = append(problems, fmt.Sprintf("block %d differs in %s", mismatch.Height, strings.Join(mismatch.Fields, ", ")))
+	}
+	return fmt.Sprintf("Chain data differs from the trusted RPC: %s", strings.Join(problems, "; "))
+}
+
+// completeIntegrityCheck records the result of a check, and alerts when the
+// data is corrupted
+func (r *AxelarNodeReconciler) completeIntegrityCheck(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, check *blockchainv1alpha1.IntegrityCheckStatus, phase, message string) {
+	now := &metav1.Time{Time: time.Now()}
+	check.Phase = phase
+	check.CompletedAt = now
+	check.Message = message
+	axelarNode.Status.IntegrityCheck = check
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionDataIntegrity,
+		Status:             metav1.ConditionTrue,
+		Reason:             "CheckPassed",
+		Message:            message,
+		ObservedGeneration: axelarNode.Generation,
+	}
+	switch phase {
+	case blockchainv1alpha1.IntegrityCheckPassed:
+		check.LastSuccess = now
+		log.Info("Integrity check passed", "from", check.FromHeight, "to", check.ToHeight)
+	case blockchainv1alpha1.IntegrityCheckCorrupted:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "CorruptionDetected"
+		log.Info("Integrity check found corrupted chain data", "heights", check.MismatchedHeights, "reason", message)
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "ChainDataCorrupted", message)
+		}
+		text := fmt.Sprintf(":rotating_light: Corrupted chain data on %s/%s: %s. Restore a backup or resync the node.", axelarNode.Namespace, axelarNode.Name, message)
+		if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+			log.Error(err, "Unable to send integrity check alert")
+		}
+	default:
+		// The data was not checked, so the last verdict stands
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "CheckFailed"
+		if previous := meta.FindStatusCondition(axelarNode.Status.Conditions, condition.Type); previous != nil && previous.Reason == "CorruptionDetected" {
+			condition.Status, condition.Reason = previous.Status, previous.Reason
+			condition.Message = previous.Message
+		}
+		log.Info("Integrity check failed", "reason", message)
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "IntegrityCheckFailed", message)
+		}
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+}
+
+// createIntegrityCheckJob creates the Job comparing the node with the trusted RPC
+func (r *AxelarNodeReconciler) createIntegrityCheckJob(axelarNode *blockchainv1alpha1.AxelarNode, spec *blockchainv1alpha1.IntegrityCheckSpec) *batchv1.Job {
+	blocks := spec.Blocks
+	if blocks < 1 {
+		blocks = 100
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{{
+			Name:    integrityCheckContainer,
+			Image:   r.ToolsImage,
+			Command: []string{"/root/manager"},
+			Args: []string{
+				"integrity-check",
+				"--node=" + nodeRPCURL(axelarNode),
+				"--trusted=" + spec.TrustedRPC,
+				fmt.Sprintf("--blocks=%d", blocks),
+			},
+		}},
+		SecurityContext: axelarNode.Spec.Security.PodSecurityContext,
+	}
+	addProxy(axelarNode, &podSpec)
+
+	backoffLimit := int32(0)
+	ttl := int32(24 * 60 * 60)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      integrityCheckName(axelarNode),
+			Namespace: axelarNode.Namespace,
+			Labels:    map[string]string{"app": integrityCheckName(axelarNode)},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": integrityCheckName(axelarNode)}},
+				Spec:       podSpec,
+			},
+		},
+	}
+	controllerutil.SetControllerReference(axelarNode, job, r.Scheme)
+	return job
+}
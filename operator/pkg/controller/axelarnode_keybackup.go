@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// nodeForKeyBackup maps a key backup to the node whose keys it escrows
+func nodeForKeyBackup(ctx context.Context, obj client.Object) []reconcile.Request {
+	keyBackup, ok := obj.(*blockchainv1alpha1.AxelarNodeKeyBackup)
+	if !ok || keyBackup.Spec.NodeRef.Name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: keyBackup.Spec.NodeRef.Name, Namespace: keyBackup.Namespace}}}
+}
+
+// reconcileKeyBackups sets the KeysEscrowed condition of validators with
+// spec.validator.keyManagement.backupKeys from the latest key backup
+// escrowing their keys
+func (r *AxelarNodeReconciler) reconcileKeyBackups(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if !isValidatorNode(axelarNode) || !axelarNode.Spec.Validator.KeyManagement.BackupKeys {
+		meta.RemoveStatusCondition(&axelarNode.Status.Conditions, blockchainv1alpha1.ConditionKeysEscrowed)
+		return nil
+	}
+	keyBackups := &blockchainv1alpha1.AxelarNodeKeyBackupList{}
+	if err := r.List(ctx, keyBackups, client.InNamespace(axelarNode.Namespace)); err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionKeysEscrowed,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoKeyBackup",
+		Message:            "No AxelarNodeKeyBackup has escrowed the keys of this validator",
+		ObservedGeneration: axelarNode.Generation,
+	}
+	var latest *blockchainv1alpha1.AxelarNodeKeyBackup
+	for i := range keyBackups.Items {
+		keyBackup := &keyBackups.Items[i]
+		if keyBackup.Spec.NodeRef.Name != axelarNode.Name || keyBackup.Status.LastBackup == nil {
+			continue
+		}
+		if latest == nil || latest.Status.LastBackup.Before(keyBackup.Status.LastBackup) {
+			latest = keyBackup
+		}
+	}
+	if latest != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "KeysEscrowed"
+		condition.Message = fmt.Sprintf("%s escrowed the keys to %s at %s, fingerprint %s", latest.Name,
+			latest.Status.Object, latest.Status.LastBackup.UTC().Format("2006-01-02T15:04:05Z"), latest.Status.Fingerprint)
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	return nil
+}
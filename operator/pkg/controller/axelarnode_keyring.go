@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// defaultKeyringBackend encrypts the keys with the keyring password
+const defaultKeyringBackend = "file"
+
+// keyringMigrationScript copies every key of the old keyring into the new
+// one. The old keyring is left in place, so a failed migration loses no key.
+const keyringMigrationScript = `set -e
+home=/home/axelard/.axelar
+passwords() { printf '%s\n%s\n%s\n' "$KEYRING_PASSWORD" "$KEYRING_PASSWORD" "$KEYRING_PASSWORD"; }
+for name in $(passwords | axelard keys list --list-names --keyring-backend "$FROM_BACKEND" --home "$home"); do
+  if passwords | axelard keys show "$name" --keyring-backend "$TO_BACKEND" --home "$home" >/dev/null 2>&1; then
+    echo "$name is already in the $TO_BACKEND keyring"
+    continue
+  fi
+  passwords | axelard keys export "$name" --keyring-backend "$FROM_BACKEND" --home "$home" 2>&1 \
+    | sed -n '/-----BEGIN/,/-----END/p' > /tmp/key.armor
+  passwords | axelard keys import "$name" /tmp/key.armor --keyring-backend "$TO_BACKEND" --home "$home"
+  rm -f /tmp/key.armor
+  echo "Migrated $name to the $TO_BACKEND keyring"
+done
+sync
+`
+
+// keyringBackend returns the keyring backend of the spec
+func keyringBackend(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if backend := axelarNode.Spec.Security.KeyringBackend; backend != "" {
+		return backend
+	}
+	return defaultKeyringBackend
+}
+
+// migratedKeyringBackend returns the keyring backend holding the keys of the node
+func migratedKeyringBackend(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if backend := axelarNode.Status.KeyringBackend; backend != "" {
+		return backend
+	}
+	return defaultKeyringBackend
+}
+
+// keyringMigrationNeeded reports whether the keys still have to be copied to
+// the keyring backend of the spec. A node that never ran has no keys yet, so
+// its backend is recorded without a migration.
+func (r *AxelarNodeReconciler) keyringMigrationNeeded(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	if keyringBackend(axelarNode) == migratedKeyringBackend(axelarNode) {
+		return false, nil
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: deploymentName(axelarNode), Namespace: axelarNode.Namespace}, &appsv1.Deployment{})
+	if errors.IsNotFound(err) {
+		axelarNode.Status.KeyringBackend = keyringBackend(axelarNode)
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// addKeyringBackend selects the keyring backend of the spec in the node and
+// vald containers. The default file backend leaves them unchanged.
+func addKeyringBackend(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	backend := keyringBackend(axelarNode)
+	if backend == defaultKeyringBackend {
+		return
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name == "axelar-node" || container.Name == "vald" {
+			container.Env = append(container.Env, corev1.EnvVar{Name: "KEYRING_BACKEND", Value: backend})
+		}
+	}
+}
+
+// addKeyringMigration turns the operation Job into a keyring migration from
+// the backend in the status to the one in op.Archive
+func addKeyringMigration(axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus, podSpec *corev1.PodSpec) {
+	container := &podSpec.Containers[0]
+	container.Command = []string{"sh", "-c", keyringMigrationScript}
+	container.Env = []corev1.EnvVar{
+		{Name: "FROM_BACKEND", Value: migratedKeyringBackend(axelarNode)},
+		{Name: "TO_BACKEND", Value: op.Archive},
+		{
+			Name: "KEYRING_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: passwordsSecretName(axelarNode)},
+					Key:                  "keyring-password",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// keySharesCheckInterval is how often the multisig keys are queried
+const keySharesCheckInterval = 10 * time.Minute
+
+// keySharesOperator returns the operator address whose key shares are
+// checked
+func keySharesOperator(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	validator := axelarNode.Spec.Validator
+	if validator.KeyShares.OperatorAddress != "" {
+		return validator.KeyShares.OperatorAddress
+	}
+	if validator.Profile != nil {
+		return validator.Profile.OperatorAddress
+	}
+	return ""
+}
+
+// reconcileKeyShares reports the active and next multisig keys of the
+// selected chains and whether the validator holds a share of each. An alert
+// is sent when a key without a share of the validator appears, as after a
+// rotation it missed the keygen of.
+func (r *AxelarNodeReconciler) reconcileKeyShares(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if !isValidatorNode(axelarNode) || axelarNode.Spec.Validator.KeyShares == nil {
+		if info := axelarNode.Status.ValidatorInfo; info != nil {
+			info.KeyShares = nil
+			info.KeySharesChecked = nil
+		}
+		return nil
+	}
+	info := axelarNode.Status.ValidatorInfo
+	if info != nil && info.KeySharesChecked != nil && time.Since(info.KeySharesChecked.Time) < keySharesCheckInterval {
+		return nil
+	}
+	operator := keySharesOperator(axelarNode)
+	if operator == "" {
+		return nil
+	}
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	known := map[string]blockchainv1alpha1.KeyShare{}
+	if info != nil {
+		for _, share := range info.KeyShares {
+			known[share.KeyID] = share
+		}
+	}
+
+	_, api, err := r.statusClients(ctx, axelarNode)
+	if err != nil {
+		log.V(1).Info("Unable to reach the status source", "error", err.Error())
+		return nil
+	}
+	var shares []blockchainv1alpha1.KeyShare
+	for _, chain := range axelarNode.Spec.Validator.KeyShares.Chains {
+		for _, role := range []string{blockchainv1alpha1.KeyRoleActive, blockchainv1alpha1.KeyRoleNext} {
+			keyID, err := api.KeyID(ctx, chain, role == blockchainv1alpha1.KeyRoleNext)
+			if err != nil {
+				log.V(1).Info("Unable to query the key ID", "chain", chain, "role", role, "error", err.Error())
+				return nil
+			}
+			if keyID == "" {
+				continue
+			}
+			key, err := api.MultisigKey(ctx, keyID)
+			if err != nil {
+				log.V(1).Info("Unable to query the key", "key", keyID, "error", err.Error())
+				return nil
+			}
+			share := blockchainv1alpha1.KeyShare{
+				Chain:        chain,
+				KeyID:        keyID,
+				Role:         role,
+				State:        key.State,
+				Participants: len(key.Participants),
+			}
+			for _, participant := range key.Participants {
+				if participant.Address == operator {
+					share.Held = true
+					share.Weight = participant.Weight
+				}
+			}
+			if seen, ok := known[keyID]; !share.Held && (!ok || seen.Held) {
+				text := fmt.Sprintf(":key: %s holds no share of %s key %s of %s (%d participants); it missed the keygen and does not sign with this key",
+					operator, strings.ToLower(role), keyID, chain, share.Participants)
+				r.notifyKeyShares(ctx, axelarNode, text)
+			}
+			shares = append(shares, share)
+		}
+	}
+
+	if info == nil {
+		info = &blockchainv1alpha1.ValidatorInfo{}
+		axelarNode.Status.ValidatorInfo = info
+	}
+	now := metav1.Now()
+	info.KeySharesChecked = &now
+	info.KeyShares = shares
+	return nil
+}
+
+// notifyKeyShares records a missing key share event and sends it to the alert channels
+func (r *AxelarNodeReconciler) notifyKeyShares(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, text string) {
+	r.Log.Info("Key share notification", "axelarnode", axelarNode.Name, "message", text)
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "KeyShareMissing", text)
+	}
+	if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+		r.Log.Error(err, "Unable to send key share alert", "axelarnode", axelarNode.Name)
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+)
+
+// nodeAppName is the app.kubernetes.io/name of the objects generated for nodes
+const nodeAppName = "axelar-node"
+
+// Components of a node. vald and tofnd run in the pod of the node unless the
+// validator uses the split topology.
+const (
+	componentNode    = "node"
+	componentStandby = "standby"
+	componentVald    = "vald"
+	componentTofnd   = "tofnd"
+)
+
+// childName names an object generated for the node, within the length limits
+// of Kubernetes names
+func childName(axelarNode *blockchainv1alpha1.AxelarNode, suffixes ...string) string {
+	return naming.Name(axelarNode.Name, suffixes...)
+}
+
+// nodeInstance describes the node to the client labelling the objects
+// generated for it
+func nodeInstance(axelarNode *blockchainv1alpha1.AxelarNode) naming.Instance {
+	return naming.Instance{
+		Owner:     axelarNode.UID,
+		Name:      nodeAppName,
+		Instance:  axelarNode.Name,
+		Component: componentNode,
+		Version:   axelarNode.Spec.Image.Tag,
+	}
+}
+
+// nodeSelector selects the pods of the node by their app label, which the
+// pods of Deployments created before the recommended labels carry too
+func nodeSelector(axelarNode *blockchainv1alpha1.AxelarNode) map[string]string {
+	return map[string]string{"app": naming.LabelValue(axelarNode.Name)}
+}
+
+// podSelector selects the pods of a component of the node by their
+// recommended labels. It never includes the version, which changes on upgrades.
+func podSelector(axelarNode *blockchainv1alpha1.AxelarNode, component string) map[string]string {
+	return map[string]string{
+		blockchainv1alpha1.NameLabel:      nodeAppName,
+		blockchainv1alpha1.InstanceLabel:  naming.LabelValue(axelarNode.Name),
+		blockchainv1alpha1.ComponentLabel: component,
+	}
+}
+
+// nodeLabels labels the objects and pods of a component of the node with the
+// recommended labels and the app label
+func nodeLabels(axelarNode *blockchainv1alpha1.AxelarNode, component string) map[string]string {
+	labels := nodeInstance(axelarNode).Labels(component)
+	for key, value := range nodeSelector(axelarNode) {
+		labels[key] = value
+	}
+	return labels
+}
+
+// deploymentSelector returns the labels selecting the pods of a Deployment of
+// the node, for the Services in front of it. Selectors are immutable, so an
+// existing Deployment keeps the one it was created with.
+func (r *AxelarNodeReconciler) deploymentSelector(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, name, component string) (map[string]string, error) {
+	found := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, found)
+	if errors.IsNotFound(err) {
+		return podSelector(axelarNode, component), nil
+	} else if err != nil {
+		return nil, err
+	}
+	if found.Spec.Selector == nil || len(found.Spec.Selector.MatchLabels) == 0 {
+		return podSelector(axelarNode, component), nil
+	}
+	return found.Spec.Selector.MatchLabels, nil
+}
+
+// mergeLabels adds labels to obj, keeping the labels set by others, and
+// reports whether it changed
+func mergeLabels(obj client.Object, labels map[string]string) bool {
+	current := obj.GetLabels()
+	changed := false
+	for key, value := range labels {
+		if current[key] == value {
+			continue
+		}
+		if current == nil {
+			current = map[string]string{}
+		}
+		current[key] = value
+		changed = true
+	}
+	obj.SetLabels(current)
+	return changed
+}
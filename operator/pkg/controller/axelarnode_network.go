@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/networks"
+)
+
+// nodeNetwork returns the profile of the network the node joins. Unknown
+// networks get an empty profile, and are reported by validateNodeSpec.
+func nodeNetwork(axelarNode *blockchainv1alpha1.AxelarNode) networks.Profile {
+	profile, _ := networks.Lookup(axelarNode.Spec.Network)
+	return profile
+}
+
+// nodeChainID returns the chain ID of the network the node joins
+func nodeChainID(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if axelarNode.Spec.ChainID != "" {
+		return axelarNode.Spec.ChainID
+	}
+	return nodeNetwork(axelarNode).ChainID
+}
+
+// nodeMinimumGasPrices returns the minimum-gas-prices of app.toml
+func nodeMinimumGasPrices(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if axelarNode.Spec.MinimumGasPrices != "" {
+		return axelarNode.Spec.MinimumGasPrices
+	}
+	if prices := nodeNetwork(axelarNode).MinimumGasPrices; prices != "" {
+		return prices
+	}
+	return defaultGasPrices
+}
+
+// nodeGenesisURL returns the URL the genesis file is downloaded from, or an
+// empty string when the network has none, the genesis file comes from a
+// ConfigMap or the operator is offline
+func nodeGenesisURL(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if axelarNode.Spec.GenesisFrom != nil || airgap.Offline() {
+		return ""
+	}
+	if axelarNode.Spec.GenesisURL != "" {
+		return axelarNode.Spec.GenesisURL
+	}
+	return nodeNetwork(axelarNode).GenesisURL
+}
+
+// productionNetwork reports whether the node joins a network where the unsafe
+// RPC routes are never enabled
+func productionNetwork(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return nodeNetwork(axelarNode).Production
+}
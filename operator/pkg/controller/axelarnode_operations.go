@@ -0,0 +1,473 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/backup"
+)
+
+// latestArchive restores the most recent backup
+const latestArchive = "latest"
+
+// archiveName matches backup archive names accepted by the restore annotation
+var archiveName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// backupScript archives the chain data of the stopped node. Keys are left out,
+// they are backed up separately.
+const backupScript = `set -e
+cd /home/axelard/.axelar
+tar -czf "/backup/$ARCHIVE.tmp" --exclude=config/priv_validator_key.json --exclude=keyring-file data config
+mv "/backup/$ARCHIVE.tmp" "/backup/$ARCHIVE"
+sync
+`
+
+// restoreScript replaces the chain data with an archive. The current signing
+// state is kept, since restoring an older one would allow double-signing.
+const restoreScript = `set -e
+if [ "$ARCHIVE" = latest ]; then
+  archive=$(ls -t /backup/*.tar.gz | head -n 1)
+else
+  archive="/backup/$ARCHIVE"
+fi
+test -f "$archive"
+cd /home/axelard/.axelar
+if [ -f data/priv_validator_state.json ]; then cp data/priv_validator_state.json /tmp/priv_validator_state.json; fi
+rm -rf data
+tar -xzf "$archive" ${RESTORE_PATHS:-data}
+if [ -f /tmp/priv_validator_state.json ]; then cp /tmp/priv_validator_state.json data/priv_validator_state.json; fi
+sync
+`
+
+// resyncScript wipes the chain data except the signing state
+const resyncScript = `set -e
+mkdir -p /home/axelard/.axelar/data
+cd /home/axelard/.axelar/data
+find . -mindepth 1 -maxdepth 1 ! -name priv_validator_state.json -exec rm -rf {} +
+sync
+`
+
+// operationRequests maps the request annotations to their operation, in the
+// order they are served
+var operationRequests = []struct {
+	annotation string
+	operation  string
+}{
+	{blockchainv1alpha1.BackupAnnotation, blockchainv1alpha1.OperationBackup},
+	{blockchainv1alpha1.RestoreAnnotation, blockchainv1alpha1.OperationRestore},
+	{blockchainv1alpha1.ResyncAnnotation, blockchainv1alpha1.OperationResync},
+	{blockchainv1alpha1.RotateSecretsAnnotation, blockchainv1alpha1.OperationSecretRotation},
+}
+
+// operationRunning reports whether a maintenance operation owns the Deployments
+func operationRunning(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	op := axelarNode.Status.Operation
+	if op == nil {
+		return false
+	}
+	switch op.Phase {
+	case blockchainv1alpha1.OperationStopping, blockchainv1alpha1.OperationRunning,
+		blockchainv1alpha1.OperationStarting, blockchainv1alpha1.OperationFailed:
+		return true
+	}
+	return false
+}
+
+// switchoverRunning reports whether a blue/green switchover owns the Deployments
+func switchoverRunning(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	sw := axelarNode.Status.Switchover
+	return sw != nil && sw.Phase != "" && sw.Phase != blockchainv1alpha1.SwitchoverCompleted
+}
+
+// reconcileOperation drives a backup, restore, resync or password rotation
+// requested through annotations, and the keyring migrations and scheduled
+// rotations the spec calls for. The node is stopped while a Job works on its
+// data volume. It returns true while the operation owns the Deployments.
+func (r *AxelarNodeReconciler) reconcileOperation(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	op := axelarNode.Status.Operation
+	if op == nil || op.Phase == "" || op.Phase == blockchainv1alpha1.OperationCompleted || op.Phase == blockchainv1alpha1.OperationFailed {
+		annotation, operation, value := requestedOperation(axelarNode)
+		if annotation == "" {
+			pending, err := r.keyringMigrationNeeded(ctx, axelarNode)
+			if err != nil {
+				return false, err
+			}
+			if pending {
+				operation, value = blockchainv1alpha1.OperationKeyringMigration, keyringBackend(axelarNode)
+			} else if due, err := secretRotationDue(axelarNode); err != nil {
+				return false, err
+			} else if due {
+				operation = blockchainv1alpha1.OperationSecretRotation
+			}
+		}
+		if operation == "" || switchoverRunning(axelarNode) || verificationRestoring(axelarNode) {
+			return operationRunning(axelarNode), nil
+		}
+
+		if annotation != "" {
+			delete(axelarNode.Annotations, annotation)
+		}
+
+		next := &blockchainv1alpha1.OperationStatus{
+			Type:      operation,
+			Phase:     blockchainv1alpha1.OperationStopping,
+			StartedAt: &metav1.Time{Time: time.Now()},
+			Message:   "Stopping the node",
+		}
+		switch operation {
+		case blockchainv1alpha1.OperationBackup:
+			next.Archive = backupArchiveName(axelarNode)
+		case blockchainv1alpha1.OperationRestore:
+			next.Archive = value
+			if next.Archive == "" || next.Archive == "true" {
+				next.Archive = latestArchive
+			}
+			if next.Archive == latestArchive && volumeSnapshotBackups(axelarNode) {
+				next.Archive = axelarNode.Status.LastBackupArchive
+			}
+		case blockchainv1alpha1.OperationKeyringMigration:
+			next.Archive = value
+		}
+
+		refusal := operationRefusal(axelarNode, next)
+		if refusal == "" && next.Type == blockchainv1alpha1.OperationRestore && volumeSnapshotBackups(axelarNode) {
+			var err error
+			if refusal, err = r.volumeSnapshotRefusal(ctx, axelarNode, next.Archive); err != nil {
+				return true, err
+			}
+		}
+		if refusal != "" {
+			if op == nil {
+				op = &blockchainv1alpha1.OperationStatus{}
+			}
+			op.Message = fmt.Sprintf("%s refused: %s", operation, refusal)
+			axelarNode.Status.Operation = op
+			return operationRunning(axelarNode), nil
+		}
+
+		log.Info("Starting maintenance operation", "operation", operation, "archive", next.Archive)
+		op = next
+		axelarNode.Status.Operation = op
+		if operation == blockchainv1alpha1.OperationSecretRotation {
+			startSecretRotation(axelarNode)
+		}
+		if operation == blockchainv1alpha1.OperationResync {
+			// The wiped volume bootstraps from the latest snapshot again
+			axelarNode.Status.Snapshot = nil
+			meta.RemoveStatusCondition(&axelarNode.Status.Conditions, blockchainv1alpha1.ConditionSnapshotBootstrapped)
+		}
+	}
+	ctx = audit.WithReason(ctx, strings.ToLower(op.Type))
+
+	switch op.Phase {
+	case blockchainv1alpha1.OperationStopping:
+		if op.Type == blockchainv1alpha1.OperationBackup && volumeSnapshotBackups(axelarNode) {
+			return true, r.startVolumeSnapshot(ctx, axelarNode, op)
+		}
+		if op.Type == blockchainv1alpha1.OperationSecretRotation && op.Archive == "" {
+			// The new passwords are staged before the node stops, the Job reads them
+			keys, err := r.stagePasswords(ctx, axelarNode)
+			if err != nil {
+				return true, err
+			}
+			if len(keys) == 0 {
+				op.Phase = blockchainv1alpha1.OperationCompleted
+				op.CompletedAt = &metav1.Time{Time: time.Now()}
+				op.Message = "SecretRotation skipped, the operator manages no password of the Secret"
+				if status := axelarNode.Status.SecretRotation; status != nil {
+					status.Message = op.Message
+				}
+				return false, nil
+			}
+			op.Archive = strings.Join(keys, ",")
+		}
+		stopped, err := r.stopValidatorPods(ctx, axelarNode)
+		if err != nil || !stopped {
+			return true, err
+		}
+		switch {
+		case op.Type == blockchainv1alpha1.OperationRestore && volumeSnapshotBackups(axelarNode):
+			if err := r.createOrUpdatePVC(ctx, r.createSnapshotPVC(axelarNode, "restore", op.Archive)); err != nil {
+				return true, err
+			}
+		case op.Type == blockchainv1alpha1.OperationBackup || op.Type == blockchainv1alpha1.OperationRestore:
+			pvc := r.createPVC(axelarNode, "backup", axelarNode.Spec.Storage.Size)
+			if err := r.createOrUpdatePVC(ctx, pvc); err != nil {
+				return true, err
+			}
+		}
+		if err := r.Create(ctx, r.createOperationJob(axelarNode, op)); err != nil && !errors.IsAlreadyExists(err) {
+			return true, err
+		}
+		op.Phase = blockchainv1alpha1.OperationRunning
+		op.Message = fmt.Sprintf("Running the %s job", operationJobSuffix(op.Type))
+
+	case blockchainv1alpha1.OperationRunning:
+		if op.Type == blockchainv1alpha1.OperationBackup && volumeSnapshotBackups(axelarNode) {
+			return true, r.checkVolumeSnapshot(ctx, axelarNode, op)
+		}
+		job := &batchv1.Job{}
+		err := r.Get(ctx, types.NamespacedName{Name: childName(axelarNode, operationJobSuffix(op.Type)), Namespace: axelarNode.Namespace}, job)
+		if err != nil {
+			return true, err
+		}
+		switch {
+		case job.Status.Succeeded > 0:
+			if op.Type == blockchainv1alpha1.OperationBackup {
+				// The node was stopped, its last observed height is in the archive
+				axelarNode.Status.LastBackup = &metav1.Time{Time: time.Now()}
+				axelarNode.Status.LastBackupArchive = op.Archive
+				axelarNode.Status.LastBackupHeight = axelarNode.Status.SyncInfo.CurrentHeight
+			}
+			if op.Type == blockchainv1alpha1.OperationKeyringMigration {
+				axelarNode.Status.KeyringBackend = op.Archive
+			}
+			if op.Type == blockchainv1alpha1.OperationSecretRotation {
+				if err := r.completeSecretRotation(ctx, axelarNode, op, true); err != nil {
+					return true, err
+				}
+			}
+			op.Phase = blockchainv1alpha1.OperationStarting
+			op.Message = "Starting the node"
+		case job.Status.Failed > 0 && op.Type == blockchainv1alpha1.OperationSecretRotation:
+			// The new keyring is only swapped in once complete, the node can
+			// start again with the current passwords
+			log.Info("Secret rotation failed, restarting the node with the current passwords")
+			if err := r.completeSecretRotation(ctx, axelarNode, op, false); err != nil {
+				return true, err
+			}
+			op.Phase = blockchainv1alpha1.OperationStarting
+			op.Message = "Secret rotation failed, starting the node with the current passwords"
+		case job.Status.Failed > 0 && op.Type == blockchainv1alpha1.OperationBackup:
+			// The chain data was only read, so the node can safely start again
+			log.Info("Backup failed, restarting the node")
+			op.Phase = blockchainv1alpha1.OperationStarting
+			op.Archive = ""
+			op.Message = "Backup failed, starting the node"
+		case job.Status.Failed > 0:
+			log.Info("Maintenance operation failed, keeping the node stopped", "operation", op.Type)
+			op.Phase = blockchainv1alpha1.OperationFailed
+			op.Message = fmt.Sprintf("%s failed and the chain data may be incomplete. The node is stopped; "+
+				"inspect the volume, then request a restore or a resync", op.Type)
+		default:
+			return true, nil
+		}
+		if op.Type == blockchainv1alpha1.OperationRestore && volumeSnapshotBackups(axelarNode) {
+			if err := r.deleteSnapshotPVC(ctx, axelarNode, "restore"); err != nil {
+				return true, err
+			}
+		}
+		return true, r.deleteOperationJob(ctx, axelarNode, op.Type)
+
+	case blockchainv1alpha1.OperationStarting:
+		if op.Type == blockchainv1alpha1.OperationResync && !r.reconcileSnapshot(ctx, axelarNode) {
+			op.Message = "Waiting for a bootstrap snapshot"
+			return true, nil
+		}
+		ready, err := r.startValidatorPods(ctx, axelarNode)
+		if err != nil || !ready {
+			return true, err
+		}
+		log.Info("Maintenance operation completed", "operation", op.Type)
+		op.Phase = blockchainv1alpha1.OperationCompleted
+		op.CompletedAt = &metav1.Time{Time: time.Now()}
+		op.Message = fmt.Sprintf("%s completed", op.Type)
+		if op.Type == blockchainv1alpha1.OperationBackup && op.Archive == "" {
+			op.Message = "Backup failed, the node was restarted without a new archive"
+		}
+		if op.Type == blockchainv1alpha1.OperationSecretRotation && axelarNode.Status.SecretRotation != nil {
+			op.Message = axelarNode.Status.SecretRotation.Message
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// backupArchiveName names a new backup of the node: the volume snapshot, or
+// the archive written to the backup volume
+func backupArchiveName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	name := fmt.Sprintf("%s-%s", axelarNode.Name, time.Now().UTC().Format("20060102-150405"))
+	if volumeSnapshotBackups(axelarNode) {
+		return name
+	}
+	name += ".tar.gz"
+	if backupEncryption(axelarNode) != nil {
+		name += backup.Extension
+	}
+	return name
+}
+
+// requestedOperation returns the first pending operation request
+func requestedOperation(axelarNode *blockchainv1alpha1.AxelarNode) (annotation, operation, value string) {
+	for _, req := range operationRequests {
+		if v, ok := axelarNode.Annotations[req.annotation]; ok {
+			return req.annotation, req.operation, v
+		}
+	}
+	return "", "", ""
+}
+
+// operationRefusal explains why an operation cannot run on the node, or
+// returns an empty string
+func operationRefusal(axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus) string {
+	if op.Type == blockchainv1alpha1.OperationSecretRotation {
+		return secretRotationRefusal(axelarNode)
+	}
+	if nodeScaledOut(axelarNode) {
+		return "scaled-out nodes keep their chain data on ephemeral volumes"
+	}
+	if op.Type == blockchainv1alpha1.OperationRestore && op.Archive != latestArchive && !archiveName.MatchString(op.Archive) {
+		return fmt.Sprintf("invalid archive name %q", op.Archive)
+	}
+	if op.Type == blockchainv1alpha1.OperationKeyringMigration {
+		return ""
+	}
+	if op.Type != blockchainv1alpha1.OperationResync && !volumeSnapshotBackups(axelarNode) {
+		return encryptionRefusal(axelarNode, op)
+	}
+	return ""
+}
+
+// operationJobSuffix returns the name suffix of the Job running an operation
+func operationJobSuffix(operation string) string {
+	switch operation {
+	case blockchainv1alpha1.OperationRestore:
+		return "restore"
+	case blockchainv1alpha1.OperationResync:
+		return "resync"
+	case blockchainv1alpha1.OperationKeyringMigration:
+		return "keyring-migration"
+	case blockchainv1alpha1.OperationSecretRotation:
+		return "secret-rotation"
+	}
+	return "backup"
+}
+
+// createOperationJob creates the Job working on the data volume of the stopped node
+func (r *AxelarNodeReconciler) createOperationJob(axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus) *batchv1.Job {
+	return r.volumeJob(axelarNode, op, childName(axelarNode, operationJobSuffix(op.Type)),
+		dataClaimName(axelarNode, activeSlot(axelarNode)))
+}
+
+// volumeJob creates a Job running an operation on the data volume claimed by dataClaim
+func (r *AxelarNodeReconciler) volumeJob(axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus, name, dataClaim string) *batchv1.Job {
+	script := backupScript
+	switch op.Type {
+	case blockchainv1alpha1.OperationRestore:
+		script = restoreScript
+	case blockchainv1alpha1.OperationResync:
+		script = resyncScript
+	}
+
+	container := corev1.Container{
+		Name:    operationJobSuffix(op.Type),
+		Image:   nodeImage(axelarNode),
+		Command: []string{"sh", "-c", script},
+		Env:     []corev1.EnvVar{{Name: "ARCHIVE", Value: op.Archive}},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: "/home/axelard/.axelar"},
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: dataClaim,
+				},
+			},
+		},
+	}
+	if op.Type == blockchainv1alpha1.OperationBackup || op.Type == blockchainv1alpha1.OperationRestore {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "backup", MountPath: "/backup"})
+		volumes = append(volumes, corev1.Volume{
+			Name: "backup",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: childName(axelarNode, "backup"),
+				},
+			},
+		})
+	}
+
+	backoffLimit := int32(0)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: axelarNode.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:   corev1.RestartPolicyNever,
+					Containers:      []corev1.Container{container},
+					Volumes:         volumes,
+					SecurityContext: axelarNode.Spec.Security.PodSecurityContext,
+				},
+			},
+		},
+	}
+
+	switch {
+	case op.Type == blockchainv1alpha1.OperationKeyringMigration:
+		addKeyringMigration(axelarNode, op, &job.Spec.Template.Spec)
+	case op.Type == blockchainv1alpha1.OperationSecretRotation:
+		addSecretRotation(axelarNode, op, &job.Spec.Template.Spec)
+	case volumeSnapshotBackups(axelarNode):
+		if op.Type == blockchainv1alpha1.OperationRestore {
+			addSnapshotSource(axelarNode, &job.Spec.Template.Spec)
+		}
+	default:
+		r.addBackupEncryption(axelarNode, op, &job.Spec.Template.Spec)
+	}
+
+	controllerutil.SetControllerReference(axelarNode, job, r.Scheme)
+	return job
+}
+
+// deleteOperationJob removes the Job of an operation and its pod
+func (r *AxelarNodeReconciler) deleteOperationJob(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, operation string) error {
+	return r.deleteJob(ctx, axelarNode, childName(axelarNode, operationJobSuffix(operation)))
+}
+
+// nodePaused reports whether reconciliation of the node is paused
+func nodePaused(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Annotations[blockchainv1alpha1.PausedAnnotation] == "true"
+}
+
+// setPausedCondition records whether reconciliation is paused. The condition
+// only appears once the node has been paused.
+func setPausedCondition(axelarNode *blockchainv1alpha1.AxelarNode) {
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionPaused,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Resumed",
+		Message:            "Reconciliation is active",
+		ObservedGeneration: axelarNode.Generation,
+	}
+	if nodePaused(axelarNode) {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Paused"
+		condition.Message = fmt.Sprintf("Reconciliation is paused by the %s annotation", blockchainv1alpha1.PausedAnnotation)
+	} else if meta.FindStatusCondition(axelarNode.Status.Conditions, condition.Type) == nil {
+		return
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+}
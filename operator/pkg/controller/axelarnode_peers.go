@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/chainregistry"
+)
+
+// peersRefreshedAnnotation restarts the node pods when its peers are refreshed
+const peersRefreshedAnnotation = "blockchain.axelar.network/peers-refreshed"
+
+// Defaults of peer remediation when the spec leaves them unset
+const (
+	defaultMinPeers              = 5
+	defaultPeerRemediationPeriod = 10 * time.Minute
+	peerRefreshCooldown          = time.Hour
+	maxRegistryPeers             = 10
+)
+
+// Peer limits of config.toml when the spec leaves them unset
+const (
+	defaultMaxInboundPeers  = 40
+	defaultMaxOutboundPeers = 10
+)
+
+// peerLimit returns a peer limit of the spec, or its default
+func peerLimit(limit *int32, fallback int32) int32 {
+	if limit == nil {
+		return fallback
+	}
+	return *limit
+}
+
+// unblockedPeers returns the peers, as node IDs or id@host:port, whose node
+// ID is not blocked by the spec
+func unblockedPeers(axelarNode *blockchainv1alpha1.AxelarNode, peers []string) []string {
+	blocked := axelarNode.Spec.Networking.P2P.BlockedPeerIDs
+	if len(blocked) == 0 {
+		return peers
+	}
+	result := []string{}
+	for _, peer := range peers {
+		if !containsString(blocked, strings.SplitN(peer, "@", 2)[0]) {
+			result = append(result, peer)
+		}
+	}
+	return result
+}
+
+// peerRegistryURL returns the chain.json the peers of the node are refreshed
+// from, or an empty string when its network has none
+func peerRegistryURL(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if url := axelarNode.Spec.Networking.P2P.PeerRemediation.RegistryURL; url != "" {
+		return url
+	}
+	return nodeNetwork(axelarNode).RegistryURL
+}
+
+// fetchRegistryPeers reads the peers of the network of the node from the
+// chain.json of spec.networking.p2p.peerRemediation.registryFrom, or downloads them
+func (r *AxelarNodeReconciler) fetchRegistryPeers(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (*chainregistry.Peers, error) {
+	if spec := axelarNode.Spec.Networking.P2P.PeerRemediation; spec != nil && spec.RegistryFrom != nil {
+		document, err := r.configMapValue(ctx, axelarNode.Namespace, spec.RegistryFrom)
+		if err != nil {
+			return nil, err
+		}
+		return chainregistry.ParsePeers(document)
+	}
+	url := peerRegistryURL(axelarNode)
+	if url == "" {
+		return nil, fmt.Errorf("network %s has no chain registry, set spec.networking.p2p.peerRemediation.registryURL", axelarNode.Spec.Network)
+	}
+	return chainregistry.FetchPeers(ctx, url)
+}
+
+// mergePeers appends the extra peers whose node ID is not in peers yet
+func mergePeers(peers, extra []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, peer := range append(append([]string{}, peers...), extra...) {
+		id := strings.SplitN(peer, "@", 2)[0]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, peer)
+	}
+	return result
+}
+
+// firstPeers returns at most maxRegistryPeers peers
+func firstPeers(peers []string) []string {
+	if len(peers) > maxRegistryPeers {
+		return peers[:maxRegistryPeers]
+	}
+	return peers
+}
+
+// nodeSeeds returns the seeds of the spec, or of the network when the spec
+// lists none, and those refreshed from the registry
+func nodeSeeds(axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	seeds := axelarNode.Spec.Networking.P2P.Seeds
+	if len(seeds) == 0 {
+		seeds = nodeNetwork(axelarNode).Seeds
+	}
+	if status := axelarNode.Status.PeerRemediation; status != nil {
+		seeds = mergePeers(seeds, status.Seeds)
+	}
+	return unblockedPeers(axelarNode, seeds)
+}
+
+// nodePersistentPeers returns the persistent peers of the spec, or of the
+// network when the spec lists none, and those refreshed from the registry
+func nodePersistentPeers(axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	peers := axelarNode.Spec.Networking.P2P.PersistentPeers
+	if len(peers) == 0 {
+		peers = nodeNetwork(axelarNode).PersistentPeers
+	}
+	if status := axelarNode.Status.PeerRemediation; status != nil {
+		peers = mergePeers(peers, status.PersistentPeers)
+	}
+	return unblockedPeers(axelarNode, peers)
+}
+
+// withoutRegistryPeers returns the node without the peers refreshed from the
+// registry. The configuration hash ignores them, so refreshed peers only
+// restart the node when remediation asks for it.
+func withoutRegistryPeers(axelarNode *blockchainv1alpha1.AxelarNode) *blockchainv1alpha1.AxelarNode {
+	if axelarNode.Status.PeerRemediation == nil {
+		return axelarNode
+	}
+	node := axelarNode.DeepCopy()
+	node.Status.PeerRemediation = nil
+	return node
+}
+
+// peersRefreshed returns the time of the peer refresh the node pods are
+// restarted for, or an empty string
+func peersRefreshed(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	spec, status := axelarNode.Spec.Networking.P2P.PeerRemediation, axelarNode.Status.PeerRemediation
+	if spec == nil || !spec.Enabled || !spec.Restart || status == nil || status.LastRefresh == nil {
+		return ""
+	}
+	return status.LastRefresh.UTC().Format(time.RFC3339)
+}
+
+// remediatePeers tracks how long a running node has had fewer peers than the
+// minimum. Once it has for the remediation period, the peers are refreshed
+// from the chain registry and an alert is raised, at most once an hour.
+func (r *AxelarNodeReconciler) remediatePeers(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) {
+	spec := axelarNode.Spec.Networking.P2P.PeerRemediation
+	if spec == nil || !spec.Enabled {
+		axelarNode.Status.PeerRemediation = nil
+		return
+	}
+	if axelarNode.Status.Phase != "Running" {
+		return
+	}
+	if axelarNode.Status.PeerRemediation == nil {
+		axelarNode.Status.PeerRemediation = &blockchainv1alpha1.PeerRemediationStatus{}
+	}
+	status := axelarNode.Status.PeerRemediation
+
+	minPeers := spec.MinPeers
+	if minPeers == 0 {
+		minPeers = defaultMinPeers
+	}
+	period := spec.Period.Duration
+	if period == 0 {
+		period = defaultPeerRemediationPeriod
+	}
+
+	peers := axelarNode.Status.NetworkInfo.Peers
+	if peers >= minPeers {
+		status.LowSince = nil
+		return
+	}
+	now := metav1.Now()
+	if status.LowSince == nil {
+		status.LowSince = &now
+		return
+	}
+	if time.Since(status.LowSince.Time) < period || (status.LastRefresh != nil && time.Since(status.LastRefresh.Time) < peerRefreshCooldown) {
+		return
+	}
+	status.LastRefresh = &now
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	message := fmt.Sprintf("%s/%s has had %d peers, below the minimum of %d, since %s",
+		axelarNode.Namespace, axelarNode.Name, peers, minPeers, status.LowSince.UTC().Format(time.RFC3339))
+	registry, err := r.fetchRegistryPeers(ctx, axelarNode)
+	if err != nil {
+		log.Info("Unable to refresh peers from the chain registry", "error", err.Error())
+		message += fmt.Sprintf("; unable to refresh peers: %v", err)
+	} else {
+		status.Seeds = firstPeers(registry.Seeds)
+		status.PersistentPeers = firstPeers(registry.PersistentPeers)
+		log.Info("Refreshed peers from the chain registry", "seeds", len(status.Seeds), "persistentPeers", len(status.PersistentPeers))
+		message += fmt.Sprintf("; added %d seeds and %d persistent peers from the chain registry", len(status.Seeds), len(status.PersistentPeers))
+	}
+	if spec.Restart {
+		message += ", restarting the node"
+	}
+
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "LowPeerCount", message)
+	}
+	if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, ":satellite: "+message); err != nil {
+		log.Error(err, "Unable to send peer count alert")
+	}
+}
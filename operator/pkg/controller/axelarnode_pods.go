@@ -0,0 +1,332 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/usage"
+)
+
+// nodeForPod maps a pod of a Deployment of a node, including the vald and
+// tofnd Deployments of the split topology, to its AxelarNode. Deployment pods
+// are owned by a ReplicaSet named after the Deployment and the pod template
+// hash, and the Deployment is controlled by the node. Label values are not
+// used, since long node names are hashed in them.
+func (r *AxelarNodeReconciler) nodeForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	owner := metav1.GetControllerOf(obj)
+	hash := obj.GetLabels()[appsv1.DefaultDeploymentUniqueLabelKey]
+	if owner == nil || owner.Kind != "ReplicaSet" || hash == "" || !strings.HasSuffix(owner.Name, "-"+hash) {
+		return nil
+	}
+	deployment := &appsv1.Deployment{}
+	name := strings.TrimSuffix(owner.Name, "-"+hash)
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}, deployment); err != nil {
+		return nil
+	}
+	node := metav1.GetControllerOf(deployment)
+	if node == nil || node.Kind != "AxelarNode" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: node.Name, Namespace: obj.GetNamespace()}}}
+}
+
+// nodePods returns the pods of the node, and the vald and tofnd pods of the
+// split topology, once each
+func (r *AxelarNodeReconciler) nodePods(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) ([]corev1.Pod, error) {
+	selectors := []map[string]string{nodeSelector(axelarNode)}
+	if splitSigner(axelarNode) {
+		selectors = append(selectors, signerPodLabels(axelarNode))
+	}
+	var pods []corev1.Pod
+	seen := map[string]bool{}
+	for _, selector := range selectors {
+		list := &corev1.PodList{}
+		if err := r.List(ctx, list, client.InNamespace(axelarNode.Namespace), client.MatchingLabels(selector)); err != nil {
+			return nil, err
+		}
+		for _, pod := range list.Items {
+			if !seen[pod.Name] {
+				seen[pod.Name] = true
+				pods = append(pods, pod)
+			}
+		}
+	}
+	return pods, nil
+}
+
+// podHealthChanged only passes pod updates that change the restart count,
+// readiness or waiting reason of a container
+var podHealthChanged = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok := e.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		newPod, ok := e.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		if len(oldPod.Status.ContainerStatuses) != len(newPod.Status.ContainerStatuses) {
+			return true
+		}
+		for i, previous := range oldPod.Status.ContainerStatuses {
+			current := newPod.Status.ContainerStatuses[i]
+			if previous.RestartCount != current.RestartCount || previous.Ready != current.Ready ||
+				waitingReason(previous) != waitingReason(current) {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// waitingReason returns why a container is waiting, or an empty string
+func waitingReason(status corev1.ContainerStatus) string {
+	if status.State.Waiting == nil {
+		return ""
+	}
+	return status.State.Waiting.Reason
+}
+
+// reportPodHealth counts the restarts and OOM kills of the node containers,
+// and of vald and tofnd in the split topology, and sets the CrashLooping
+// condition while one of them is in crash loop back-off. A container that
+// exited cleanly at the halt of the node is not crash looping.
+func (r *AxelarNodeReconciler) reportPodHealth(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	pods, err := r.nodePods(ctx, axelarNode)
+	if err != nil {
+		return err
+	}
+
+	health := axelarNode.Status.PodHealth
+	if health == nil {
+		health = &blockchainv1alpha1.PodHealthStatus{}
+	}
+	observed := map[string]int32{}
+	health.Restarts = 0
+	var looping []string
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			key := pod.Name + "/" + status.Name
+			health.Restarts += status.RestartCount
+			observed[key] = status.RestartCount
+
+			// Each termination is counted once, when its restart is first seen
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && status.RestartCount > health.ObservedRestarts[key] {
+				if terminated.Reason == "OOMKilled" {
+					health.OOMKills++
+				}
+				health.LastTerminatedContainer = status.Name
+				health.LastTerminationReason = terminated.Reason
+				health.LastExitCode = terminated.ExitCode
+				health.LastTerminationMessage = truncateMessage(terminated.Message)
+				finishedAt := terminated.FinishedAt
+				health.LastTerminationTime = &finishedAt
+
+				if terminated.Reason == "OOMKilled" || (terminated.ExitCode != 0 && status.RestartCount > 1) {
+					if recommendation := r.recommendResources(ctx, axelarNode, &pod, status.Name, terminated.Reason); recommendation != nil {
+						health.Recommendations = setRecommendation(health.Recommendations, *recommendation)
+					}
+				}
+			}
+
+			if waitingReason(status) != "CrashLoopBackOff" {
+				continue
+			}
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.ExitCode == 0 && haltConfigured(axelarNode) {
+				continue
+			}
+			reason := ""
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				reason = fmt.Sprintf(", last terminated: %s (exit code %d)", terminated.Reason, terminated.ExitCode)
+			}
+			looping = append(looping, fmt.Sprintf("%s in pod %s after %d restarts%s", status.Name, pod.Name, status.RestartCount, reason))
+		}
+	}
+	health.ObservedRestarts = observed
+	health.Recommendations = pendingRecommendations(health.Recommendations, pods)
+	axelarNode.Status.PodHealth = health
+
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionCrashLooping,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Stable",
+		Message:            fmt.Sprintf("No container is crash looping, %d restarts, %d OOM kills", health.Restarts, health.OOMKills),
+		ObservedGeneration: axelarNode.Generation,
+	}
+	if len(looping) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "CrashLoopBackOff"
+		condition.Message = "Crash looping: " + strings.Join(looping, "; ")
+	}
+	previous := meta.FindStatusCondition(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionCrashLooping)
+	if r.Recorder != nil && condition.Status == metav1.ConditionTrue && (previous == nil || previous.Status != metav1.ConditionTrue) {
+		r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "CrashLooping", condition.Message)
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	return nil
+}
+
+// maxTerminationMessage is the length termination messages are truncated to
+const maxTerminationMessage = 512
+
+// truncateMessage keeps the end of a termination message, where the fatal
+// error usually is
+func truncateMessage(message string) string {
+	message = strings.TrimSpace(message)
+	if len(message) <= maxTerminationMessage {
+		return message
+	}
+	return "..." + message[len(message)-maxTerminationMessage:]
+}
+
+// usageWindow is the usage history recommendations are computed from
+const usageWindow = 7 * 24 * time.Hour
+
+// Recommendations leave headroom over the peak usage, grow the memory of OOM
+// killed containers by half, and are rounded up to 256Mi and 100m
+const (
+	usageHeadroom  = 1.25
+	oomKillGrowth  = 1.5
+	memoryRounding = 256 * 1024 * 1024
+	cpuRounding    = 100
+)
+
+// containerResource returns a resource of a container: its request, or its
+// limit when the request is unset. preferLimit looks the limit up first.
+func containerResource(pod *corev1.Pod, name string, resourceName corev1.ResourceName, preferLimit bool) *resource.Quantity {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != name {
+			continue
+		}
+		lists := []corev1.ResourceList{container.Resources.Requests, container.Resources.Limits}
+		if preferLimit {
+			lists[0], lists[1] = lists[1], lists[0]
+		}
+		for _, list := range lists {
+			if quantity, ok := list[resourceName]; ok {
+				return &quantity
+			}
+		}
+	}
+	return nil
+}
+
+// recommendResources suggests requests for a container that was OOM killed
+// or keeps crashing. An OOM killed container is given half as much memory
+// again. With usage history, requests are raised above the peak usage of the
+// last week. Nil is returned when the current requests already cover it.
+func (r *AxelarNodeReconciler) recommendResources(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, pod *corev1.Pod, container, reason string) *blockchainv1alpha1.ResourceRecommendation {
+	memory := containerResource(pod, container, corev1.ResourceMemory, false)
+	cpu := containerResource(pod, container, corev1.ResourceCPU, false)
+	var memoryBytes, cpuMillis int64
+	var basis []string
+
+	// The kernel kills a container at its memory limit
+	if limit := containerResource(pod, container, corev1.ResourceMemory, true); reason == "OOMKilled" && limit != nil {
+		memoryBytes = int64(float64(limit.Value()) * oomKillGrowth)
+		basis = append(basis, fmt.Sprintf("OOM killed at %s", limit.String()))
+	}
+
+	if r.Usage != nil {
+		series := usage.Container{Namespace: axelarNode.Namespace, PodPrefix: axelarNode.Name + "-", Name: container}
+		log := r.Log.WithValues("axelarnode", axelarNode.Name, "container", container)
+		if peak, ok, err := r.Usage.PeakMemory(ctx, series, usageWindow); err != nil {
+			log.V(1).Info("Unable to read the memory usage history", "error", err.Error())
+		} else if ok && int64(peak*usageHeadroom) > memoryBytes && (memory == nil || int64(peak*usageHeadroom) > memory.Value()) {
+			memoryBytes = int64(peak * usageHeadroom)
+			basis = append(basis, fmt.Sprintf("peak memory usage of %s over 7 days",
+				resource.NewQuantity(int64(peak), resource.BinarySI).String()))
+		}
+		if peak, ok, err := r.Usage.PeakCPU(ctx, series, usageWindow); err != nil {
+			log.V(1).Info("Unable to read the CPU usage history", "error", err.Error())
+		} else if ok && (cpu == nil || int64(peak*usageHeadroom*1000) > cpu.MilliValue()) {
+			cpuMillis = int64(peak * usageHeadroom * 1000)
+			basis = append(basis, fmt.Sprintf("peak CPU usage of %s over 7 days",
+				resource.NewMilliQuantity(int64(peak*1000), resource.DecimalSI).String()))
+		}
+	}
+
+	if memoryBytes == 0 && cpuMillis == 0 {
+		return nil
+	}
+	recommendation := &blockchainv1alpha1.ResourceRecommendation{
+		Container: container,
+		Reason:    reason,
+		Basis:     strings.Join(basis, ", "),
+	}
+	if memoryBytes > 0 {
+		rounded := (memoryBytes + memoryRounding - 1) / memoryRounding * memoryRounding
+		recommendation.Memory = resource.NewQuantity(rounded, resource.BinarySI).String()
+	}
+	if cpuMillis > 0 {
+		rounded := (cpuMillis + cpuRounding - 1) / cpuRounding * cpuRounding
+		recommendation.CPU = resource.NewMilliQuantity(rounded, resource.DecimalSI).String()
+	}
+	return recommendation
+}
+
+// setRecommendation replaces the recommendation for the same container
+func setRecommendation(recommendations []blockchainv1alpha1.ResourceRecommendation, recommendation blockchainv1alpha1.ResourceRecommendation) []blockchainv1alpha1.ResourceRecommendation {
+	for i := range recommendations {
+		if recommendations[i].Container == recommendation.Container {
+			recommendations[i] = recommendation
+			return recommendations
+		}
+	}
+	return append(recommendations, recommendation)
+}
+
+// pendingRecommendations drops the recommendations the requests of the
+// current pods have caught up with
+func pendingRecommendations(recommendations []blockchainv1alpha1.ResourceRecommendation, pods []corev1.Pod) []blockchainv1alpha1.ResourceRecommendation {
+	var pending []blockchainv1alpha1.ResourceRecommendation
+	for _, recommendation := range recommendations {
+		applied := false
+		for i := range pods {
+			if pods[i].DeletionTimestamp != nil {
+				continue
+			}
+			applied = covers(containerResource(&pods[i], recommendation.Container, corev1.ResourceMemory, false), recommendation.Memory) &&
+				covers(containerResource(&pods[i], recommendation.Container, corev1.ResourceCPU, false), recommendation.CPU)
+			break
+		}
+		if !applied {
+			pending = append(pending, recommendation)
+		}
+	}
+	return pending
+}
+
+// covers reports whether current is at least the recommended quantity. An
+// empty recommendation is always covered.
+func covers(current *resource.Quantity, recommended string) bool {
+	if recommended == "" {
+		return true
+	}
+	quantity, err := resource.ParseQuantity(recommended)
+	if err != nil {
+		return true
+	}
+	return current != nil && current.Cmp(quantity) >= 0
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// testScheme registers the Kubernetes and operator types
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := blockchainv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+// nodeDeployment returns a Deployment named name controlled by axelarNode
+func nodeDeployment(axelarNode *blockchainv1alpha1.AxelarNode, name string) *appsv1.Deployment {
+	controller := true
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: axelarNode.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: blockchainv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "AxelarNode",
+				Name:       axelarNode.Name,
+				UID:        axelarNode.UID,
+				Controller: &controller,
+			}},
+		},
+	}
+}
+
+// deploymentPod returns a pod of the ReplicaSet of the Deployment named
+// deployment, labelled with labels
+func deploymentPod(namespace, name, deployment string, labels map[string]string) *corev1.Pod {
+	podLabels := map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "5d8f7c6b9"}
+	for key, value := range labels {
+		podLabels[key] = value
+	}
+	controller := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    podLabels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       deployment + "-5d8f7c6b9",
+				UID:        "replicaset",
+				Controller: &controller,
+			}},
+		},
+	}
+}
+
+func TestNodeForPod(t *testing.T) {
+	axelarNode := &blockchainv1alpha1.AxelarNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mainnet-validator-" + strings.Repeat("a", 60),
+			Namespace: "axelar",
+			UID:       "node",
+		},
+	}
+	orphan := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "axelar"}}
+	c := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(
+		nodeDeployment(axelarNode, deploymentName(axelarNode)),
+		nodeDeployment(axelarNode, valdName(axelarNode)),
+		orphan,
+	).Build()
+	r := &AxelarNodeReconciler{Client: c}
+
+	tests := []struct {
+		name string
+		pod  client.Object
+		want string
+	}{
+		{"node pod", deploymentPod("axelar", "node", deploymentName(axelarNode), nodeSelector(axelarNode)), axelarNode.Name},
+		{"vald pod", deploymentPod("axelar", "vald", valdName(axelarNode), nil), axelarNode.Name},
+		{"Deployment not controlled by a node", deploymentPod("axelar", "orphan", "orphan", nil), ""},
+		{"Deployment not found", deploymentPod("axelar", "gone", "gone", nil), ""},
+		{"pod without a ReplicaSet", &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bare", Namespace: "axelar", Labels: nodeSelector(axelarNode)}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := r.nodeForPod(context.Background(), tt.pod)
+			if tt.want == "" {
+				if len(requests) != 0 {
+					t.Fatalf("requests = %v, want none", requests)
+				}
+				return
+			}
+			if len(requests) != 1 || requests[0].Name != tt.want || requests[0].Namespace != "axelar" {
+				t.Fatalf("requests = %v, want axelar/%s", requests, tt.want)
+			}
+		})
+	}
+}
+
+// crashLooping returns the status of a container in crash loop back-off
+func crashLooping(name string, restarts int32) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:         name,
+		RestartCount: restarts,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+		},
+	}
+}
+
+func TestReportPodHealth(t *testing.T) {
+	split := &blockchainv1alpha1.AxelarNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "validator", Namespace: "axelar", UID: "node"},
+		Spec: blockchainv1alpha1.AxelarNodeSpec{
+			Validator: &blockchainv1alpha1.ValidatorSpec{
+				Enabled:  true,
+				Topology: blockchainv1alpha1.ValidatorTopologySplit,
+			},
+		},
+	}
+	sidecars := split.DeepCopy()
+	sidecars.Spec.Validator.Topology = ""
+
+	nodePod := deploymentPod("axelar", "validator-node", deploymentName(split), nodeLabels(split, componentNode))
+	nodePod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "axelar-node", RestartCount: 2}}
+	valdPod := deploymentPod("axelar", "validator-vald", valdName(split), signerLabels(split, componentVald, valdName(split)))
+	valdPod.Status.ContainerStatuses = []corev1.ContainerStatus{crashLooping("vald", 1)}
+
+	tests := []struct {
+		name         string
+		node         *blockchainv1alpha1.AxelarNode
+		wantRestarts int32
+		wantLooping  metav1.ConditionStatus
+	}{
+		{"split topology", split, 3, metav1.ConditionTrue},
+		{"sidecars", sidecars, 2, metav1.ConditionFalse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(nodePod.DeepCopy(), valdPod.DeepCopy()).Build()
+			r := &AxelarNodeReconciler{Client: c}
+			axelarNode := tt.node.DeepCopy()
+
+			if err := r.reportPodHealth(context.Background(), axelarNode); err != nil {
+				t.Fatal(err)
+			}
+			if got := axelarNode.Status.PodHealth.Restarts; got != tt.wantRestarts {
+				t.Errorf("restarts = %d, want %d", got, tt.wantRestarts)
+			}
+			condition := meta.FindStatusCondition(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionCrashLooping)
+			if condition == nil || condition.Status != tt.wantLooping {
+				t.Fatalf("CrashLooping = %v, want %s", condition, tt.wantLooping)
+			}
+		})
+	}
+}
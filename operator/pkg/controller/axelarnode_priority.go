@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// nodeClassAnnotation records the controller that owns a node, so a node
+// changing class is only reconciled by one of them at a time
+const nodeClassAnnotation = "blockchain.axelar.network/node-class"
+
+// Classes of nodes, each served by a controller
+const (
+	nodeClassValidator = "validator"
+	nodeClassNode      = "node"
+)
+
+// nodeClassHandOverInterval is how often the new controller of a node
+// changing class checks whether the old one has released it
+const nodeClassHandOverInterval = 2 * time.Second
+
+// nodeClassReconciler reconciles the nodes of one class only. Validators and
+// the other nodes are served by controllers of their own, each with its own
+// queue and workers, so a deep queue of observers after an operator restart
+// over a big fleet does not hold back the signing infrastructure.
+type nodeClassReconciler struct {
+	*AxelarNodeReconciler
+	validators bool
+}
+
+// class returns the class of the nodes the controller serves
+func (c *nodeClassReconciler) class() string {
+	return nodeClassOf(c.validators)
+}
+
+// nodeClassOf returns the class of validators, or of the other nodes
+func nodeClassOf(validators bool) string {
+	if validators {
+		return nodeClassValidator
+	}
+	return nodeClassNode
+}
+
+// Reconcile reconciles the node when the controller owns it. A node that
+// changed class is released by its old controller once that one is done
+// with it, and only then claimed by the new one, so the two never write its
+// Deployments and status together.
+func (c *nodeClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	axelarNode := &blockchainv1alpha1.AxelarNode{}
+	if err := c.Get(ctx, req.NamespacedName, axelarNode); err != nil {
+		if errors.IsNotFound(err) {
+			c.nodeGone(req)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	owner := axelarNode.Annotations[nodeClassAnnotation]
+	wanted := nodeClassOf(isValidatorNode(axelarNode))
+	switch {
+	case wanted != c.class():
+		if owner == c.class() {
+			return handOverResult(c.setNodeClass(ctx, axelarNode, wanted))
+		}
+		return ctrl.Result{}, nil
+	case owner == "":
+		if err := c.setNodeClass(ctx, axelarNode, wanted); err != nil {
+			return handOverResult(err)
+		}
+	case owner != wanted:
+		// The old controller has not released the node yet
+		return ctrl.Result{RequeueAfter: nodeClassHandOverInterval}, nil
+	}
+	return c.reconcileFetched(ctx, axelarNode)
+}
+
+// setNodeClass hands the node to the controller of class. The write fails
+// over a stale read, so a node is never claimed from an outdated copy.
+func (c *nodeClassReconciler) setNodeClass(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, class string) error {
+	patch := client.MergeFromWithOptions(axelarNode.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if axelarNode.Annotations == nil {
+		axelarNode.Annotations = map[string]string{}
+	}
+	axelarNode.Annotations[nodeClassAnnotation] = class
+	return c.Patch(ctx, axelarNode, patch)
+}
+
+// handOverResult retries a hand-over written over a stale read once the cache
+// catches up
+func handOverResult(err error) (ctrl.Result, error) {
+	if errors.IsConflict(err) {
+		return ctrl.Result{RequeueAfter: nodeClassHandOverInterval}, nil
+	}
+	return ctrl.Result{}, err
+}
+
+// nodeClass passes the events of the nodes of a class, and of the nodes the
+// controller still owns, so it sees a node changing class and releases it
+func nodeClass(validators bool) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		axelarNode, ok := obj.(*blockchainv1alpha1.AxelarNode)
+		return ok && (isValidatorNode(axelarNode) == validators || axelarNode.Annotations[nodeClassAnnotation] == nodeClassOf(validators))
+	})
+}
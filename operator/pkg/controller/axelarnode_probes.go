@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Probe types
+const (
+	probeRPC      = "rpc"
+	probeExec     = "exec"
+	probeProgress = "progress"
+	probeSynced   = "synced"
+	probeDisabled = "disabled"
+)
+
+// defaultStallTimeout is how long the height may stay unchanged before the
+// progress probe fails
+const defaultStallTimeout = 5 * time.Minute
+
+// progressScript fails once the latest block height has not changed for the
+// stall timeout. The height and the time it was first seen are kept in the
+// container filesystem, so a restart starts the clock over.
+const progressScript = `height=$(wget -qO- -T %d http://127.0.0.1:%d/status | grep -Eo '"latest_block_height": ?"[0-9]+"' | grep -Eo '[0-9]+') || exit 1
+now=$(date +%%s)
+if [ "$height" != "$(cat /tmp/probe-height 2>/dev/null)" ]; then
+  echo "$height" > /tmp/probe-height
+  echo "$now" > /tmp/probe-since
+  exit 0
+fi
+[ $((now - $(cat /tmp/probe-since))) -lt %d ]
+`
+
+// livenessProbe returns the liveness probe of the node container. Unlike the
+// Prometheus port, the RPC stops answering when the node is wedged.
+func livenessProbe(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Probe {
+	return nodeProbe(axelarNode.Spec.Probes.Liveness, axelarNode.Spec.Networking.RPC.Port, corev1.Probe{
+		InitialDelaySeconds: 120,
+		PeriodSeconds:       30,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	})
+}
+
+// readinessProbe returns the readiness probe of the node container
+func readinessProbe(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Probe {
+	return nodeProbe(axelarNode.Spec.Probes.Readiness, axelarNode.Spec.Networking.RPC.Port, corev1.Probe{
+		InitialDelaySeconds: 60,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	})
+}
+
+// nodeProbe builds the probe described by spec against the node RPC, falling
+// back to defaults for unset timings. All fields the API server would default
+// are set, so the probe compares equal to the deployed one.
+func nodeProbe(spec blockchainv1alpha1.ProbeSpec, rpcPort int32, defaults corev1.Probe) *corev1.Probe {
+	probe := defaults
+	probe.SuccessThreshold = 1
+	if spec.InitialDelaySeconds > 0 {
+		probe.InitialDelaySeconds = spec.InitialDelaySeconds
+	}
+	if spec.PeriodSeconds > 0 {
+		probe.PeriodSeconds = spec.PeriodSeconds
+	}
+	if spec.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = spec.TimeoutSeconds
+	}
+	if spec.FailureThreshold > 0 {
+		probe.FailureThreshold = spec.FailureThreshold
+	}
+
+	switch spec.Type {
+	case probeDisabled:
+		return nil
+	case probeExec:
+		probe.Exec = &corev1.ExecAction{
+			Command: []string{"sh", "-c", fmt.Sprintf(
+				"timeout %d axelard status --node tcp://127.0.0.1:%d >/dev/null", probe.TimeoutSeconds, rpcPort)},
+		}
+	case probeProgress:
+		stallTimeout := spec.StallTimeout.Duration
+		if stallTimeout <= 0 {
+			stallTimeout = defaultStallTimeout
+		}
+		probe.Exec = &corev1.ExecAction{
+			Command: []string{"sh", "-c", fmt.Sprintf(
+				progressScript, probe.TimeoutSeconds, rpcPort, int(stallTimeout.Seconds()))},
+		}
+	case probeSynced:
+		probe.Exec = syncedReadinessProbe(rpcPort).Exec
+	default:
+		probe.HTTPGet = &corev1.HTTPGetAction{
+			Path:   "/health",
+			Port:   intstr.FromInt(int(rpcPort)),
+			Scheme: corev1.URISchemeHTTP,
+		}
+	}
+	return &probe
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/cosmos"
+)
+
+// profileCheckInterval is how often the on-chain validator is compared to the spec
+const profileCheckInterval = 5 * time.Minute
+
+// profileRetryInterval is how long a failed edit-validator transaction is
+// left before it is retried
+const profileRetryInterval = time.Hour
+
+// profileJobName names the Job sending edit-validator transactions
+const profileJobName = "edit-validator"
+
+// profileDrift compares the on-chain validator to the profile and returns the
+// flags of the edit-validator transaction correcting it, keyed by field
+func profileDrift(profile *blockchainv1alpha1.ValidatorProfileSpec, validator *cosmos.Validator) (map[string]string, error) {
+	drift := map[string]string{}
+	if profile.CommissionRate != "" {
+		equal, err := decimalsEqual(profile.CommissionRate, validator.Commission.CommissionRates.Rate)
+		if err != nil {
+			return nil, err
+		}
+		if !equal {
+			drift["commissionRate"] = "--commission-rate=" + profile.CommissionRate
+		}
+	}
+	if profile.MinSelfDelegation != "" {
+		equal, err := decimalsEqual(profile.MinSelfDelegation, validator.MinSelfDelegation)
+		if err != nil {
+			return nil, err
+		}
+		if !equal {
+			drift["minSelfDelegation"] = "--min-self-delegation=" + profile.MinSelfDelegation
+		}
+	}
+
+	description, onChain := profile.Description, validator.Description
+	for _, field := range []struct{ name, flag, desired, actual string }{
+		{"website", "--website", description.Website, onChain.Website},
+		{"details", "--details", description.Details, onChain.Details},
+		{"identity", "--identity", description.Identity, onChain.Identity},
+		{"securityContact", "--security-contact", description.SecurityContact, onChain.SecurityContact},
+	} {
+		if field.desired != "" && field.desired != field.actual {
+			drift[field.name] = field.flag + "=" + field.desired
+		}
+	}
+	return drift, nil
+}
+
+// decimalsEqual compares decimal strings, such as 0.05 and the 18 digit
+// decimals of the chain
+func decimalsEqual(desired, actual string) (bool, error) {
+	a, ok := new(big.Rat).SetString(desired)
+	if !ok {
+		return false, fmt.Errorf("invalid decimal %q", desired)
+	}
+	b, ok := new(big.Rat).SetString(actual)
+	if !ok {
+		return false, fmt.Errorf("invalid on-chain decimal %q", actual)
+	}
+	return a.Cmp(b) == 0, nil
+}
+
+// reconcileValidatorProfile compares the on-chain validator to
+// spec.validator.profile and, when allowed, corrects it with an
+// edit-validator transaction
+func (r *AxelarNodeReconciler) reconcileValidatorProfile(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if axelarNode.Spec.Validator == nil || !axelarNode.Spec.Validator.Enabled || axelarNode.Spec.Validator.Profile == nil {
+		axelarNode.Status.ValidatorProfile = nil
+		meta.RemoveStatusCondition(&axelarNode.Status.Conditions, blockchainv1alpha1.ConditionValidatorProfileSynced)
+		return nil
+	}
+	profile := axelarNode.Spec.Validator.Profile
+	if axelarNode.Status.ValidatorProfile == nil {
+		axelarNode.Status.ValidatorProfile = &blockchainv1alpha1.ValidatorProfileStatus{}
+	}
+	status := axelarNode.Status.ValidatorProfile
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	// Wait for a running transaction, and re-check the chain once it is done
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: childName(axelarNode, profileJobName), Namespace: axelarNode.Namespace}, job)
+	if err == nil {
+		if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+			r.setProfileCondition(axelarNode, metav1.ConditionFalse, "Applying", "Sending an edit-validator transaction")
+			return nil
+		}
+		output, err := r.txJobOutput(ctx, job)
+		if err != nil {
+			return err
+		}
+		if job.Status.Succeeded > 0 {
+			log.Info("Edit-validator transaction sent", "tx", output)
+			status.LastTxHash = output
+		} else {
+			if output == "" {
+				output = "the edit-validator Job failed, see its logs"
+			}
+			log.Info("Edit-validator transaction failed", "reason", output)
+			r.setProfileCondition(axelarNode, metav1.ConditionFalse, "TxFailed", output)
+		}
+		if err := r.deleteJob(ctx, axelarNode, job.Name); err != nil {
+			return err
+		}
+		status.LastChecked = nil
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	if status.LastChecked != nil && time.Since(status.LastChecked.Time) < profileCheckInterval {
+		return nil
+	}
+	now := metav1.Now()
+	status.LastChecked = &now
+
+	_, api, err := r.statusClients(ctx, axelarNode)
+	if err != nil {
+		log.V(1).Info("Unable to reach the status source", "error", err.Error())
+		r.setProfileCondition(axelarNode, metav1.ConditionUnknown, "QueryFailed", err.Error())
+		return nil
+	}
+	validator, err := api.Validator(ctx, profile.OperatorAddress)
+	if err != nil {
+		log.V(1).Info("Unable to query the validator", "error", err.Error())
+		r.setProfileCondition(axelarNode, metav1.ConditionUnknown, "QueryFailed", err.Error())
+		return nil
+	}
+	drift, err := profileDrift(profile, validator)
+	if err != nil {
+		r.setProfileCondition(axelarNode, metav1.ConditionFalse, "InvalidProfile", err.Error())
+		return nil
+	}
+
+	status.Drift = nil
+	flags := []string{}
+	for field, flag := range drift {
+		status.Drift = append(status.Drift, field)
+		flags = append(flags, flag)
+	}
+	sort.Strings(status.Drift)
+	sort.Strings(flags)
+	if len(drift) == 0 {
+		r.setProfileCondition(axelarNode, metav1.ConditionTrue, "InSync", "The on-chain validator matches the profile")
+		return nil
+	}
+
+	message := "On-chain validator differs in " + strings.Join(status.Drift, ", ")
+	if !profile.AllowTx {
+		r.setProfileCondition(axelarNode, metav1.ConditionFalse, "Drifted", message+"; allowTx is disabled")
+		return nil
+	}
+	if status.LastTxTime != nil && time.Since(status.LastTxTime.Time) < profileRetryInterval {
+		r.setProfileCondition(axelarNode, metav1.ConditionFalse, "Drifted",
+			fmt.Sprintf("%s; retrying after %s", message, status.LastTxTime.Add(profileRetryInterval).UTC().Format(time.RFC3339)))
+		return nil
+	}
+
+	args := []string{"tx", "staking", "edit-validator"}
+	for _, flag := range flags {
+		args = append(args, shellQuote(flag))
+	}
+	if err := r.createTxJob(ctx, axelarNode, profileJobName, strings.Join(args, " ")+"\n", txKeySecret(axelarNode)); err != nil {
+		return err
+	}
+	status.LastTxTime = &now
+	log.Info("Correcting validator drift", "fields", status.Drift)
+	r.setProfileCondition(axelarNode, metav1.ConditionFalse, "Applying", message+"; sending an edit-validator transaction")
+	return nil
+}
+
+// shellQuote quotes s for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// setProfileCondition records whether the on-chain validator matches the profile
+func (r *AxelarNodeReconciler) setProfileCondition(axelarNode *blockchainv1alpha1.AxelarNode, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionValidatorProfileSynced,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: axelarNode.Generation,
+	})
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+)
+
+// proxyAnnotation holds a hash of the proxy settings of the pods, so
+// changing them rolls the node
+const proxyAnnotation = "blockchain.axelar.network/proxy"
+
+// proxyCAMountPath is where the CA bundle of spec.proxy is mounted. It is
+// added to SSL_CERT_DIR, so the system roots stay trusted.
+const proxyCAMountPath = "/etc/ssl/outbound"
+
+// proxyDownloaders are the containers that download on behalf of the
+// operator, and use its proxy when the node sets none
+var proxyDownloaders = map[string]bool{
+	snapshotBootstrapContainer: true,
+	"addrbook-seed":            true,
+	integrityCheckContainer:    true,
+}
+
+// nodeProxy returns the proxy of the node pods and whether the node sets it,
+// or the proxy of the operator. It returns nil when there is no proxy.
+func nodeProxy(axelarNode *blockchainv1alpha1.AxelarNode) (*blockchainv1alpha1.ProxySpec, bool) {
+	if axelarNode.Spec.Proxy != nil {
+		return axelarNode.Spec.Proxy, true
+	}
+	opts := outbound.Current()
+	if opts.HTTPProxy == "" && opts.HTTPSProxy == "" {
+		return nil, false
+	}
+	return &blockchainv1alpha1.ProxySpec{HTTPProxy: opts.HTTPProxy, HTTPSProxy: opts.HTTPSProxy, NoProxy: opts.NoProxy}, false
+}
+
+// proxyEnv returns the environment variables of proxy, in both cases since
+// tools disagree on which one they read
+func proxyEnv(proxy *blockchainv1alpha1.ProxySpec) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, variable := range []struct{ name, value string }{
+		{"HTTP_PROXY", proxy.HTTPProxy},
+		{"HTTPS_PROXY", proxy.HTTPSProxy},
+		{"NO_PROXY", proxy.NoProxy},
+	} {
+		if variable.value == "" {
+			continue
+		}
+		env = append(env,
+			corev1.EnvVar{Name: variable.name, Value: variable.value},
+			corev1.EnvVar{Name: strings.ToLower(variable.name), Value: variable.value})
+	}
+	if proxy.CABundleSecretRef != nil {
+		env = append(env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: "/etc/ssl/certs:" + proxyCAMountPath})
+	}
+	return env
+}
+
+// proxyHash returns a hash of the proxy settings of the node pods, or an
+// empty string when there is no proxy
+func proxyHash(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	proxy, _ := nodeProxy(axelarNode)
+	if proxy == nil {
+		return ""
+	}
+	h := sha256.New()
+	for _, value := range []string{proxy.HTTPProxy, proxy.HTTPSProxy, proxy.NoProxy} {
+		h.Write([]byte(value))
+		h.Write([]byte{0})
+	}
+	if ref := proxy.CABundleSecretRef; ref != nil {
+		h.Write([]byte(ref.Name + "/" + ref.Key))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// addProxy routes the Internet traffic of the node pods through the proxy of
+// spec.proxy. Without it, only the downloaders use the proxy of the operator.
+func addProxy(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	proxy, own := nodeProxy(axelarNode)
+	if proxy == nil {
+		return
+	}
+	env := proxyEnv(proxy)
+	ca := proxy.CABundleSecretRef
+	if ca != nil {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "outbound-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ca.Name,
+					Items:      []corev1.KeyToPath{{Key: ca.Key, Path: "ca.crt"}},
+				},
+			},
+		})
+	}
+	apply := func(container *corev1.Container) {
+		container.Env = append(container.Env, env...)
+		if ca != nil {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "outbound-ca", MountPath: proxyCAMountPath, ReadOnly: true})
+		}
+	}
+	for i := range podSpec.InitContainers {
+		if own || proxyDownloaders[podSpec.InitContainers[i].Name] {
+			apply(&podSpec.InitContainers[i])
+		}
+	}
+	for i := range podSpec.Containers {
+		if own || proxyDownloaders[podSpec.Containers[i].Name] {
+			apply(&podSpec.Containers[i])
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tendermint"
+)
+
+// endpointSliceManager marks the EndpointSlices written by the operator, so
+// the EndpointSlice controller leaves them alone
+const endpointSliceManager = "axelar-operator"
+
+// publicServiceName returns the name of the Service of the synced pods
+func publicServiceName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "public")
+}
+
+// publicEnabled reports whether the node asks for a public Service
+func publicEnabled(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Spec.Networking.Public != nil
+}
+
+// publicServicePorts returns the client facing ports of the public Service.
+// P2P and metrics stay on the node Service.
+func publicServicePorts(axelarNode *blockchainv1alpha1.AxelarNode) []corev1.ServicePort {
+	networking := axelarNode.Spec.Networking
+	ports := []corev1.ServicePort{
+		{Name: "rpc", Port: networking.RPC.Port, TargetPort: intstr.FromInt(int(rpcUpstreamPort(axelarNode)))},
+	}
+	if networking.API.Enabled {
+		ports = append(ports, corev1.ServicePort{
+			Name: "api", Port: networking.API.Port, TargetPort: intstr.FromInt(int(networking.API.Port)),
+		})
+	}
+	ports = append(ports, gatewayServicePorts(axelarNode)...)
+	return append(ports, tlsServicePorts(axelarNode)...)
+}
+
+// publicExclusion returns why a pod may not serve public traffic, or an empty
+// string when it is running, ready, caught up and has enough peers
+func publicExclusion(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "terminating"
+	}
+	if pod.Status.Phase != corev1.PodRunning || !podReady(pod) {
+		return "not ready"
+	}
+	family := serviceIPFamilies(axelarNode.Spec.Networking)[0]
+	address := podIP(pod, family)
+	if address == "" {
+		return fmt.Sprintf("no %s address", family)
+	}
+
+	rpc := tendermint.NewClient(podURL(address, axelarNode.Spec.Networking.RPC.Port))
+	rpc.HTTPClient = nodeHTTPClient(axelarNode)
+	status, err := rpc.Status(ctx)
+	if err != nil {
+		return "RPC unreachable"
+	}
+	if status.SyncInfo.CatchingUp {
+		return fmt.Sprintf("catching up at height %d", status.SyncInfo.Height())
+	}
+	netInfo, err := rpc.NetInfo(ctx)
+	if err != nil {
+		return "RPC unreachable"
+	}
+	if minPeers := axelarNode.Spec.Networking.Public.MinPeers; netInfo.Peers() < minPeers {
+		return fmt.Sprintf("%d peers, %d required", netInfo.Peers(), minPeers)
+	}
+	return ""
+}
+
+// podReady reports whether the Ready condition of a pod is true
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// reconcilePublicService serves the RPC and API of the synced pods on the
+// <node>-public Service. The Service has no selector: the operator writes its
+// EndpointSlice with the pods that pass publicExclusion, so clients are never
+// sent to a replica still catching up or cut off from its peers.
+func (r *AxelarNodeReconciler) reconcilePublicService(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	name := publicServiceName(axelarNode)
+	if !publicEnabled(axelarNode) {
+		axelarNode.Status.PublicEndpoints = nil
+		slice := &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: axelarNode.Namespace}}
+		if err := r.Delete(ctx, slice); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: axelarNode.Namespace}}
+		if err := r.Delete(ctx, service); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	service := createPublicService(axelarNode)
+	if err := controllerutil.SetControllerReference(axelarNode, service, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if err := r.Create(ctx, service); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else {
+		found.Annotations = service.Annotations
+		found.Spec.Type = service.Spec.Type
+		found.Spec.Ports = service.Spec.Ports
+		applyIPFamilies(axelarNode.Spec.Networking, &found.Spec)
+		if err := r.Update(ctx, found); err != nil {
+			return err
+		}
+	}
+
+	return r.reconcilePublicEndpoints(ctx, axelarNode, service.Spec.Ports)
+}
+
+// createPublicService creates the public service object, without a selector
+func createPublicService(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Service {
+	spec := axelarNode.Spec.Networking.Public
+	serviceType := spec.Type
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        publicServiceName(axelarNode),
+			Namespace:   axelarNode.Namespace,
+			Annotations: dnsAnnotations(axelarNode, rpcHostname(axelarNode), spec.Annotations),
+		},
+		Spec: corev1.ServiceSpec{
+			Type:  serviceType,
+			Ports: publicServicePorts(axelarNode),
+		},
+	}
+	applyIPFamilies(axelarNode.Spec.Networking, &service.Spec)
+	return service
+}
+
+// reconcilePublicEndpoints writes the EndpointSlice of the public Service
+// with the pods eligible to serve it, and records the others in the status
+func (r *AxelarNodeReconciler) reconcilePublicEndpoints(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, servicePorts []corev1.ServicePort) error {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(axelarNode.Namespace),
+		client.MatchingLabels(nodeSelector(axelarNode))); err != nil {
+		return err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	family := serviceIPFamilies(axelarNode.Spec.Networking)[0]
+	ready := true
+	var endpoints []discoveryv1.Endpoint
+	var excluded []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if reason := publicExclusion(ctx, axelarNode, pod); reason != "" {
+			excluded = append(excluded, fmt.Sprintf("%s: %s", pod.Name, reason))
+			continue
+		}
+		endpoints = append(endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{podIP(pod, family)},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			NodeName:   &pod.Spec.NodeName,
+			TargetRef: &corev1.ObjectReference{
+				Kind:      "Pod",
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				UID:       pod.UID,
+			},
+		})
+	}
+
+	ports := make([]discoveryv1.EndpointPort, 0, len(servicePorts))
+	for _, servicePort := range servicePorts {
+		name, port, protocol := servicePort.Name, int32(servicePort.TargetPort.IntValue()), corev1.ProtocolTCP
+		ports = append(ports, discoveryv1.EndpointPort{Name: &name, Port: &port, Protocol: &protocol})
+	}
+
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      publicServiceName(axelarNode),
+			Namespace: axelarNode.Namespace,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: publicServiceName(axelarNode),
+				discoveryv1.LabelManagedBy:   endpointSliceManager,
+			},
+		},
+		AddressType: discoveryv1.AddressType(family),
+		Endpoints:   endpoints,
+		Ports:       ports,
+	}
+	if err := controllerutil.SetControllerReference(axelarNode, slice, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &discoveryv1.EndpointSlice{}
+	err := r.Get(ctx, types.NamespacedName{Name: slice.Name, Namespace: slice.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if err := r.Create(ctx, slice); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else {
+		found.Labels = slice.Labels
+		found.Endpoints = slice.Endpoints
+		found.Ports = slice.Ports
+		if err := r.Update(ctx, found); err != nil {
+			return err
+		}
+	}
+
+	previous := axelarNode.Status.PublicEndpoints
+	if len(endpoints) == 0 && r.Recorder != nil && (previous == nil || previous.Ready > 0) {
+		r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "NoPublicEndpoints",
+			fmt.Sprintf("No pod is synced with %d peers, %s serves no traffic", axelarNode.Spec.Networking.Public.MinPeers, publicServiceName(axelarNode)))
+	}
+	axelarNode.Status.PublicEndpoints = &blockchainv1alpha1.PublicEndpointsStatus{
+		Ready:    int32(len(endpoints)),
+		Excluded: excluded,
+	}
+	return nil
+}
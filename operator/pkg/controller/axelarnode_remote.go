@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/remote"
+)
+
+// requestAnnotationPrefix marks annotations that request one-shot operations
+const requestAnnotationPrefix = "blockchain.axelar.network/"
+
+// remotePollInterval is how often the hub mirrors status from agent clusters
+const remotePollInterval = time.Minute
+
+// clusterClient returns the client and namespace the node's workloads live in
+func clusterClient(ctx context.Context, local client.Client, clusters *remote.Clusters, axelarNode *blockchainv1alpha1.AxelarNode) (client.Client, string, error) {
+	ref := axelarNode.Spec.Cluster
+	if ref == nil {
+		return local, axelarNode.Namespace, nil
+	}
+	if clusters == nil {
+		return nil, "", fmt.Errorf("node %s is placed in a remote cluster but the operator is not running in hub mode", axelarNode.Name)
+	}
+
+	remoteClient, err := clusters.Client(ctx, local, ref, axelarNode.Namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = axelarNode.Namespace
+	}
+	return remoteClient, namespace, nil
+}
+
+// reconcileRemote materializes the node in its agent cluster and mirrors the
+// agent's status back. The agent operator manages the workloads themselves.
+func (r *AxelarNodeReconciler) reconcileRemote(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (ctrl.Result, error) {
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+	ctx = audit.WithReason(ctx, "remote-mirror")
+
+	remoteClient, namespace, err := clusterClient(ctx, r.Client, r.Clusters, axelarNode)
+	if err != nil {
+		log.Error(err, "Unable to reach remote cluster")
+		r.setRemoteCondition(axelarNode, metav1.ConditionFalse, "ClusterUnreachable", err.Error())
+		return ctrl.Result{RequeueAfter: remotePollInterval}, nil
+	}
+
+	desired := axelarNode.Spec.DeepCopy()
+	desired.Cluster = nil
+	requests := requestAnnotations(axelarNode)
+
+	found := &blockchainv1alpha1.AxelarNode{}
+	err = remoteClient.Get(ctx, types.NamespacedName{Name: axelarNode.Name, Namespace: namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		labels := map[string]string{blockchainv1alpha1.HubManagedLabel: "true"}
+		for k, v := range axelarNode.Labels {
+			labels[k] = v
+		}
+		found = &blockchainv1alpha1.AxelarNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        axelarNode.Name,
+				Namespace:   namespace,
+				Labels:      labels,
+				Annotations: requests,
+			},
+			Spec: *desired,
+		}
+		log.Info("Creating AxelarNode in remote cluster", "namespace", namespace)
+		if err := remoteClient.Create(ctx, found); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if err != nil {
+		return ctrl.Result{}, err
+	} else if !equality.Semantic.DeepEqual(found.Spec, *desired) || len(requests) > 0 || nodePaused(found) != nodePaused(axelarNode) {
+		found.Spec = *desired
+		if found.Annotations == nil {
+			found.Annotations = map[string]string{}
+		}
+		for k, v := range requests {
+			found.Annotations[k] = v
+		}
+		if nodePaused(axelarNode) {
+			found.Annotations[blockchainv1alpha1.PausedAnnotation] = "true"
+		} else {
+			delete(found.Annotations, blockchainv1alpha1.PausedAnnotation)
+		}
+		log.Info("Updating AxelarNode in remote cluster", "namespace", namespace)
+		if err := remoteClient.Update(ctx, found); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Requests are handed to the agent, which clears them once acted on
+	for k := range requests {
+		delete(axelarNode.Annotations, k)
+	}
+
+	r.mirrorRemoteStatus(axelarNode, found)
+	return ctrl.Result{RequeueAfter: remotePollInterval}, nil
+}
+
+// mirrorRemoteStatus copies the agent's status onto the hub node. Conditions
+// observed for the agent's current spec are re-stamped with the hub generation.
+func (r *AxelarNodeReconciler) mirrorRemoteStatus(axelarNode, found *blockchainv1alpha1.AxelarNode) {
+	status := found.Status.DeepCopy()
+	for i := range status.Conditions {
+		if status.Conditions[i].ObservedGeneration == found.Generation {
+			status.Conditions[i].ObservedGeneration = axelarNode.Generation
+		} else {
+			status.Conditions[i].ObservedGeneration = 0
+		}
+	}
+	if status.Phase == "" {
+		status.Phase = "Initializing"
+	}
+	axelarNode.Status = *status
+	r.setRemoteCondition(axelarNode, metav1.ConditionTrue, "Mirrored",
+		fmt.Sprintf("Materialized in namespace %s of the remote cluster", found.Namespace))
+}
+
+// deleteRemote removes the node from its agent cluster
+func (r *AxelarNodeReconciler) deleteRemote(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	remoteClient, namespace, err := clusterClient(ctx, r.Client, r.Clusters, axelarNode)
+	if err != nil {
+		return err
+	}
+	found := &blockchainv1alpha1.AxelarNode{
+		ObjectMeta: metav1.ObjectMeta{Name: axelarNode.Name, Namespace: namespace},
+	}
+	if err := remoteClient.Delete(ctx, found); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// setRemoteCondition records whether the remote cluster is reachable
+func (r *AxelarNodeReconciler) setRemoteCondition(axelarNode *blockchainv1alpha1.AxelarNode, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionRemoteReachable,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: axelarNode.Generation,
+	})
+}
+
+// requestAnnotations returns the operation requests set on the node. The
+// paused annotation is not a request; it is kept in sync instead.
+func requestAnnotations(axelarNode *blockchainv1alpha1.AxelarNode) map[string]string {
+	requests := map[string]string{}
+	for k, v := range axelarNode.Annotations {
+		if strings.HasPrefix(k, requestAnnotationPrefix) && k != blockchainv1alpha1.PausedAnnotation {
+			requests[k] = v
+		}
+	}
+	return requests
+}
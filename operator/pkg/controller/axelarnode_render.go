@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Render returns the child objects the reconciler creates for the node, built
+// the same way but without reaching a cluster: the configuration ConfigMaps,
+// the passwords Secret, the PVCs, the Services and the Deployments. Objects
+// that depend on the cluster, such as the EndpointSlice of the public Service
+// or the backups, are left out. The status of the node is honoured, so a node
+// exported from a cluster renders its active slot and adopted objects.
+func (r *AxelarNodeReconciler) Render(axelarNode *blockchainv1alpha1.AxelarNode) ([]client.Object, error) {
+	if problems := validateNodeSpec(axelarNode); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid spec: %s", strings.Join(problems, "; "))
+	}
+	data, err := renderConfig(axelarNode)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []client.Object{configVersionMap(axelarNode, r.configVersion(axelarNode), data)}
+	if axelarNode.Spec.Networking.TLS != nil {
+		objects = append(objects, createTLSProxyConfigMap(axelarNode))
+	}
+	if axelarNode.Spec.Security.SecretManagement.SecretName == "" {
+		objects = append(objects, r.createSecret(axelarNode))
+	}
+
+	// PVCs, except the claims the node uses without creating them
+	slots := []string{activeSlot(axelarNode)}
+	if standbyEnabled(axelarNode) {
+		slots = append(slots, standbySlot(axelarNode))
+	}
+	for _, slot := range slots {
+		if dataClaimName(axelarNode, slot) == childName(axelarNode, dataVolumeSuffix(slot)) {
+			objects = append(objects, r.createPVC(axelarNode, dataVolumeSuffix(slot), axelarNode.Spec.Storage.Size))
+		}
+	}
+	if sharedVolumeType(axelarNode) == blockchainv1alpha1.SharedVolumePVC {
+		shared := axelarNode.Spec.Storage.Shared
+		size := shared.Size
+		if size == "" {
+			size = defaultSharedVolumeSize
+		}
+		objects = append(objects, r.volumePVC(axelarNode, "shared", size, shared.StorageClass))
+	}
+	for _, volume := range axelarNode.Spec.Storage.Volumes {
+		objects = append(objects, r.volumePVC(axelarNode, extraVolumePrefix+volume.Name, volume.Size, volume.StorageClass))
+	}
+
+	// Services
+	objects = append(objects, r.createService(axelarNode))
+	if port := pprofPort(axelarNode); port != 0 {
+		objects = append(objects, createDebugService(axelarNode, port))
+	}
+	if hostname := p2pHostname(axelarNode); hostname != "" {
+		objects = append(objects, createP2PService(axelarNode, hostname))
+	}
+	if publicEnabled(axelarNode) {
+		objects = append(objects, createPublicService(axelarNode))
+	}
+	if standbyEnabled(axelarNode) {
+		objects = append(objects, createStandbyService(axelarNode))
+	}
+	if splitSigner(axelarNode) && ownTofnd(axelarNode) {
+		objects = append(objects, createTofndService(axelarNode))
+	}
+
+	// Deployments
+	objects = append(objects, r.createDeployment(axelarNode))
+	if standbyEnabled(axelarNode) {
+		objects = append(objects, r.createStandbyDeployment(axelarNode))
+	}
+	if splitSigner(axelarNode) && ownTofnd(axelarNode) {
+		objects = append(objects, r.createTofndDeployment(axelarNode))
+	}
+	if splitSigner(axelarNode) {
+		objects = append(objects, r.createValdDeployment(axelarNode))
+	}
+
+	for _, obj := range objects {
+		if err := controllerutil.SetControllerReference(axelarNode, obj, r.Scheme); err != nil {
+			return nil, err
+		}
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+	return objects, nil
+}
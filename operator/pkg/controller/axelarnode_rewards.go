@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+)
+
+// defaultRewardsSchedule applies when the spec leaves the schedule unset
+const defaultRewardsSchedule = "0 12 * * *"
+
+// rewardsScript withdraws the validator commission and rewards and
+// delegates RESTAKE_PERCENT of the balance they added back to the validator
+const rewardsScript = `addr=$(axelard keys show operator -a --keyring-backend test --home /tmp/keys)
+valoper=$(axelard keys show operator -a --bech val --keyring-backend test --home /tmp/keys)
+balance() {
+  wget -qO- "$API/cosmos/bank/v1beta1/balances/$addr/by_denom?denom=uaxl" | grep -o '"amount":"[0-9]*"' | cut -d'"' -f4
+}
+if [ -n "$WITHDRAW_ADDRESS" ]; then
+  current=$(wget -qO- "$API/cosmos/distribution/v1beta1/delegators/$addr/withdraw_address" | grep -o '"withdraw_address":"[a-z0-9]*"' | cut -d'"' -f4)
+  if [ "$current" != "$WITHDRAW_ADDRESS" ]; then
+    tx distribution set-withdraw-addr "$WITHDRAW_ADDRESS"
+  fi
+fi
+before=$(balance)
+tx distribution withdraw-rewards "$valoper" --commission
+after=$(balance)
+amount=$(( (after - before) * RESTAKE_PERCENT / 100 ))
+if [ "$amount" -gt 0 ]; then
+  tx staking delegate "$valoper" "${amount}uaxl"
+fi
+`
+
+// txHashPattern matches the transaction hashes in a termination message
+var txHashPattern = regexp.MustCompile(`^[0-9A-F]{64}$`)
+
+// rewardsName names the rewards CronJob and labels its Jobs
+func rewardsName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return naming.CronJobName(axelarNode.Name, "rewards")
+}
+
+// createRewardsCronJob returns the CronJob withdrawing the validator rewards
+func (r *AxelarNodeReconciler) createRewardsCronJob(axelarNode *blockchainv1alpha1.AxelarNode) *batchv1.CronJob {
+	spec := axelarNode.Spec.Validator.Rewards
+	schedule := spec.Schedule
+	if schedule == "" {
+		schedule = defaultRewardsSchedule
+	}
+	key := txKeySecret(axelarNode)
+	if spec.KeySecretRef != nil {
+		key = *spec.KeySecretRef
+	}
+
+	podSpec := txPodSpec(axelarNode, "rewards", rewardsScript, key)
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env,
+		corev1.EnvVar{Name: "WITHDRAW_ADDRESS", Value: spec.WithdrawAddress},
+		corev1.EnvVar{Name: "RESTAKE_PERCENT", Value: fmt.Sprint(spec.RestakePercent)},
+	)
+
+	backoffLimit := int32(0)
+	historyLimit := int32(3)
+	labels := map[string]string{"app": rewardsName(axelarNode)}
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rewardsName(axelarNode),
+			Namespace: axelarNode.Namespace,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &historyLimit,
+			FailedJobsHistoryLimit:     &historyLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec:       podSpec,
+					},
+				},
+			},
+		},
+	}
+}
+
+// reconcileRewards manages the rewards CronJob and records the outcome of
+// its last finished run
+func (r *AxelarNodeReconciler) reconcileRewards(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	found := &batchv1.CronJob{}
+	err := r.Get(ctx, types.NamespacedName{Name: rewardsName(axelarNode), Namespace: axelarNode.Namespace}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if axelarNode.Spec.Validator == nil || !axelarNode.Spec.Validator.Enabled || axelarNode.Spec.Validator.Rewards == nil {
+		axelarNode.Status.Rewards = nil
+		if exists {
+			return r.Delete(ctx, found, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		}
+		return nil
+	}
+
+	cronJob := r.createRewardsCronJob(axelarNode)
+	if err := controllerutil.SetControllerReference(axelarNode, cronJob, r.Scheme); err != nil {
+		return err
+	}
+	if !exists {
+		r.Log.Info("Creating rewards CronJob", "axelarnode", axelarNode.Name, "schedule", cronJob.Spec.Schedule)
+		return r.Create(ctx, cronJob)
+	}
+	found.Spec = cronJob.Spec
+	if err := r.Update(ctx, found); err != nil {
+		return err
+	}
+
+	return r.reportRewards(ctx, axelarNode)
+}
+
+// reportRewards records the transactions of the latest finished rewards Job
+func (r *AxelarNodeReconciler) reportRewards(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(axelarNode.Namespace), client.MatchingLabels{"app": rewardsName(axelarNode)}); err != nil {
+		return err
+	}
+	var latest *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if job.Status.StartTime == nil || (job.Status.Succeeded == 0 && job.Status.Failed == 0) {
+			continue
+		}
+		if latest == nil || latest.Status.StartTime.Before(job.Status.StartTime) {
+			latest = job
+		}
+	}
+	status := axelarNode.Status.Rewards
+	if latest == nil || (status != nil && status.LastRun != nil && !status.LastRun.Before(latest.Status.StartTime)) {
+		return nil
+	}
+
+	output, err := r.txJobOutput(ctx, latest)
+	if err != nil {
+		return err
+	}
+	status = &blockchainv1alpha1.RewardsStatus{
+		LastRun:   latest.Status.StartTime.DeepCopy(),
+		Succeeded: latest.Status.Succeeded > 0,
+	}
+	messages := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if txHashPattern.MatchString(line) {
+			status.TxHashes = append(status.TxHashes, line)
+		} else if line != "" {
+			messages = append(messages, line)
+		}
+	}
+	status.Message = strings.Join(messages, "; ")
+	if !status.Succeeded && status.Message == "" {
+		status.Message = "the rewards Job failed, see its logs"
+	}
+	axelarNode.Status.Rewards = status
+
+	if status.Succeeded {
+		r.Log.Info("Withdrew validator rewards", "axelarnode", axelarNode.Name, "txs", status.TxHashes)
+	} else {
+		r.Log.Info("Rewards withdrawal failed", "axelarnode", axelarNode.Name, "reason", status.Message)
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "RewardsWithdrawalFailed", status.Message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/maintenance"
+)
+
+// defaultRotationSchedule rotates the passwords monthly
+const defaultRotationSchedule = "0 0 1 * *"
+
+// rotatedAtAnnotation records on the Secret when its passwords were last rotated
+const rotatedAtAnnotation = "blockchain.axelar.network/rotated-at"
+
+// nextPasswordSuffix marks the Secret keys staging the new passwords until
+// the keyring is re-encrypted
+const nextPasswordSuffix = ".next"
+
+// rotatedPasswords are the Secret keys a rotation replaces, when the operator
+// manages them
+var rotatedPasswords = []string{"keyring-password", "tofnd-password"}
+
+// secretRotationScript re-encrypts the file keyring of each home with the new
+// password. The new keyrings are built next to the old ones and swapped in
+// once all are complete, so a failed rotation leaves the keys untouched. The
+// old keyring is kept as keyring-file.previous until the next rotation.
+const secretRotationScript = `set -e
+if [ -z "$NEW_KEYRING_PASSWORD" ]; then
+  echo "The keyring is not re-encrypted"
+  exit 0
+fi
+lines() { for line in "$@"; do printf '%s\n' "$line"; done; }
+for home in $HOMES; do
+  rm -rf "$home/keyring-file.rotating"
+  if [ ! -d "$home/keyring-file" ]; then
+    echo "$home has no file keyring"
+    continue
+  fi
+  staging="$home/.rotating"
+  rm -rf "$staging"
+  mkdir -p "$staging"
+  for name in $(lines "$KEYRING_PASSWORD" | axelard keys list --list-names --keyring-backend file --home "$home"); do
+    lines "$KEYRING_PASSWORD" "$KEYRING_PASSWORD" "$KEYRING_PASSWORD" | axelard keys export "$name" --keyring-backend file --home "$home" 2>&1 \
+      | sed -n '/-----BEGIN/,/-----END/p' > /tmp/key.armor
+    lines "$KEYRING_PASSWORD" "$NEW_KEYRING_PASSWORD" "$NEW_KEYRING_PASSWORD" | axelard keys import "$name" /tmp/key.armor --keyring-backend file --home "$staging"
+    rm -f /tmp/key.armor
+    echo "Re-encrypted $name in $home"
+  done
+  mkdir -p "$staging/keyring-file"
+  mv "$staging/keyring-file" "$home/keyring-file.rotating"
+  rm -rf "$staging"
+done
+sync
+for home in $HOMES; do
+  if [ -d "$home/keyring-file.rotating" ]; then
+    rm -rf "$home/keyring-file.previous"
+    mv "$home/keyring-file" "$home/keyring-file.previous"
+    mv "$home/keyring-file.rotating" "$home/keyring-file"
+  fi
+done
+sync
+`
+
+// secretRotationDue reports whether AutoRotation has a rotation due. The
+// Secret of the spec is managed outside the operator, and never rotated.
+func secretRotationDue(axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	secrets := axelarNode.Spec.Security.SecretManagement
+	if !secrets.AutoRotation || secretRotationRefusal(axelarNode) != "" {
+		return false, nil
+	}
+	spec := secrets.RotationSchedule
+	if spec == "" {
+		spec = defaultRotationSchedule
+	}
+	schedule, err := maintenance.ParseSchedule(spec)
+	if err != nil {
+		return false, fmt.Errorf("spec.security.secretManagement.rotationSchedule: %w", err)
+	}
+	last := axelarNode.CreationTimestamp.Time
+	if status := axelarNode.Status.SecretRotation; status != nil && status.StartedAt != nil {
+		last = status.StartedAt.Time
+	}
+	return !schedule.Next(last).After(time.Now()), nil
+}
+
+// secretRotationRefusal explains why the passwords of the node cannot be
+// rotated, or returns an empty string
+func secretRotationRefusal(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if axelarNode.Spec.Security.SecretManagement.SecretName != "" {
+		return "the passwords are in a Secret managed outside the operator, rotate them at their source"
+	}
+	if nodeScaledOut(axelarNode) {
+		return "scaled-out nodes keep their keyring on ephemeral volumes"
+	}
+	return ""
+}
+
+// startSecretRotation records the start of a rotation
+func startSecretRotation(axelarNode *blockchainv1alpha1.AxelarNode) {
+	status := axelarNode.Status.SecretRotation
+	if status == nil {
+		status = &blockchainv1alpha1.SecretRotationStatus{}
+		axelarNode.Status.SecretRotation = status
+	}
+	status.StartedAt = &metav1.Time{Time: time.Now()}
+	status.Message = "Rotating the passwords"
+}
+
+// passwordsSecret returns the Secret the operator creates for the passwords
+func (r *AxelarNodeReconciler) passwordsSecret(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: passwordsSecretName(axelarNode), Namespace: axelarNode.Namespace}, secret)
+	return secret, err
+}
+
+// stagePasswords adds a new password next to each managed password of the
+// Secret, and returns the keys rotated. Passwords already staged are kept,
+// so stopping the node over several reconciles stages them once.
+func (r *AxelarNodeReconciler) stagePasswords(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) ([]string, error) {
+	secret, err := r.passwordsSecret(ctx, axelarNode)
+	if err != nil {
+		return nil, err
+	}
+	managed := managedKeys(secret)
+	var keys []string
+	changed := false
+	for _, key := range rotatedPasswords {
+		if _, ok := secret.Data[key]; !ok || !managed[key] {
+			continue
+		}
+		keys = append(keys, key)
+		if _, staged := secret.Data[key+nextPasswordSuffix]; staged {
+			continue
+		}
+		password, err := newPassword()
+		if err != nil {
+			return nil, err
+		}
+		secret.Data[key+nextPasswordSuffix] = password
+		changed = true
+	}
+	if changed {
+		if err := r.Update(ctx, secret); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// finishPasswords replaces the passwords with the staged ones when promote
+// is true, or discards the staged ones. Passwords no longer staged were
+// already finished.
+func (r *AxelarNodeReconciler) finishPasswords(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, promote bool) error {
+	secret, err := r.passwordsSecret(ctx, axelarNode)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, key := range rotatedPasswords {
+		next, staged := secret.Data[key+nextPasswordSuffix]
+		if !staged {
+			continue
+		}
+		if promote {
+			secret.Data[key] = next
+		}
+		delete(secret.Data, key+nextPasswordSuffix)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if promote {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[rotatedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	}
+	return r.Update(ctx, secret)
+}
+
+// completeSecretRotation promotes or discards the staged passwords once the
+// re-encryption Job is done, and records the outcome
+func (r *AxelarNodeReconciler) completeSecretRotation(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus, succeeded bool) error {
+	if err := r.finishPasswords(ctx, axelarNode, succeeded); err != nil {
+		return err
+	}
+	keys := rotationKeys(op)
+	status := axelarNode.Status.SecretRotation
+	if status == nil {
+		status = &blockchainv1alpha1.SecretRotationStatus{}
+		axelarNode.Status.SecretRotation = status
+	}
+	if !succeeded {
+		status.Message = "The keyring could not be re-encrypted, the passwords were kept"
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "SecretRotationFailed", status.Message)
+		}
+		return nil
+	}
+	status.LastRotation = &metav1.Time{Time: time.Now()}
+	status.Keys = keys
+	status.Message = fmt.Sprintf("Rotated %s", strings.Join(keys, " and "))
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, corev1.EventTypeNormal, "SecretsRotated", status.Message)
+	}
+	return nil
+}
+
+// rotationKeys returns the Secret keys a rotation replaces, recorded in the
+// archive of the operation
+func rotationKeys(op *blockchainv1alpha1.OperationStatus) []string {
+	if op.Archive == "" {
+		return nil
+	}
+	keys := strings.Split(op.Archive, ",")
+	sort.Strings(keys)
+	return keys
+}
+
+// addSecretRotation turns the operation Job into the re-encryption of the
+// file keyring of the active node, and of the standby one
+func addSecretRotation(axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus, podSpec *corev1.PodSpec) {
+	container := &podSpec.Containers[0]
+	container.Command = []string{"sh", "-c", secretRotationScript}
+	homes := "/home/axelard/.axelar"
+	if standbyEnabled(axelarNode) {
+		homes += " /standby"
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "standby", MountPath: "/standby"})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "standby",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: dataClaimName(axelarNode, standbySlot(axelarNode)),
+				},
+			},
+		})
+	}
+	container.Env = []corev1.EnvVar{{Name: "HOMES", Value: homes}}
+
+	// Only the file backend encrypts the keyring with the password
+	rotated := false
+	for _, key := range rotationKeys(op) {
+		rotated = rotated || key == "keyring-password"
+	}
+	if !rotated || migratedKeyringBackend(axelarNode) != defaultKeyringBackend {
+		return
+	}
+	secret := corev1.LocalObjectReference{Name: passwordsSecretName(axelarNode)}
+	container.Env = append(container.Env,
+		corev1.EnvVar{
+			Name: "KEYRING_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: secret, Key: "keyring-password"},
+			},
+		},
+		corev1.EnvVar{
+			Name: "NEW_KEYRING_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: secret, Key: "keyring-password" + nextPasswordSuffix},
+			},
+		})
+}
+
+// newPassword returns a random password
+func newPassword() ([]byte, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return []byte(base64.RawURLEncoding.EncodeToString(buf)), nil
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// passwordsMountPath is where password files are mounted
+const passwordsMountPath = "/run/secrets/axelar"
+
+// passwordKeys maps the Secret keys of the passwords to their variables
+var passwordKeys = map[string]string{
+	"keyring-password": "KEYRING_PASSWORD",
+	"tofnd-password":   "TOFND_PASSWORD",
+}
+
+// managedKeysAnnotation lists the keys of a Secret the operator manages.
+// Other keys are left to the user, and removing a key from the list hands it
+// over to the user.
+const managedKeysAnnotation = "blockchain.axelar.network/managed-keys"
+
+// managedKeys returns the keys of secret the operator manages. Secrets created
+// before the annotation existed are managed for the passwords they hold.
+func managedKeys(secret *corev1.Secret) map[string]bool {
+	managed := map[string]bool{}
+	value, ok := secret.Annotations[managedKeysAnnotation]
+	if !ok {
+		for key := range passwordKeys {
+			if _, found := secret.Data[key]; found {
+				managed[key] = true
+			}
+		}
+		return managed
+	}
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			managed[key] = true
+		}
+	}
+	return managed
+}
+
+// setManagedKeys records the keys the operator manages on secret
+func setManagedKeys(secret *corev1.Secret, managed map[string]bool) {
+	keys := make([]string, 0, len(managed))
+	for key, ok := range managed {
+		if ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[managedKeysAnnotation] = strings.Join(keys, ",")
+}
+
+// mergeSecretData merges the keys the operator wants into found, and reports
+// whether found changed. Missing keys are added and managed. Managed keys keep
+// their value, as the passwords may have been changed since, and are removed
+// once no longer wanted. Keys the operator does not manage are never touched,
+// even when it wants them.
+func mergeSecretData(found *corev1.Secret, desired map[string][]byte) bool {
+	managed := managedKeys(found)
+	_, annotated := found.Annotations[managedKeysAnnotation]
+	changed := !annotated
+	if found.Data == nil {
+		found.Data = map[string][]byte{}
+	}
+	for key, value := range desired {
+		if _, ok := found.Data[key]; !ok {
+			found.Data[key] = value
+			managed[key] = true
+			changed = true
+		}
+	}
+	for key := range managed {
+		if _, wanted := desired[key]; !wanted {
+			delete(found.Data, key)
+			delete(managed, key)
+			changed = true
+		}
+	}
+	if changed {
+		setManagedKeys(found, managed)
+	}
+	return changed
+}
+
+// passwordsSecretName returns the Secret holding the keyring and tofnd passwords
+func passwordsSecretName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if name := axelarNode.Spec.Security.SecretManagement.SecretName; name != "" {
+		return name
+	}
+	return childName(axelarNode, "secrets")
+}
+
+// addPasswordFiles replaces the password environment variables with files
+// mounted from the Secret, so the passwords do not show up in the pod spec or
+// the container runtime configuration. Each container exports them to its
+// own process only.
+func addPasswordFiles(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	if axelarNode.Spec.Security.SecretManagement.Delivery != blockchainv1alpha1.SecretDeliveryFile {
+		return
+	}
+
+	mounted := false
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		env := container.Env[:0]
+		script := ""
+		for _, variable := range container.Env {
+			if ref := variable.ValueFrom; ref != nil && ref.SecretKeyRef != nil && passwordKeys[ref.SecretKeyRef.Key] == variable.Name {
+				file := passwordsMountPath + "/" + ref.SecretKeyRef.Key
+				script += fmt.Sprintf("export %s=\"$(cat %s)\"\n", variable.Name, file)
+				container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+					Name: "passwords", MountPath: file, SubPath: ref.SecretKeyRef.Key, ReadOnly: true,
+				})
+				continue
+			}
+			env = append(env, variable)
+		}
+		if script == "" {
+			continue
+		}
+		// The original command is passed as the script arguments
+		container.Env = env
+		container.Args = append(append([]string{}, container.Command...), container.Args...)
+		container.Command = []string{"sh", "-c", script + `exec "$0" "$@"`}
+		mounted = true
+	}
+	if !mounted {
+		return
+	}
+
+	mode := int32(0o440)
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "passwords",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: passwordsSecretName(axelarNode), DefaultMode: &mode},
+		},
+	})
+}
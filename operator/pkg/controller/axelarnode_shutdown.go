@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Shutdown defaults for nodes created before the shutdown spec existed
+const (
+	defaultShutdownGracePeriod = 5 * time.Minute
+	defaultShutdownDrainDelay  = 5 * time.Second
+)
+
+// signerStopTimeout bounds how long the node container of a validator waits
+// for tofnd to stop before stopping axelard
+const signerStopTimeout = 30 * time.Second
+
+// stopScript waits for endpoints to drain, then stops axelard with SIGTERM and
+// waits for it to exit. axelard flushes its WAL and closes the block store on
+// SIGTERM; the kubelet only signals the container entrypoint, which does not
+// always forward it, and the kill at the end of the grace period is what
+// corrupts the store.
+const stopScript = `sleep %d
+for pid in $(pidof axelard); do kill -TERM $pid; done
+while pidof axelard >/dev/null; do sleep 1; done
+`
+
+// signerWaitScript waits for tofnd to close its port. vald and tofnd are
+// signalled as soon as the pod stops, so validators stop signing before
+// axelard stops.
+const signerWaitScript = `i=0
+while [ $i -lt %d ] && nc -z -w 1 127.0.0.1 %d; do i=$((i+1)); sleep 1; done
+`
+
+// addGracefulShutdown stops axelard cleanly before the kubelet kills the node container
+func addGracefulShutdown(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	gracePeriod := axelarNode.Spec.Shutdown.GracePeriod.Duration
+	if gracePeriod <= 0 {
+		gracePeriod = defaultShutdownGracePeriod
+	}
+	drainDelay := axelarNode.Spec.Shutdown.DrainDelay.Duration
+	if drainDelay <= 0 {
+		drainDelay = defaultShutdownDrainDelay
+	}
+
+	script := fmt.Sprintf(stopScript, int(drainDelay.Seconds()))
+	if ownTofnd(axelarNode) && !splitSigner(axelarNode) {
+		script = fmt.Sprintf(signerWaitScript, int(signerStopTimeout.Seconds()), tofndPort) + script
+		gracePeriod += signerStopTimeout
+	}
+
+	// The grace period covers the drain delay as well as the stop
+	seconds := int64((gracePeriod + drainDelay).Seconds())
+	podSpec.TerminationGracePeriodSeconds = &seconds
+
+	podSpec.Containers[0].Lifecycle = &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sh", "-c", script},
+			},
+		},
+	}
+}
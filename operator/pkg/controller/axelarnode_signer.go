@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// armedAnnotation records on the pod template whether the node may sign
+const armedAnnotation = "blockchain.axelar.network/armed"
+
+// armScript installs the consensus key and raises the signing state to the
+// replicated height plus a safety margin, so the node never signs a height
+// the previously armed node may already have signed
+const armScript = `set -e
+CONFIG=/home/axelard/.axelar/config
+DATA=/home/axelard/.axelar/data
+mkdir -p $CONFIG $DATA
+cp /signer-key/priv_validator_key.json $CONFIG/priv_validator_key.json
+REPLICATED=$(cat /signing-state/height 2>/dev/null || echo 0)
+LOCAL=0
+if [ -f $DATA/priv_validator_state.json ]; then
+  LOCAL=$(grep -o '"height": *"[0-9]*"' $DATA/priv_validator_state.json | grep -o '[0-9][0-9]*' || echo 0)
+fi
+SAFE=$((REPLICATED + %d))
+if [ "$REPLICATED" -gt 0 ] && [ "$SAFE" -gt "$LOCAL" ]; then
+  printf '{"height":"%%s","round":0,"step":0}' "$SAFE" > $DATA/priv_validator_state.json
+fi
+`
+
+// disarmScript removes the consensus key so the node cannot sign
+const disarmScript = `rm -f /home/axelard/.axelar/config/priv_validator_key.json`
+
+// signerSpec returns the signer configuration of a validator, if any
+func signerSpec(axelarNode *blockchainv1alpha1.AxelarNode) *blockchainv1alpha1.SignerSpec {
+	if axelarNode.Spec.Validator == nil || !axelarNode.Spec.Validator.Enabled {
+		return nil
+	}
+	return axelarNode.Spec.Validator.Signer
+}
+
+// validatorSigning reports whether the node runs the validator signing stack
+func validatorSigning(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	if axelarNode.Spec.Validator == nil || !axelarNode.Spec.Validator.Enabled {
+		return false
+	}
+	signer := signerSpec(axelarNode)
+	return signer == nil || signer.Armed
+}
+
+// addSignerGuard adds the init container that arms or disarms the node
+func (r *AxelarNodeReconciler) addSignerGuard(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	signer := signerSpec(axelarNode)
+	if signer == nil {
+		return
+	}
+
+	guard := corev1.Container{
+		Name:    "signer-guard",
+		Image:   nodeImage(axelarNode),
+		Command: []string{"sh", "-c", disarmScript},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: "/home/axelard/.axelar"},
+		},
+	}
+
+	if signer.Armed {
+		guard.Command = []string{"sh", "-c", fmt.Sprintf(armScript, signer.SafetyMargin)}
+		guard.VolumeMounts = append(guard.VolumeMounts,
+			corev1.VolumeMount{Name: "signer-key", MountPath: "/signer-key", ReadOnly: true})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "signer-key",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: signer.KeySecret},
+			},
+		})
+
+		if signer.SigningStateConfigMap != "" {
+			optional := true
+			guard.VolumeMounts = append(guard.VolumeMounts,
+				corev1.VolumeMount{Name: "signing-state", MountPath: "/signing-state", ReadOnly: true})
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: "signing-state",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: signer.SigningStateConfigMap},
+						Optional:             &optional,
+					},
+				},
+			})
+		}
+	}
+
+	podSpec.InitContainers = append(podSpec.InitContainers, guard)
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Defaults of the SLO when the spec leaves them unset
+const (
+	defaultSLOMaxSyncLag          = time.Minute
+	defaultSLOMaxDowntimePerMonth = 43 * time.Minute
+)
+
+// sloSampleInterval is the minimum time between two availability samples
+const sloSampleInterval = 15 * time.Second
+
+// sloMaxSampleGap bounds the time a sample accounts for, so the time the
+// operator itself was down is not attributed to the node
+const sloMaxSampleGap = 5 * time.Minute
+
+// sloHistoryMonths is the number of past months kept in the status
+const sloHistoryMonths = 12
+
+// sloErrorBudgetRemaining is exported on the operator metrics endpoint
+var sloErrorBudgetRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "axelar_node_slo_error_budget_remaining",
+	Help: "Share of the monthly downtime budget of the node left, negative once overspent",
+}, []string{"namespace", "name"})
+
+func init() {
+	metrics.Registry.MustRegister(sloErrorBudgetRemaining)
+}
+
+// sloDown reports whether the node is down for its SLO. The lag is measured
+// from the chain head of the reference RPCs when known, so a halted chain
+// is not held against the node.
+func sloDown(axelarNode *blockchainv1alpha1.AxelarNode, spec *blockchainv1alpha1.SLOSpec, now time.Time) bool {
+	maxLag := spec.MaxSyncLag.Duration
+	if maxLag <= 0 {
+		maxLag = defaultSLOMaxSyncLag
+	}
+	head := now
+	if headTime := axelarNode.Status.SyncInfo.HeadTime; headTime != nil && headTime.Time.Before(now) {
+		head = headTime.Time
+	}
+	last := axelarNode.Status.SyncInfo.LastSyncTime
+	return axelarNode.Status.Phase != "Running" || last == nil || head.Sub(last.Time) > maxLag
+}
+
+// trackSLO samples the availability of the node, accounts the time since
+// the previous sample as up or down, and rolls the month over into the
+// history. An alert is sent when the downtime of the month exceeds its budget.
+func (r *AxelarNodeReconciler) trackSLO(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) {
+	spec := axelarNode.Spec.Monitoring.SLO
+	if spec == nil {
+		axelarNode.Status.SLO = nil
+		sloErrorBudgetRemaining.DeleteLabelValues(axelarNode.Namespace, axelarNode.Name)
+		return
+	}
+	now := time.Now().UTC()
+	month := now.Format("2006-01")
+
+	status := axelarNode.Status.SLO
+	if status == nil {
+		status = &blockchainv1alpha1.SLOStatus{Month: month, Compliant: true}
+	}
+	if status.Month != month {
+		history := status.History
+		if status.ObservedSeconds > 0 {
+			history = append([]blockchainv1alpha1.SLOMonthSummary{{
+				Month:           status.Month,
+				Availability:    status.Availability,
+				DowntimeSeconds: status.DowntimeSeconds,
+				Compliant:       status.Compliant,
+			}}, history...)
+		}
+		if len(history) > sloHistoryMonths {
+			history = history[:sloHistoryMonths]
+		}
+		status = &blockchainv1alpha1.SLOStatus{Month: month, Compliant: true, History: history}
+	}
+	axelarNode.Status.SLO = status
+
+	if status.LastSample != nil {
+		elapsed := now.Sub(status.LastSample.Time)
+		if elapsed < sloSampleInterval {
+			return
+		}
+		if elapsed > sloMaxSampleGap {
+			elapsed = sloMaxSampleGap
+		}
+		status.ObservedSeconds += int64(elapsed.Seconds())
+		if sloDown(axelarNode, spec, now) {
+			status.DowntimeSeconds += int64(elapsed.Seconds())
+		}
+	}
+	status.LastSample = &metav1.Time{Time: now}
+
+	budget := spec.MaxDowntimePerMonth.Duration
+	if budget <= 0 {
+		budget = defaultSLOMaxDowntimePerMonth
+	}
+	remaining := 1 - float64(status.DowntimeSeconds)/budget.Seconds()
+	sloErrorBudgetRemaining.WithLabelValues(axelarNode.Namespace, axelarNode.Name).Set(remaining)
+	status.ErrorBudgetRemaining = fmt.Sprintf("%.1f%%", 100*max(remaining, 0))
+	if status.ObservedSeconds > 0 {
+		status.Availability = fmt.Sprintf("%.3f%%", 100*float64(status.ObservedSeconds-status.DowntimeSeconds)/float64(status.ObservedSeconds))
+	}
+
+	wasCompliant := status.Compliant
+	status.Compliant = time.Duration(status.DowntimeSeconds)*time.Second <= budget
+	if wasCompliant && !status.Compliant {
+		text := fmt.Sprintf(":chart_with_downwards_trend: The downtime of %s in %s exceeds its budget of %s, availability is %s",
+			axelarNode.Name, month, budget, status.Availability)
+		r.Log.Info("SLO notification", "axelarnode", axelarNode.Name, "message", text)
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "SLOBudgetExhausted", text)
+		}
+		if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+			r.Log.Error(err, "Unable to send SLO alert", "axelarnode", axelarNode.Name)
+		}
+	}
+}
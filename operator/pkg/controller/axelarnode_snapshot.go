@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/snapshot"
+)
+
+// snapshotAnnotation records the bootstrap snapshot on the pod template, so a
+// newly selected snapshot reaches the init container
+const snapshotAnnotation = "blockchain.axelar.network/snapshot"
+
+// snapshotProgressPort is where the downloader serves its progress
+const snapshotProgressPort = 26670
+
+// snapshotBootstrapContainer is the name of the downloader init container
+const snapshotBootstrapContainer = "snapshot-bootstrap"
+
+// bootstrapSnapshot returns the snapshot empty data volumes are bootstrapped
+// from, or nil
+func bootstrapSnapshot(axelarNode *blockchainv1alpha1.AxelarNode) *blockchainv1alpha1.SnapshotStatus {
+	if axelarNode.Spec.Sync.SnapshotProvider == nil {
+		return nil
+	}
+	return axelarNode.Status.Snapshot
+}
+
+// reconcileSnapshot selects the latest matching snapshot from the provider
+// index once, and reports whether a snapshot is selected or none is needed
+func (r *AxelarNodeReconciler) reconcileSnapshot(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	provider := axelarNode.Spec.Sync.SnapshotProvider
+	if provider == nil || axelarNode.Status.Snapshot != nil {
+		return true
+	}
+
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionSnapshotSelected,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: axelarNode.Generation,
+	}
+
+	snapshots, err := r.snapshotIndex(ctx, axelarNode, provider)
+	if err != nil {
+		condition.Reason = "IndexUnavailable"
+		condition.Message = err.Error()
+		meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+		return false
+	}
+	// Entries of the index above the chain head are ignored, the reference
+	// RPCs lagging by a few blocks at most
+	if head, _, err := chainHead(ctx, axelarNode); err != nil {
+		r.Log.WithValues("axelarnode", axelarNode.Name).V(1).Info("Unable to learn the chain head", "error", err.Error())
+	} else if head > 0 {
+		snapshots = snapshot.AtMost(snapshots, head+syncLagThreshold)
+	}
+	latest, err := snapshot.Latest(snapshots, axelarNode.Spec.Network, axelarNode.Spec.Pruning)
+	if err != nil {
+		condition.Reason = "NoMatchingSnapshot"
+		condition.Message = err.Error()
+		meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+		return false
+	}
+	if airgap.Offline() && !strings.HasPrefix(latest.URL, "file://") {
+		condition.Reason = "Offline"
+		condition.Message = fmt.Sprintf("Snapshot %s is outside the cluster, offline nodes bootstrap from file://%s archives of spec.sync.snapshotProvider.volumeClaim",
+			latest.URL, snapshotVolumeMountPath)
+		meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+		return false
+	}
+	checksum, _ := snapshot.Checksum(latest.Checksum)
+
+	r.Log.WithValues("axelarnode", axelarNode.Name).Info("Selected bootstrap snapshot", "url", latest.URL, "height", latest.Height)
+	axelarNode.Status.Snapshot = &blockchainv1alpha1.SnapshotStatus{
+		URL:        latest.URL,
+		Height:     latest.Height,
+		Checksum:   checksum,
+		SelectedAt: &metav1.Time{Time: time.Now()},
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "Selected"
+	condition.Message = fmt.Sprintf("Bootstrapping empty data volumes from the snapshot at height %d", latest.Height)
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	return true
+}
+
+// snapshotIndex reads the provider index from spec.sync.snapshotProvider.indexFrom,
+// or downloads it
+func (r *AxelarNodeReconciler) snapshotIndex(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, provider *blockchainv1alpha1.SnapshotProviderSpec) ([]snapshot.Snapshot, error) {
+	if provider.IndexFrom != nil {
+		document, err := r.configMapValue(ctx, axelarNode.Namespace, provider.IndexFrom)
+		if err != nil {
+			return nil, err
+		}
+		snapshots, err := snapshot.Parse(document)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot index in ConfigMap %s: %w", provider.IndexFrom.Name, err)
+		}
+		return snapshots, nil
+	}
+	return snapshot.Fetch(ctx, snapshot.IndexURL(provider.IndexURL, axelarNode.Spec.Network, axelarNode.Spec.Pruning))
+}
+
+// addSnapshotBootstrap downloads the selected snapshot into an empty data
+// volume before the node starts
+func (r *AxelarNodeReconciler) addSnapshotBootstrap(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	snap := bootstrapSnapshot(axelarNode)
+	if snap == nil {
+		return
+	}
+
+	args := []string{
+		"snapshot-download",
+		"--url=" + snap.URL,
+		"--sha256=" + snap.Checksum,
+		"--home=/home/axelard/.axelar",
+		fmt.Sprintf("--progress-listen=:%d", snapshotProgressPort),
+	}
+	if connections := axelarNode.Spec.Sync.SnapshotProvider.Connections; connections > 0 {
+		args = append(args, fmt.Sprintf("--connections=%d", connections))
+	}
+	bootstrap := corev1.Container{
+		Name:  snapshotBootstrapContainer,
+		Image: r.ToolsImage,
+		Args:  args,
+		Ports: []corev1.ContainerPort{
+			{Name: "snapshot", ContainerPort: snapshotProgressPort, Protocol: corev1.ProtocolTCP},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: "/home/axelard/.axelar"},
+		},
+	}
+	if claim := axelarNode.Spec.Sync.SnapshotProvider.VolumeClaim; claim != "" {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "snapshots",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim, ReadOnly: true},
+			},
+		})
+		bootstrap.VolumeMounts = append(bootstrap.VolumeMounts, corev1.VolumeMount{Name: "snapshots", MountPath: snapshotVolumeMountPath, ReadOnly: true})
+	}
+	podSpec.InitContainers = append([]corev1.Container{bootstrap}, podSpec.InitContainers...)
+}
+
+// reportSnapshotProgress records the progress of the snapshot bootstrap in
+// the SnapshotBootstrapped condition
+func (r *AxelarNodeReconciler) reportSnapshotProgress(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) {
+	snap := bootstrapSnapshot(axelarNode)
+	if snap == nil || meta.IsStatusConditionTrue(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionSnapshotBootstrapped) {
+		return
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(axelarNode.Namespace), client.MatchingLabels(nodeSelector(axelarNode))); err != nil {
+		return
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != snapshotBootstrapContainer {
+				continue
+			}
+			condition := metav1.Condition{
+				Type:               blockchainv1alpha1.ConditionSnapshotBootstrapped,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: axelarNode.Generation,
+			}
+			switch {
+			case status.State.Terminated != nil && status.State.Terminated.ExitCode == 0:
+				condition.Status = metav1.ConditionTrue
+				condition.Reason = "Bootstrapped"
+				condition.Message = fmt.Sprintf("Data volume bootstrapped from the snapshot at height %d", snap.Height)
+			case status.State.Running != nil && pod.Status.PodIP != "":
+				progress, err := snapshot.GetProgress(ctx, podURL(pod.Status.PodIP, snapshotProgressPort)+"/progress")
+				if err != nil {
+					return
+				}
+				condition.Reason = progress.Phase
+				condition.Message = progress.String()
+			case status.LastTerminationState.Terminated != nil:
+				condition.Reason = "DownloadFailed"
+				condition.Message = status.LastTerminationState.Terminated.Message
+				if condition.Message == "" {
+					condition.Message = fmt.Sprintf("Snapshot download exited with code %d", status.LastTerminationState.Terminated.ExitCode)
+				}
+			default:
+				return
+			}
+			meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+			return
+		}
+	}
+}
+
+// snapshotBootstrapping reports whether the snapshot bootstrap is under way
+func snapshotBootstrapping(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	condition := meta.FindStatusCondition(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionSnapshotBootstrapped)
+	return bootstrapSnapshot(axelarNode) != nil && condition != nil && condition.Status == metav1.ConditionFalse
+}
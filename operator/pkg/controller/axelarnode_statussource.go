@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/cosmos"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/nodeclient"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tendermint"
+)
+
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// statusClients returns the RPC and REST clients the pollers query the node with
+func (r *AxelarNodeReconciler) statusClients(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (*tendermint.Client, *cosmos.Client, error) {
+	return nodeStatusClients(ctx, r.Client, r.userReader(), r.RESTConfig, axelarNode)
+}
+
+// nodeStatusClients returns the RPC and REST clients of a node, following
+// spec.monitoring.statusSource. Their requests share the connections, rate
+// limit and circuit breaker of the node. The credentials of the status source
+// are read through reader.
+func nodeStatusClients(ctx context.Context, c client.Client, reader client.Reader, restConfig *rest.Config, axelarNode *blockchainv1alpha1.AxelarNode) (*tendermint.Client, *cosmos.Client, error) {
+	rpcURL, apiURL := nodeRPCURL(axelarNode), nodeAPIURL(axelarNode)
+	source := axelarNode.Spec.Monitoring.StatusSource
+	if source == nil {
+		httpClient := nodeHTTPClient(axelarNode)
+		rpc := tendermint.NewClient(rpcURL)
+		rpc.HTTPClient = httpClient
+		api := cosmos.NewClient(apiURL)
+		api.HTTPClient = httpClient
+		return rpc, api, nil
+	}
+
+	timeout := source.Timeout.Duration
+	headers, err := statusTransport(ctx, reader, axelarNode, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	var transport http.RoundTripper = headers
+
+	switch source.Mode {
+	case blockchainv1alpha1.StatusSourcePod:
+		pod, err := statusPod(ctx, c, axelarNode)
+		if err != nil {
+			return nil, nil, err
+		}
+		address := podIP(pod, serviceIPFamilies(axelarNode.Spec.Networking)[0])
+		rpcURL = podURL(address, axelarNode.Spec.Networking.RPC.Port)
+		apiURL = podURL(address, axelarNode.Spec.Networking.API.Port)
+	case blockchainv1alpha1.StatusSourceURL:
+		if source.RPCURL != "" {
+			rpcURL = strings.TrimSuffix(source.RPCURL, "/")
+		}
+		if source.APIURL != "" {
+			apiURL = strings.TrimSuffix(source.APIURL, "/")
+		}
+	case blockchainv1alpha1.StatusSourceExec:
+		if restConfig == nil {
+			return nil, nil, fmt.Errorf("the Exec status source needs the Kubernetes API configuration")
+		}
+		pod, err := statusPod(ctx, c, axelarNode)
+		if err != nil {
+			return nil, nil, err
+		}
+		clientset, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		if timeout <= 0 {
+			timeout = tendermint.DefaultTimeout
+		}
+		transport = &execTransport{
+			config:    restConfig,
+			clientset: clientset,
+			pod:       types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace},
+			container: "axelar-node",
+			timeout:   timeout,
+			header:    headers.header,
+		}
+		rpcURL = podURL("127.0.0.1", axelarNode.Spec.Networking.RPC.Port)
+		apiURL = podURL("127.0.0.1", axelarNode.Spec.Networking.API.Port)
+	}
+
+	httpClient := nodeclient.Client(axelarNode.Namespace, axelarNode.Name, timeout, transport)
+	rpc := tendermint.NewClient(rpcURL)
+	rpc.HTTPClient = httpClient
+	api := cosmos.NewClient(apiURL)
+	api.HTTPClient = httpClient
+	return rpc, api, nil
+}
+
+// statusPod returns a running and ready pod of the node
+func statusPod(ctx context.Context, c client.Client, axelarNode *blockchainv1alpha1.AxelarNode) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(axelarNode.Namespace), client.MatchingLabels(nodeSelector(axelarNode))); err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning && podReady(pod) {
+			return pod, nil
+		}
+	}
+	return nil, fmt.Errorf("no ready pod of %s to collect the status from", axelarNode.Name)
+}
+
+// nodeHTTPClient returns the pooled client of the requests sent to the pods
+// and Services of a node
+func nodeHTTPClient(axelarNode *blockchainv1alpha1.AxelarNode) *http.Client {
+	return nodeclient.Client(axelarNode.Namespace, axelarNode.Name, 0, nil)
+}
+
+// statusTransport returns the transport of a status source, sending its
+// bearer token and verifying HTTPS endpoints with its TLS settings. The
+// transport is kept by the node client pool while the settings are unchanged.
+func statusTransport(ctx context.Context, reader client.Reader, axelarNode *blockchainv1alpha1.AxelarNode, source *blockchainv1alpha1.StatusSourceSpec) (*headerTransport, error) {
+	namespace := axelarNode.Namespace
+	var bundle []byte
+	fingerprint := sha256.New()
+	if spec := source.TLS; spec != nil {
+		if spec.CASecretRef != nil {
+			var err error
+			if bundle, err = secretValue(ctx, reader, namespace, *spec.CASecretRef); err != nil {
+				return nil, err
+			}
+			if !x509.NewCertPool().AppendCertsFromPEM(bundle) {
+				return nil, fmt.Errorf("secret %s holds no PEM certificate under %s", spec.CASecretRef.Name, spec.CASecretRef.Key)
+			}
+		}
+		fmt.Fprintf(fingerprint, "%s\x00%t\x00", spec.ServerName, spec.InsecureSkipVerify)
+		fingerprint.Write(bundle)
+	}
+	base := nodeclient.Transport(namespace, axelarNode.Name, hex.EncodeToString(fingerprint.Sum(nil)), func() *http.Transport {
+		base := outbound.Transport()
+		if spec := source.TLS; spec != nil {
+			base.TLSClientConfig = &tls.Config{
+				ServerName:         spec.ServerName,
+				InsecureSkipVerify: spec.InsecureSkipVerify,
+				RootCAs:            outbound.RootCAs(),
+			}
+			if bundle != nil {
+				pool := outbound.RootCAs()
+				if pool != nil {
+					pool = pool.Clone()
+				} else if systemPool, err := x509.SystemCertPool(); err == nil {
+					pool = systemPool
+				} else {
+					pool = x509.NewCertPool()
+				}
+				pool.AppendCertsFromPEM(bundle)
+				base.TLSClientConfig.RootCAs = pool
+			}
+		}
+		return base
+	})
+
+	header := http.Header{}
+	if ref := source.BearerTokenSecretRef; ref != nil {
+		token, err := secretValue(ctx, reader, namespace, *ref)
+		if err != nil {
+			return nil, err
+		}
+		header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return &headerTransport{base: base, header: header}, nil
+}
+
+// secretValue reads a key of a Secret
+func secretValue(ctx context.Context, reader client.Reader, namespace string, ref corev1.SecretKeySelector) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return value, nil
+}
+
+// headerTransport adds headers to the requests of base
+type headerTransport struct {
+	base   http.RoundTripper
+	header http.Header
+}
+
+// RoundTrip sends req with the headers of the transport
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.header) > 0 {
+		req = req.Clone(req.Context())
+		for name, values := range t.header {
+			req.Header[name] = values
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// execTransport sends GET requests from inside a pod with wget, through the
+// exec subresource of the Kubernetes API
+type execTransport struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+	pod       types.NamespacedName
+	container string
+	timeout   time.Duration
+	header    http.Header
+}
+
+// RoundTrip runs wget against the URL of req in the pod. Any answer wget
+// accepts is reported as 200 OK.
+func (t *execTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return nil, fmt.Errorf("the Exec status source only sends GET requests")
+	}
+	command := []string{"wget", "-q", "-O-", "-T", fmt.Sprintf("%d", int(t.timeout.Seconds())+1)}
+	for name := range t.header {
+		command = append(command, "--header", name+": "+t.header.Get(name))
+	}
+	command = append(command, req.URL.String())
+
+	execReq := t.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(t.pod.Namespace).Name(t.pod.Name).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: t.container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(t.config, http.MethodPost, execReq.URL())
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	if err := executor.StreamWithContext(req.Context(), remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return nil, fmt.Errorf("wget %s in %s: %w: %s", req.URL.Path, t.pod.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(&stdout),
+		ContentLength: int64(stdout.Len()),
+		Request:       req,
+	}, nil
+}
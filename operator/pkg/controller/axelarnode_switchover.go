@@ -0,0 +1,501 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tendermint"
+)
+
+// Values of the switchover annotation that resolve a failed switchover
+const (
+	switchoverRollback = "rollback"
+	switchoverForce    = "force"
+)
+
+// transferScript moves the validator signing key and state from the old to the
+// new data volume. The key is only removed from the old volume once the new
+// volume holds a complete copy, and neither node runs while it executes.
+const transferScript = `set -e
+test -f /old/config/priv_validator_key.json
+mkdir -p /new/config /new/data
+cp /old/config/priv_validator_key.json /new/config/priv_validator_key.json.tmp
+if [ -f /old/data/priv_validator_state.json ]; then
+  cp /old/data/priv_validator_state.json /new/data/priv_validator_state.json.tmp
+  mv /new/data/priv_validator_state.json.tmp /new/data/priv_validator_state.json
+fi
+if [ -d /old/keyring-file ]; then
+  rm -rf /new/keyring-file
+  cp -a /old/keyring-file /new/keyring-file
+fi
+sync
+mv /new/config/priv_validator_key.json.tmp /new/config/priv_validator_key.json
+rm /old/config/priv_validator_key.json
+sync
+`
+
+// activeSlot returns the data volume slot of the signing node
+func activeSlot(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if sw := axelarNode.Status.Switchover; sw != nil && sw.ActiveSlot != "" {
+		return sw.ActiveSlot
+	}
+	return blockchainv1alpha1.SlotBlue
+}
+
+// standbySlot returns the data volume slot of the standby node
+func standbySlot(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if activeSlot(axelarNode) == blockchainv1alpha1.SlotBlue {
+		return blockchainv1alpha1.SlotGreen
+	}
+	return blockchainv1alpha1.SlotBlue
+}
+
+// dataVolumeSuffix returns the PVC name suffix of a data volume slot
+func dataVolumeSuffix(slot string) string {
+	if slot == blockchainv1alpha1.SlotGreen {
+		return "green-data"
+	}
+	return "data"
+}
+
+// dataClaimName returns the PVC of a data volume slot. The blue slot uses
+// spec.storage.existingClaim or the adopted PVC when set.
+func dataClaimName(axelarNode *blockchainv1alpha1.AxelarNode, slot string) string {
+	if slot != blockchainv1alpha1.SlotGreen {
+		if claim := axelarNode.Spec.Storage.ExistingClaim; claim != "" {
+			return claim
+		}
+		if adoption := axelarNode.Status.Adoption; adoption != nil && adoption.DataClaim != "" {
+			return adoption.DataClaim
+		}
+	}
+	return childName(axelarNode, dataVolumeSuffix(slot))
+}
+
+// standbyEnabled reports whether a standby node runs next to the validator
+func standbyEnabled(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Spec.Validator != nil && axelarNode.Spec.Validator.Enabled &&
+		axelarNode.Spec.Validator.Standby.Enabled
+}
+
+// reconcileStandby creates or removes the standby node
+func (r *AxelarNodeReconciler) reconcileStandby(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	standby := r.createStandbyDeployment(axelarNode)
+
+	if !standbyEnabled(axelarNode) {
+		err := r.Delete(ctx, standby)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return r.deleteStandbyService(ctx, axelarNode)
+	}
+
+	if err := r.reconcileDataClaim(ctx, axelarNode, standbySlot(axelarNode)); err != nil {
+		return err
+	}
+
+	if err := r.reconcileStandbyService(ctx, axelarNode); err != nil {
+		return err
+	}
+
+	// The standby follows the node, whose changes are only previewed in dry-run
+	if nodeDryRun(axelarNode) {
+		return nil
+	}
+	return r.applyDeployment(ctx, axelarNode, standby)
+}
+
+// deleteStandbyService removes the Service of a standby that is no longer run
+func (r *AxelarNodeReconciler) deleteStandbyService(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	found := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: childName(axelarNode, "standby-service"), Namespace: axelarNode.Namespace}, found)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := r.Delete(ctx, found); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileStandbyService creates the Service used to query the standby RPC
+func (r *AxelarNodeReconciler) reconcileStandbyService(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	service := createStandbyService(axelarNode)
+	if err := controllerutil.SetControllerReference(axelarNode, service, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if service.Spec.Selector, err = r.deploymentSelector(ctx, axelarNode, childName(axelarNode, "standby"), componentStandby); err != nil {
+			return err
+		}
+		return r.Create(ctx, service)
+	} else if err != nil {
+		return err
+	}
+
+	found.Spec.Ports = service.Spec.Ports
+	applyIPFamilies(axelarNode.Spec.Networking, &found.Spec)
+	mergeLabels(found, service.Labels)
+	return r.Update(ctx, found)
+}
+
+// createStandbyService creates the standby service object
+func createStandbyService(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      childName(axelarNode, "standby-service"),
+			Namespace: axelarNode.Namespace,
+			Labels:    nodeLabels(axelarNode, componentStandby),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: podSelector(axelarNode, componentStandby),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "rpc",
+					Port:       axelarNode.Spec.Networking.RPC.Port,
+					TargetPort: intstr.FromInt(int(axelarNode.Spec.Networking.RPC.Port)),
+				},
+			},
+		},
+	}
+	applyIPFamilies(axelarNode.Spec.Networking, &service.Spec)
+	return service
+}
+
+// createStandbyDeployment creates the standby deployment object. The standby
+// runs the node on the other data volume slot without vald and tofnd.
+func (r *AxelarNodeReconciler) createStandbyDeployment(axelarNode *blockchainv1alpha1.AxelarNode) *appsv1.Deployment {
+	deployment := r.createDeployment(axelarNode)
+	deployment.Name = childName(axelarNode, "standby")
+
+	// The app label of the standby differs from the node, so the pods of the
+	// node listed by their app label never include the standby
+	labels := nodeLabels(axelarNode, componentStandby)
+	labels["app"] = naming.LabelValue(deployment.Name)
+	deployment.Labels = labels
+	deployment.Spec.Selector.MatchLabels = podSelector(axelarNode, componentStandby)
+	deployment.Spec.Template.Labels = labels
+
+	podSpec := &deployment.Spec.Template.Spec
+	podSpec.Containers = podSpec.Containers[:1]
+	removeIndexer(podSpec)
+	for i := range podSpec.Volumes {
+		switch podSpec.Volumes[i].Name {
+		case "data":
+			podSpec.Volumes[i].PersistentVolumeClaim.ClaimName = dataClaimName(axelarNode, standbySlot(axelarNode))
+		case "shared":
+			podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		default:
+			// The additional volumes stay with the active validator
+			if strings.HasPrefix(podSpec.Volumes[i].Name, extraVolumePrefix) {
+				podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+			}
+		}
+	}
+
+	setTemplateHash(deployment)
+
+	controllerutil.SetControllerReference(axelarNode, deployment, r.Scheme)
+	return deployment
+}
+
+// reconcileSwitchover drives a blue/green switchover from the active validator
+// to its standby. It returns true while the switchover owns the Deployments.
+func (r *AxelarNodeReconciler) reconcileSwitchover(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+	ctx = audit.WithReason(ctx, "switchover")
+
+	request, requested := axelarNode.Annotations[blockchainv1alpha1.SwitchoverAnnotation]
+	sw := axelarNode.Status.Switchover
+
+	if sw == nil || sw.Phase == "" || sw.Phase == blockchainv1alpha1.SwitchoverCompleted {
+		if !requested || operationRunning(axelarNode) {
+			return false, nil
+		}
+
+		delete(axelarNode.Annotations, blockchainv1alpha1.SwitchoverAnnotation)
+
+		if err := r.standbyReady(ctx, axelarNode); err != nil {
+			if sw == nil {
+				sw = &blockchainv1alpha1.SwitchoverStatus{ActiveSlot: activeSlot(axelarNode)}
+			}
+			sw.Message = fmt.Sprintf("Switchover refused: %s", err)
+			axelarNode.Status.Switchover = sw
+			return false, nil
+		}
+
+		sw = &blockchainv1alpha1.SwitchoverStatus{
+			ActiveSlot: activeSlot(axelarNode),
+			Phase:      blockchainv1alpha1.SwitchoverStopping,
+			StartedAt:  &metav1.Time{Time: time.Now()},
+			Message:    "Stopping the active validator and the standby",
+		}
+		log.Info("Starting validator switchover", "from", sw.ActiveSlot)
+		axelarNode.Status.Switchover = sw
+	}
+
+	switch sw.Phase {
+	case blockchainv1alpha1.SwitchoverStopping:
+		stopped, err := r.stopValidatorPods(ctx, axelarNode)
+		if err != nil || !stopped {
+			return true, err
+		}
+		if err := r.Create(ctx, r.createTransferJob(axelarNode)); err != nil && !errors.IsAlreadyExists(err) {
+			return true, err
+		}
+		sw.Phase = blockchainv1alpha1.SwitchoverTransferring
+		sw.Message = "Transferring the validator key and signing state"
+
+	case blockchainv1alpha1.SwitchoverTransferring:
+		job := &batchv1.Job{}
+		err := r.Get(ctx, types.NamespacedName{Name: childName(axelarNode, "switchover"), Namespace: axelarNode.Namespace}, job)
+		if err != nil {
+			return true, err
+		}
+		if job.Status.Succeeded > 0 {
+			sw.ActiveSlot = standbySlot(axelarNode)
+			sw.Phase = blockchainv1alpha1.SwitchoverStarting
+			sw.Message = fmt.Sprintf("Starting the validator on the %s volume", sw.ActiveSlot)
+			return true, r.deleteTransferJob(ctx, axelarNode)
+		}
+		if job.Status.Failed > 0 {
+			log.Info("Validator switchover failed, keeping both nodes stopped")
+			sw.Phase = blockchainv1alpha1.SwitchoverFailed
+			sw.Message = fmt.Sprintf("Key transfer failed. Both nodes are stopped to prevent double-signing; "+
+				"inspect the volumes and set the %s annotation to %q or %q",
+				blockchainv1alpha1.SwitchoverAnnotation, switchoverRollback, switchoverForce)
+		}
+
+	case blockchainv1alpha1.SwitchoverFailed:
+		switch request {
+		case switchoverRollback:
+			sw.Message = "Rolled back to the previously active volume"
+		case switchoverForce:
+			sw.ActiveSlot = standbySlot(axelarNode)
+			sw.Message = fmt.Sprintf("Forced the validator onto the %s volume", sw.ActiveSlot)
+		default:
+			return true, nil
+		}
+		delete(axelarNode.Annotations, blockchainv1alpha1.SwitchoverAnnotation)
+		sw.Phase = blockchainv1alpha1.SwitchoverStarting
+		axelarNode.Status.Switchover = sw
+		return true, r.deleteTransferJob(ctx, axelarNode)
+
+	case blockchainv1alpha1.SwitchoverStarting:
+		ready, err := r.startValidatorPods(ctx, axelarNode)
+		if err != nil || !ready {
+			return true, err
+		}
+
+		log.Info("Validator switchover completed", "active", sw.ActiveSlot)
+		sw.Phase = blockchainv1alpha1.SwitchoverCompleted
+		sw.CompletedAt = &metav1.Time{Time: time.Now()}
+		sw.Message = fmt.Sprintf("Validator signing on the %s volume", sw.ActiveSlot)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// standbyReady checks that the standby is synced and close to the active node
+func (r *AxelarNodeReconciler) standbyReady(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if !standbyEnabled(axelarNode) {
+		return fmt.Errorf("no standby is configured")
+	}
+
+	rpc := tendermint.NewClient(fmt.Sprintf("http://%s.%s.svc:%d",
+		childName(axelarNode, "standby-service"), axelarNode.Namespace, axelarNode.Spec.Networking.RPC.Port))
+	rpc.HTTPClient = nodeHTTPClient(axelarNode)
+	status, err := rpc.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("standby status unavailable: %w", err)
+	}
+	if status.SyncInfo.CatchingUp {
+		return fmt.Errorf("standby is still catching up")
+	}
+
+	lag := axelarNode.Status.SyncInfo.CurrentHeight - status.SyncInfo.Height()
+	if lag > axelarNode.Spec.Validator.Standby.MaxHeightLag {
+		return fmt.Errorf("standby is %d blocks behind the active node", lag)
+	}
+	return nil
+}
+
+// stopValidatorPods scales the active and standby Deployments, and vald and
+// tofnd in the split topology, to zero and reports whether all of their pods
+// are gone
+func (r *AxelarNodeReconciler) stopValidatorPods(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	stopped := true
+	for _, name := range []string{valdName(axelarNode), tofndName(axelarNode), deploymentName(axelarNode), childName(axelarNode, "standby")} {
+		gone, err := r.stopDeployment(ctx, axelarNode, name)
+		if err != nil {
+			return false, err
+		}
+		stopped = stopped && gone
+	}
+	return stopped, nil
+}
+
+// stopDeployment scales a Deployment of the node to zero and reports whether
+// all of its pods are gone. Pods are found with the selector of the
+// Deployment, which adopted Deployments keep.
+func (r *AxelarNodeReconciler) stopDeployment(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, name string) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, deployment)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil && (deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0) {
+		patch := client.MergeFrom(deployment.DeepCopy())
+		replicas := int32(0)
+		deployment.Spec.Replicas = &replicas
+		if err := r.Patch(ctx, deployment, patch); err != nil {
+			return false, err
+		}
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{"app": naming.LabelValue(name)})
+	if err == nil && deployment.Spec.Selector != nil {
+		if selector, err = metav1.LabelSelectorAsSelector(deployment.Spec.Selector); err != nil {
+			return false, err
+		}
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(axelarNode.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return false, err
+	}
+	return len(pods.Items) == 0, nil
+}
+
+// startValidatorPods restores the active and standby Deployments, and vald
+// and tofnd in the split topology, and reports whether the active node is ready
+func (r *AxelarNodeReconciler) startValidatorPods(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (bool, error) {
+	deployment := r.createDeployment(axelarNode)
+	if err := controllerutil.SetControllerReference(axelarNode, deployment, r.Scheme); err != nil {
+		return false, err
+	}
+	if err := r.applyDeployment(ctx, axelarNode, deployment); err != nil {
+		return false, err
+	}
+	if err := r.reconcileSplitSigner(ctx, axelarNode); err != nil {
+		return false, err
+	}
+	if err := r.reconcileStandby(ctx, axelarNode); err != nil {
+		return false, err
+	}
+
+	found := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found); err != nil {
+		return false, err
+	}
+	return found.Status.ReadyReplicas > 0, nil
+}
+
+// createTransferJob creates the Job moving the signing key between data volumes
+func (r *AxelarNodeReconciler) createTransferJob(axelarNode *blockchainv1alpha1.AxelarNode) *batchv1.Job {
+	backoffLimit := int32(0)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      childName(axelarNode, "switchover"),
+			Namespace: axelarNode.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "transfer",
+							Image:   nodeImage(axelarNode),
+							Command: []string{"sh", "-c", transferScript},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "old", MountPath: "/old"},
+								{Name: "new", MountPath: "/new"},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "old",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: dataClaimName(axelarNode, activeSlot(axelarNode)),
+								},
+							},
+						},
+						{
+							Name: "new",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: dataClaimName(axelarNode, standbySlot(axelarNode)),
+								},
+							},
+						},
+					},
+					SecurityContext: axelarNode.Spec.Security.PodSecurityContext,
+				},
+			},
+		},
+	}
+
+	controllerutil.SetControllerReference(axelarNode, job, r.Scheme)
+	return job
+}
+
+// deleteTransferJob removes the transfer Job and its pod
+func (r *AxelarNodeReconciler) deleteTransferJob(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      childName(axelarNode, "switchover"),
+			Namespace: axelarNode.Namespace,
+		},
+	}
+	err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// applyDeployment creates the deployment or replaces the spec of an existing one
+func (r *AxelarNodeReconciler) applyDeployment(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, deployment *appsv1.Deployment) error {
+	found := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, deployment)
+	} else if err != nil {
+		return err
+	}
+	keepSelector(found, deployment)
+
+	// The template hash covers the data volume slot the pods mount
+	if r.deploymentEqual(found, deployment) {
+		return nil
+	}
+
+	found.Spec = deployment.Spec
+	mergeLabels(found, deployment.Labels)
+	return r.Update(ctx, found)
+}
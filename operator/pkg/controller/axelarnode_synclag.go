@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tendermint"
+)
+
+// syncLagThreshold is the number of blocks a node may trail the chain head
+// by and still be Synced, about a minute of Axelar blocks
+const syncLagThreshold = 10
+
+// referenceHeightTTL is how long the height of a reference RPC is reused,
+// so the nodes of a network share one query
+const referenceHeightTTL = 15 * time.Second
+
+// referenceHeight is the last block queried from a reference RPC
+type referenceHeight struct {
+	height    int64
+	time      time.Time
+	err       error
+	fetchedAt time.Time
+}
+
+// referenceEntry caches the last query of a reference RPC. Its lock is held
+// during the query, so concurrent reconciles wait for one query per URL
+// rather than each sending their own.
+type referenceEntry struct {
+	mu   sync.Mutex
+	last referenceHeight
+}
+
+var (
+	referenceMu      sync.Mutex
+	referenceEntries = map[string]*referenceEntry{}
+)
+
+// referenceRPCs returns the reference RPCs of the network of the node, or
+// none when the operator is offline
+func referenceRPCs(axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	if airgap.Offline() {
+		return nil
+	}
+	return nodeNetwork(axelarNode).ReferenceRPCs
+}
+
+// queryReference returns the latest block of the reference RPC at url.
+// Failures are cached too, so an unreachable reference is not queried on
+// every reconcile.
+func queryReference(ctx context.Context, url string) referenceHeight {
+	referenceMu.Lock()
+	entry, ok := referenceEntries[url]
+	if !ok {
+		entry = &referenceEntry{}
+		referenceEntries[url] = entry
+	}
+	referenceMu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if time.Since(entry.last.fetchedAt) < referenceHeightTTL {
+		return entry.last
+	}
+	rpc := tendermint.NewClient(strings.TrimSuffix(url, "/"))
+	rpc.HTTPClient = outbound.Client(tendermint.DefaultTimeout)
+	last := referenceHeight{fetchedAt: time.Now()}
+	if status, err := rpc.Status(ctx); err != nil {
+		last.err = err
+	} else {
+		last.height = status.SyncInfo.Height()
+		last.time = status.SyncInfo.LatestBlockTime
+	}
+	entry.last = last
+	return last
+}
+
+// chainHead returns the highest block among the reference RPCs of the
+// network of the node, queried concurrently. A reference that is down or
+// itself behind does not hold the head back, as long as one answers. The
+// height is 0 when the network has no reference RPCs.
+func chainHead(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (int64, time.Time, error) {
+	urls := referenceRPCs(axelarNode)
+	references := make([]referenceHeight, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			references[i] = queryReference(ctx, url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	var head referenceHeight
+	var failures []string
+	for i, reference := range references {
+		if reference.err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", urls[i], reference.err))
+			continue
+		}
+		if reference.height > head.height {
+			head = reference
+		}
+	}
+	if head.height == 0 && len(failures) > 0 {
+		return 0, time.Time{}, fmt.Errorf("no reference RPC answered: %s", strings.Join(failures, "; "))
+	}
+	return head.height, head.time, nil
+}
+
+// reportSyncLag measures how far the node trails the chain head of the
+// reference RPCs. A node can stop catching up while its peers are themselves
+// behind, so its own view of the chain tip is not enough. HeadTime stays unset
+// while no reference answers, so the node is not reported Synced on its own
+// word.
+func (r *AxelarNodeReconciler) reportSyncLag(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) {
+	syncInfo := &axelarNode.Status.SyncInfo
+	if syncInfo.CurrentHeight == 0 {
+		return
+	}
+	height, blockTime, err := chainHead(ctx, axelarNode)
+	if err != nil {
+		r.Log.V(1).Info("Unable to learn the chain head", "axelarnode", axelarNode.Name, "error", err.Error())
+		return
+	}
+	if height == 0 {
+		return
+	}
+	if height > syncInfo.LatestHeight {
+		syncInfo.LatestHeight = height
+		syncInfo.HeadTime = &metav1.Time{Time: blockTime}
+	} else {
+		syncInfo.HeadTime = syncInfo.LastSyncTime
+	}
+	syncInfo.SyncLag = syncInfo.LatestHeight - syncInfo.CurrentHeight
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/networks"
+)
+
+// referenceServer serves the /status of a reference RPC at height, or fails
+// when height is negative
+func referenceServer(t *testing.T, height int64, blockTime time.Time) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if height < 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{"latest_block_height":"%d","latest_block_time":%q,"catching_up":false}}}`,
+			height, blockTime.Format(time.RFC3339Nano))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestReportSyncLag(t *testing.T) {
+	nodeTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	headTime := nodeTime.Add(time.Minute)
+
+	tests := []struct {
+		name       string
+		references []int64
+		wantErr    bool
+		wantLatest int64
+		wantLag    int64
+		wantHead   *time.Time
+	}{
+		{name: "no references", wantLatest: 1000},
+		{name: "reference above", references: []int64{1100}, wantLatest: 1100, wantLag: 100, wantHead: &headTime},
+		{name: "reference below", references: []int64{900}, wantLatest: 1000, wantHead: &nodeTime},
+		{name: "reference failing", references: []int64{-1}, wantErr: true, wantLatest: 1000},
+		{name: "highest reference answering", references: []int64{-1, 1020, 1050}, wantLatest: 1050, wantLag: 50, wantHead: &headTime},
+	}
+	defer networks.Register()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := networks.Profile{Name: "synclag-test", ChainID: "synclag-test-1"}
+			for _, height := range tt.references {
+				profile.ReferenceRPCs = append(profile.ReferenceRPCs, referenceServer(t, height, headTime))
+			}
+			networks.Register(profile)
+
+			axelarNode := &blockchainv1alpha1.AxelarNode{}
+			axelarNode.Name = "node"
+			axelarNode.Spec.Network = profile.Name
+			axelarNode.Status.SyncInfo = blockchainv1alpha1.SyncInfo{
+				CurrentHeight: 1000,
+				LatestHeight:  1000,
+				LastSyncTime:  &metav1.Time{Time: nodeTime},
+			}
+
+			_, _, err := chainHead(context.Background(), axelarNode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("chainHead() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			r := &AxelarNodeReconciler{Log: logr.Discard()}
+			r.reportSyncLag(context.Background(), axelarNode)
+			syncInfo := axelarNode.Status.SyncInfo
+			if syncInfo.LatestHeight != tt.wantLatest || syncInfo.SyncLag != tt.wantLag {
+				t.Errorf("latestHeight = %d, syncLag = %d, want %d and %d", syncInfo.LatestHeight, syncInfo.SyncLag, tt.wantLatest, tt.wantLag)
+			}
+			switch {
+			case tt.wantHead == nil && syncInfo.HeadTime != nil:
+				t.Errorf("headTime = %s, want unset", syncInfo.HeadTime)
+			case tt.wantHead != nil && (syncInfo.HeadTime == nil || !syncInfo.HeadTime.Time.Equal(*tt.wantHead)):
+				t.Errorf("headTime = %v, want %s", syncInfo.HeadTime, tt.wantHead)
+			}
+		})
+	}
+}
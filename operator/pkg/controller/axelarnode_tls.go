@@ -0,0 +1,368 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// DefaultTLSProxyImage is the image of the TLS terminating proxy sidecar
+const DefaultTLSProxyImage = "nginx:1.25-alpine"
+
+// certificateGVK is the cert-manager Certificate kind. It is handled as an
+// unstructured object, so the operator runs in clusters without cert-manager.
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// Ports the proxy serves TLS on when the spec leaves them unset
+const (
+	defaultTLSRPCPort  = 26443
+	defaultTLSAPIPort  = 1443
+	defaultTLSGRPCPort = 9443
+)
+
+// grpcPort is the port of the Cosmos SDK gRPC server
+const grpcPort = 9090
+
+// tlsProxyScript runs nginx and reloads it every 6 hours, so renewed
+// certificates are picked up without restarting the pod. The container exits
+// once nginx does.
+const tlsProxyScript = `nginx -c /etc/tls-proxy/nginx.conf -g 'daemon off;' &
+pid=$!
+i=0
+while kill -0 $pid 2>/dev/null; do
+  sleep 60
+  i=$((i + 1))
+  if [ $((i %% 360)) -eq 0 ]; then nginx -c /etc/tls-proxy/nginx.conf -s reload; fi
+done
+exit 1
+`
+
+// tlsProxyConfig is the nginx.conf of the proxy. The RPC server also accepts
+// websocket subscriptions, so upgrades are passed through.
+const tlsProxyConfig = `worker_processes 1;
+error_log /dev/stderr warn;
+pid /tmp/nginx.pid;
+
+events {
+  worker_connections 1024;
+}
+
+http {
+  access_log off;
+  client_body_temp_path /tmp/client_body;
+  proxy_temp_path /tmp/proxy;
+  fastcgi_temp_path /tmp/fastcgi;
+  uwsgi_temp_path /tmp/uwsgi;
+  scgi_temp_path /tmp/scgi;
+
+  ssl_certificate /etc/tls/tls.crt;
+  ssl_certificate_key /etc/tls/tls.key;
+  ssl_protocols TLSv1.2 TLSv1.3;
+
+  map $http_upgrade $connection_upgrade {
+    default upgrade;
+    '' close;
+  }
+
+  server {
+    %s
+    location / {
+      proxy_pass http://127.0.0.1:%d;
+      proxy_http_version 1.1;
+      proxy_set_header Upgrade $http_upgrade;
+      proxy_set_header Connection $connection_upgrade;
+      proxy_set_header Host $host;
+      proxy_read_timeout 1h;
+    }
+  }
+
+  server {
+    %s
+    location / {
+      proxy_pass http://127.0.0.1:%d;
+      proxy_set_header Host $host;
+    }
+  }
+
+  server {
+    %s
+    location / {
+      grpc_pass grpc://127.0.0.1:%d;
+    }
+  }
+}
+`
+
+// tlsPorts returns the ports the proxy serves the RPC, API and gRPC on
+func tlsPorts(spec *blockchainv1alpha1.TLSSpec) (rpc, api, grpc int32) {
+	rpc, api, grpc = defaultTLSRPCPort, defaultTLSAPIPort, defaultTLSGRPCPort
+	if spec.RPCPort > 0 {
+		rpc = spec.RPCPort
+	}
+	if spec.APIPort > 0 {
+		api = spec.APIPort
+	}
+	if spec.GRPCPort > 0 {
+		grpc = spec.GRPCPort
+	}
+	return rpc, api, grpc
+}
+
+// tlsSecretName names the Secret cert-manager stores the certificate in
+func tlsSecretName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "tls")
+}
+
+// tlsProxyConfigMapName names the ConfigMap holding the proxy configuration
+func tlsProxyConfigMapName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "tls-proxy")
+}
+
+// tlsDNSNames returns the names the certificate is issued for: the in-cluster
+// names of the node Service, then those of the spec
+func tlsDNSNames(axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	service := serviceName(axelarNode)
+	names := []string{
+		service,
+		fmt.Sprintf("%s.%s", service, axelarNode.Namespace),
+		fmt.Sprintf("%s.%s.svc", service, axelarNode.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, axelarNode.Namespace),
+	}
+	return append(names, axelarNode.Spec.Networking.TLS.DNSNames...)
+}
+
+// tlsServicePorts returns the TLS ports of the node Service
+func tlsServicePorts(axelarNode *blockchainv1alpha1.AxelarNode) []corev1.ServicePort {
+	spec := axelarNode.Spec.Networking.TLS
+	if spec == nil {
+		return nil
+	}
+	rpc, api, grpc := tlsPorts(spec)
+	return []corev1.ServicePort{
+		{Name: "rpc-tls", Port: rpc, TargetPort: intstr.FromInt(int(rpc))},
+		{Name: "api-tls", Port: api, TargetPort: intstr.FromInt(int(api))},
+		{Name: "grpc-tls", Port: grpc, TargetPort: intstr.FromInt(int(grpc))},
+	}
+}
+
+// addTLSProxy fronts the RPC, API and gRPC with the TLS terminating proxy.
+// The certificate volume is optional, so the node starts before the
+// certificate is issued; the proxy restarts until it is.
+func (r *AxelarNodeReconciler) addTLSProxy(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	spec := axelarNode.Spec.Networking.TLS
+	if spec == nil {
+		return
+	}
+	image := r.TLSProxyImage
+	if image == "" {
+		image = airgap.Image(DefaultTLSProxyImage)
+	}
+	rpc, api, grpc := tlsPorts(spec)
+	optional := true
+
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:    "tls-proxy",
+		Image:   image,
+		Command: []string{"sh", "-c", tlsProxyScript},
+		Ports: []corev1.ContainerPort{
+			{Name: "rpc-tls", ContainerPort: rpc, Protocol: corev1.ProtocolTCP},
+			{Name: "api-tls", ContainerPort: api, Protocol: corev1.ProtocolTCP},
+			{Name: "grpc-tls", ContainerPort: grpc, Protocol: corev1.ProtocolTCP},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "tls", MountPath: "/etc/tls", ReadOnly: true},
+			{Name: "tls-proxy", MountPath: "/etc/tls-proxy", ReadOnly: true},
+		},
+	})
+	podSpec.Volumes = append(podSpec.Volumes,
+		corev1.Volume{
+			Name: "tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: tlsSecretName(axelarNode), Optional: &optional},
+			},
+		},
+		corev1.Volume{
+			Name: "tls-proxy",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: tlsProxyConfigMapName(axelarNode)},
+				},
+			},
+		},
+	)
+}
+
+// reconcileTLS requests the certificate of the node and renders the proxy
+// configuration. Both are removed once TLS is disabled.
+func (r *AxelarNodeReconciler) reconcileTLS(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	spec := axelarNode.Spec.Networking.TLS
+	if spec == nil {
+		if meta.FindStatusCondition(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionTLSReady) == nil {
+			return nil
+		}
+		if err := r.deleteTLSResources(ctx, axelarNode); err != nil {
+			return err
+		}
+		meta.RemoveStatusCondition(&axelarNode.Status.Conditions, blockchainv1alpha1.ConditionTLSReady)
+		return nil
+	}
+
+	if err := r.reconcileTLSProxyConfig(ctx, axelarNode); err != nil {
+		return err
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetName(axelarNode.Name)
+	certificate.SetNamespace(axelarNode.Namespace)
+	certificate.SetLabels(nodeLabels(axelarNode, componentNode))
+	issuer := map[string]interface{}{"name": spec.IssuerRef.Name, "kind": "Issuer", "group": "cert-manager.io"}
+	if spec.IssuerRef.Kind != "" {
+		issuer["kind"] = spec.IssuerRef.Kind
+	}
+	if spec.IssuerRef.Group != "" {
+		issuer["group"] = spec.IssuerRef.Group
+	}
+	dnsNames := []interface{}{}
+	for _, name := range tlsDNSNames(axelarNode) {
+		dnsNames = append(dnsNames, name)
+	}
+	certificate.Object["spec"] = map[string]interface{}{
+		"secretName": tlsSecretName(axelarNode),
+		"dnsNames":   dnsNames,
+		"issuerRef":  issuer,
+	}
+	if err := controllerutil.SetControllerReference(axelarNode, certificate, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(certificateGVK)
+	err := r.Get(ctx, types.NamespacedName{Name: certificate.GetName(), Namespace: certificate.GetNamespace()}, found)
+	switch {
+	case meta.IsNoMatchError(err):
+		r.setTLSCondition(axelarNode, metav1.ConditionFalse, "CertManagerMissing", "cert-manager is not installed in the cluster")
+		return nil
+	case errors.IsNotFound(err):
+		if err := r.Create(ctx, certificate); err != nil {
+			return err
+		}
+		r.setTLSCondition(axelarNode, metav1.ConditionFalse, "Issuing", "Waiting for the certificate to be issued")
+		return nil
+	case err != nil:
+		return err
+	}
+	found.Object["spec"] = certificate.Object["spec"]
+	if err := r.Update(ctx, found); err != nil {
+		return err
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(found.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		if condition["status"] == string(metav1.ConditionTrue) {
+			r.setTLSCondition(axelarNode, metav1.ConditionTrue, "Issued", message)
+			return nil
+		}
+		reason, _ := condition["reason"].(string)
+		if reason == "" {
+			reason = "Issuing"
+		}
+		r.setTLSCondition(axelarNode, metav1.ConditionFalse, reason, message)
+		return nil
+	}
+	r.setTLSCondition(axelarNode, metav1.ConditionFalse, "Issuing", "Waiting for the certificate to be issued")
+	return nil
+}
+
+// reconcileTLSProxyConfig renders the nginx configuration of the proxy.
+// Clients of the TLS RPC go through the gateway when the node has one.
+func (r *AxelarNodeReconciler) reconcileTLSProxyConfig(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	configMap := createTLSProxyConfigMap(axelarNode)
+	if err := controllerutil.SetControllerReference(axelarNode, configMap, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, configMap)
+	} else if err != nil {
+		return err
+	}
+	found.Data = configMap.Data
+	return r.Update(ctx, found)
+}
+
+// createTLSProxyConfigMap creates the ConfigMap object of the proxy configuration
+func createTLSProxyConfigMap(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.ConfigMap {
+	rpc, api, grpc := tlsPorts(axelarNode.Spec.Networking.TLS)
+	rpcUpstream := rpcUpstreamPort(axelarNode)
+	if port := gatewayPort(axelarNode); port != 0 {
+		rpcUpstream = port
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tlsProxyConfigMapName(axelarNode),
+			Namespace: axelarNode.Namespace,
+		},
+		Data: map[string]string{
+			"nginx.conf": fmt.Sprintf(tlsProxyConfig,
+				nginxListen(axelarNode.Spec.Networking, rpc, "ssl"), rpcUpstream,
+				nginxListen(axelarNode.Spec.Networking, api, "ssl"), axelarNode.Spec.Networking.API.Port,
+				nginxListen(axelarNode.Spec.Networking, grpc, "ssl http2"), grpcPort),
+		},
+	}
+	return configMap
+}
+
+// deleteTLSResources removes the certificate and the proxy configuration. The
+// certificate Secret is left to cert-manager.
+func (r *AxelarNodeReconciler) deleteTLSResources(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	key := types.NamespacedName{Name: axelarNode.Name, Namespace: axelarNode.Namespace}
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	err := r.Get(ctx, key, certificate)
+	if err == nil {
+		err = r.Delete(ctx, certificate)
+	}
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = r.Get(ctx, types.NamespacedName{Name: tlsProxyConfigMapName(axelarNode), Namespace: axelarNode.Namespace}, configMap)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return r.Delete(ctx, configMap)
+}
+
+// setTLSCondition records whether the certificate of the node is issued
+func (r *AxelarNodeReconciler) setTLSCondition(axelarNode *blockchainv1alpha1.AxelarNode, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionTLSReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: axelarNode.Generation,
+	})
+}
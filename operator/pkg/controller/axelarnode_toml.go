@@ -0,0 +1,290 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// defaultTelemetryRetentionSeconds keeps metrics for a minute of scrapes
+const defaultTelemetryRetentionSeconds = 60
+
+// appTOML is the app.toml of the node
+type appTOML struct {
+	MinimumGasPrices string        `toml:"minimum-gas-prices"`
+	Pruning          string        `toml:"pruning"`
+	HaltHeight       int64         `toml:"halt-height"`
+	HaltTime         int64         `toml:"halt-time"`
+	Telemetry        telemetryTOML `toml:"telemetry"`
+	API              apiTOML       `toml:"api"`
+	GRPC             grpcTOML      `toml:"grpc"`
+	Wasm             wasmTOML      `toml:"wasm"`
+}
+
+// telemetryTOML is the [telemetry] section of app.toml
+type telemetryTOML struct {
+	ServiceName             string     `toml:"service-name"`
+	Enabled                 bool       `toml:"enabled"`
+	EnableHostname          bool       `toml:"enable-hostname"`
+	EnableHostnameLabel     bool       `toml:"enable-hostname-label"`
+	EnableServiceLabel      bool       `toml:"enable-service-label"`
+	PrometheusRetentionTime int64      `toml:"prometheus-retention-time"`
+	GlobalLabels            [][]string `toml:"global-labels"`
+}
+
+// apiTOML is the [api] section of app.toml
+type apiTOML struct {
+	Enable             bool   `toml:"enable"`
+	Swagger            bool   `toml:"swagger"`
+	Address            string `toml:"address"`
+	MaxOpenConnections int32  `toml:"max-open-connections"`
+	RPCReadTimeout     int64  `toml:"rpc-read-timeout"`
+	EnabledUnsafeCORS  bool   `toml:"enabled-unsafe-cors"`
+}
+
+// grpcTOML is the [grpc] section of app.toml
+type grpcTOML struct {
+	Enable         bool   `toml:"enable"`
+	Address        string `toml:"address"`
+	MaxRecvMsgSize string `toml:"max-recv-msg-size"`
+}
+
+// wasmTOML is the [wasm] section of app.toml
+type wasmTOML struct {
+	QueryGasLimit      int64  `toml:"query_gas_limit"`
+	MemoryCacheSize    int32  `toml:"memory_cache_size"`
+	SimulationGasLimit *int64 `toml:"simulation_gas_limit,omitempty"`
+}
+
+// tendermintTOML is the config.toml of the node
+type tendermintTOML struct {
+	Moniker         string              `toml:"moniker"`
+	FastSync        bool                `toml:"fast_sync"`
+	DBBackend       string              `toml:"db_backend"`
+	LogLevel        string              `toml:"log_level"`
+	LogFormat       string              `toml:"log_format"`
+	RPC             rpcTOML             `toml:"rpc"`
+	P2P             p2pTOML             `toml:"p2p"`
+	Mempool         mempoolTOML         `toml:"mempool"`
+	Consensus       consensusTOML       `toml:"consensus"`
+	Instrumentation instrumentationTOML `toml:"instrumentation"`
+	TxIndex         *txIndexTOML        `toml:"tx_index,omitempty"`
+}
+
+// rpcTOML is the [rpc] section of config.toml
+type rpcTOML struct {
+	Laddr                    string   `toml:"laddr"`
+	CORSAllowedOrigins       []string `toml:"cors_allowed_origins"`
+	MaxOpenConnections       int32    `toml:"max_open_connections"`
+	MaxSubscriptionClients   int32    `toml:"max_subscription_clients"`
+	TimeoutBroadcastTxCommit string   `toml:"timeout_broadcast_tx_commit"`
+	Unsafe                   bool     `toml:"unsafe"`
+	PprofLaddr               string   `toml:"pprof_laddr"`
+}
+
+// p2pTOML is the [p2p] section of config.toml
+type p2pTOML struct {
+	Laddr                        string `toml:"laddr"`
+	ExternalAddress              string `toml:"external_address"`
+	PersistentPeers              string `toml:"persistent_peers"`
+	Seeds                        string `toml:"seeds"`
+	MaxNumInboundPeers           int32  `toml:"max_num_inbound_peers"`
+	MaxNumOutboundPeers          int32  `toml:"max_num_outbound_peers"`
+	PersistentPeersMaxDialPeriod string `toml:"persistent_peers_max_dial_period"`
+	UnconditionalPeerIDs         string `toml:"unconditional_peer_ids"`
+}
+
+// mempoolTOML is the [mempool] section of config.toml
+type mempoolTOML struct {
+	Size        int32 `toml:"size"`
+	CacheSize   int32 `toml:"cache_size"`
+	MaxTxsBytes int64 `toml:"max_txs_bytes"`
+	MaxTxBytes  int32 `toml:"max_tx_bytes"`
+	Broadcast   bool  `toml:"broadcast"`
+}
+
+// consensusTOML is the [consensus] section of config.toml
+type consensusTOML struct {
+	TimeoutCommit     string `toml:"timeout_commit"`
+	SkipTimeoutCommit bool   `toml:"skip_timeout_commit"`
+}
+
+// txIndexTOML is the [tx_index] section of config.toml
+type txIndexTOML struct {
+	Indexer  string `toml:"indexer"`
+	PSQLConn string `toml:"psql-conn"`
+}
+
+// instrumentationTOML is the [instrumentation] section of config.toml
+type instrumentationTOML struct {
+	Prometheus           bool   `toml:"prometheus"`
+	PrometheusListenAddr string `toml:"prometheus_listen_addr"`
+}
+
+// nodeAppConfig returns the app.toml of the node
+func nodeAppConfig(axelarNode *blockchainv1alpha1.AxelarNode) appTOML {
+	pruning := axelarNode.Spec.Pruning
+	if pruning == "" {
+		pruning = "default"
+	}
+	networking := axelarNode.Spec.Networking
+	api := networking.API
+
+	return appTOML{
+		MinimumGasPrices: nodeMinimumGasPrices(axelarNode),
+		Pruning:          pruning,
+		HaltHeight:       axelarNode.Spec.HaltHeight,
+		HaltTime:         haltTime(axelarNode),
+		Telemetry:        telemetryConfig(axelarNode),
+		API: apiTOML{
+			Enable:             api.Enabled,
+			Swagger:            api.Swagger,
+			Address:            "tcp://" + listenAddress(networking, api.Port),
+			MaxOpenConnections: api.MaxOpenConnections,
+			RPCReadTimeout:     apiReadTimeoutSeconds(axelarNode),
+			EnabledUnsafeCORS:  api.EnableUnsafeCORS,
+		},
+		GRPC: grpcTOML{
+			Enable:         true,
+			Address:        listenAddress(networking, grpcPort),
+			MaxRecvMsgSize: fmt.Sprintf("%d", grpcMaxRecvMsgSize(axelarNode)),
+		},
+		Wasm: wasmConfig(axelarNode),
+	}
+}
+
+// telemetryConfig returns the [telemetry] section of app.toml
+func telemetryConfig(axelarNode *blockchainv1alpha1.AxelarNode) telemetryTOML {
+	telemetry := axelarNode.Spec.Monitoring.Telemetry
+	retention := int64(telemetry.RetentionTime.Seconds())
+	if retention <= 0 {
+		retention = defaultTelemetryRetentionSeconds
+	}
+	return telemetryTOML{
+		ServiceName:             telemetry.ServiceName,
+		Enabled:                 axelarNode.Spec.Monitoring.Enabled,
+		EnableHostname:          telemetry.EnableHostname,
+		EnableHostnameLabel:     telemetry.EnableHostnameLabel,
+		EnableServiceLabel:      telemetry.EnableServiceLabel,
+		PrometheusRetentionTime: retention,
+		GlobalLabels:            telemetryLabels(axelarNode),
+	}
+}
+
+// telemetryLabels returns the global-labels of app.toml, sorted by name, so
+// the metrics of every node can be told apart in Prometheus
+func telemetryLabels(axelarNode *blockchainv1alpha1.AxelarNode) [][]string {
+	labels := map[string]string{
+		"chain_id":  nodeChainID(axelarNode),
+		"network":   axelarNode.Spec.Network,
+		"namespace": axelarNode.Namespace,
+		"node":      axelarNode.Name,
+	}
+	for name, value := range axelarNode.Spec.Monitoring.Telemetry.GlobalLabels {
+		labels[name] = value
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([][]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, []string{name, labels[name]})
+	}
+	return pairs
+}
+
+// nodeTendermintConfig returns the config.toml of the node
+func nodeTendermintConfig(axelarNode *blockchainv1alpha1.AxelarNode) tendermintTOML {
+	networking := axelarNode.Spec.Networking
+	rpc, p2p := networking.RPC, networking.P2P
+	mempool, consensus := tendermintSettings(axelarNode)
+
+	return tendermintTOML{
+		Moniker:   axelarNode.Spec.Moniker,
+		FastSync:  true,
+		DBBackend: "goleveldb",
+		LogLevel:  "info",
+		LogFormat: "json",
+		RPC: rpcTOML{
+			Laddr:                    "tcp://" + listenAddress(networking, rpc.Port),
+			CORSAllowedOrigins:       append([]string{}, corsAllowedOrigins(axelarNode)...),
+			MaxOpenConnections:       rpc.MaxOpenConnections,
+			MaxSubscriptionClients:   rpc.MaxSubscriptionClients,
+			TimeoutBroadcastTxCommit: broadcastTxCommitTimeout(axelarNode),
+			Unsafe:                   rpc.Unsafe && !productionNetwork(axelarNode),
+			PprofLaddr:               pprofAddress(axelarNode),
+		},
+		P2P: p2pTOML{
+			Laddr:                        "tcp://" + listenAddress(networking, p2p.Port),
+			ExternalAddress:              p2pExternalAddress(axelarNode),
+			PersistentPeers:              joinStrings(nodePersistentPeers(axelarNode)),
+			Seeds:                        joinStrings(nodeSeeds(axelarNode)),
+			MaxNumInboundPeers:           peerLimit(p2p.MaxNumInboundPeers, defaultMaxInboundPeers),
+			MaxNumOutboundPeers:          peerLimit(p2p.MaxNumOutboundPeers, defaultMaxOutboundPeers),
+			PersistentPeersMaxDialPeriod: p2p.PersistentPeersMaxDialPeriod.Duration.String(),
+			UnconditionalPeerIDs:         joinStrings(unblockedPeers(axelarNode, p2p.UnconditionalPeerIDs)),
+		},
+		Mempool: mempoolTOML{
+			Size:        mempool.Size,
+			CacheSize:   mempool.CacheSize,
+			MaxTxsBytes: mempool.MaxTxsBytes,
+			MaxTxBytes:  mempool.MaxTxBytes,
+			Broadcast:   mempool.Broadcast == nil || *mempool.Broadcast,
+		},
+		Consensus: consensusTOML{
+			TimeoutCommit:     consensus.TimeoutCommit.Duration.String(),
+			SkipTimeoutCommit: consensus.SkipTimeoutCommit,
+		},
+		Instrumentation: instrumentationTOML{
+			Prometheus:           axelarNode.Spec.Monitoring.Enabled,
+			PrometheusListenAddr: fmt.Sprintf(":%d", axelarNode.Spec.Monitoring.Prometheus.Port),
+		},
+		TxIndex: txIndexConfig(axelarNode),
+	}
+}
+
+// encodeTOML renders a configuration file with a header comment, and checks
+// the result parses before it is written anywhere
+func encodeTOML(header string, config interface{}) (string, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(header)
+	encoder := toml.NewEncoder(buf)
+	encoder.Indent = ""
+	if err := encoder.Encode(config); err != nil {
+		return "", err
+	}
+
+	parsed := map[string]interface{}{}
+	if _, err := toml.Decode(buf.String(), &parsed); err != nil {
+		return "", fmt.Errorf("rendered configuration does not parse: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// renderConfig renders the configuration files of the node
+func renderConfig(axelarNode *blockchainv1alpha1.AxelarNode) (map[string]string, error) {
+	app, err := encodeTOML("# Axelar Node Configuration\n", nodeAppConfig(axelarNode))
+	if err != nil {
+		return nil, fmt.Errorf("rendering app.toml: %w", err)
+	}
+	tendermint, err := encodeTOML("# Tendermint Configuration\n", nodeTendermintConfig(axelarNode))
+	if err != nil {
+		return nil, fmt.Errorf("rendering config.toml: %w", err)
+	}
+	data := map[string]string{
+		"app.toml":    app,
+		"config.toml": tendermint,
+		"chain-id":    nodeChainID(axelarNode),
+		"network":     axelarNode.Spec.Network,
+	}
+	if url := nodeGenesisURL(axelarNode); url != "" {
+		data["genesis-url"] = url
+	}
+	return data, nil
+}
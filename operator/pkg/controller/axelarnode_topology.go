@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+)
+
+// defaultTofndImage applies when the spec leaves the tofnd image unset
+const defaultTofndImage = "axelarnet/tofnd:v0.10.1"
+
+// tofndImage returns the image tofnd runs
+func tofndImage(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if validator := axelarNode.Spec.Validator; validator != nil && validator.TofndImage != "" {
+		return airgap.Image(validator.TofndImage)
+	}
+	return airgap.Image(defaultTofndImage)
+}
+
+// splitSigner reports whether vald and tofnd run in Deployments of their own
+// rather than in the pod of the node
+func splitSigner(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return validatorSigning(axelarNode) && axelarNode.Spec.Validator.Topology == blockchainv1alpha1.ValidatorTopologySplit
+}
+
+// tofndClusterName returns the TofndCluster vald signs with, empty when the
+// node runs its own tofnd
+func tofndClusterName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if validator := axelarNode.Spec.Validator; validator != nil && validator.TofndClusterRef != nil {
+		return validator.TofndClusterRef.Name
+	}
+	return ""
+}
+
+// ownTofnd reports whether the node runs a tofnd of its own
+func ownTofnd(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return validatorSigning(axelarNode) && tofndClusterName(axelarNode) == ""
+}
+
+// valdName names the Deployment of vald in the split topology
+func valdName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "vald")
+}
+
+// tofndName names the Deployment and Service of tofnd in the split topology
+func tofndName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "tofnd")
+}
+
+// signerHosts returns the hosts vald reaches the node RPC and tofnd on: the
+// pod itself for sidecars, the Services of the node and of tofnd otherwise.
+// A TofndCluster is always reached through its Service.
+func signerHosts(axelarNode *blockchainv1alpha1.AxelarNode) (rpcHost, tofndHost string) {
+	rpcHost, tofndHost = "127.0.0.1", "127.0.0.1"
+	if splitSigner(axelarNode) {
+		rpcHost = fmt.Sprintf("%s.%s.svc", serviceName(axelarNode), axelarNode.Namespace)
+		tofndHost = fmt.Sprintf("%s.%s.svc", tofndName(axelarNode), axelarNode.Namespace)
+	}
+	if cluster := tofndClusterName(axelarNode); cluster != "" {
+		tofndHost = tofndClusterHost(cluster, axelarNode.Namespace)
+	}
+	return rpcHost, tofndHost
+}
+
+// sidecarContainers returns the validator containers running in the pod of
+// the node
+func (r *AxelarNodeReconciler) sidecarContainers(axelarNode *blockchainv1alpha1.AxelarNode) []corev1.Container {
+	if !validatorSigning(axelarNode) || splitSigner(axelarNode) {
+		return nil
+	}
+	containers := r.createValidatorContainers(axelarNode)
+	if !ownTofnd(axelarNode) {
+		return containers[:1]
+	}
+	return containers
+}
+
+// reconcileSplitSigner runs tofnd and vald in Deployments of their own when
+// the node asks for the split topology, and removes them otherwise. tofnd is
+// left out when the node signs with a TofndCluster. The Deployments are
+// scheduled next to the node, whose data and shared volumes they mount.
+func (r *AxelarNodeReconciler) reconcileSplitSigner(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if !splitSigner(axelarNode) {
+		if err := r.deleteDeployment(ctx, axelarNode, valdName(axelarNode)); err != nil {
+			return err
+		}
+	}
+	if !splitSigner(axelarNode) || !ownTofnd(axelarNode) {
+		if err := r.deleteDeployment(ctx, axelarNode, tofndName(axelarNode)); err != nil {
+			return err
+		}
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: tofndName(axelarNode), Namespace: axelarNode.Namespace}}
+		if err := r.Delete(ctx, service); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	// The signer follows the node, whose changes are only previewed in dry-run
+	if !splitSigner(axelarNode) || nodeDryRun(axelarNode) {
+		return nil
+	}
+
+	deployments := []*appsv1.Deployment{r.createValdDeployment(axelarNode)}
+	if ownTofnd(axelarNode) {
+		if err := r.reconcileTofndService(ctx, axelarNode); err != nil {
+			return err
+		}
+		deployments = append([]*appsv1.Deployment{r.createTofndDeployment(axelarNode)}, deployments...)
+	}
+	for _, deployment := range deployments {
+		if err := controllerutil.SetControllerReference(axelarNode, deployment, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.applyDeployment(ctx, axelarNode, deployment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteDeployment deletes a Deployment of the node, if present
+func (r *AxelarNodeReconciler) deleteDeployment(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, name string) error {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: axelarNode.Namespace}}
+	return client.IgnoreNotFound(r.Delete(ctx, deployment))
+}
+
+// reconcileTofndService creates or updates the Service of the split tofnd
+func (r *AxelarNodeReconciler) reconcileTofndService(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	service := createTofndService(axelarNode)
+	if err := controllerutil.SetControllerReference(axelarNode, service, r.Scheme); err != nil {
+		return err
+	}
+	found := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
+	if errors.IsNotFound(err) {
+		if err := r.Create(ctx, service); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else {
+		found.Spec.Ports = service.Spec.Ports
+		applyIPFamilies(axelarNode.Spec.Networking, &found.Spec)
+		return r.Update(ctx, found)
+	}
+	return nil
+}
+
+// createTofndService creates the Service vald reaches tofnd on
+func createTofndService(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tofndName(axelarNode),
+			Namespace: axelarNode.Namespace,
+			Labels:    signerLabels(axelarNode, componentTofnd, tofndName(axelarNode)),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: podSelector(axelarNode, componentTofnd),
+			Ports: []corev1.ServicePort{
+				{Name: "tofnd", Port: tofndPort, TargetPort: intstr.FromInt(tofndPort)},
+			},
+		},
+	}
+	applyIPFamilies(axelarNode.Spec.Networking, &service.Spec)
+	return service
+}
+
+// createValdDeployment creates the Deployment running vald against the node
+// and tofnd Services
+func (r *AxelarNodeReconciler) createValdDeployment(axelarNode *blockchainv1alpha1.AxelarNode) *appsv1.Deployment {
+	containers := r.createValidatorContainers(axelarNode)
+	podSpec := r.signerPodSpec(axelarNode, containers[0])
+	podSpec.Volumes = append([]corev1.Volume{{
+		Name: "data",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: dataClaimName(axelarNode, activeSlot(axelarNode)),
+			},
+		},
+	}}, podSpec.Volumes...)
+	addValdRecovery(axelarNode, &podSpec)
+	addKeyringBackend(axelarNode, &podSpec)
+	addPasswordFiles(axelarNode, &podSpec)
+	return r.signerDeployment(axelarNode, valdName(axelarNode), componentVald, podSpec)
+}
+
+// createTofndDeployment creates the Deployment running tofnd, listening on
+// all addresses so vald reaches it through its Service
+func (r *AxelarNodeReconciler) createTofndDeployment(axelarNode *blockchainv1alpha1.AxelarNode) *appsv1.Deployment {
+	tofnd := r.createValidatorContainers(axelarNode)[1]
+	tofnd.Args = append(tofnd.Args, "-a", "0.0.0.0")
+	podSpec := r.signerPodSpec(axelarNode, tofnd)
+	addPasswordFiles(axelarNode, &podSpec)
+	return r.signerDeployment(axelarNode, tofndName(axelarNode), componentTofnd, podSpec)
+}
+
+// signerPodSpec runs container next to the pod of the node, mounting the
+// shared volume of the node
+func (r *AxelarNodeReconciler) signerPodSpec(axelarNode *blockchainv1alpha1.AxelarNode, container corev1.Container) corev1.PodSpec {
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{container},
+		Volumes: []corev1.Volume{{
+			Name: "shared",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: childName(axelarNode, "shared"),
+				},
+			},
+		}},
+		SecurityContext: axelarNode.Spec.Security.PodSecurityContext,
+		// The volumes of the node can only be mounted on its Kubernetes node
+		Affinity: &corev1.Affinity{
+			PodAffinity: &corev1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: nodeSelector(axelarNode)},
+					TopologyKey:   corev1.LabelHostname,
+				}},
+			},
+		},
+	}
+	// Validation refuses the split topology without a shared PVC, the volume
+	// still follows the spec so no pod waits for a claim that is not created
+	configureSharedVolume(axelarNode, &podSpec)
+	return podSpec
+}
+
+// signerDeployment creates the Deployment of a signer component. A single pod
+// runs at a time, so two signers never sign for the validator together.
+func (r *AxelarNodeReconciler) signerDeployment(axelarNode *blockchainv1alpha1.AxelarNode, name, component string, podSpec corev1.PodSpec) *appsv1.Deployment {
+	replicas := int32(1)
+	labels := signerLabels(axelarNode, component, name)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: axelarNode.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Selector: &metav1.LabelSelector{MatchLabels: podSelector(axelarNode, component)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						configHashAnnotation: r.nodeConfigVersion(axelarNode),
+					},
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+	setTemplateHash(deployment)
+	return deployment
+}
+
+// signerLabels labels the objects of a signer component. Their app label
+// differs from the node, so the pods of the node listed by their app label
+// never include the signer.
+func signerLabels(axelarNode *blockchainv1alpha1.AxelarNode, component, name string) map[string]string {
+	labels := nodeLabels(axelarNode, component)
+	labels["app"] = naming.LabelValue(name)
+	return labels
+}
+
+// signerPodLabels selects the pods running vald and tofnd: the pods of the
+// node for sidecars, every pod of the node otherwise
+func signerPodLabels(axelarNode *blockchainv1alpha1.AxelarNode) map[string]string {
+	if !splitSigner(axelarNode) {
+		return nodeSelector(axelarNode)
+	}
+	return map[string]string{
+		blockchainv1alpha1.NameLabel:     nodeAppName,
+		blockchainv1alpha1.InstanceLabel: naming.LabelValue(axelarNode.Name),
+	}
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// defaultGasPrices applies when the spec leaves the gas prices unset
+const defaultGasPrices = "0.007uaxl"
+
+// txScript imports the operator key into a throwaway keyring and defines tx,
+// which signs and broadcasts a transaction, waits until it is in a block and
+// appends its hash to the termination message. The scripts of transaction
+// Jobs follow it.
+const txScript = `set -e
+export HOME=/tmp
+axelard keys add operator --recover --keyring-backend test --home /tmp/keys < /keys/mnemonic > /dev/null
+tx() {
+  out=$(axelard tx "$@" --from operator --keyring-backend test --home /tmp/keys \
+    --chain-id "$CHAIN_ID" --node "$NODE" --gas auto --gas-adjustment 1.5 --gas-prices "$GAS_PRICES" \
+    --broadcast-mode sync --yes --output json)
+  echo "$out"
+  hash=$(echo "$out" | grep -o '"txhash":"[0-9A-F]*"' | cut -d'"' -f4)
+  if ! echo "$out" | grep -q '"code":0'; then
+    echo "transaction $hash was rejected" >> /dev/termination-log
+    exit 1
+  fi
+  for i in $(seq 30); do
+    if result=$(wget -qO- "$API/cosmos/tx/v1beta1/txs/$hash" 2>/dev/null); then
+      if ! echo "$result" | grep -q '"code":0'; then
+        echo "transaction $hash failed" >> /dev/termination-log
+        exit 1
+      fi
+      echo "$hash" >> /dev/termination-log
+      return
+    fi
+    sleep 2
+  done
+  echo "transaction $hash was not included in a block" >> /dev/termination-log
+  exit 1
+}
+`
+
+// nodeRPCURL returns the in-cluster URL of the node RPC
+func nodeRPCURL(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", serviceName(axelarNode), axelarNode.Namespace, axelarNode.Spec.Networking.RPC.Port)
+}
+
+// nodeAPIURL returns the in-cluster URL of the node REST API
+func nodeAPIURL(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", serviceName(axelarNode), axelarNode.Namespace, axelarNode.Spec.Networking.API.Port)
+}
+
+// txKeySecret returns the Secret key holding the mnemonic of the validator
+// operator account
+func txKeySecret(axelarNode *blockchainv1alpha1.AxelarNode) corev1.SecretKeySelector {
+	if ref := axelarNode.Spec.Validator.Tx.KeySecretRef; ref != nil {
+		return *ref
+	}
+	return corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: passwordsSecretName(axelarNode)},
+		Key:                  "validator-mnemonic",
+	}
+}
+
+// txPodSpec returns the pod running script after txScript, signing with key.
+// The Job does not mount the data volume, so it runs next to the node.
+func txPodSpec(axelarNode *blockchainv1alpha1.AxelarNode, name, script string, key corev1.SecretKeySelector) corev1.PodSpec {
+	gasPrices := axelarNode.Spec.Validator.Tx.GasPrices
+	if gasPrices == "" {
+		gasPrices = defaultGasPrices
+	}
+	mode := int32(0o440)
+
+	return corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{
+			{
+				Name:    name,
+				Image:   nodeImage(axelarNode),
+				Command: []string{"sh", "-c", txScript + script},
+				Env: []corev1.EnvVar{
+					{Name: "CHAIN_ID", Value: nodeChainID(axelarNode)},
+					{Name: "NODE", Value: nodeRPCURL(axelarNode)},
+					{Name: "API", Value: nodeAPIURL(axelarNode)},
+					{Name: "GAS_PRICES", Value: gasPrices},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "operator-key", MountPath: "/keys", ReadOnly: true},
+				},
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: "operator-key",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName:  key.Name,
+						Items:       []corev1.KeyToPath{{Key: key.Key, Path: "mnemonic"}},
+						DefaultMode: &mode,
+					},
+				},
+			},
+		},
+		SecurityContext: axelarNode.Spec.Security.PodSecurityContext,
+	}
+}
+
+// createTxJob creates the Job sending the transactions of script, signed with key
+func (r *AxelarNodeReconciler) createTxJob(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, name, script string, key corev1.SecretKeySelector) error {
+	backoffLimit := int32(0)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      childName(axelarNode, name),
+			Namespace: axelarNode.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: txPodSpec(axelarNode, name, script, key),
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(axelarNode, job, r.Scheme); err != nil {
+		return err
+	}
+	return r.Create(ctx, job)
+}
+
+// txJobOutput returns the termination message of the pod of a finished
+// transaction Job: the hashes of its transactions, or why it failed
+func (r *AxelarNodeReconciler) txJobOutput(ctx context.Context, job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil {
+				return strings.TrimSpace(status.State.Terminated.Message), nil
+			}
+		}
+	}
+	return "", nil
+}
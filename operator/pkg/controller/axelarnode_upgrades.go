@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// upgradeHistoryLimit is the number of upgrades kept in the status
+const upgradeHistoryLimit = 20
+
+// upgradeTimeout fails an upgrade whose node is not running the new image in time
+const upgradeTimeout = time.Hour
+
+// upgradeCrashLoopGrace is how long the upgraded node may crash loop before
+// the upgrade is failed
+const upgradeCrashLoopGrace = 10 * time.Minute
+
+// imageTag returns the tag of an image reference
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
+
+// nodeImageTag returns the image tag of the node container of a Deployment
+func nodeImageTag(deployment *appsv1.Deployment) string {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "axelar-node" {
+			return imageTag(container.Image)
+		}
+	}
+	return ""
+}
+
+// upgradeTrigger returns what moved the node to the tag: the trigger
+// annotation when it names that tag, an emergency rollout, or the spec
+func upgradeTrigger(axelarNode *blockchainv1alpha1.AxelarNode, to string) string {
+	if value, ok := axelarNode.Annotations[blockchainv1alpha1.UpgradeTriggerAnnotation]; ok {
+		if i := strings.LastIndex(value, "="); i > 0 && value[i+1:] == to {
+			return value[:i]
+		}
+	}
+	if axelarNode.Spec.Upgrade.MaintenanceWindow != nil {
+		deferred := meta.FindStatusCondition(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionRolloutDeferred)
+		if deferred != nil && deferred.Reason == "EmergencyRollout" {
+			return "EmergencyRollout"
+		}
+	}
+	return "Spec"
+}
+
+// recordUpgrade adds an upgrade to the history once a Deployment change
+// moving the node from one image tag to another has been applied. An upgrade
+// still in progress is closed as rolled back when the node returns to its
+// previous tag, or as superseded otherwise.
+func (r *AxelarNodeReconciler) recordUpgrade(axelarNode *blockchainv1alpha1.AxelarNode, from, to string) {
+	if from == to || to == "" {
+		return
+	}
+	now := metav1.Now()
+	history := axelarNode.Status.Upgrades
+	if len(history) > 0 && history[0].Outcome == blockchainv1alpha1.UpgradeInProgress {
+		last := &history[0]
+		last.CompletedAt = &now
+		last.Duration = now.Sub(last.StartedAt.Time).Round(time.Second).String()
+		if last.From == to {
+			last.Outcome = blockchainv1alpha1.UpgradeRolledBack
+			last.Message = fmt.Sprintf("Rolled back to %s", to)
+		} else {
+			last.Outcome = blockchainv1alpha1.UpgradeSuperseded
+			last.Message = fmt.Sprintf("Superseded by the upgrade to %s", to)
+		}
+	}
+
+	record := blockchainv1alpha1.UpgradeRecord{
+		From:      from,
+		To:        to,
+		Trigger:   upgradeTrigger(axelarNode, to),
+		StartedAt: now,
+		Outcome:   blockchainv1alpha1.UpgradeInProgress,
+	}
+	if axelarNode.Status.LastBackup != nil {
+		record.BackupArchive = axelarNode.Status.LastBackupArchive
+	}
+	r.Log.Info("Upgrade started", "axelarnode", axelarNode.Name, "from", from, "to", to, "trigger", record.Trigger)
+
+	history = append([]blockchainv1alpha1.UpgradeRecord{record}, history...)
+	if len(history) > upgradeHistoryLimit {
+		history = history[:upgradeHistoryLimit]
+	}
+	axelarNode.Status.Upgrades = history
+}
+
+// upgradeRolledOut reports whether every replica of the Deployment runs its
+// current template and is available
+func upgradeRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return replicas > 0 &&
+		status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.AvailableReplicas >= replicas &&
+		status.Replicas == replicas
+}
+
+// trackUpgrade completes the upgrade in progress: it succeeds once the
+// Deployment has rolled out and the node is running again, and fails when the
+// node crash loops or the rollout does not finish in time
+func (r *AxelarNodeReconciler) trackUpgrade(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, deployment *appsv1.Deployment) {
+	if len(axelarNode.Status.Upgrades) == 0 {
+		return
+	}
+	last := &axelarNode.Status.Upgrades[0]
+	if last.Outcome != blockchainv1alpha1.UpgradeInProgress {
+		return
+	}
+	now := metav1.Now()
+	elapsed := now.Sub(last.StartedAt.Time)
+
+	switch {
+	case upgradeRolledOut(deployment) && axelarNode.Status.Phase == "Running" && axelarNode.Status.SyncInfo.CurrentHeight > 0:
+		last.Outcome = blockchainv1alpha1.UpgradeSucceeded
+		last.Height = axelarNode.Status.SyncInfo.CurrentHeight
+		last.Message = fmt.Sprintf("Running %s at height %d", last.To, last.Height)
+		axelarNode.Status.LastUpgrade = &now
+	case meta.IsStatusConditionTrue(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionCrashLooping) && elapsed > upgradeCrashLoopGrace:
+		last.Outcome = blockchainv1alpha1.UpgradeFailed
+		last.Message = fmt.Sprintf("Node is crash looping on %s", last.To)
+	case elapsed > upgradeTimeout:
+		last.Outcome = blockchainv1alpha1.UpgradeFailed
+		last.Message = fmt.Sprintf("Node is not running %s after %s", last.To, upgradeTimeout)
+	default:
+		return
+	}
+	last.CompletedAt = &now
+	last.Duration = elapsed.Round(time.Second).String()
+
+	if last.Outcome == blockchainv1alpha1.UpgradeSucceeded {
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeNormal, "UpgradeSucceeded",
+				fmt.Sprintf("Upgraded from %s to %s in %s", last.From, last.To, last.Duration))
+		}
+		return
+	}
+
+	text := fmt.Sprintf(":x: The upgrade of %s from %s to %s failed: %s", axelarNode.Name, last.From, last.To, last.Message)
+	r.Log.Info("Upgrade notification", "axelarnode", axelarNode.Name, "message", text)
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "UpgradeFailed", text)
+	}
+	if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+		r.Log.Error(err, "Unable to send upgrade alert", "axelarnode", axelarNode.Name)
+	}
+}
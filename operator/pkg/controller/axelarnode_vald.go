@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// valdRecoveryMountPath is where the recovery file of vald is mounted
+const valdRecoveryMountPath = "/home/axelard/recovery"
+
+// valdSpec returns the vald configuration of the node
+func valdSpec(axelarNode *blockchainv1alpha1.AxelarNode) blockchainv1alpha1.ValdSpec {
+	if axelarNode.Spec.Validator == nil {
+		return blockchainv1alpha1.ValdSpec{}
+	}
+	return axelarNode.Spec.Validator.Vald
+}
+
+// valdArgs returns the flags vald is started with
+func valdArgs(axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	vald := valdSpec(axelarNode)
+	var args []string
+	if backend := vald.KeyringBackend; backend != "" {
+		args = append(args, "--keyring-backend", backend)
+	} else if backend := keyringBackend(axelarNode); backend != defaultKeyringBackend {
+		args = append(args, "--keyring-backend", backend)
+	}
+	if interval := vald.ChainMaintainerCheckInterval; interval != nil && interval.Duration > 0 {
+		args = append(args, "--chain-maintainer-check-interval", interval.Duration.String())
+	}
+	if timeout := vald.BroadcastTimeout; timeout != nil && timeout.Duration > 0 {
+		args = append(args, "--broadcast-timeout", timeout.Duration.String())
+	}
+	if vald.NoSigVerify {
+		args = append(args, "--no-sig-verify")
+	}
+	if ref := vald.RecoverySecretRef; ref != nil {
+		args = append(args, "--tofnd-recovery", fmt.Sprintf("%s/%s", valdRecoveryMountPath, ref.Key))
+	}
+	return append(args, vald.ExtraArgs...)
+}
+
+// addValdRecovery mounts the recovery file of the spec into the vald container
+func addValdRecovery(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	ref := valdSpec(axelarNode).RecoverySecretRef
+	if ref == nil {
+		return
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name != "vald" {
+			continue
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name: "vald-recovery", MountPath: valdRecoveryMountPath, ReadOnly: true,
+		})
+
+		mode := int32(0o440)
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "vald-recovery",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  ref.Name,
+					Items:       []corev1.KeyToPath{{Key: ref.Key, Path: ref.Key}},
+					DefaultMode: &mode,
+				},
+			},
+		})
+		return
+	}
+}
@@ -0,0 +1,287 @@
+package controller
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/maintenance"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/networks"
+)
+
+// reservedPorts are listened on by the sidecars and init containers the
+// operator adds to node pods
+var reservedPorts = map[int32]string{
+	grpcPort:             "gRPC",
+	rpcProxyPort:         "RPC proxy",
+	rpcProxyMetricsPort:  "RPC proxy metrics",
+	snapshotProgressPort: "snapshot download progress",
+	addrbookPort:         "address book",
+	tofndPort:            "tofnd",
+}
+
+// namedPort is a port set by the spec
+type namedPort struct {
+	field string
+	port  int32
+}
+
+// nodePorts returns the ports the spec has the node pod listen on
+func nodePorts(axelarNode *blockchainv1alpha1.AxelarNode) []namedPort {
+	networking := axelarNode.Spec.Networking
+	ports := []namedPort{
+		{"spec.networking.p2p.port", networking.P2P.Port},
+		{"spec.networking.rpc.port", networking.RPC.Port},
+	}
+	if axelarNode.Spec.Monitoring.Enabled {
+		ports = append(ports, namedPort{"spec.monitoring.prometheus.port", axelarNode.Spec.Monitoring.Prometheus.Port})
+	}
+	if networking.API.Enabled {
+		ports = append(ports, namedPort{"spec.networking.api.port", networking.API.Port})
+	}
+	if port := pprofPort(axelarNode); port > 0 {
+		ports = append(ports, namedPort{"spec.debug.pprof.port", port})
+	}
+	if port := gatewayPort(axelarNode); port > 0 {
+		ports = append(ports, namedPort{"spec.networking.gateway.port", port})
+	}
+	if networking.TLS != nil {
+		rpc, api, grpc := tlsPorts(networking.TLS)
+		ports = append(ports,
+			namedPort{"spec.networking.tls.rpcPort", rpc},
+			namedPort{"spec.networking.tls.apiPort", api},
+			namedPort{"spec.networking.tls.grpcPort", grpc})
+	}
+	return ports
+}
+
+// validatePorts reports ports out of range, set twice or taken by a sidecar
+func validatePorts(ports []namedPort) []string {
+	var problems []string
+	used := map[int32]string{}
+	for _, p := range ports {
+		if p.port < 1 || p.port > 65535 {
+			problems = append(problems, fmt.Sprintf("%s is %d, set a port between 1 and 65535", p.field, p.port))
+			continue
+		}
+		if other, ok := used[p.port]; ok {
+			problems = append(problems, fmt.Sprintf("%s and %s are both %d, give each a distinct port", other, p.field, p.port))
+			continue
+		}
+		if sidecar, ok := reservedPorts[p.port]; ok {
+			problems = append(problems, fmt.Sprintf("%s is %d, which the %s listens on, choose another port", p.field, p.port, sidecar))
+			continue
+		}
+		used[p.port] = p.field
+	}
+	return problems
+}
+
+// validateSize reports a storage size that does not parse or is not positive
+func validateSize(field, size string) []string {
+	if size == "" {
+		return []string{fmt.Sprintf("%s is empty, set a size such as 500Gi", field)}
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return []string{fmt.Sprintf("%s %q is not a quantity, set a size such as 500Gi", field, size)}
+	}
+	if quantity.Sign() <= 0 {
+		return []string{fmt.Sprintf("%s is %s, set a positive size", field, size)}
+	}
+	return nil
+}
+
+// validateProxyURL reports a proxy that is not an absolute URL
+func validateProxyURL(field, proxy string) []string {
+	if proxy == "" {
+		return nil
+	}
+	if u, err := url.Parse(proxy); err != nil || u.Scheme == "" || u.Host == "" {
+		return []string{fmt.Sprintf("%s %q is not a URL, set one such as http://proxy.internal:3128", field, proxy)}
+	}
+	return nil
+}
+
+// validateAmount reports a minimum balance that is not a whole amount
+func validateAmount(field, amount string) []string {
+	if amount == "" {
+		return nil
+	}
+	if value, err := strconv.ParseInt(amount, 10, 64); err != nil || value < 0 {
+		return []string{fmt.Sprintf("%s %q is not an amount, set one in the smallest unit of the denom such as \"5000000\"", field, amount)}
+	}
+	return nil
+}
+
+// validateEgress reports endpoints the egress policy cannot allow
+func validateEgress(axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	egress := axelarNode.Spec.Egress
+	if egress == nil {
+		return nil
+	}
+	var problems []string
+	for i, endpoint := range egress.Endpoints {
+		field := fmt.Sprintf("spec.egress.endpoints[%d]", i)
+		switch {
+		case endpoint.CIDR != "" && endpoint.FQDN != "":
+			problems = append(problems, fmt.Sprintf("%s sets both cidr and fqdn, split it into two endpoints", field))
+		case endpoint.CIDR != "":
+			if _, _, err := net.ParseCIDR(endpoint.CIDR); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.cidr %q is not a CIDR, set one such as 203.0.113.10/32", field, endpoint.CIDR))
+			}
+		case endpoint.FQDN != "":
+			if egressProvider(axelarNode) != egressProviderCilium {
+				problems = append(problems, fmt.Sprintf("%s.fqdn %q needs the Cilium provider, NetworkPolicies only match CIDRs, set spec.egress.provider to Cilium or use the CIDR of the endpoint", field, endpoint.FQDN))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s sets neither cidr nor fqdn, set the address of the endpoint", field))
+		}
+	}
+	return problems
+}
+
+// validateNodeSpec returns the problems of the spec that would produce broken
+// manifests, each with how to fix it
+func validateNodeSpec(axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	problems := validatePorts(nodePorts(axelarNode))
+	if _, ok := networks.Lookup(axelarNode.Spec.Network); !ok {
+		problems = append(problems, fmt.Sprintf("spec.network %q is not a known network, use one of %s or register it in the networks ConfigMap",
+			axelarNode.Spec.Network, strings.Join(networks.Names(), ", ")))
+	} else if nodeChainID(axelarNode) == "" {
+		problems = append(problems, "spec.chainId is empty and the network has none, set the chain ID")
+	}
+
+	if provider := axelarNode.Spec.Sync.SnapshotProvider; provider != nil && provider.IndexURL == "" && provider.IndexFrom == nil {
+		problems = append(problems, "spec.sync.snapshotProvider has no index, set indexURL or indexFrom")
+	}
+
+	if schedule := axelarNode.Spec.Security.SecretManagement.RotationSchedule; schedule != "" {
+		if _, err := maintenance.ParseSchedule(schedule); err != nil {
+			problems = append(problems, fmt.Sprintf("spec.security.secretManagement.rotationSchedule %q is not a cron schedule, set one such as \"0 0 1 * *\"", schedule))
+		}
+	}
+
+	if proxy := axelarNode.Spec.Proxy; proxy != nil {
+		problems = append(problems, validateProxyURL("spec.proxy.httpProxy", proxy.HTTPProxy)...)
+		problems = append(problems, validateProxyURL("spec.proxy.httpsProxy", proxy.HTTPSProxy)...)
+	}
+
+	problems = append(problems, validateEgress(axelarNode)...)
+
+	if validator := axelarNode.Spec.Validator; validator != nil && validator.Balances != nil {
+		balances := validator.Balances
+		problems = append(problems, validateAmount("spec.validator.balances.minBroadcasterBalance", balances.MinBroadcasterBalance)...)
+		problems = append(problems, validateAmount("spec.validator.balances.minWithdrawBalance", balances.MinWithdrawBalance)...)
+		for i, account := range balances.Accounts {
+			problems = append(problems, validateAmount(fmt.Sprintf("spec.validator.balances.accounts[%d].minBalance", i), account.MinBalance)...)
+		}
+	}
+
+	if psqlIndexing(axelarNode) != nil && nodeScaledOut(axelarNode) {
+		problems = append(problems, "spec.indexing.psql is set on a scaled-out node, whose replicas would index the same blocks, index from a single replica")
+	}
+
+	storage := axelarNode.Spec.Storage
+	problems = append(problems, validateSize("spec.storage.size", storage.Size)...)
+	if sharedVolumeType(axelarNode) == blockchainv1alpha1.SharedVolumePVC && storage.Shared.Size != "" {
+		problems = append(problems, validateSize("spec.storage.shared.size", storage.Shared.Size)...)
+	}
+	for i, volume := range storage.Volumes {
+		problems = append(problems, validateSize(fmt.Sprintf("spec.storage.volumes[%d].size", i), volume.Size)...)
+	}
+	if splitSigner(axelarNode) && sharedVolumeType(axelarNode) != blockchainv1alpha1.SharedVolumePVC {
+		problems = append(problems, "spec.validator.topology Split shares the shared volume between pods, set spec.storage.shared.type to pvc")
+	}
+
+	if gatewayAPI := axelarNode.Spec.Networking.GatewayAPI; gatewayAPI != nil {
+		if gatewayAPI.GatewayRef.Name == "" {
+			problems = append(problems, "spec.networking.gatewayAPI.gatewayRef.name is empty, name the Gateway the routes attach to")
+		}
+		if gatewayAPI.API != nil && !axelarNode.Spec.Networking.API.Enabled {
+			problems = append(problems, "spec.networking.gatewayAPI.api routes the REST API, set spec.networking.api.enabled or remove the route")
+		}
+	}
+
+	if dns := axelarNode.Spec.Networking.DNS; dns != nil {
+		for _, hostname := range [][2]string{{"spec.networking.dns.p2p", dns.P2P}, {"spec.networking.dns.rpc", dns.RPC}} {
+			if strings.ContainsAny(hostname[1], ":/") {
+				problems = append(problems, fmt.Sprintf("%s %q is not a hostname, drop the scheme and port", hostname[0], hostname[1]))
+			}
+		}
+		if dns.RPC != "" && !publicEnabled(axelarNode) {
+			problems = append(problems, "spec.networking.dns.rpc names the public Service, set spec.networking.public")
+		}
+	}
+
+	networking := axelarNode.Spec.Networking
+	if len(networking.IPFamilies) == 2 {
+		if networking.IPFamilies[0] == networking.IPFamilies[1] {
+			problems = append(problems, fmt.Sprintf("spec.networking.ipFamilies lists %s twice, list IPv4 and IPv6 once each", networking.IPFamilies[0]))
+		} else if networking.IPFamilyPolicy != nil && *networking.IPFamilyPolicy == corev1.IPFamilyPolicySingleStack {
+			problems = append(problems, "spec.networking.ipFamilies lists two families, set spec.networking.ipFamilyPolicy to PreferDualStack or RequireDualStack")
+		}
+	}
+
+	if ref := valdSpec(axelarNode).RecoverySecretRef; ref != nil && (ref.Name == "" || ref.Key == "" || strings.Contains(ref.Key, "/")) {
+		problems = append(problems, "spec.validator.vald.recoverySecretRef must name a Secret and a key without slashes holding the recovery file")
+	}
+
+	if isValidatorNode(axelarNode) && axelarNode.Spec.Validator.KeyShares != nil && keySharesOperator(axelarNode) == "" {
+		problems = append(problems, "spec.validator.keyShares has no operator address, set spec.validator.keyShares.operatorAddress or spec.validator.profile.operatorAddress")
+	}
+
+	// Validators starved of CPU or memory miss blocks
+	if isValidatorNode(axelarNode) {
+		requests := axelarNode.Spec.Resources.Requests
+		var missing []string
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if quantity, ok := requests[name]; !ok || quantity.Sign() <= 0 {
+				missing = append(missing, string(name))
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			problems = append(problems, fmt.Sprintf("spec.resources.requests must set %s for a validator, so it is scheduled with the resources it needs to sign",
+				strings.Join(missing, " and ")))
+		}
+	}
+	return problems
+}
+
+// checkNodeSpec sets the Degraded condition while the spec is invalid, and
+// returns false then so no resources are generated from it
+func (r *AxelarNodeReconciler) checkNodeSpec(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	problems := validateNodeSpec(axelarNode)
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             "SpecValid",
+		Message:            "The spec is valid",
+		ObservedGeneration: axelarNode.Generation,
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "InvalidSpec"
+		condition.Message = strings.Join(problems, "; ")
+
+		previous := meta.FindStatusCondition(axelarNode.Status.Conditions, condition.Type)
+		if r.Recorder != nil && (previous == nil || previous.Status != metav1.ConditionTrue || previous.Message != condition.Message) {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "InvalidSpec", condition.Message)
+		}
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Spec is invalid", "problems", problems)
+	}
+	if condition.Status == metav1.ConditionTrue || meta.FindStatusCondition(axelarNode.Status.Conditions, condition.Type) != nil {
+		meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	}
+	return len(problems) == 0
+}
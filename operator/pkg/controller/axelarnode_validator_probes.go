@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// tofndPort is the gRPC port vald reaches tofnd on
+const tofndPort = 50051
+
+// Sidecar probe types
+const (
+	sidecarProbeGRPC     = "grpc"
+	sidecarProbeTCP      = "tcp"
+	sidecarProbeExec     = "exec"
+	sidecarProbeDisabled = "disabled"
+)
+
+// validatorProbes returns the probes of the validator sidecars
+func validatorProbes(axelarNode *blockchainv1alpha1.AxelarNode) blockchainv1alpha1.ValidatorProbesSpec {
+	if axelarNode.Spec.Validator == nil {
+		return blockchainv1alpha1.ValidatorProbesSpec{}
+	}
+	return axelarNode.Spec.Validator.Probes
+}
+
+// valdWaitScript starts vald once the node RPC answers and tofnd accepts
+// connections, so vald never starts against an unready node
+const valdWaitScript = `until wget -qO- -T 5 http://%s:%d/status >/dev/null 2>&1; do
+  echo "Waiting for the node RPC"; sleep 5
+done
+until nc -z -w 5 %s %d; do
+  echo "Waiting for tofnd"; sleep 5
+done
+exec vald-start%s
+`
+
+// valdStartScript returns the command of the vald container. A vald of its
+// own reaches the node and tofnd through their Services.
+func valdStartScript(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	rpcHost, tofndHost := signerHosts(axelarNode)
+	flags := valdArgs(axelarNode)
+	if tofndHost != "127.0.0.1" {
+		flags = append([]string{"--tofnd-host", tofndHost}, flags...)
+	}
+	if rpcHost != "127.0.0.1" {
+		flags = append([]string{"--node", fmt.Sprintf("tcp://%s:%d", rpcHost, axelarNode.Spec.Networking.RPC.Port)}, flags...)
+	}
+	args := ""
+	for _, arg := range flags {
+		args += " " + shellQuote(arg)
+	}
+	return fmt.Sprintf(valdWaitScript, rpcHost, axelarNode.Spec.Networking.RPC.Port, tofndHost, tofndPort, args)
+}
+
+// valdLivenessProbe restarts vald once it has lost tofnd for a while
+func valdLivenessProbe(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Probe {
+	_, tofndHost := signerHosts(axelarNode)
+	return sidecarProbe(validatorProbes(axelarNode).Vald.Liveness, sidecarProbeExec, tofndHost, corev1.Probe{
+		InitialDelaySeconds: 120,
+		PeriodSeconds:       30,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	})
+}
+
+// valdReadinessProbe is disabled unless configured
+func valdReadinessProbe(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Probe {
+	_, tofndHost := signerHosts(axelarNode)
+	return sidecarProbe(validatorProbes(axelarNode).Vald.Readiness, sidecarProbeDisabled, tofndHost, corev1.Probe{
+		InitialDelaySeconds: 90,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	})
+}
+
+// tofndLivenessProbe restarts tofnd once its gRPC port stops accepting connections
+func tofndLivenessProbe(probes blockchainv1alpha1.SidecarProbesSpec) *corev1.Probe {
+	return sidecarProbe(probes.Liveness, sidecarProbeTCP, "127.0.0.1", corev1.Probe{
+		InitialDelaySeconds: 30,
+		PeriodSeconds:       20,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	})
+}
+
+// tofndReadinessProbe is disabled unless configured
+func tofndReadinessProbe(probes blockchainv1alpha1.SidecarProbesSpec) *corev1.Probe {
+	return sidecarProbe(probes.Readiness, sidecarProbeDisabled, "127.0.0.1", corev1.Probe{
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	})
+}
+
+// sidecarProbe builds the probe described by spec, falling back to
+// defaultType and the default timings. The grpc and tcp checks target tofnd,
+// the only sidecar serving a port, and the tcp and exec checks reach it on
+// tofndHost. All fields the API server would default are set, so the probe
+// compares equal to the deployed one.
+func sidecarProbe(spec blockchainv1alpha1.SidecarProbeSpec, defaultType, tofndHost string, defaults corev1.Probe) *corev1.Probe {
+	probe := defaults
+	probe.SuccessThreshold = 1
+	if spec.InitialDelaySeconds > 0 {
+		probe.InitialDelaySeconds = spec.InitialDelaySeconds
+	}
+	if spec.PeriodSeconds > 0 {
+		probe.PeriodSeconds = spec.PeriodSeconds
+	}
+	if spec.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = spec.TimeoutSeconds
+	}
+	if spec.FailureThreshold > 0 {
+		probe.FailureThreshold = spec.FailureThreshold
+	}
+
+	probeType := spec.Type
+	if probeType == "" {
+		probeType = defaultType
+	}
+	switch probeType {
+	case sidecarProbeGRPC:
+		service := spec.GRPCService
+		probe.GRPC = &corev1.GRPCAction{Port: tofndPort, Service: &service}
+	case sidecarProbeTCP:
+		probe.TCPSocket = &corev1.TCPSocketAction{Port: intstr.FromInt(tofndPort)}
+		if tofndHost != "127.0.0.1" {
+			probe.TCPSocket.Host = tofndHost
+		}
+	case sidecarProbeExec:
+		command := spec.Command
+		if len(command) == 0 {
+			command = []string{"sh", "-c", fmt.Sprintf("nc -z -w %d %s %d", probe.TimeoutSeconds, tofndHost, tofndPort)}
+		}
+		probe.Exec = &corev1.ExecAction{Command: command}
+	default:
+		return nil
+	}
+	return &probe
+}
+
+// reportValidatorHealth reflects the state of the vald and tofnd containers,
+// in the node pod or in their own pods, in the ValdHealthy and TofndHealthy
+// conditions
+func (r *AxelarNodeReconciler) reportValidatorHealth(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if !validatorSigning(axelarNode) {
+		meta.RemoveStatusCondition(&axelarNode.Status.Conditions, blockchainv1alpha1.ConditionValdHealthy)
+		meta.RemoveStatusCondition(&axelarNode.Status.Conditions, blockchainv1alpha1.ConditionTofndHealthy)
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(axelarNode.Namespace), client.MatchingLabels(signerPodLabels(axelarNode))); err != nil {
+		return err
+	}
+	for _, sidecar := range []struct{ container, conditionType string }{
+		{"vald", blockchainv1alpha1.ConditionValdHealthy},
+		{"tofnd", blockchainv1alpha1.ConditionTofndHealthy},
+	} {
+		condition := containerHealth(pods.Items, sidecar.container)
+		if sidecar.container == "tofnd" && !ownTofnd(axelarNode) {
+			var err error
+			if condition, err = r.tofndClusterHealth(ctx, axelarNode); err != nil {
+				return err
+			}
+		}
+		condition.Type = sidecar.conditionType
+		condition.ObservedGeneration = axelarNode.Generation
+
+		previous := meta.FindStatusCondition(axelarNode.Status.Conditions, sidecar.conditionType)
+		if r.Recorder != nil && condition.Status == metav1.ConditionFalse && previous != nil && previous.Status == metav1.ConditionTrue {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "ValidatorSidecarUnhealthy", condition.Message)
+		}
+		meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	}
+	return nil
+}
+
+// tofndClusterHealth describes the state of the TofndCluster the node signs with
+func (r *AxelarNodeReconciler) tofndClusterHealth(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (metav1.Condition, error) {
+	name := tofndClusterName(axelarNode)
+	cluster := &blockchainv1alpha1.TofndCluster{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}, cluster)
+	if errors.IsNotFound(err) {
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: "TofndClusterNotFound",
+			Message: fmt.Sprintf("TofndCluster %s not found", name)}, nil
+	} else if err != nil {
+		return metav1.Condition{}, err
+	}
+	ready := meta.FindStatusCondition(cluster.Status.Conditions, blockchainv1alpha1.ConditionTofndReady)
+	if ready == nil {
+		return metav1.Condition{Status: metav1.ConditionUnknown, Reason: "NoPod",
+			Message: fmt.Sprintf("TofndCluster %s reports no state yet", name)}, nil
+	}
+	return metav1.Condition{Status: ready.Status, Reason: ready.Reason,
+		Message: fmt.Sprintf("TofndCluster %s: %s", name, ready.Message)}, nil
+}
+
+// containerHealth describes the state of a container in the first pod running it
+func containerHealth(pods []corev1.Pod, container string) metav1.Condition {
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != container {
+				continue
+			}
+			switch {
+			case status.State.Running != nil && status.Ready:
+				return metav1.Condition{Status: metav1.ConditionTrue, Reason: "Running",
+					Message: fmt.Sprintf("%s is running in pod %s, %d restarts", container, pod.Name, status.RestartCount)}
+			case status.State.Running != nil:
+				return metav1.Condition{Status: metav1.ConditionFalse, Reason: "NotReady",
+					Message: fmt.Sprintf("%s is failing its readiness probe in pod %s", container, pod.Name)}
+			case status.State.Waiting != nil:
+				reason := status.State.Waiting.Reason
+				if reason == "" {
+					reason = "Waiting"
+				}
+				return metav1.Condition{Status: metav1.ConditionFalse, Reason: reason,
+					Message: fmt.Sprintf("%s is waiting in pod %s, %d restarts: %s", container, pod.Name, status.RestartCount, status.State.Waiting.Message)}
+			default:
+				return metav1.Condition{Status: metav1.ConditionFalse, Reason: "Terminated",
+					Message: fmt.Sprintf("%s has terminated in pod %s, %d restarts", container, pod.Name, status.RestartCount)}
+			}
+		}
+	}
+	return metav1.Condition{Status: metav1.ConditionUnknown, Reason: "NoPod",
+		Message: fmt.Sprintf("No pod is running %s", container)}
+}
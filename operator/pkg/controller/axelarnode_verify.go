@@ -0,0 +1,295 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/maintenance"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tendermint"
+)
+
+// defaultVerifyTimeout bounds a restore drill when the spec leaves it unset
+const defaultVerifyTimeout = 2 * time.Hour
+
+// verifyName names the volume, Job and pod of a restore drill
+func verifyName(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	return childName(axelarNode, "verify")
+}
+
+// verificationRestoring reports whether a restore drill is reading the backup volume
+func verificationRestoring(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	v := axelarNode.Status.BackupVerification
+	return v != nil && v.Phase == blockchainv1alpha1.VerificationRestoring
+}
+
+// verificationRunning reports whether a restore drill is in progress
+func verificationRunning(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	v := axelarNode.Status.BackupVerification
+	return v != nil && (v.Phase == blockchainv1alpha1.VerificationRestoring || v.Phase == blockchainv1alpha1.VerificationReplaying)
+}
+
+// reconcileBackupVerification runs the scheduled restore drills. The last
+// backup is restored into a throwaway volume, and a node without peers is
+// started from it to check that it replays to the height of the backup. The
+// running node is not touched.
+func (r *AxelarNodeReconciler) reconcileBackupVerification(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	spec := axelarNode.Spec.Storage.Backup.Verify
+	v := axelarNode.Status.BackupVerification
+	ctx = audit.WithReason(ctx, "backup-verification")
+
+	if !verificationRunning(axelarNode) {
+		if spec == nil || axelarNode.Status.LastBackupArchive == "" || nodeScaledOut(axelarNode) {
+			return nil
+		}
+		due, err := verificationDue(axelarNode, spec)
+		if err != nil || !due {
+			return err
+		}
+
+		next := &blockchainv1alpha1.BackupVerificationStatus{
+			Phase:        blockchainv1alpha1.VerificationRestoring,
+			Archive:      axelarNode.Status.LastBackupArchive,
+			TargetHeight: axelarNode.Status.LastBackupHeight,
+			StartedAt:    &metav1.Time{Time: time.Now()},
+			Message:      "Restoring the backup into a throwaway volume",
+		}
+		if v != nil {
+			next.LastSuccess = v.LastSuccess
+		}
+		if volumeSnapshotBackups(axelarNode) {
+			return r.startSnapshotVerification(ctx, axelarNode, next)
+		}
+		op := &blockchainv1alpha1.OperationStatus{Type: blockchainv1alpha1.OperationRestore, Archive: next.Archive}
+		if refusal := encryptionRefusal(axelarNode, op); refusal != "" {
+			r.completeVerification(ctx, axelarNode, next, false, fmt.Sprintf("Restore drill refused: %s", refusal))
+			return nil
+		}
+
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Starting backup restore drill", "archive", next.Archive)
+		v = next
+		axelarNode.Status.BackupVerification = v
+		if err := r.createOrUpdatePVC(ctx, r.createPVC(axelarNode, "verify", axelarNode.Spec.Storage.Size)); err != nil {
+			return err
+		}
+		job := r.volumeJob(axelarNode, op, verifyName(axelarNode), verifyName(axelarNode))
+		// The throwaway node needs the archived configuration as well
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{Name: "RESTORE_PATHS", Value: "data config"})
+		if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	}
+
+	timeout := defaultVerifyTimeout
+	if spec != nil && spec.Timeout.Duration > 0 {
+		timeout = spec.Timeout.Duration
+	}
+	if time.Since(v.StartedAt.Time) > timeout {
+		return r.finishVerification(ctx, axelarNode, false, fmt.Sprintf("Restore drill timed out after %s at height %d of %d", timeout, v.Height, v.TargetHeight))
+	}
+
+	switch v.Phase {
+	case blockchainv1alpha1.VerificationRestoring:
+		job := &batchv1.Job{}
+		err := r.Get(ctx, types.NamespacedName{Name: verifyName(axelarNode), Namespace: axelarNode.Namespace}, job)
+		if errors.IsNotFound(err) {
+			return r.finishVerification(ctx, axelarNode, false, "The restore Job was deleted")
+		} else if err != nil {
+			return err
+		}
+		switch {
+		case job.Status.Succeeded > 0:
+			if err := r.deleteJob(ctx, axelarNode, verifyName(axelarNode)); err != nil {
+				return err
+			}
+			if err := r.Create(ctx, r.createVerifyPod(axelarNode)); err != nil && !errors.IsAlreadyExists(err) {
+				return err
+			}
+			v.Phase = blockchainv1alpha1.VerificationReplaying
+			v.Message = "Replaying the restored chain data"
+		case job.Status.Failed > 0:
+			return r.finishVerification(ctx, axelarNode, false, fmt.Sprintf("Unable to restore %s", v.Archive))
+		}
+
+	case blockchainv1alpha1.VerificationReplaying:
+		pod := &corev1.Pod{}
+		err := r.Get(ctx, types.NamespacedName{Name: verifyName(axelarNode), Namespace: axelarNode.Namespace}, pod)
+		if errors.IsNotFound(err) {
+			return r.finishVerification(ctx, axelarNode, false, "The node restored from the backup was deleted")
+		} else if err != nil {
+			return err
+		}
+		if pod.Status.Phase == corev1.PodFailed || containerExited(pod) {
+			return r.finishVerification(ctx, axelarNode, false, "The node restored from the backup exited")
+		}
+		if pod.Status.PodIP == "" {
+			return nil
+		}
+		rpc := tendermint.NewClient(podURL(pod.Status.PodIP, axelarNode.Spec.Networking.RPC.Port))
+		rpc.HTTPClient = nodeHTTPClient(axelarNode)
+		status, err := rpc.Status(ctx)
+		if err != nil {
+			// The RPC only answers once the replay is done
+			return nil
+		}
+		v.Height = status.SyncInfo.Height()
+		if v.Height >= v.TargetHeight {
+			return r.finishVerification(ctx, axelarNode, true,
+				fmt.Sprintf("The node restored from %s started at height %d", v.Archive, v.Height))
+		}
+		v.Message = fmt.Sprintf("Replayed to height %d of %d", v.Height, v.TargetHeight)
+	}
+	return nil
+}
+
+// verificationDue reports whether the schedule calls for a drill since the last one
+func verificationDue(axelarNode *blockchainv1alpha1.AxelarNode, spec *blockchainv1alpha1.BackupVerifySpec) (bool, error) {
+	schedule, err := maintenance.ParseSchedule(spec.Schedule)
+	if err != nil {
+		return false, err
+	}
+	last := axelarNode.CreationTimestamp.Time
+	if v := axelarNode.Status.BackupVerification; v != nil && v.StartedAt != nil {
+		last = v.StartedAt.Time
+	}
+	return !schedule.Next(last).After(time.Now()), nil
+}
+
+// finishVerification removes the throwaway node and volume and records the result
+func (r *AxelarNodeReconciler) finishVerification(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, succeeded bool, message string) error {
+	name := verifyName(axelarNode)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: axelarNode.Namespace}}
+	if err := r.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err := r.deleteJob(ctx, axelarNode, name); err != nil {
+		return err
+	}
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: axelarNode.Namespace}}
+	if err := r.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	r.completeVerification(ctx, axelarNode, axelarNode.Status.BackupVerification, succeeded, message)
+	return nil
+}
+
+// completeVerification records the result of a drill, and alerts when it failed
+func (r *AxelarNodeReconciler) completeVerification(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, v *blockchainv1alpha1.BackupVerificationStatus, succeeded bool, message string) {
+	now := &metav1.Time{Time: time.Now()}
+	v.CompletedAt = now
+	v.Message = message
+	axelarNode.Status.BackupVerification = v
+
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionBackupVerified,
+		Status:             metav1.ConditionTrue,
+		Reason:             "DrillSucceeded",
+		Message:            message,
+		ObservedGeneration: axelarNode.Generation,
+	}
+	if succeeded {
+		v.Phase = blockchainv1alpha1.VerificationSucceeded
+		v.LastSuccess = now
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Backup restore drill succeeded", "archive", v.Archive, "height", v.Height)
+	} else {
+		v.Phase = blockchainv1alpha1.VerificationFailed
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "DrillFailed"
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Backup restore drill failed", "archive", v.Archive, "reason", message)
+		if r.Recorder != nil {
+			r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "BackupVerificationFailed", message)
+		}
+		text := fmt.Sprintf(":warning: Backup restore drill failed for %s/%s: %s", axelarNode.Namespace, axelarNode.Name, message)
+		if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+			r.Log.Error(err, "Unable to send backup verification alert", "axelarnode", axelarNode.Name)
+		}
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+}
+
+// deleteJob removes a Job and its pod
+func (r *AxelarNodeReconciler) deleteJob(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, name string) error {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: axelarNode.Namespace}}
+	err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// createVerifyPod creates the throwaway node replaying the restored volume.
+// It has no peers and a key of its own, so it never joins the network.
+func (r *AxelarNodeReconciler) createVerifyPod(axelarNode *blockchainv1alpha1.AxelarNode) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      verifyName(axelarNode),
+			Namespace: axelarNode.Namespace,
+			Labels:    map[string]string{"app": verifyName(axelarNode)},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:            "axelar-node",
+					Image:           nodeImage(axelarNode),
+					ImagePullPolicy: axelarNode.Spec.Image.PullPolicy,
+					Command: []string{
+						"axelard", "start",
+						"--home", "/home/axelard/.axelar",
+						"--p2p.laddr", fmt.Sprintf("tcp://127.0.0.1:%d", axelarNode.Spec.Networking.P2P.Port),
+						"--p2p.pex=false",
+						"--p2p.seeds=",
+						"--p2p.persistent_peers=",
+						"--rpc.laddr", "tcp://" + listenAddress(axelarNode.Spec.Networking, axelarNode.Spec.Networking.RPC.Port),
+					},
+					Ports: []corev1.ContainerPort{
+						{Name: "rpc", ContainerPort: axelarNode.Spec.Networking.RPC.Port},
+					},
+					Resources: axelarNode.Spec.Resources,
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: "/home/axelard/.axelar"},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: verifyName(axelarNode),
+						},
+					},
+				},
+			},
+			SecurityContext: axelarNode.Spec.Security.PodSecurityContext,
+		},
+	}
+
+	controllerutil.SetControllerReference(axelarNode, pod, r.Scheme)
+	return pod
+}
+
+// containerExited reports whether a container of the pod has terminated
+func containerExited(pod *corev1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil || status.RestartCount > 0 {
+			return true
+		}
+	}
+	return false
+}
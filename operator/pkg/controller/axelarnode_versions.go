@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// runningVersions returns the running versions of the node status, creating them
+func runningVersions(axelarNode *blockchainv1alpha1.AxelarNode) *blockchainv1alpha1.RunningVersions {
+	if axelarNode.Status.Versions == nil {
+		axelarNode.Status.Versions = &blockchainv1alpha1.RunningVersions{}
+	}
+	return axelarNode.Status.Versions
+}
+
+// reportVersions records the tofnd version the validator signs with and
+// raises VersionDrift while the axelard version the node reports differs
+// from spec.image.tag, as when cosmovisor swapped the binary. Versions that
+// cannot be read keep their last value.
+func (r *AxelarNodeReconciler) reportVersions(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	switch {
+	case validatorSigning(axelarNode):
+		tofnd, err := r.tofndVersion(ctx, axelarNode)
+		if err != nil {
+			return err
+		}
+		if tofnd != "" {
+			runningVersions(axelarNode).Tofnd = tofnd
+		}
+	case axelarNode.Spec.Validator == nil || !axelarNode.Spec.Validator.Enabled:
+		if axelarNode.Status.Versions != nil {
+			axelarNode.Status.Versions.Tofnd = ""
+		}
+	}
+
+	if axelarNode.Status.Versions == nil || axelarNode.Status.Versions.Axelard == "" {
+		return nil
+	}
+	running, tag := axelarNode.Status.Versions.Axelard, axelarNode.Spec.Image.Tag
+	condition := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionVersionDrift,
+		Status:             metav1.ConditionFalse,
+		Reason:             "VersionMatches",
+		Message:            fmt.Sprintf("The node runs axelard %s", running),
+		ObservedGeneration: axelarNode.Generation,
+	}
+	switch {
+	case !versionedTag(tag):
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "UnversionedTag"
+		condition.Message = fmt.Sprintf("The node runs axelard %s, image tag %q names no version", running, tag)
+	case !versionMatches(running, tag):
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "VersionDrift"
+		condition.Message = fmt.Sprintf("The node runs axelard %s but spec.image.tag is %s", running, tag)
+	}
+
+	previous := meta.FindStatusCondition(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionVersionDrift)
+	if r.Recorder != nil && condition.Status == metav1.ConditionTrue && (previous == nil || previous.Status != metav1.ConditionTrue) {
+		r.Recorder.Event(axelarNode, corev1.EventTypeWarning, "VersionDrift", condition.Message)
+	}
+	meta.SetStatusCondition(&axelarNode.Status.Conditions, condition)
+	return nil
+}
+
+// tofndVersion returns the image tag of the running tofnd container, in the
+// pods of the node or of the TofndCluster it signs with, or empty when none runs
+func (r *AxelarNodeReconciler) tofndVersion(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) (string, error) {
+	selector := signerPodLabels(axelarNode)
+	if !ownTofnd(axelarNode) {
+		selector = tofndClusterLabels(&blockchainv1alpha1.TofndCluster{ObjectMeta: metav1.ObjectMeta{Name: tofndClusterName(axelarNode)}})
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(axelarNode.Namespace), client.MatchingLabels(selector)); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == "tofnd" && status.State.Running != nil {
+				return imageTag(status.Image), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// versionedTag reports whether an image tag names a version, like v0.35.5
+func versionedTag(tag string) bool {
+	tag = strings.TrimPrefix(tag, "v")
+	return tag != "" && tag[0] >= '0' && tag[0] <= '9'
+}
+
+// versionMatches reports whether the version axelard reports is the one the
+// image tag names. axelard reports versions with or without the v prefix, and
+// tags may carry a suffix such as -alpine.
+func versionMatches(running, tag string) bool {
+	running, tag = strings.TrimPrefix(running, "v"), strings.TrimPrefix(tag, "v")
+	return tag == running || strings.HasPrefix(tag, running+"-")
+}
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// defaultSharedVolumeSize applies when spec.storage.shared leaves the size unset
+const defaultSharedVolumeSize = "10Gi"
+
+// extraVolumePrefix prefixes the pod volumes and PVCs of spec.storage.volumes,
+// keeping them apart from the volumes of the operator
+const extraVolumePrefix = "vol-"
+
+// sharedVolumeType returns how the shared volume is provided to the pod. Only
+// validators get one unless the spec asks for it.
+func sharedVolumeType(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	switch t := axelarNode.Spec.Storage.Shared.Type; t {
+	case "":
+		if isValidatorNode(axelarNode) {
+			return blockchainv1alpha1.SharedVolumePVC
+		}
+		return blockchainv1alpha1.SharedVolumeNone
+	case blockchainv1alpha1.SharedVolumeNone:
+		if validatorSigning(axelarNode) {
+			return blockchainv1alpha1.SharedVolumeEmptyDir
+		}
+		return t
+	default:
+		return t
+	}
+}
+
+// volumePVC creates the PVC object of a volume, in the storage class of the
+// volume when it sets one
+func (r *AxelarNodeReconciler) volumePVC(axelarNode *blockchainv1alpha1.AxelarNode, suffix, size, storageClass string) *corev1.PersistentVolumeClaim {
+	pvc := r.createPVC(axelarNode, suffix, size)
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	return pvc
+}
+
+// reconcileVolumes creates the shared PVC and the PVCs of spec.storage.volumes.
+// The shared PVC is deleted once the node no longer uses it, such as after the
+// validator is disabled, and no Deployment of the node mounts it anymore. The
+// PVCs of spec.storage.volumes are never deleted here, so that removing a
+// volume from the spec keeps its data.
+func (r *AxelarNodeReconciler) reconcileVolumes(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if sharedVolumeType(axelarNode) == blockchainv1alpha1.SharedVolumePVC {
+		shared := axelarNode.Spec.Storage.Shared
+		size := shared.Size
+		if size == "" {
+			size = defaultSharedVolumeSize
+		}
+		if err := r.createOrUpdatePVC(ctx, r.volumePVC(axelarNode, "shared", size, shared.StorageClass)); err != nil {
+			return err
+		}
+	} else if err := r.deleteSharedPVC(ctx, axelarNode); err != nil {
+		return err
+	}
+	for _, volume := range axelarNode.Spec.Storage.Volumes {
+		pvc := r.volumePVC(axelarNode, extraVolumePrefix+volume.Name, volume.Size, volume.StorageClass)
+		if err := r.createOrUpdatePVC(ctx, pvc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSharedPVC removes the shared PVC created for the node, if any
+func (r *AxelarNodeReconciler) deleteSharedPVC(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	found := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: childName(axelarNode, "shared"), Namespace: axelarNode.Namespace}, found)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(found, axelarNode) || found.DeletionTimestamp != nil {
+		return nil
+	}
+	// The Deployments roll off the claim first, the claim is deleted at a
+	// later reconcile
+	mounted, err := r.claimMounted(ctx, axelarNode, found.Name)
+	if err != nil || mounted {
+		return err
+	}
+	r.Log.Info("Deleting the unused shared PVC", "axelarnode", axelarNode.Name, "pvc", found.Name)
+	if err := r.Delete(ctx, found); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// claimMounted reports whether the pod template of a Deployment of the node
+// still mounts the claim
+func (r *AxelarNodeReconciler) claimMounted(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, claim string) (bool, error) {
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(axelarNode.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if !metav1.IsControlledBy(deployment, axelarNode) {
+			continue
+		}
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claim {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// addVolumes provides the shared volume as configured and mounts the volumes
+// of spec.storage.volumes in the node container
+func addVolumes(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	configureSharedVolume(axelarNode, podSpec)
+	for _, volume := range axelarNode.Spec.Storage.Volumes {
+		name := extraVolumePrefix + volume.Name
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: childName(axelarNode, name),
+				},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: volume.MountPath,
+			ReadOnly:  volume.ReadOnly,
+		})
+	}
+}
+
+// configureSharedVolume replaces the shared PVC of the pod with an emptyDir, or
+// removes it, as configured
+func configureSharedVolume(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	switch sharedVolumeType(axelarNode) {
+	case blockchainv1alpha1.SharedVolumeEmptyDir:
+		for i := range podSpec.Volumes {
+			if podSpec.Volumes[i].Name == "shared" {
+				podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+			}
+		}
+	case blockchainv1alpha1.SharedVolumeNone:
+		removeVolume(podSpec, "shared")
+	}
+}
+
+// removeVolume removes a volume and its mounts from the pod
+func removeVolume(podSpec *corev1.PodSpec, name string) {
+	volumes := []corev1.Volume{}
+	for _, volume := range podSpec.Volumes {
+		if volume.Name != name {
+			volumes = append(volumes, volume)
+		}
+	}
+	podSpec.Volumes = volumes
+	for i := range podSpec.Containers {
+		mounts := []corev1.VolumeMount{}
+		for _, mount := range podSpec.Containers[i].VolumeMounts {
+			if mount.Name != name {
+				mounts = append(mounts, mount)
+			}
+		}
+		podSpec.Containers[i].VolumeMounts = mounts
+	}
+}
+
+// ephemeralVolumes gives each replica of the pod its own copy of the volumes
+// of spec.storage.volumes, deleted with the replica
+func (r *AxelarNodeReconciler) ephemeralVolumes(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	for _, volume := range axelarNode.Spec.Storage.Volumes {
+		claim := r.volumePVC(axelarNode, extraVolumePrefix+volume.Name, volume.Size, volume.StorageClass)
+		for i := range podSpec.Volumes {
+			if podSpec.Volumes[i].Name == extraVolumePrefix+volume.Name {
+				podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{
+					Ephemeral: &corev1.EphemeralVolumeSource{
+						VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{Spec: claim.Spec},
+					},
+				}
+			}
+		}
+	}
+}
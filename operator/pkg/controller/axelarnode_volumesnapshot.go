@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// volumeSnapshotGVK is the CSI VolumeSnapshot kind. It is handled as an
+// unstructured object, so the operator runs in clusters without the CSI
+// snapshot CRDs.
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// snapshotRestoreScript replaces the chain data with the data of a volume
+// snapshot mounted at /snapshot, keeping the current signing state like
+// restoreScript
+const snapshotRestoreScript = `set -e
+test -d /snapshot/data
+cd /home/axelard/.axelar
+if [ -f data/priv_validator_state.json ]; then cp data/priv_validator_state.json /tmp/priv_validator_state.json; fi
+rm -rf data
+cp -a /snapshot/data data
+if [ -f /tmp/priv_validator_state.json ]; then cp /tmp/priv_validator_state.json data/priv_validator_state.json; fi
+sync
+`
+
+// volumeSnapshotBackups reports whether the node is backed up with CSI volume snapshots
+func volumeSnapshotBackups(axelarNode *blockchainv1alpha1.AxelarNode) bool {
+	return axelarNode.Spec.Storage.Backup.Method == blockchainv1alpha1.BackupMethodVolumeSnapshot
+}
+
+// createVolumeSnapshot cuts a snapshot of the active data volume. Snapshots
+// are not owned by the node, so backups outlive it.
+func (r *AxelarNodeReconciler) createVolumeSnapshot(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, name string) error {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(name)
+	snapshot.SetNamespace(axelarNode.Namespace)
+	snapshot.SetLabels(nodeLabels(axelarNode, componentNode))
+
+	claim := dataClaimName(axelarNode, activeSlot(axelarNode))
+	if err := unstructured.SetNestedField(snapshot.Object, claim, "spec", "source", "persistentVolumeClaimName"); err != nil {
+		return err
+	}
+	if class := axelarNode.Spec.Storage.Backup.VolumeSnapshotClass; class != "" {
+		if err := unstructured.SetNestedField(snapshot.Object, class, "spec", "volumeSnapshotClassName"); err != nil {
+			return err
+		}
+	}
+
+	if err := r.Create(ctx, snapshot); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// startVolumeSnapshot cuts the snapshot of a backup, halting the node first
+// when the spec asks for it
+func (r *AxelarNodeReconciler) startVolumeSnapshot(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus) error {
+	if axelarNode.Spec.Storage.Backup.HaltNode {
+		stopped, err := r.stopValidatorPods(ctx, axelarNode)
+		if err != nil || !stopped {
+			return err
+		}
+	}
+	if err := r.createVolumeSnapshot(ctx, axelarNode, op.Archive); err != nil {
+		return err
+	}
+	op.Phase = blockchainv1alpha1.OperationRunning
+	op.Message = fmt.Sprintf("Taking volume snapshot %s", op.Archive)
+	return nil
+}
+
+// checkVolumeSnapshot waits for the snapshot of a backup to be cut. The node
+// is started again as soon as it is; the snapshot becomes ready to use later.
+func (r *AxelarNodeReconciler) checkVolumeSnapshot(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, op *blockchainv1alpha1.OperationStatus) error {
+	cut, _, failure, err := r.volumeSnapshotState(ctx, axelarNode.Namespace, op.Archive)
+	if errors.IsNotFound(err) {
+		failure = "the volume snapshot was deleted"
+	} else if err != nil {
+		return err
+	}
+
+	switch {
+	case failure != "":
+		r.Log.WithValues("axelarnode", axelarNode.Name).Info("Volume snapshot failed, restarting the node", "snapshot", op.Archive, "reason", failure)
+		op.Archive = ""
+		op.Message = "Backup failed, starting the node"
+	case cut:
+		axelarNode.Status.LastBackup = &metav1.Time{Time: time.Now()}
+		axelarNode.Status.LastBackupArchive = op.Archive
+		axelarNode.Status.LastBackupHeight = axelarNode.Status.SyncInfo.CurrentHeight
+		op.Message = "Starting the node"
+	default:
+		return nil
+	}
+	op.Phase = blockchainv1alpha1.OperationStarting
+	return nil
+}
+
+// volumeSnapshotState reports whether a snapshot has been cut, and whether it
+// is ready to restore from. failure explains why the CSI driver could not take
+// the snapshot.
+func (r *AxelarNodeReconciler) volumeSnapshotState(ctx context.Context, namespace, name string) (cut, ready bool, failure string, err error) {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, snapshot); err != nil {
+		return false, false, "", err
+	}
+	if message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found {
+		return false, false, message, nil
+	}
+	_, cut, _ = unstructured.NestedString(snapshot.Object, "status", "creationTime")
+	ready, _, _ = unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	return cut, ready, "", nil
+}
+
+// volumeSnapshotRefusal explains why a volume snapshot cannot be restored, or
+// returns an empty string
+func (r *AxelarNodeReconciler) volumeSnapshotRefusal(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, name string) (string, error) {
+	if name == "" || name == latestArchive {
+		return "no volume snapshot backup was taken", nil
+	}
+	_, ready, failure, err := r.volumeSnapshotState(ctx, axelarNode.Namespace, name)
+	switch {
+	case errors.IsNotFound(err):
+		return fmt.Sprintf("volume snapshot %s not found", name), nil
+	case err != nil:
+		return "", err
+	case failure != "":
+		return fmt.Sprintf("volume snapshot %s failed: %s", name, failure), nil
+	case !ready:
+		return fmt.Sprintf("volume snapshot %s is not ready to use", name), nil
+	}
+	return "", nil
+}
+
+// startSnapshotVerification starts a restore drill of a volume snapshot. The
+// throwaway volume is provisioned from the snapshot, so the drill replays
+// right away.
+func (r *AxelarNodeReconciler) startSnapshotVerification(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, v *blockchainv1alpha1.BackupVerificationStatus) error {
+	_, ready, failure, err := r.volumeSnapshotState(ctx, axelarNode.Namespace, v.Archive)
+	switch {
+	case errors.IsNotFound(err):
+		r.completeVerification(ctx, axelarNode, v, false, fmt.Sprintf("Restore drill refused: volume snapshot %s not found", v.Archive))
+		return nil
+	case err != nil:
+		return err
+	case failure != "":
+		r.completeVerification(ctx, axelarNode, v, false, fmt.Sprintf("Restore drill refused: volume snapshot %s failed: %s", v.Archive, failure))
+		return nil
+	case !ready:
+		// The drill starts once the snapshot is ready to use
+		return nil
+	}
+
+	r.Log.WithValues("axelarnode", axelarNode.Name).Info("Starting backup restore drill", "snapshot", v.Archive)
+	v.Phase = blockchainv1alpha1.VerificationReplaying
+	v.Message = "Replaying the chain data of the volume snapshot"
+	axelarNode.Status.BackupVerification = v
+	if err := r.createOrUpdatePVC(ctx, r.createSnapshotPVC(axelarNode, "verify", v.Archive)); err != nil {
+		return err
+	}
+	pod := r.createVerifyPod(axelarNode)
+	stripSnapshotKeys(&pod.Spec)
+	if err := r.Create(ctx, pod); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// createSnapshotPVC creates a volume provisioned from a volume snapshot
+func (r *AxelarNodeReconciler) createSnapshotPVC(axelarNode *blockchainv1alpha1.AxelarNode, suffix, snapshot string) *corev1.PersistentVolumeClaim {
+	pvc := r.createPVC(axelarNode, suffix, axelarNode.Spec.Storage.Size)
+	apiGroup := volumeSnapshotGVK.Group
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     volumeSnapshotGVK.Kind,
+		Name:     snapshot,
+	}
+	return pvc
+}
+
+// deleteSnapshotPVC removes a volume provisioned from a volume snapshot
+func (r *AxelarNodeReconciler) deleteSnapshotPVC(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode, suffix string) error {
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: childName(axelarNode, suffix), Namespace: axelarNode.Namespace}}
+	if err := r.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// stripSnapshotKeys removes the keys of the node from a volume restored from
+// a snapshot, so a restore drill signs with a key of its own
+func stripSnapshotKeys(podSpec *corev1.PodSpec) {
+	container := podSpec.Containers[0]
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:         "strip-keys",
+		Image:        container.Image,
+		Command:      []string{"sh", "-c", "rm -rf /home/axelard/.axelar/config/priv_validator_key.json /home/axelard/.axelar/keyring-file /home/axelard/.axelar/keyring-test"},
+		VolumeMounts: container.VolumeMounts,
+	})
+}
+
+// addSnapshotSource mounts the volume restored from a snapshot in place of
+// the backup volume of a restore Job
+func addSnapshotSource(axelarNode *blockchainv1alpha1.AxelarNode, podSpec *corev1.PodSpec) {
+	container := &podSpec.Containers[0]
+	container.Command = []string{"sh", "-c", snapshotRestoreScript}
+	for i := range container.VolumeMounts {
+		if container.VolumeMounts[i].Name == "backup" {
+			container.VolumeMounts[i].MountPath = "/snapshot"
+			container.VolumeMounts[i].ReadOnly = true
+		}
+	}
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == "backup" {
+			podSpec.Volumes[i].PersistentVolumeClaim.ClaimName = childName(axelarNode, "restore")
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/cosmos"
+)
+
+// voteJobName names the Job sending vote transactions
+const voteJobName = "vote"
+
+// voteRetryInterval is how long a failed or skipped vote is left before it is
+// tried again
+const voteRetryInterval = time.Hour
+
+// proposalIDPattern matches valid proposal IDs
+var proposalIDPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// voteOptions are the options accepted in spec.validator.governance.votes
+var voteOptions = map[string]bool{
+	blockchainv1alpha1.VoteOptionYes:        true,
+	blockchainv1alpha1.VoteOptionNo:         true,
+	blockchainv1alpha1.VoteOptionAbstain:    true,
+	blockchainv1alpha1.VoteOptionNoWithVeto: true,
+}
+
+// reconcileVotes casts the votes listed in spec.validator.governance, one
+// transaction Job at a time. Proposals that are not listed are never voted on.
+func (r *AxelarNodeReconciler) reconcileVotes(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	log := r.Log.WithValues("axelarnode", axelarNode.Name)
+
+	// Record the outcome of the vote in flight first
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: childName(axelarNode, voteJobName), Namespace: axelarNode.Namespace}, job)
+	if err == nil {
+		if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+			return nil
+		}
+		output, err := r.txJobOutput(ctx, job)
+		if err != nil {
+			return err
+		}
+		for id, vote := range axelarNode.Status.Votes {
+			if vote.Phase != blockchainv1alpha1.VotePhaseVoting {
+				continue
+			}
+			if job.Status.Succeeded > 0 {
+				vote.Phase = blockchainv1alpha1.VotePhaseVoted
+				vote.TxHash = output
+				vote.Message = ""
+				log.Info("Voted on proposal", "proposal", id, "option", vote.Option, "tx", output)
+				r.recordVoteEvent(axelarNode, corev1.EventTypeNormal, "VoteCast", fmt.Sprintf("Voted %s on proposal #%s", vote.Option, id))
+			} else {
+				if output == "" {
+					output = "the vote Job failed, see its logs"
+				}
+				vote.Phase = blockchainv1alpha1.VotePhaseFailed
+				vote.Message = output
+				log.Info("Vote failed", "proposal", id, "reason", output)
+				r.recordVoteEvent(axelarNode, corev1.EventTypeWarning, "VoteFailed", fmt.Sprintf("Vote on proposal #%s failed: %s", id, output))
+			}
+			axelarNode.Status.Votes[id] = vote
+		}
+		return r.deleteJob(ctx, axelarNode, job.Name)
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	var votes map[string]string
+	if spec := axelarNode.Spec.Validator; spec != nil && spec.Enabled && spec.Governance != nil {
+		votes = spec.Governance.Votes
+	}
+	for id := range axelarNode.Status.Votes {
+		if _, ok := votes[id]; !ok {
+			delete(axelarNode.Status.Votes, id)
+		}
+	}
+	if len(votes) == 0 {
+		axelarNode.Status.Votes = nil
+		return nil
+	}
+	if axelarNode.Status.Votes == nil {
+		axelarNode.Status.Votes = map[string]blockchainv1alpha1.VoteStatus{}
+	}
+
+	ids := make([]string, 0, len(votes))
+	for id := range votes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	_, api, err := r.statusClients(ctx, axelarNode)
+	if err != nil {
+		log.V(1).Info("Unable to reach the status source", "error", err.Error())
+		return nil
+	}
+	now := metav1.Now()
+	for _, id := range ids {
+		option := votes[id]
+		if current, ok := axelarNode.Status.Votes[id]; ok && current.Option == option {
+			if current.Phase == blockchainv1alpha1.VotePhaseVoted {
+				continue
+			}
+			if current.LastAttempt != nil && time.Since(current.LastAttempt.Time) < voteRetryInterval {
+				continue
+			}
+		}
+
+		vote := blockchainv1alpha1.VoteStatus{Option: option, Phase: blockchainv1alpha1.VotePhaseFailed, LastAttempt: &now}
+		if !proposalIDPattern.MatchString(id) {
+			vote.Message = fmt.Sprintf("invalid proposal ID %q", id)
+		} else if !voteOptions[option] {
+			vote.Message = fmt.Sprintf("invalid vote option %q", option)
+		} else if proposal, err := api.Proposal(ctx, id); err != nil {
+			vote.Message = fmt.Sprintf("unable to query the proposal: %v", err)
+		} else if proposal.Status != cosmos.ProposalStatusVotingPeriod {
+			vote.Phase = blockchainv1alpha1.VotePhaseSkipped
+			vote.Message = fmt.Sprintf("proposal is in status %s", proposal.Status)
+		} else {
+			key := txKeySecret(axelarNode)
+			if ref := axelarNode.Spec.Validator.Governance.KeySecretRef; ref != nil {
+				key = *ref
+			}
+			if err := r.createTxJob(ctx, axelarNode, voteJobName, fmt.Sprintf("tx gov vote %s %s\n", id, option), key); err != nil {
+				return err
+			}
+			log.Info("Voting on proposal", "proposal", id, "option", option)
+			vote.Phase = blockchainv1alpha1.VotePhaseVoting
+			axelarNode.Status.Votes[id] = vote
+			return nil
+		}
+		axelarNode.Status.Votes[id] = vote
+	}
+	return nil
+}
+
+// recordVoteEvent records an event for a vote when a recorder is configured
+func (r *AxelarNodeReconciler) recordVoteEvent(axelarNode *blockchainv1alpha1.AxelarNode, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(axelarNode, eventType, reason, message)
+	}
+}
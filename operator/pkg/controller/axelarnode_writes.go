@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// writeNode writes the changes a reconcile made to the node since original:
+// the status in one patch, then the finalizers and annotations in another.
+// The status goes first, so it is recorded before the finalizer of a deleted
+// node is removed.
+func (r *AxelarNodeReconciler) writeNode(ctx context.Context, original, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	if !equality.Semantic.DeepEqual(original.Status, axelarNode.Status) {
+		if err := r.patchStatus(ctx, axelarNode); err != nil {
+			return err
+		}
+	}
+	return r.patchMetadata(ctx, original, axelarNode)
+}
+
+// patchStatus writes the status of the node. The patch is only applied over
+// the version of the node it was computed from; on a conflict the status is
+// applied again over the latest version, since the controller owns it.
+func (r *AxelarNodeReconciler) patchStatus(ctx context.Context, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	status := axelarNode.Status.DeepCopy()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &blockchainv1alpha1.AxelarNode{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(axelarNode), latest); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(latest.Status, *status) {
+			return nil
+		}
+		patch := client.MergeFromWithOptions(latest.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		latest.Status = *status
+		if err := r.Status().Patch(ctx, latest, patch); err != nil {
+			return err
+		}
+		axelarNode.ResourceVersion = latest.ResourceVersion
+		return nil
+	})
+}
+
+// patchMetadata writes the finalizer and annotation changes made since
+// original. Annotations are merged key by key, so requests added meanwhile
+// are kept. A finalizer change replaces the whole list and is only applied
+// over the version of the node it was made on.
+func (r *AxelarNodeReconciler) patchMetadata(ctx context.Context, original, axelarNode *blockchainv1alpha1.AxelarNode) error {
+	finalizersChanged := !equality.Semantic.DeepEqual(original.Finalizers, axelarNode.Finalizers)
+	if !finalizersChanged && equality.Semantic.DeepEqual(original.Annotations, axelarNode.Annotations) {
+		return nil
+	}
+
+	base := &blockchainv1alpha1.AxelarNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            axelarNode.Name,
+			Namespace:       axelarNode.Namespace,
+			ResourceVersion: axelarNode.ResourceVersion,
+			Annotations:     original.Annotations,
+			Finalizers:      original.Finalizers,
+		},
+	}
+	changed := base.DeepCopy()
+	changed.Annotations = axelarNode.Annotations
+	changed.Finalizers = axelarNode.Finalizers
+
+	var opts []client.MergeFromOption
+	if finalizersChanged {
+		opts = append(opts, client.MergeFromWithOptimisticLock{})
+	}
+	err := r.Patch(ctx, changed, client.MergeFromWithOptions(base, opts...))
+	if errors.IsNotFound(err) && len(axelarNode.Finalizers) == 0 {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,431 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/alert"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/backup"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/maintenance"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tenancy"
+)
+
+// keyBackupRetryInterval is how long a failed key backup waits before it is retried
+const keyBackupRetryInterval = time.Hour
+
+// keyBackupPollInterval is how often a running key backup Job is checked
+const keyBackupPollInterval = 30 * time.Second
+
+// keyBackupNodeLabel labels the Jobs of a key backup with the node backed up
+const keyBackupNodeLabel = "blockchain.axelar.network/key-backup-node"
+
+// AxelarNodeKeyBackupReconciler reconciles an AxelarNodeKeyBackup object
+type AxelarNodeKeyBackupReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	// ToolsImage is the image running the key escrow
+	ToolsImage string
+
+	// Recorder emits events on key backups
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarnodekeybackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarnodekeybackups/status,verbs=get;update;patch
+
+// Reconcile runs the backup Jobs of an AxelarNodeKeyBackup and records the
+// archive each one uploaded
+func (r *AxelarNodeKeyBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("axelarnodekeybackup", req.NamespacedName)
+
+	keyBackup := &blockchainv1alpha1.AxelarNodeKeyBackup{}
+	if err := r.Get(ctx, req.NamespacedName, keyBackup); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("AxelarNodeKeyBackup resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get AxelarNodeKeyBackup")
+		return ctrl.Result{}, err
+	}
+	ctx = audit.WithActor(ctx, keyBackup)
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(keyBackup))
+	ctx = naming.WithInstance(ctx, naming.Instance{Owner: keyBackup.UID, Name: "axelar-key-backup", Instance: keyBackup.Name, Component: "backup"})
+
+	status := &keyBackup.Status
+	var result ctrl.Result
+	var err error
+	if status.Phase == blockchainv1alpha1.KeyBackupRunning {
+		result, err = r.followKeyBackup(ctx, keyBackup)
+	} else {
+		result, err = r.startKeyBackup(ctx, keyBackup)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Status().Update(ctx, keyBackup); err != nil {
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// keyBackupDue returns whether a backup is due, or else when the next one is
+func keyBackupDue(keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup, now time.Time) (bool, time.Time, error) {
+	status := keyBackup.Status
+	if status.ObservedGeneration != keyBackup.Generation {
+		return true, now, nil
+	}
+	if status.Phase == blockchainv1alpha1.KeyBackupFailed && status.StartedAt != nil {
+		retry := status.StartedAt.Add(keyBackupRetryInterval)
+		return !retry.After(now), retry, nil
+	}
+	if keyBackup.Spec.Schedule == "" {
+		return false, time.Time{}, nil
+	}
+	schedule, err := maintenance.ParseSchedule(keyBackup.Spec.Schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	last := keyBackup.CreationTimestamp.Time
+	if status.StartedAt != nil {
+		last = status.StartedAt.Time
+	}
+	next := schedule.Next(last)
+	return !next.After(now), next, nil
+}
+
+// startKeyBackup creates the backup Job once a backup is due
+func (r *AxelarNodeKeyBackupReconciler) startKeyBackup(ctx context.Context, keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup) (ctrl.Result, error) {
+	now := time.Now()
+	due, next, err := keyBackupDue(keyBackup, now)
+	if err != nil {
+		r.failKeyBackup(ctx, keyBackup, nil, fmt.Sprintf("invalid schedule %q: %v", keyBackup.Spec.Schedule, err))
+		return ctrl.Result{}, nil
+	}
+	if !due {
+		if next.IsZero() {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	axelarNode := &blockchainv1alpha1.AxelarNode{}
+	err = r.Get(ctx, types.NamespacedName{Name: keyBackup.Spec.NodeRef.Name, Namespace: keyBackup.Namespace}, axelarNode)
+	if errors.IsNotFound(err) {
+		keyBackup.Status.Phase = blockchainv1alpha1.KeyBackupPending
+		keyBackup.Status.Message = fmt.Sprintf("AxelarNode %s not found", keyBackup.Spec.NodeRef.Name)
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	keyBackup.Status.ObservedGeneration = keyBackup.Generation
+	keyBackup.Status.StartedAt = &metav1.Time{Time: now}
+	if refusal := keyBackupRefusal(keyBackup, axelarNode); refusal != "" {
+		r.failKeyBackup(ctx, keyBackup, axelarNode, refusal)
+		return ctrl.Result{RequeueAfter: keyBackupRetryInterval}, nil
+	}
+
+	job, err := r.createKeyBackupJob(ctx, keyBackup, axelarNode, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Log.Info("Starting key backup", "axelarnodekeybackup", keyBackup.Name, "axelarnode", axelarNode.Name, "job", job.Name)
+	if err := r.Create(ctx, job); err != nil {
+		return ctrl.Result{}, err
+	}
+	keyBackup.Status.Phase = blockchainv1alpha1.KeyBackupRunning
+	keyBackup.Status.Job = job.Name
+	keyBackup.Status.Message = "Escrowing the keys of " + axelarNode.Name
+	return ctrl.Result{RequeueAfter: keyBackupPollInterval}, nil
+}
+
+// followKeyBackup records the outcome of the running backup Job
+func (r *AxelarNodeKeyBackupReconciler) followKeyBackup(ctx context.Context, keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup) (ctrl.Result, error) {
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: keyBackup.Status.Job, Namespace: keyBackup.Namespace}, job)
+	if errors.IsNotFound(err) {
+		r.failKeyBackup(ctx, keyBackup, r.keyBackupNode(ctx, keyBackup), "the backup Job was deleted before it finished")
+		return ctrl.Result{RequeueAfter: keyBackupRetryInterval}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+	if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		return ctrl.Result{RequeueAfter: keyBackupPollInterval}, nil
+	}
+
+	reader := &AxelarNodeReconciler{Client: r.Client}
+	output, err := reader.txJobOutput(ctx, job)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if job.Status.Succeeded == 0 {
+		if output == "" {
+			output = "the backup Job failed, see its logs"
+		}
+		r.failKeyBackup(ctx, keyBackup, r.keyBackupNode(ctx, keyBackup), output)
+		return ctrl.Result{RequeueAfter: keyBackupRetryInterval}, nil
+	}
+
+	escrowed := &backup.EscrowResult{}
+	if err := json.Unmarshal([]byte(output), escrowed); err != nil || escrowed.Fingerprint == "" {
+		r.failKeyBackup(ctx, keyBackup, r.keyBackupNode(ctx, keyBackup), fmt.Sprintf("unable to read the backup result %q", output))
+		return ctrl.Result{RequeueAfter: keyBackupRetryInterval}, nil
+	}
+	status := &keyBackup.Status
+	status.Phase = blockchainv1alpha1.KeyBackupSucceeded
+	status.LastBackup = &metav1.Time{Time: time.Now()}
+	if job.Status.CompletionTime != nil {
+		status.LastBackup = job.Status.CompletionTime.DeepCopy()
+	}
+	status.Object = escrowed.Object
+	status.Fingerprint = escrowed.Fingerprint
+	status.Files = escrowed.Files
+	status.Message = fmt.Sprintf("Escrowed %d key files to %s", len(escrowed.Files), escrowed.Object)
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionKeysEscrowed,
+		Status:             metav1.ConditionTrue,
+		Reason:             "BackupSucceeded",
+		Message:            status.Message,
+		ObservedGeneration: keyBackup.Generation,
+	})
+	r.Log.Info("Escrowed keys", "axelarnodekeybackup", keyBackup.Name, "object", status.Object, "fingerprint", status.Fingerprint)
+	if r.Recorder != nil {
+		r.Recorder.Event(keyBackup, corev1.EventTypeNormal, "KeysEscrowed", fmt.Sprintf("%s, fingerprint %s", status.Message, status.Fingerprint))
+	}
+
+	if keyBackup.Spec.Schedule != "" {
+		_, next, _ := keyBackupDue(keyBackup, time.Now())
+		return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// keyBackupNode returns the node of a key backup, or nil when it is gone
+func (r *AxelarNodeKeyBackupReconciler) keyBackupNode(ctx context.Context, keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup) *blockchainv1alpha1.AxelarNode {
+	axelarNode := &blockchainv1alpha1.AxelarNode{}
+	if err := r.Get(ctx, types.NamespacedName{Name: keyBackup.Spec.NodeRef.Name, Namespace: keyBackup.Namespace}, axelarNode); err != nil {
+		return nil
+	}
+	return axelarNode
+}
+
+// failKeyBackup records a failed backup, and alerts through the alert
+// channels of the node when it is known
+func (r *AxelarNodeKeyBackupReconciler) failKeyBackup(ctx context.Context, keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup, axelarNode *blockchainv1alpha1.AxelarNode, message string) {
+	status := &keyBackup.Status
+	status.Phase = blockchainv1alpha1.KeyBackupFailed
+	status.Message = message
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionKeysEscrowed,
+		Status:             metav1.ConditionFalse,
+		Reason:             "BackupFailed",
+		Message:            message,
+		ObservedGeneration: keyBackup.Generation,
+	})
+	r.Log.Info("Key backup failed", "axelarnodekeybackup", keyBackup.Name, "reason", message)
+	if r.Recorder != nil {
+		r.Recorder.Event(keyBackup, corev1.EventTypeWarning, "KeyBackupFailed", message)
+	}
+	if axelarNode != nil {
+		text := fmt.Sprintf(":warning: Key backup %s/%s of %s failed: %s", keyBackup.Namespace, keyBackup.Name, axelarNode.Name, message)
+		if err := alert.Send(ctx, axelarNode.Spec.Monitoring.Alerts, text); err != nil {
+			r.Log.Error(err, "Unable to send key backup alert", "axelarnodekeybackup", keyBackup.Name)
+		}
+	}
+}
+
+// escrowKeys returns the keys a backup escrows
+func escrowKeys(keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup) []string {
+	if len(keyBackup.Spec.Keys) > 0 {
+		return keyBackup.Spec.Keys
+	}
+	return []string{blockchainv1alpha1.EscrowKeyTofnd, blockchainv1alpha1.EscrowKeyBroadcaster, blockchainv1alpha1.EscrowKeyNode}
+}
+
+// broadcasterKeyring returns the keyring backend holding the broadcaster key
+func broadcasterKeyring(axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if backend := valdSpec(axelarNode).KeyringBackend; backend != "" {
+		return backend
+	}
+	return migratedKeyringBackend(axelarNode)
+}
+
+// escrowPaths returns the key files of the node, relative to the home of axelard
+func escrowPaths(keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup, axelarNode *blockchainv1alpha1.AxelarNode) []string {
+	var paths []string
+	for _, key := range escrowKeys(keyBackup) {
+		switch key {
+		case blockchainv1alpha1.EscrowKeyTofnd:
+			paths = append(paths, "shared/tofnd.txt")
+		case blockchainv1alpha1.EscrowKeyBroadcaster:
+			paths = append(paths, ".axelar/keyring-"+broadcasterKeyring(axelarNode))
+		case blockchainv1alpha1.EscrowKeyNode:
+			paths = append(paths, ".axelar/config/node_key.json", ".axelar/config/priv_validator_key.json")
+		}
+	}
+	return paths
+}
+
+// keyBackupRefusal explains why the keys of the node cannot be escrowed, or
+// returns an empty string
+func keyBackupRefusal(keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup, axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if refusal := encryptionProblem(&keyBackup.Spec.Encryption); refusal != "" {
+		return refusal
+	}
+	if keyBackup.Spec.Destination.BucketURL == "" {
+		return "destination.bucketURL is required"
+	}
+	for _, key := range escrowKeys(keyBackup) {
+		switch key {
+		case blockchainv1alpha1.EscrowKeyTofnd:
+			if cluster := tofndClusterName(axelarNode); cluster != "" {
+				return fmt.Sprintf("the tofnd mnemonic of %s is held by TofndCluster %s, back it up with its spec.backup or drop tofnd from keys", axelarNode.Name, cluster)
+			}
+			if sharedVolumeType(axelarNode) != blockchainv1alpha1.SharedVolumePVC {
+				return fmt.Sprintf("the tofnd mnemonic of %s is not kept on a shared PVC, set spec.storage.shared.type to pvc or drop tofnd from keys", axelarNode.Name)
+			}
+		case blockchainv1alpha1.EscrowKeyBroadcaster:
+			if broadcasterKeyring(axelarNode) == "os" {
+				return fmt.Sprintf("the broadcaster key of %s is in the os keyring, which is not on a volume", axelarNode.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// createKeyBackupJob returns the Job escrowing the keys of the node. The
+// volumes of the node are mounted read-only, and the Job runs next to the
+// node pod so ReadWriteOnce volumes can be attached to both.
+func (r *AxelarNodeKeyBackupReconciler) createKeyBackupJob(ctx context.Context, keyBackup *blockchainv1alpha1.AxelarNodeKeyBackup, axelarNode *blockchainv1alpha1.AxelarNode, now time.Time) (*batchv1.Job, error) {
+	spec := keyBackup.Spec
+	object := path.Join(spec.Destination.Prefix, keyBackup.Namespace, axelarNode.Name,
+		fmt.Sprintf("keys-%s.tar.gz%s", now.UTC().Format("20060102-150405"), backup.Extension))
+	args := []string{"key-escrow", "--bucket-url=" + spec.Destination.BucketURL, "--object=" + object}
+	if age := spec.Encryption.Age; age != nil {
+		args = append(args, "--age-recipient="+age.Recipient)
+	}
+	if kms := spec.Encryption.KMS; kms != nil {
+		args = append(args, "--kms-key-url="+kms.KeyURL)
+	}
+	args = append(args, escrowPaths(keyBackup, axelarNode)...)
+
+	container := corev1.Container{
+		Name:    "key-escrow",
+		Image:   r.ToolsImage,
+		Command: []string{"/root/manager"},
+		Args:    args,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: "/home/axelard/.axelar", ReadOnly: true},
+		},
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Volumes: []corev1.Volume{
+			{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: dataClaimName(axelarNode, activeSlot(axelarNode)),
+						ReadOnly:  true,
+					},
+				},
+			},
+		},
+		SecurityContext:    axelarNode.Spec.Security.PodSecurityContext,
+		ServiceAccountName: spec.Destination.ServiceAccountName,
+	}
+	if sharedVolumeType(axelarNode) == blockchainv1alpha1.SharedVolumePVC {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "shared", MountPath: "/home/axelard/shared", ReadOnly: true})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "shared",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: childName(axelarNode, "shared"), ReadOnly: true},
+			},
+		})
+	}
+	for _, secret := range []string{spec.Destination.CredentialsSecret, kmsCredentialsSecret(&spec.Encryption)} {
+		if secret != "" {
+			container.EnvFrom = append(container.EnvFrom, corev1.EnvFromSource{
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret}},
+			})
+		}
+	}
+	if podSpec.ServiceAccountName == "" && spec.Encryption.KMS != nil {
+		podSpec.ServiceAccountName = spec.Encryption.KMS.ServiceAccountName
+	}
+	podSpec.Containers = []corev1.Container{container}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(axelarNode.Namespace), client.MatchingLabels(nodeSelector(axelarNode))); err != nil {
+		return nil, err
+	}
+	if len(pods.Items) > 0 {
+		podSpec.Affinity = &corev1.Affinity{
+			PodAffinity: &corev1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: nodeSelector(axelarNode)},
+					TopologyKey:   corev1.LabelHostname,
+				}},
+			},
+		}
+	}
+
+	backoffLimit := int32(0)
+	ttl := int32(7 * 24 * 60 * 60)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.Name(keyBackup.Name, fmt.Sprint(now.Unix())),
+			Namespace: keyBackup.Namespace,
+			Labels:    map[string]string{keyBackupNodeLabel: naming.LabelValue(axelarNode.Name)},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{keyBackupNodeLabel: naming.LabelValue(axelarNode.Name)}},
+				Spec:       podSpec,
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(keyBackup, job, r.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// kmsCredentialsSecret returns the Secret holding the KMS credentials, if any
+func kmsCredentialsSecret(enc *blockchainv1alpha1.BackupEncryptionSpec) string {
+	if enc.KMS == nil {
+		return ""
+	}
+	return enc.KMS.CredentialsSecret
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *AxelarNodeKeyBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&blockchainv1alpha1.AxelarNodeKeyBackup{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
@@ -0,0 +1,496 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tenancy"
+)
+
+// fleetAppName is the app.kubernetes.io/name of the objects generated for fleets
+const fleetAppName = "axelar-rpc-fleet"
+
+// componentRPC is the component of the replicas of a fleet
+const componentRPC = "rpc"
+
+// AxelarRPCFleetReconciler reconciles an AxelarRPCFleet object
+type AxelarRPCFleetReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	// ProxyImage is the image of the RPC proxy sidecar
+	ProxyImage string
+}
+
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarrpcfleets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarrpcfleets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarrpcfleets/scale,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile handles AxelarRPCFleet reconciliation
+func (r *AxelarRPCFleetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("axelarrpcfleet", req.NamespacedName)
+
+	// Fetch the AxelarRPCFleet instance
+	fleet := &blockchainv1alpha1.AxelarRPCFleet{}
+	err := r.Get(ctx, req.NamespacedName, fleet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("AxelarRPCFleet resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get AxelarRPCFleet")
+		return ctrl.Result{}, err
+	}
+	ctx = audit.WithActor(ctx, fleet)
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(fleet))
+	ctx = naming.WithInstance(ctx, fleetInstance(fleet))
+
+	// The replicas share the rendering of an observer node
+	node := fleetNode(fleet)
+	renderer := &AxelarNodeReconciler{Client: r.Client, Scheme: r.Scheme, Log: r.Log}
+
+	data, err := renderConfig(node)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileConfigMap(ctx, fleet, renderer.configVersion(node), data); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileSecret(ctx, fleet); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileServices(ctx, fleet); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Each replica gets its own copy of the additional volumes
+	podSpec := renderer.createPodSpec(node)
+	renderer.ephemeralVolumes(node, &podSpec)
+	statefulSet, err := r.reconcileStatefulSet(ctx, fleet, podSpec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileAutoscaler(ctx, fleet); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	fleet.Status.Replicas = statefulSet.Status.Replicas
+	fleet.Status.ReadyReplicas = statefulSet.Status.ReadyReplicas
+	fleet.Status.Selector = labels.SelectorFromSet(statefulSet.Spec.Selector.MatchLabels).String()
+
+	available := metav1.Condition{
+		Type:               "Available",
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoSyncedReplicas",
+		Message:            "No replica is synced",
+		ObservedGeneration: fleet.Generation,
+	}
+	if fleet.Status.ReadyReplicas > 0 {
+		available.Status = metav1.ConditionTrue
+		available.Reason = "SyncedReplicas"
+		available.Message = fmt.Sprintf("%d of %d replicas are synced and serving", fleet.Status.ReadyReplicas, fleet.Status.Replicas)
+	}
+	meta.SetStatusCondition(&fleet.Status.Conditions, available)
+
+	if err := r.Status().Update(ctx, fleet); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+}
+
+// fleetNode returns the observer AxelarNode every replica of the fleet runs as
+func fleetNode(fleet *blockchainv1alpha1.AxelarRPCFleet) *blockchainv1alpha1.AxelarNode {
+	return &blockchainv1alpha1.AxelarNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fleet.Name,
+			Namespace: fleet.Namespace,
+		},
+		Spec: blockchainv1alpha1.AxelarNodeSpec{
+			NodeType:   "observer",
+			Network:    fleet.Spec.Network,
+			Moniker:    fleet.Name,
+			Image:      fleet.Spec.Image,
+			Resources:  fleet.Spec.Resources,
+			Storage:    fleet.Spec.Storage,
+			Pruning:    fleet.Spec.Pruning,
+			Networking: fleet.Spec.Networking,
+			Monitoring: fleet.Spec.Monitoring,
+		},
+	}
+}
+
+// fleetInstance describes the fleet to the client labelling the objects
+// generated for it
+func fleetInstance(fleet *blockchainv1alpha1.AxelarRPCFleet) naming.Instance {
+	return naming.Instance{
+		Owner:     fleet.UID,
+		Name:      fleetAppName,
+		Instance:  fleet.Name,
+		Component: componentRPC,
+		Version:   fleet.Spec.Image.Tag,
+	}
+}
+
+// fleetSelector selects the pods of the fleet by their recommended labels.
+// It never includes the version, which changes on upgrades.
+func fleetSelector(fleet *blockchainv1alpha1.AxelarRPCFleet) map[string]string {
+	return map[string]string{
+		blockchainv1alpha1.NameLabel:      fleetAppName,
+		blockchainv1alpha1.InstanceLabel:  naming.LabelValue(fleet.Name),
+		blockchainv1alpha1.ComponentLabel: componentRPC,
+	}
+}
+
+// statefulSetSelector returns the labels selecting the pods of the fleet, for
+// the Services in front of them. Selectors are immutable, so an existing
+// StatefulSet keeps the one it was created with.
+func (r *AxelarRPCFleetReconciler) statefulSetSelector(ctx context.Context, fleet *blockchainv1alpha1.AxelarRPCFleet) (map[string]string, error) {
+	found := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Name: fleet.Name, Namespace: fleet.Namespace}, found)
+	if errors.IsNotFound(err) {
+		return fleetSelector(fleet), nil
+	} else if err != nil {
+		return nil, err
+	}
+	if found.Spec.Selector == nil || len(found.Spec.Selector.MatchLabels) == 0 {
+		return fleetSelector(fleet), nil
+	}
+	return found.Spec.Selector.MatchLabels, nil
+}
+
+// fleetLabels returns the labels of the pods of the fleet. The app and fleet
+// labels are kept, since StatefulSets created before the recommended labels
+// select them.
+func fleetLabels(fleet *blockchainv1alpha1.AxelarRPCFleet) map[string]string {
+	labels := fleetInstance(fleet).Labels(componentRPC)
+	labels["app"] = naming.LabelValue(fleet.Name)
+	labels[blockchainv1alpha1.FleetLabel] = naming.LabelValue(fleet.Name)
+	return labels
+}
+
+// reconcileConfigMap creates the configuration version shared by the
+// replicas. The version the StatefulSet runs is kept until it is rolled.
+func (r *AxelarRPCFleetReconciler) reconcileConfigMap(ctx context.Context, fleet *blockchainv1alpha1.AxelarRPCFleet, version string, data map[string]string) error {
+	keep := []string{}
+	found := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Name: fleet.Name, Namespace: fleet.Namespace}, found)
+	if err == nil {
+		for _, volume := range found.Spec.Template.Spec.Volumes {
+			if volume.Name == "config" && volume.ConfigMap != nil {
+				keep = append(keep, strings.TrimPrefix(volume.ConfigMap.Name, fleet.Name+"-config-"))
+			}
+		}
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+	return reconcileConfigVersion(ctx, r.Client, r.Scheme, fleet, version, data, defaultConfigHistoryLimit, keep...)
+}
+
+// reconcileSecret creates the keyring secret of the replicas, and adds the
+// keys it manages to an existing one
+func (r *AxelarRPCFleetReconciler) reconcileSecret(ctx context.Context, fleet *blockchainv1alpha1.AxelarRPCFleet) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.Name(fleet.Name, "secrets"),
+			Namespace: fleet.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"keyring-password": []byte("default-password-change-me"),
+		},
+	}
+
+	setManagedKeys(secret, map[string]bool{"keyring-password": true})
+	if err := controllerutil.SetControllerReference(fleet, secret, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, secret)
+	} else if err != nil {
+		return err
+	}
+	if !mergeSecretData(found, secret.Data) {
+		return nil
+	}
+	return r.Update(ctx, found)
+}
+
+// reconcileServices creates the headless Service governing the StatefulSet
+// and the client Service, which only routes to synced replicas
+func (r *AxelarRPCFleetReconciler) reconcileServices(ctx context.Context, fleet *blockchainv1alpha1.AxelarRPCFleet) error {
+	selector, err := r.statefulSetSelector(ctx, fleet)
+	if err != nil {
+		return err
+	}
+	rpcTarget, prometheusTarget := fleet.Spec.Networking.RPC.Port, fleet.Spec.Monitoring.Prometheus.Port
+	if fleetProxied(fleet) {
+		rpcTarget, prometheusTarget = rpcProxyPort, rpcProxyMetricsPort
+	}
+
+	ports := []corev1.ServicePort{
+		{
+			Name:       "rpc",
+			Port:       fleet.Spec.Networking.RPC.Port,
+			TargetPort: intstr.FromInt(int(rpcTarget)),
+		},
+		{
+			Name:       "api",
+			Port:       fleet.Spec.Networking.API.Port,
+			TargetPort: intstr.FromInt(int(fleet.Spec.Networking.API.Port)),
+		},
+	}
+	if metricsScraped(fleet.Spec.Monitoring, fleetProxied(fleet)) {
+		ports = append(ports, corev1.ServicePort{
+			Name:       "prometheus",
+			Port:       fleet.Spec.Monitoring.Prometheus.Port,
+			TargetPort: intstr.FromInt(int(prometheusTarget)),
+		})
+	}
+
+	headless := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.Name(fleet.Name, "headless"),
+			Namespace: fleet.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Selector:                 selector,
+			Ports:                    ports,
+		},
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.Name(fleet.Name, "service"),
+			Namespace: fleet.Namespace,
+			Annotations: prometheusAnnotations(fleet.Spec.Monitoring, fleetProxied(fleet),
+				fleet.Spec.Monitoring.Prometheus.Port),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports:    ports,
+		},
+	}
+
+	for _, svc := range []*corev1.Service{headless, service} {
+		if err := controllerutil.SetControllerReference(fleet, svc, r.Scheme); err != nil {
+			return err
+		}
+
+		found := &corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, found)
+		if err != nil && errors.IsNotFound(err) {
+			if err := r.Create(ctx, svc); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		found.Spec.Ports = svc.Spec.Ports
+		found.Annotations = svc.Annotations
+		if err := r.Update(ctx, found); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileStatefulSet creates or updates the StatefulSet running the replicas
+func (r *AxelarRPCFleetReconciler) reconcileStatefulSet(ctx context.Context, fleet *blockchainv1alpha1.AxelarRPCFleet, podSpec corev1.PodSpec) (*appsv1.StatefulSet, error) {
+	statefulSet, err := r.createStatefulSet(fleet, podSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := controllerutil.SetControllerReference(fleet, statefulSet, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	found := &appsv1.StatefulSet{}
+	err = r.Get(ctx, types.NamespacedName{Name: statefulSet.Name, Namespace: statefulSet.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return statefulSet, r.Create(ctx, statefulSet)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if *found.Spec.Replicas != *statefulSet.Spec.Replicas ||
+		found.Annotations[templateHashAnnotation] != statefulSet.Annotations[templateHashAnnotation] {
+		if found.Annotations == nil {
+			found.Annotations = map[string]string{}
+		}
+		found.Annotations[templateHashAnnotation] = statefulSet.Annotations[templateHashAnnotation]
+		found.Spec.Replicas = statefulSet.Spec.Replicas
+		found.Spec.Template = statefulSet.Spec.Template
+		if err := r.Update(ctx, found); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// createStatefulSet creates a StatefulSet object with a data volume per replica
+func (r *AxelarRPCFleetReconciler) createStatefulSet(fleet *blockchainv1alpha1.AxelarRPCFleet, podSpec corev1.PodSpec) (*appsv1.StatefulSet, error) {
+	replicas := int32(2)
+	if fleet.Spec.Replicas != nil {
+		replicas = *fleet.Spec.Replicas
+	}
+
+	// The data volume comes from the claim template and nothing is shared
+	volumes := []corev1.Volume{}
+	for _, volume := range podSpec.Volumes {
+		switch volume.Name {
+		case "data":
+			continue
+		case "shared":
+			volume.VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		}
+		volumes = append(volumes, volume)
+	}
+	podSpec.Volumes = volumes
+	podSpec.Containers[0].ReadinessProbe = syncedReadinessProbe(fleet.Spec.Networking.RPC.Port)
+
+	prometheusPort := fleet.Spec.Monitoring.Prometheus.Port
+	if fleetProxied(fleet) {
+		addRPCProxy(&podSpec, r.ProxyImage, fleet.Spec.Networking.RPC.Port, fleet.Spec.Monitoring)
+		prometheusPort = rpcProxyMetricsPort
+	}
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      fleetLabels(fleet),
+			Annotations: prometheusAnnotations(fleet.Spec.Monitoring, fleetProxied(fleet), prometheusPort),
+		},
+		Spec: podSpec,
+	}
+	if tenant := tenancy.Of(fleet); tenant != "" {
+		template.Labels[blockchainv1alpha1.TenantLabel] = tenant
+	}
+	hash, err := templateHash(template)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(fleet.Spec.Storage.Size),
+				},
+			},
+		},
+	}
+	if fleet.Spec.Storage.StorageClass != "" {
+		claim.Spec.StorageClassName = &fleet.Spec.Storage.StorageClass
+	}
+
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fleet.Name,
+			Namespace:   fleet.Namespace,
+			Annotations: map[string]string{templateHashAnnotation: hash},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:            &replicas,
+			ServiceName:         naming.Name(fleet.Name, "headless"),
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: fleetSelector(fleet),
+			},
+			Template:             template,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim},
+		},
+	}, nil
+}
+
+// reconcileAutoscaler manages the fleet HPA, which scales the fleet through
+// its scale subresource
+func (r *AxelarRPCFleetReconciler) reconcileAutoscaler(ctx context.Context, fleet *blockchainv1alpha1.AxelarRPCFleet) error {
+	return reconcileAutoscaler(ctx, r.Client, r.Scheme, fleet, autoscalingv2.CrossVersionObjectReference{
+		APIVersion: blockchainv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "AxelarRPCFleet",
+		Name:       fleet.Name,
+	}, fleet.Spec.Autoscaling)
+}
+
+// fleetProxied reports whether the fleet runs the RPC proxy for its autoscaling metrics
+func fleetProxied(fleet *blockchainv1alpha1.AxelarRPCFleet) bool {
+	return fleet.Spec.Autoscaling != nil && fleet.Spec.Autoscaling.Enabled
+}
+
+// syncedReadinessProbe only passes while the node reports it is not catching up
+func syncedReadinessProbe(rpcPort int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sh", "-c", fmt.Sprintf(
+					`wget -qO- http://127.0.0.1:%d/status | grep -Eq '"catching_up": ?false'`, rpcPort)},
+			},
+		},
+		InitialDelaySeconds: 60,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      5,
+		SuccessThreshold:    1,
+		FailureThreshold:    3,
+	}
+}
+
+// templateHash returns a stable hash of a pod template
+func templateHash(template corev1.PodTemplateSpec) (string, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *AxelarRPCFleetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&blockchainv1alpha1.AxelarRPCFleet{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Complete(r)
+}
@@ -0,0 +1,393 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/cosmos"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tenancy"
+)
+
+// onboardingCheckInterval is how often the steps of an onboarding in progress are checked
+const onboardingCheckInterval = 30 * time.Second
+
+// onboardingRetryInterval is how long a failed onboarding transaction waits before it is sent again
+const onboardingRetryInterval = time.Hour
+
+// Default minimum balances, in uaxl
+const (
+	defaultMinOperatorBalance    = 1000000
+	defaultMinBroadcasterBalance = 5000000
+)
+
+// registerProxyScript registers the broadcaster of the validator
+const registerProxyScript = `tx snapshot register-proxy "$BROADCASTER"
+`
+
+// registerMaintainerScript registers the validator as a maintainer of CHAINS
+const registerMaintainerScript = `tx nexus register-chain-maintainer $CHAINS
+`
+
+// AxelarValidatorOnboardingReconciler reconciles an AxelarValidatorOnboarding object
+type AxelarValidatorOnboardingReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	// Recorder emits events on onboardings
+	Recorder record.EventRecorder
+
+	// RESTConfig reaches the Kubernetes API for the Exec status source
+	RESTConfig *rest.Config
+
+	// APIReader reads the status source credentials, which the manager does
+	// not cache
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarvalidatoronboardings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=axelarvalidatoronboardings/status,verbs=get;update;patch
+
+// stepResult is the state of an onboarding step
+type stepResult struct {
+	done    bool
+	reason  string
+	message string
+}
+
+// Reconcile checks the onboarding steps in order, sending the transactions
+// of the first step not completed yet, and reports each step as a condition
+func (r *AxelarValidatorOnboardingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("axelarvalidatoronboarding", req.NamespacedName)
+
+	onboarding := &blockchainv1alpha1.AxelarValidatorOnboarding{}
+	if err := r.Get(ctx, req.NamespacedName, onboarding); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("AxelarValidatorOnboarding resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get AxelarValidatorOnboarding")
+		return ctrl.Result{}, err
+	}
+	ctx = audit.WithActor(ctx, onboarding)
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(onboarding))
+	ctx = naming.WithInstance(ctx, naming.Instance{Owner: onboarding.UID, Name: "axelar-validator-onboarding", Instance: onboarding.Name, Component: "onboarding"})
+
+	axelarNode := &blockchainv1alpha1.AxelarNode{}
+	err := r.Get(ctx, types.NamespacedName{Name: onboarding.Spec.NodeRef.Name, Namespace: onboarding.Namespace}, axelarNode)
+	if apierrors.IsNotFound(err) {
+		axelarNode = nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := &onboarding.Status
+	status.Step = ""
+	previous := ""
+	for _, step := range blockchainv1alpha1.OnboardingSteps {
+		condition := metav1.Condition{
+			Type:               step,
+			Status:             metav1.ConditionUnknown,
+			Reason:             "Waiting",
+			Message:            "Waiting for " + previous,
+			ObservedGeneration: onboarding.Generation,
+		}
+		if status.Step == "" {
+			result, err := r.checkStep(ctx, onboarding, axelarNode, step)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = result.reason
+			condition.Message = result.message
+			if result.done {
+				condition.Status = metav1.ConditionTrue
+				if !meta.IsStatusConditionTrue(status.Conditions, step) {
+					log.Info("Onboarding step completed", "step", step, "message", result.message)
+					if r.Recorder != nil {
+						r.Recorder.Event(onboarding, corev1.EventTypeNormal, step, result.message)
+					}
+				}
+			} else {
+				status.Step = step
+			}
+		}
+		meta.SetStatusCondition(&status.Conditions, condition)
+		previous = step
+	}
+
+	result := ctrl.Result{}
+	if status.Step == "" {
+		status.Phase = blockchainv1alpha1.OnboardingCompleted
+		if status.CompletedAt == nil {
+			status.CompletedAt = &metav1.Time{Time: time.Now()}
+			log.Info("Validator onboarded", "axelarnode", onboarding.Spec.NodeRef.Name)
+		}
+	} else {
+		status.Phase = blockchainv1alpha1.OnboardingInProgress
+		result.RequeueAfter = onboardingCheckInterval
+	}
+	if err := r.Status().Update(ctx, onboarding); err != nil {
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// onboardingOperator returns the operator address of the validator onboarded
+func onboardingOperator(onboarding *blockchainv1alpha1.AxelarValidatorOnboarding, axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if onboarding.Spec.OperatorAddress != "" {
+		return onboarding.Spec.OperatorAddress
+	}
+	if profile := axelarNode.Spec.Validator.Profile; profile != nil {
+		return profile.OperatorAddress
+	}
+	return ""
+}
+
+// onboardingBroadcaster returns the broadcaster address of the validator onboarded
+func onboardingBroadcaster(onboarding *blockchainv1alpha1.AxelarValidatorOnboarding, axelarNode *blockchainv1alpha1.AxelarNode) string {
+	if onboarding.Spec.BroadcasterAddress != "" {
+		return onboarding.Spec.BroadcasterAddress
+	}
+	if heartbeat := axelarNode.Spec.Validator.Heartbeat; heartbeat != nil {
+		return heartbeat.BroadcasterAddress
+	}
+	return ""
+}
+
+// minBalance parses a minimum balance of the spec, in uaxl
+func minBalance(value string, fallback int64) int64 {
+	if amount, err := strconv.ParseInt(value, 10, 64); err == nil && value != "" {
+		return amount
+	}
+	return fallback
+}
+
+// notFound reports whether a REST query failed because the object does not exist
+func notFound(err error) bool {
+	var httpErr *cosmos.HTTPError
+	return errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusNotFound || httpErr.StatusCode == http.StatusBadRequest)
+}
+
+// checkStep returns the state of a step, sending its transaction when it is
+// one the operator takes
+func (r *AxelarValidatorOnboardingReconciler) checkStep(ctx context.Context, onboarding *blockchainv1alpha1.AxelarValidatorOnboarding, axelarNode *blockchainv1alpha1.AxelarNode, step string) (stepResult, error) {
+	if axelarNode == nil {
+		return stepResult{reason: "NodeNotFound", message: fmt.Sprintf("AxelarNode %s not found", onboarding.Spec.NodeRef.Name)}, nil
+	}
+	if !isValidatorNode(axelarNode) {
+		return stepResult{reason: "NotValidator", message: fmt.Sprintf("Set spec.validator.enabled on AxelarNode %s", axelarNode.Name)}, nil
+	}
+	operator := onboardingOperator(onboarding, axelarNode)
+	broadcaster := onboardingBroadcaster(onboarding, axelarNode)
+	queryFailed := func(err error) (stepResult, error) {
+		return stepResult{reason: "QueryFailed", message: err.Error()}, nil
+	}
+	_, api, err := nodeStatusClients(ctx, r.Client, r.APIReader, r.RESTConfig, axelarNode)
+	if err != nil {
+		return queryFailed(err)
+	}
+
+	switch step {
+	case blockchainv1alpha1.OnboardingNodeSynced:
+		if !nodeSynced(axelarNode) {
+			return stepResult{reason: "Syncing", message: fmt.Sprintf("The node is syncing, at height %d", axelarNode.Status.SyncInfo.CurrentHeight)}, nil
+		}
+		return stepResult{done: true, reason: "Synced", message: fmt.Sprintf("The node is synced at height %d", axelarNode.Status.SyncInfo.CurrentHeight)}, nil
+
+	case blockchainv1alpha1.OnboardingFunded:
+		if operator == "" || broadcaster == "" {
+			return stepResult{reason: "MissingAddress", message: "Set spec.operatorAddress and spec.broadcasterAddress"}, nil
+		}
+		account, err := cosmos.AccountAddress(operator)
+		if err != nil {
+			return stepResult{reason: "InvalidAddress", message: fmt.Sprintf("spec.operatorAddress: %v", err)}, nil
+		}
+		var short []string
+		for _, funded := range []struct {
+			name, address string
+			min           int64
+		}{
+			{"operator", account, minBalance(onboarding.Spec.MinOperatorBalance, defaultMinOperatorBalance)},
+			{"broadcaster", broadcaster, minBalance(onboarding.Spec.MinBroadcasterBalance, defaultMinBroadcasterBalance)},
+		} {
+			balance, err := api.Balance(ctx, funded.address, "uaxl")
+			if err != nil {
+				return queryFailed(err)
+			}
+			if balance < funded.min {
+				short = append(short, fmt.Sprintf("the %s account %s holds %duaxl of the %duaxl needed", funded.name, funded.address, balance, funded.min))
+			}
+		}
+		if len(short) > 0 {
+			return stepResult{reason: "Underfunded", message: "Fund " + strings.Join(short, "; ")}, nil
+		}
+		return stepResult{done: true, reason: "Funded", message: "The operator and broadcaster accounts are funded"}, nil
+
+	case blockchainv1alpha1.OnboardingTofndKeysGenerated:
+		if !meta.IsStatusConditionTrue(axelarNode.Status.Conditions, blockchainv1alpha1.ConditionTofndHealthy) {
+			return stepResult{reason: "TofndNotReady", message: "Waiting for tofnd to generate its keys and serve vald"}, nil
+		}
+		return stepResult{done: true, reason: "TofndReady", message: "tofnd has its keys and serves vald"}, nil
+
+	case blockchainv1alpha1.OnboardingValidatorCreated:
+		if _, err := api.Validator(ctx, operator); notFound(err) {
+			return stepResult{reason: "ManualStep", message: fmt.Sprintf(
+				"Create %s with axelard tx staking create-validator, using the consensus key of the node from axelard tendermint show-validator", operator)}, nil
+		} else if err != nil {
+			return queryFailed(err)
+		}
+		return stepResult{done: true, reason: "Created", message: fmt.Sprintf("Validator %s exists", operator)}, nil
+
+	case blockchainv1alpha1.OnboardingProxyRegistered:
+		proxy, active, err := api.Proxy(ctx, operator)
+		if err != nil {
+			return queryFailed(err)
+		}
+		switch {
+		case proxy == broadcaster && active:
+			return stepResult{done: true, reason: "Registered", message: fmt.Sprintf("%s is the active broadcaster of %s", broadcaster, operator)}, nil
+		case proxy != "" && proxy != broadcaster:
+			return stepResult{reason: "ProxyMismatch", message: fmt.Sprintf("%s is registered as the broadcaster of %s, not %s", proxy, operator, broadcaster)}, nil
+		case proxy != "":
+			return stepResult{reason: "ProxyInactive", message: fmt.Sprintf("The broadcaster %s is registered but inactive", broadcaster)}, nil
+		}
+		key := txKeySecret(axelarNode)
+		if ref := onboarding.Spec.OperatorKeySecretRef; ref != nil {
+			key = *ref
+		}
+		return r.runTxStep(ctx, onboarding, axelarNode, step, "register-proxy", registerProxyScript, key,
+			corev1.EnvVar{Name: "BROADCASTER", Value: broadcaster})
+
+	case blockchainv1alpha1.OnboardingMaintainersRegistered:
+		var missing []string
+		for _, chain := range onboarding.Spec.Chains {
+			maintainers, err := api.ChainMaintainers(ctx, chain)
+			if err != nil {
+				return queryFailed(err)
+			}
+			if !containsString(maintainers, operator) {
+				missing = append(missing, chain)
+			}
+		}
+		if len(missing) == 0 {
+			return stepResult{done: true, reason: "Registered", message: fmt.Sprintf("%s maintains %d chains", operator, len(onboarding.Spec.Chains))}, nil
+		}
+		if onboarding.Spec.BroadcasterKeySecretRef == nil {
+			return stepResult{reason: "ManualStep", message: fmt.Sprintf(
+				"Register with axelard tx nexus register-chain-maintainer %s --from broadcaster", strings.Join(missing, " "))}, nil
+		}
+		return r.runTxStep(ctx, onboarding, axelarNode, step, "register-chain-maintainer", registerMaintainerScript,
+			*onboarding.Spec.BroadcasterKeySecretRef, corev1.EnvVar{Name: "CHAINS", Value: strings.Join(missing, " ")})
+	}
+	return stepResult{reason: "UnknownStep", message: step}, nil
+}
+
+// runTxStep sends the transaction of a step with a transaction Job, and
+// reports the Job until the chain reflects the transaction
+func (r *AxelarValidatorOnboardingReconciler) runTxStep(ctx context.Context, onboarding *blockchainv1alpha1.AxelarValidatorOnboarding, axelarNode *blockchainv1alpha1.AxelarNode,
+	step, name, script string, key corev1.SecretKeySelector, env ...corev1.EnvVar) (stepResult, error) {
+	jobName := naming.Name(onboarding.Name, name)
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: onboarding.Namespace}, job)
+	if apierrors.IsNotFound(err) {
+		podSpec := txPodSpec(axelarNode, name, script, key)
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, env...)
+		backoffLimit := int32(0)
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: onboarding.Namespace},
+			Spec: batchv1.JobSpec{
+				BackoffLimit: &backoffLimit,
+				Template:     corev1.PodTemplateSpec{Spec: podSpec},
+			},
+		}
+		if err := controllerutil.SetControllerReference(onboarding, job, r.Scheme); err != nil {
+			return stepResult{}, err
+		}
+		r.Log.Info("Sending onboarding transaction", "axelarvalidatoronboarding", onboarding.Name, "step", step, "job", jobName)
+		if err := r.Create(ctx, job); err != nil {
+			return stepResult{}, err
+		}
+		onboarding.Status.Job = jobName
+		return stepResult{reason: "TxPending", message: "Sending " + name}, nil
+	} else if err != nil {
+		return stepResult{}, err
+	}
+	onboarding.Status.Job = jobName
+
+	if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		return stepResult{reason: "TxPending", message: "Sending " + name}, nil
+	}
+	reader := &AxelarNodeReconciler{Client: r.Client}
+	output, err := reader.txJobOutput(ctx, job)
+	if err != nil {
+		return stepResult{}, err
+	}
+	if job.Status.Succeeded > 0 {
+		if hashes := strings.Fields(output); len(hashes) > 0 {
+			if onboarding.Status.TxHashes == nil {
+				onboarding.Status.TxHashes = map[string]string{}
+			}
+			onboarding.Status.TxHashes[step] = hashes[len(hashes)-1]
+		}
+		// The next check reads the result from the chain, and a new Job is
+		// sent if the chain does not reflect it
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return stepResult{}, err
+		}
+		return stepResult{reason: "TxSent", message: fmt.Sprintf("Sent %s in %s", name, onboarding.Status.TxHashes[step])}, nil
+	}
+
+	if output == "" {
+		output = "the transaction Job failed, see its logs"
+	}
+	if job.Status.StartTime != nil && time.Since(job.Status.StartTime.Time) > onboardingRetryInterval {
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return stepResult{}, err
+		}
+	}
+	if condition := meta.FindStatusCondition(onboarding.Status.Conditions, step); r.Recorder != nil && (condition == nil || condition.Reason != "TxFailed") {
+		r.Recorder.Event(onboarding, corev1.EventTypeWarning, "OnboardingTxFailed", fmt.Sprintf("%s: %s", name, output))
+	}
+	return stepResult{reason: "TxFailed", message: fmt.Sprintf("%s failed, retried after an hour: %s", name, output)}, nil
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *AxelarValidatorOnboardingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&blockchainv1alpha1.AxelarValidatorOnboarding{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+)
+
+// configVersionLabel labels the configuration versions with the name of
+// their owner, as a label value
+const configVersionLabel = "blockchain.axelar.network/config"
+
+// defaultConfigHistoryLimit applies when the spec leaves the history limit unset
+const defaultConfigHistoryLimit = 5
+
+// configMapName names the ConfigMap holding a configuration version
+func configMapName(owner, version string) string {
+	return owner + "-config-" + version
+}
+
+// reconcileConfigVersion creates the immutable ConfigMap of a configuration
+// version of owner, then removes the oldest versions beyond limit. The
+// versions listed in keep, such as those still mounted by running pods, are
+// never removed.
+func reconcileConfigVersion(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object,
+	version string, data map[string]string, limit int, keep ...string) error {
+	name := configMapName(owner.GetName(), version)
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: owner.GetNamespace()}, &corev1.ConfigMap{})
+	if errors.IsNotFound(err) {
+		configMap := configVersionMap(owner, version, data)
+		if err := controllerutil.SetControllerReference(owner, configMap, scheme); err != nil {
+			return err
+		}
+		if err := c.Create(ctx, configMap); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	versions := &corev1.ConfigMapList{}
+	if err := c.List(ctx, versions, client.InNamespace(owner.GetNamespace()), client.MatchingLabels{configVersionLabel: naming.LabelValue(owner.GetName())}); err != nil {
+		return err
+	}
+	retained := map[string]bool{name: true}
+	for _, version := range keep {
+		retained[configMapName(owner.GetName(), version)] = true
+	}
+
+	// Newest first
+	items := versions.Items
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreationTimestamp.Equal(&items[j].CreationTimestamp) {
+			return items[j].CreationTimestamp.Before(&items[i].CreationTimestamp)
+		}
+		return items[i].Name > items[j].Name
+	})
+	for i := range items {
+		configMap := &items[i]
+		if i < limit || retained[configMap.Name] || !metav1.IsControlledBy(configMap, owner) || configMap.DeletionTimestamp != nil {
+			continue
+		}
+		if err := c.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// configVersionExists returns whether a configuration version of owner is retained
+func configVersionExists(ctx context.Context, c client.Client, owner client.Object, version string) (bool, error) {
+	err := c.Get(ctx, types.NamespacedName{Name: configMapName(owner.GetName(), version), Namespace: owner.GetNamespace()}, &corev1.ConfigMap{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// configVersionMap creates the immutable ConfigMap object of a configuration
+// version of owner
+func configVersionMap(owner client.Object, version string, data map[string]string) *corev1.ConfigMap {
+	immutable := true
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName(owner.GetName(), version),
+			Namespace: owner.GetNamespace(),
+			Labels:    map[string]string{configVersionLabel: naming.LabelValue(owner.GetName())},
+		},
+		Data:      data,
+		Immutable: &immutable,
+	}
+}
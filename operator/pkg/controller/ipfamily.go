@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// serviceIPFamilies returns the IP families of the generated Services, the
+// primary first. Clusters default to IPv4 when the spec leaves them unset.
+func serviceIPFamilies(networking blockchainv1alpha1.NetworkingSpec) []corev1.IPFamily {
+	if len(networking.IPFamilies) == 0 {
+		return []corev1.IPFamily{corev1.IPv4Protocol}
+	}
+	return networking.IPFamilies
+}
+
+// ipv6Enabled reports whether the node serves IPv6 clients
+func ipv6Enabled(networking blockchainv1alpha1.NetworkingSpec) bool {
+	for _, family := range networking.IPFamilies {
+		if family == corev1.IPv6Protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// listenHost returns the wildcard address servers bind. The IPv6 wildcard
+// also accepts IPv4 connections, so it serves dual-stack pods too.
+func listenHost(networking blockchainv1alpha1.NetworkingSpec) string {
+	if ipv6Enabled(networking) {
+		return "[::]"
+	}
+	return "0.0.0.0"
+}
+
+// listenAddress returns the wildcard address of a port, such as 0.0.0.0:26657
+func listenAddress(networking blockchainv1alpha1.NetworkingSpec, port int32) string {
+	return fmt.Sprintf("%s:%d", listenHost(networking), port)
+}
+
+// applyIPFamilies sets the IP families of the spec on a Service. Unset
+// fields are left to the cluster defaults.
+func applyIPFamilies(networking blockchainv1alpha1.NetworkingSpec, spec *corev1.ServiceSpec) {
+	if len(networking.IPFamilies) > 0 {
+		spec.IPFamilies = networking.IPFamilies
+	}
+	if networking.IPFamilyPolicy != nil {
+		policy := *networking.IPFamilyPolicy
+		spec.IPFamilyPolicy = &policy
+	}
+}
+
+// podIP returns the address of a pod in an IP family, empty when it has none
+func podIP(pod *corev1.Pod, family corev1.IPFamily) string {
+	addresses := []string{pod.Status.PodIP}
+	for _, address := range pod.Status.PodIPs {
+		addresses = append(addresses, address.IP)
+	}
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			continue
+		}
+		if (ip.To4() != nil) == (family == corev1.IPv4Protocol) {
+			return address
+		}
+	}
+	return ""
+}
+
+// podURL returns the base URL of a port of a pod, bracketing IPv6 addresses
+func podURL(address string, port int32) string {
+	return "http://" + net.JoinHostPort(address, fmt.Sprintf("%d", port))
+}
+
+// nginxListen returns the listen directives of an nginx server, on IPv6 too
+// when the node serves it
+func nginxListen(networking blockchainv1alpha1.NetworkingSpec, port int32, params string) string {
+	directives := []string{fmt.Sprintf("listen %d %s;", port, params)}
+	if ipv6Enabled(networking) {
+		directives = append(directives, fmt.Sprintf("listen [::]:%d %s;", port, params))
+	}
+	return strings.Join(directives, "\n    ")
+}
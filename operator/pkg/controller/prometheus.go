@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"fmt"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Annotations asking Prometheus to scrape a pod or Service
+const (
+	prometheusScrapeAnnotation = "prometheus.io/scrape"
+	prometheusPortAnnotation   = "prometheus.io/port"
+	prometheusPathAnnotation   = "prometheus.io/path"
+)
+
+// metricsScraped reports whether the pods publish metrics for Prometheus: the
+// node metrics while monitoring is enabled, and the metrics of the RPC proxy,
+// which the HPA scales on, whenever the pods are proxied
+func metricsScraped(monitoring blockchainv1alpha1.MonitoringSpec, proxied bool) bool {
+	return monitoring.Enabled || proxied
+}
+
+// prometheusAnnotations returns the annotations asking Prometheus to scrape
+// port, none when the pods publish no metrics
+func prometheusAnnotations(monitoring blockchainv1alpha1.MonitoringSpec, proxied bool, port int32) map[string]string {
+	if !metricsScraped(monitoring, proxied) {
+		return nil
+	}
+	return map[string]string{
+		prometheusScrapeAnnotation: "true",
+		prometheusPortAnnotation:   fmt.Sprintf("%d", port),
+		prometheusPathAnnotation:   monitoring.Prometheus.Path,
+	}
+}
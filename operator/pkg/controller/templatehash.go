@@ -0,0 +1,6 @@
+package controller
+
+// templateHashAnnotation records the hash of the rendered pod template on the
+// Deployments and StatefulSets of the controllers, which are updated when it
+// changes
+const templateHashAnnotation = "blockchain.axelar.network/template-hash"
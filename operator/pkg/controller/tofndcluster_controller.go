@@ -0,0 +1,618 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/audit"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/backup"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/maintenance"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/naming"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tenancy"
+)
+
+// tofndClusterAppName is the app.kubernetes.io/name of the objects of a TofndCluster
+const tofndClusterAppName = "axelar-tofnd-cluster"
+
+// tofndHome is where the volume of a TofndCluster is mounted. The escrow
+// archives it relative to the home of axelard, like the keys of a node.
+const tofndHome = "/home/axelard/.tofnd"
+
+// TofndClusterReconciler reconciles a TofndCluster object
+type TofndClusterReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	// ToolsImage is the image running the backups
+	ToolsImage string
+
+	// Recorder emits events on tofnd clusters
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=tofndclusters,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=blockchain.axelar.network,resources=tofndclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile runs tofnd for the validators referencing the cluster, restricts
+// access to them and backs up its volume
+func (r *TofndClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("tofndcluster", req.NamespacedName)
+
+	cluster := &blockchainv1alpha1.TofndCluster{}
+	if err := r.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("TofndCluster resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get TofndCluster")
+		return ctrl.Result{}, err
+	}
+	ctx = audit.WithActor(ctx, cluster)
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(cluster))
+	ctx = naming.WithInstance(ctx, naming.Instance{Owner: cluster.UID, Name: tofndClusterAppName, Instance: cluster.Name, Component: "tofnd", Version: imageTag(tofndClusterImage(cluster))})
+
+	validators, err := r.clusterValidators(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileTofndService(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	statefulSet, err := r.reconcileTofndStatefulSet(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileTofndNetworkPolicy(ctx, cluster, validators); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := &cluster.Status
+	status.Endpoint = fmt.Sprintf("%s:%d", tofndClusterHost(cluster.Name, cluster.Namespace), tofndPort)
+	status.Validators = validators
+	ready := metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionTofndReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NotReady",
+		Message:            "tofnd is not ready",
+		ObservedGeneration: cluster.Generation,
+	}
+	status.Phase = blockchainv1alpha1.TofndClusterPending
+	if statefulSet.Status.ReadyReplicas > 0 {
+		status.Phase = blockchainv1alpha1.TofndClusterRunning
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = "Running"
+		ready.Message = fmt.Sprintf("tofnd is serving %d validators", len(validators))
+	}
+	meta.SetStatusCondition(&status.Conditions, ready)
+
+	result := ctrl.Result{RequeueAfter: 5 * time.Minute}
+	if cluster.Spec.Backup != nil {
+		var backupResult ctrl.Result
+		if status.Backup.Phase == blockchainv1alpha1.KeyBackupRunning {
+			backupResult, err = r.followTofndBackup(ctx, cluster)
+		} else {
+			backupResult, err = r.startTofndBackup(ctx, cluster)
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if backupResult.RequeueAfter > 0 && backupResult.RequeueAfter < result.RequeueAfter {
+			result = backupResult
+		}
+	}
+
+	if err := r.Status().Update(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// tofndClusterHost returns the host vald dials the TofndCluster name on
+func tofndClusterHost(name, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc", name, namespace)
+}
+
+// tofndClusterImage returns the image tofnd runs in the cluster
+func tofndClusterImage(cluster *blockchainv1alpha1.TofndCluster) string {
+	if cluster.Spec.Image != "" {
+		return airgap.Image(cluster.Spec.Image)
+	}
+	return airgap.Image(defaultTofndImage)
+}
+
+// tofndClusterLabels returns the labels selecting the tofnd pod of the cluster
+func tofndClusterLabels(cluster *blockchainv1alpha1.TofndCluster) map[string]string {
+	return map[string]string{
+		blockchainv1alpha1.NameLabel:      tofndClusterAppName,
+		blockchainv1alpha1.InstanceLabel:  naming.LabelValue(cluster.Name),
+		blockchainv1alpha1.ComponentLabel: "tofnd",
+	}
+}
+
+// tofndClaimName returns the PVC the StatefulSet creates for the tofnd volume
+func tofndClaimName(cluster *blockchainv1alpha1.TofndCluster) string {
+	return "data-" + cluster.Name + "-0"
+}
+
+// clusterValidators returns the sorted names of the AxelarNodes referencing the cluster
+func (r *TofndClusterReconciler) clusterValidators(ctx context.Context, cluster *blockchainv1alpha1.TofndCluster) ([]string, error) {
+	nodes := &blockchainv1alpha1.AxelarNodeList{}
+	if err := r.List(ctx, nodes, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+	validators := []string{}
+	for i := range nodes.Items {
+		if tofndClusterName(&nodes.Items[i]) == cluster.Name {
+			validators = append(validators, nodes.Items[i].Name)
+		}
+	}
+	sort.Strings(validators)
+	return validators, nil
+}
+
+// reconcileTofndService creates the Service vald dials tofnd on
+func (r *TofndClusterReconciler) reconcileTofndService(ctx context.Context, cluster *blockchainv1alpha1.TofndCluster) error {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: tofndClusterLabels(cluster),
+			Ports: []corev1.ServicePort{
+				{Name: "tofnd", Port: tofndPort, TargetPort: intstr.FromInt(tofndPort)},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(cluster, service, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, service)
+	} else if err != nil {
+		return err
+	}
+	found.Spec.Ports = service.Spec.Ports
+	return r.Update(ctx, found)
+}
+
+// reconcileTofndStatefulSet creates or updates the StatefulSet running tofnd.
+// A StatefulSet never runs two tofnd pods at once, so a key share is never
+// used by two signers.
+func (r *TofndClusterReconciler) reconcileTofndStatefulSet(ctx context.Context, cluster *blockchainv1alpha1.TofndCluster) (*appsv1.StatefulSet, error) {
+	statefulSet, err := createTofndStatefulSet(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if err := controllerutil.SetControllerReference(cluster, statefulSet, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	found := &appsv1.StatefulSet{}
+	err = r.Get(ctx, types.NamespacedName{Name: statefulSet.Name, Namespace: statefulSet.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return statefulSet, r.Create(ctx, statefulSet)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if found.Annotations[templateHashAnnotation] != statefulSet.Annotations[templateHashAnnotation] {
+		if found.Annotations == nil {
+			found.Annotations = map[string]string{}
+		}
+		found.Annotations[templateHashAnnotation] = statefulSet.Annotations[templateHashAnnotation]
+		found.Spec.Template = statefulSet.Spec.Template
+		if err := r.Update(ctx, found); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// createTofndStatefulSet creates the StatefulSet object of tofnd, which keeps
+// its mnemonic and key shares on its own volume
+func createTofndStatefulSet(cluster *blockchainv1alpha1.TofndCluster) (*appsv1.StatefulSet, error) {
+	spec := cluster.Spec
+	container := corev1.Container{
+		Name:    "tofnd",
+		Image:   tofndClusterImage(cluster),
+		Command: []string{"tofnd"},
+		Args: []string{
+			"-m", tofndHome + "/tofnd.txt",
+			"-d", tofndHome,
+			"-a", "0.0.0.0",
+		},
+		Env: []corev1.EnvVar{{
+			Name:      "TOFND_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: spec.PasswordSecretRef.DeepCopy()},
+		}},
+		Ports: []corev1.ContainerPort{
+			{Name: "tofnd", ContainerPort: tofndPort},
+		},
+		Resources: spec.Resources,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: tofndHome},
+		},
+		LivenessProbe:  tofndLivenessProbe(spec.Probes),
+		ReadinessProbe: tofndReadinessProbe(spec.Probes),
+	}
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: tofndClusterLabels(cluster)},
+		Spec: corev1.PodSpec{
+			Containers:      []corev1.Container{container},
+			SecurityContext: spec.SecurityContext,
+		},
+	}
+	if tenant := tenancy.Of(cluster); tenant != "" {
+		template.Labels[blockchainv1alpha1.TenantLabel] = tenant
+	}
+	hash, err := templateHash(template)
+	if err != nil {
+		return nil, err
+	}
+
+	size := spec.Storage.Size
+	if size == "" {
+		size = "10Gi"
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec.storage.size %q: %w", size, err)
+	}
+	claim := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: quantity},
+			},
+		},
+	}
+	if spec.Storage.StorageClass != "" {
+		claim.Spec.StorageClassName = &spec.Storage.StorageClass
+	}
+
+	replicas := int32(1)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cluster.Name,
+			Namespace:   cluster.Namespace,
+			Annotations: map[string]string{templateHashAnnotation: hash},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:             &replicas,
+			ServiceName:          cluster.Name,
+			Selector:             &metav1.LabelSelector{MatchLabels: tofndClusterLabels(cluster)},
+			Template:             template,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim},
+		},
+	}, nil
+}
+
+// reconcileTofndNetworkPolicy only admits the pods of the validators
+// referencing the cluster to tofnd, and removes the policy once it is disabled
+func (r *TofndClusterReconciler) reconcileTofndNetworkPolicy(ctx context.Context, cluster *blockchainv1alpha1.TofndCluster, validators []string) error {
+	if enabled := cluster.Spec.NetworkPolicy; enabled != nil && !*enabled {
+		policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name, Namespace: cluster.Namespace}}
+		return client.IgnoreNotFound(r.Delete(ctx, policy))
+	}
+
+	// No ingress rule denies all traffic; a rule without peers would allow it
+	ingress := []networkingv1.NetworkPolicyIngressRule{}
+	if len(validators) > 0 {
+		instances := make([]string, len(validators))
+		for i, validator := range validators {
+			instances[i] = naming.LabelValue(validator)
+		}
+		port := intstr.FromInt(tofndPort)
+		protocol := corev1.ProtocolTCP
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &port}},
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{blockchainv1alpha1.NameLabel: nodeAppName},
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      blockchainv1alpha1.InstanceLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   instances,
+					}},
+				},
+			}},
+		})
+	}
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: tofndClusterLabels(cluster)},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
+	}
+	if err := controllerutil.SetControllerReference(cluster, policy, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return r.Create(ctx, policy)
+	} else if err != nil {
+		return err
+	}
+	found.Spec = policy.Spec
+	return r.Update(ctx, found)
+}
+
+// tofndBackupDue returns whether a backup is due, or else when the next one is
+func tofndBackupDue(cluster *blockchainv1alpha1.TofndCluster, now time.Time) (bool, time.Time, error) {
+	status := cluster.Status.Backup
+	if status.Phase == blockchainv1alpha1.KeyBackupFailed && status.StartedAt != nil {
+		retry := status.StartedAt.Add(keyBackupRetryInterval)
+		return !retry.After(now), retry, nil
+	}
+	schedule, err := maintenance.ParseSchedule(cluster.Spec.Backup.Schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	last := cluster.CreationTimestamp.Time
+	if status.StartedAt != nil {
+		last = status.StartedAt.Time
+	}
+	next := schedule.Next(last)
+	return !next.After(now), next, nil
+}
+
+// startTofndBackup creates the backup Job once a backup is due
+func (r *TofndClusterReconciler) startTofndBackup(ctx context.Context, cluster *blockchainv1alpha1.TofndCluster) (ctrl.Result, error) {
+	now := time.Now()
+	due, next, err := tofndBackupDue(cluster, now)
+	if err != nil {
+		r.failTofndBackup(cluster, fmt.Sprintf("invalid schedule %q: %v", cluster.Spec.Backup.Schedule, err))
+		return ctrl.Result{}, nil
+	}
+	if !due {
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	status := &cluster.Status.Backup
+	status.StartedAt = &metav1.Time{Time: now}
+	refusal := encryptionProblem(&cluster.Spec.Backup.Encryption)
+	if refusal == "" && cluster.Spec.Backup.Destination.BucketURL == "" {
+		refusal = "destination.bucketURL is required"
+	}
+	if refusal != "" {
+		r.failTofndBackup(cluster, refusal)
+		return ctrl.Result{RequeueAfter: keyBackupRetryInterval}, nil
+	}
+
+	job, err := r.createTofndBackupJob(ctx, cluster, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Log.Info("Starting tofnd backup", "tofndcluster", cluster.Name, "job", job.Name)
+	if err := r.Create(ctx, job); err != nil {
+		return ctrl.Result{}, err
+	}
+	status.Phase = blockchainv1alpha1.KeyBackupRunning
+	status.Job = job.Name
+	status.Message = "Escrowing the tofnd volume"
+	return ctrl.Result{RequeueAfter: keyBackupPollInterval}, nil
+}
+
+// followTofndBackup records the outcome of the running backup Job
+func (r *TofndClusterReconciler) followTofndBackup(ctx context.Context, cluster *blockchainv1alpha1.TofndCluster) (ctrl.Result, error) {
+	status := &cluster.Status.Backup
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: status.Job, Namespace: cluster.Namespace}, job)
+	if errors.IsNotFound(err) {
+		r.failTofndBackup(cluster, "the backup Job was deleted before it finished")
+		return ctrl.Result{RequeueAfter: keyBackupRetryInterval}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+	if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		return ctrl.Result{RequeueAfter: keyBackupPollInterval}, nil
+	}
+
+	reader := &AxelarNodeReconciler{Client: r.Client}
+	output, err := reader.txJobOutput(ctx, job)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if job.Status.Succeeded == 0 {
+		if output == "" {
+			output = "the backup Job failed, see its logs"
+		}
+		r.failTofndBackup(cluster, output)
+		return ctrl.Result{RequeueAfter: keyBackupRetryInterval}, nil
+	}
+
+	escrowed := &backup.EscrowResult{}
+	if err := json.Unmarshal([]byte(output), escrowed); err != nil || escrowed.Fingerprint == "" {
+		r.failTofndBackup(cluster, fmt.Sprintf("unable to read the backup result %q", output))
+		return ctrl.Result{RequeueAfter: keyBackupRetryInterval}, nil
+	}
+	status.Phase = blockchainv1alpha1.KeyBackupSucceeded
+	status.LastBackup = &metav1.Time{Time: time.Now()}
+	if job.Status.CompletionTime != nil {
+		status.LastBackup = job.Status.CompletionTime.DeepCopy()
+	}
+	status.Object = escrowed.Object
+	status.Fingerprint = escrowed.Fingerprint
+	status.Message = fmt.Sprintf("Escrowed %d files to %s", len(escrowed.Files), escrowed.Object)
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionKeysEscrowed,
+		Status:             metav1.ConditionTrue,
+		Reason:             "BackupSucceeded",
+		Message:            status.Message,
+		ObservedGeneration: cluster.Generation,
+	})
+	r.Log.Info("Escrowed tofnd volume", "tofndcluster", cluster.Name, "object", status.Object, "fingerprint", status.Fingerprint)
+	if r.Recorder != nil {
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "KeysEscrowed", fmt.Sprintf("%s, fingerprint %s", status.Message, status.Fingerprint))
+	}
+
+	_, next, _ := tofndBackupDue(cluster, time.Now())
+	return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+}
+
+// failTofndBackup records a failed backup
+func (r *TofndClusterReconciler) failTofndBackup(cluster *blockchainv1alpha1.TofndCluster, message string) {
+	status := &cluster.Status.Backup
+	status.Phase = blockchainv1alpha1.KeyBackupFailed
+	status.Message = message
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               blockchainv1alpha1.ConditionKeysEscrowed,
+		Status:             metav1.ConditionFalse,
+		Reason:             "BackupFailed",
+		Message:            message,
+		ObservedGeneration: cluster.Generation,
+	})
+	r.Log.Info("Tofnd backup failed", "tofndcluster", cluster.Name, "reason", message)
+	if r.Recorder != nil {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "KeyBackupFailed", message)
+	}
+}
+
+// createTofndBackupJob returns the Job escrowing the tofnd volume. The volume
+// is mounted read-only, and the Job runs next to the tofnd pod so the
+// ReadWriteOnce volume can be attached to both.
+func (r *TofndClusterReconciler) createTofndBackupJob(ctx context.Context, cluster *blockchainv1alpha1.TofndCluster, now time.Time) (*batchv1.Job, error) {
+	spec := cluster.Spec.Backup
+	object := path.Join(spec.Destination.Prefix, cluster.Namespace, cluster.Name,
+		fmt.Sprintf("tofnd-%s.tar.gz%s", now.UTC().Format("20060102-150405"), backup.Extension))
+	args := []string{"key-escrow", "--bucket-url=" + spec.Destination.BucketURL, "--object=" + object}
+	if age := spec.Encryption.Age; age != nil {
+		args = append(args, "--age-recipient="+age.Recipient)
+	}
+	if kms := spec.Encryption.KMS; kms != nil {
+		args = append(args, "--kms-key-url="+kms.KeyURL)
+	}
+	args = append(args, path.Base(tofndHome))
+
+	container := corev1.Container{
+		Name:    "key-escrow",
+		Image:   r.ToolsImage,
+		Command: []string{"/root/manager"},
+		Args:    args,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: tofndHome, ReadOnly: true},
+		},
+	}
+	for _, secret := range []string{spec.Destination.CredentialsSecret, kmsCredentialsSecret(&spec.Encryption)} {
+		if secret != "" {
+			container.EnvFrom = append(container.EnvFrom, corev1.EnvFromSource{
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret}},
+			})
+		}
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers:    []corev1.Container{container},
+		Volumes: []corev1.Volume{{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: tofndClaimName(cluster), ReadOnly: true},
+			},
+		}},
+		SecurityContext:    cluster.Spec.SecurityContext,
+		ServiceAccountName: spec.Destination.ServiceAccountName,
+	}
+	if podSpec.ServiceAccountName == "" && spec.Encryption.KMS != nil {
+		podSpec.ServiceAccountName = spec.Encryption.KMS.ServiceAccountName
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels(tofndClusterLabels(cluster))); err != nil {
+		return nil, err
+	}
+	if len(pods.Items) > 0 {
+		podSpec.Affinity = &corev1.Affinity{
+			PodAffinity: &corev1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: tofndClusterLabels(cluster)},
+					TopologyKey:   corev1.LabelHostname,
+				}},
+			},
+		}
+	}
+
+	backoffLimit := int32(0)
+	ttl := int32(7 * 24 * 60 * 60)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.Name(cluster.Name, fmt.Sprint(now.Unix())),
+			Namespace: cluster.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template:                corev1.PodTemplateSpec{Spec: podSpec},
+		},
+	}
+	if err := controllerutil.SetControllerReference(cluster, job, r.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// clusterForNode maps an AxelarNode to the TofndCluster it references
+func clusterForNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	axelarNode, ok := obj.(*blockchainv1alpha1.AxelarNode)
+	if !ok {
+		return nil
+	}
+	name := tofndClusterName(axelarNode)
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: axelarNode.Namespace}}}
+}
+
+// SetupWithManager sets up the controller with the Manager. Validators
+// that stop referencing the cluster are picked up by the periodic requeue.
+func (r *TofndClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&blockchainv1alpha1.TofndCluster{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&batchv1.Job{}).
+		Watches(&blockchainv1alpha1.AxelarNode{}, handler.EnqueueRequestsFromMapFunc(clusterForNode)).
+		Complete(r)
+}
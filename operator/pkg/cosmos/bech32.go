@@ -0,0 +1,84 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// bech32Charset maps 5-bit groups to the characters of a bech32 string
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// AccountPrefix is the bech32 prefix of Axelar account addresses
+const AccountPrefix = "axelar"
+
+// AccountAddress returns the account address of a validator operator
+// address, such as axelar1... for axelarvaloper1...
+func AccountAddress(operatorAddress string) (string, error) {
+	return rebech32(operatorAddress, AccountPrefix)
+}
+
+// rebech32 re-encodes a bech32 address with another prefix
+func rebech32(address, prefix string) (string, error) {
+	address = strings.ToLower(address)
+	sep := strings.LastIndexByte(address, '1')
+	if sep < 1 || sep+7 > len(address) {
+		return "", fmt.Errorf("%q is not a bech32 address", address)
+	}
+	hrp := address[:sep]
+	data := make([]byte, 0, len(address)-sep-1)
+	for _, c := range address[sep+1:] {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return "", fmt.Errorf("%q is not a bech32 address", address)
+		}
+		data = append(data, byte(v))
+	}
+	if bech32Polymod(append(bech32ExpandHRP(hrp), data...)) != 1 {
+		return "", errors.New("invalid bech32 checksum")
+	}
+	data = data[:len(data)-6]
+
+	values := append(bech32ExpandHRP(prefix), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ 1
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte('1')
+	for _, v := range data {
+		b.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		b.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return b.String(), nil
+}
+
+// bech32ExpandHRP expands the human readable part for the checksum
+func bech32ExpandHRP(hrp string) []byte {
+	values := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	return values
+}
+
+// bech32Polymod computes the bech32 checksum of values
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
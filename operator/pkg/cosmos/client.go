@@ -0,0 +1,276 @@
+// Package cosmos queries the Cosmos SDK REST API of an Axelar node
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the timeout applied to REST requests
+const DefaultTimeout = 10 * time.Second
+
+// Client queries the REST API of an Axelar node
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the REST API at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// Validator is the on-chain state of a validator
+type Validator struct {
+	OperatorAddress   string      `json:"operator_address"`
+	Jailed            bool        `json:"jailed"`
+	Status            string      `json:"status"`
+	Tokens            string      `json:"tokens"`
+	Description       Description `json:"description"`
+	Commission        Commission  `json:"commission"`
+	MinSelfDelegation string      `json:"min_self_delegation"`
+}
+
+// Description is the public description of a validator
+type Description struct {
+	Moniker         string `json:"moniker"`
+	Identity        string `json:"identity"`
+	Website         string `json:"website"`
+	SecurityContact string `json:"security_contact"`
+	Details         string `json:"details"`
+}
+
+// Commission holds the commission rates of a validator, as decimal strings
+type Commission struct {
+	CommissionRates struct {
+		Rate          string `json:"rate"`
+		MaxRate       string `json:"max_rate"`
+		MaxChangeRate string `json:"max_change_rate"`
+	} `json:"commission_rates"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// Proposal is a governance proposal
+type Proposal struct {
+	ProposalID string `json:"proposal_id"`
+	Content    struct {
+		Type        string `json:"@type"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"content"`
+	Status          string    `json:"status"`
+	SubmitTime      time.Time `json:"submit_time"`
+	DepositEndTime  time.Time `json:"deposit_end_time"`
+	VotingStartTime time.Time `json:"voting_start_time"`
+	VotingEndTime   time.Time `json:"voting_end_time"`
+}
+
+// ProposalStatusVotingPeriod selects the proposals open for voting
+const ProposalStatusVotingPeriod = "PROPOSAL_STATUS_VOTING_PERIOD"
+
+// HTTPError is returned when the API answers with a status other than 200 OK
+type HTTPError struct {
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s returned HTTP %d: %s", e.Path, e.StatusCode, e.Body)
+}
+
+// Validator returns the on-chain state of the validator with the given operator address
+func (c *Client) Validator(ctx context.Context, operatorAddress string) (*Validator, error) {
+	result := &struct {
+		Validator Validator `json:"validator"`
+	}{}
+	if err := c.get(ctx, "/cosmos/staking/v1beta1/validators/"+url.PathEscape(operatorAddress), result); err != nil {
+		return nil, err
+	}
+	return &result.Validator, nil
+}
+
+// Proposals returns the governance proposals with the given status
+func (c *Client) Proposals(ctx context.Context, status string) ([]Proposal, error) {
+	result := &struct {
+		Proposals []Proposal `json:"proposals"`
+	}{}
+	query := url.Values{"proposal_status": {status}, "pagination.limit": {"100"}}
+	if err := c.get(ctx, "/cosmos/gov/v1beta1/proposals?"+query.Encode(), result); err != nil {
+		return nil, err
+	}
+	return result.Proposals, nil
+}
+
+// Proposal returns a governance proposal
+func (c *Client) Proposal(ctx context.Context, proposalID string) (*Proposal, error) {
+	result := &struct {
+		Proposal Proposal `json:"proposal"`
+	}{}
+	if err := c.get(ctx, "/cosmos/gov/v1beta1/proposals/"+url.PathEscape(proposalID), result); err != nil {
+		return nil, err
+	}
+	return &result.Proposal, nil
+}
+
+// Voted returns whether voter has voted on a proposal
+func (c *Client) Voted(ctx context.Context, proposalID, voter string) (bool, error) {
+	path := fmt.Sprintf("/cosmos/gov/v1beta1/proposals/%s/votes/%s", url.PathEscape(proposalID), url.PathEscape(voter))
+	err := c.get(ctx, path, &struct{}{})
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusNotFound || httpErr.StatusCode == http.StatusBadRequest) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Balance returns the balance of an account in denom, such as uaxl. An
+// account the chain has never seen has no balance.
+func (c *Client) Balance(ctx context.Context, address, denom string) (int64, error) {
+	result := &struct {
+		Balance struct {
+			Amount string `json:"amount"`
+		} `json:"balance"`
+	}{}
+	path := fmt.Sprintf("/cosmos/bank/v1beta1/balances/%s/by_denom?denom=%s", url.PathEscape(address), url.QueryEscape(denom))
+	if err := c.get(ctx, path, result); err != nil {
+		return 0, err
+	}
+	if result.Balance.Amount == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(result.Balance.Amount, 10, 64)
+}
+
+// Proxy returns the broadcaster registered for a validator with
+// register-proxy and whether it is active, or an empty address when none is
+func (c *Client) Proxy(ctx context.Context, operatorAddress string) (string, bool, error) {
+	result := &struct {
+		Address string `json:"address"`
+		Status  string `json:"status"`
+	}{}
+	err := c.get(ctx, "/axelar/snapshot/v1beta1/proxy/"+url.PathEscape(operatorAddress), result)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusNotFound || httpErr.StatusCode == http.StatusBadRequest) {
+		return "", false, nil
+	}
+	return result.Address, result.Status == "PROXY_STATUS_ACTIVE", err
+}
+
+// ChainMaintainers returns the operator addresses of the validators
+// maintaining a chain
+func (c *Client) ChainMaintainers(ctx context.Context, chain string) ([]string, error) {
+	result := &struct {
+		Maintainers []string `json:"maintainers"`
+	}{}
+	if err := c.get(ctx, "/axelar/nexus/v1beta1/chain_maintainers/"+url.PathEscape(chain), result); err != nil {
+		return nil, err
+	}
+	return result.Maintainers, nil
+}
+
+// MultisigKey is a key of the multisig module, shared by the validators
+// that took part in its keygen
+type MultisigKey struct {
+	KeyID        string `json:"key_id"`
+	State        string `json:"state"`
+	Participants []struct {
+		Address string `json:"address"`
+		Weight  string `json:"weight"`
+		PubKey  string `json:"pub_key"`
+	} `json:"participants"`
+}
+
+// KeyID returns the ID of the key a chain signs with, or of the key it
+// rotates to when next is set. It is empty when the chain has none.
+func (c *Client) KeyID(ctx context.Context, chain string, next bool) (string, error) {
+	path := "/axelar/multisig/v1beta1/key_id/"
+	if next {
+		path = "/axelar/multisig/v1beta1/next_key_id/"
+	}
+	result := &struct {
+		KeyID string `json:"key_id"`
+	}{}
+	err := c.get(ctx, path+url.PathEscape(chain), result)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	return result.KeyID, err
+}
+
+// MultisigKey returns a key with its participants
+func (c *Client) MultisigKey(ctx context.Context, keyID string) (*MultisigKey, error) {
+	result := &MultisigKey{}
+	if err := c.get(ctx, "/axelar/multisig/v1beta1/key?key_id="+url.QueryEscape(keyID), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// heartbeatMsg is part of the type URL of the heartbeats vald sends, also
+// when they are wrapped in a refund request
+const heartbeatMsg = "HeartBeatRequest"
+
+// LastHeartbeat returns the height of the last heartbeat sent by the
+// broadcaster among its recent transactions, or 0 when there is none
+func (c *Client) LastHeartbeat(ctx context.Context, broadcaster string) (int64, error) {
+	result := &struct {
+		TxResponses []struct {
+			Height string          `json:"height"`
+			Tx     json.RawMessage `json:"tx"`
+		} `json:"tx_responses"`
+	}{}
+	query := url.Values{
+		"events":           {fmt.Sprintf("message.sender='%s'", broadcaster)},
+		"order_by":         {"ORDER_BY_DESC"},
+		"pagination.limit": {"50"},
+	}
+	if err := c.get(ctx, "/cosmos/tx/v1beta1/txs?"+query.Encode(), result); err != nil {
+		return 0, err
+	}
+	var last int64
+	for _, tx := range result.TxResponses {
+		if !strings.Contains(string(tx.Tx), heartbeatMsg) {
+			continue
+		}
+		if height, err := strconv.ParseInt(tx.Height, 10, 64); err == nil && height > last {
+			last = height
+		}
+	}
+	return last, nil
+}
+
+// get performs a GET against path and decodes the JSON response
+func (c *Client) get(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &HTTPError{Path: path, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+	return nil
+}
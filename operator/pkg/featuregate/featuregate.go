@@ -0,0 +1,150 @@
+// Package featuregate lets experimental subsystems of the operator ship
+// disabled and be turned on per cluster with the --feature-gates flag, the
+// way Kubernetes components gate their alpha and beta features.
+package featuregate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature names a gated capability
+type Feature string
+
+// Stage is the maturity of a feature
+type Stage string
+
+// Feature stages. Alpha features are disabled by default, beta features are
+// enabled by default, and GA features can no longer be disabled.
+const (
+	Alpha Stage = "ALPHA"
+	Beta  Stage = "BETA"
+	GA    Stage = "GA"
+)
+
+// FeatureSpec describes a known feature
+type FeatureSpec struct {
+	// Default is whether the feature is enabled when the flag leaves it unset
+	Default bool
+
+	// Stage is the maturity of the feature
+	Stage Stage
+}
+
+// Gates of the experimental subsystems
+const (
+	// AutoUnjail unjails validators once their downtime jail period ends
+	AutoUnjail Feature = "AutoUnjail"
+
+	// AutoResync wipes and resyncs nodes whose chain data is found corrupted
+	AutoResync Feature = "AutoResync"
+
+	// RemoteSigner signs blocks through a remote signer instead of the node key
+	RemoteSigner Feature = "RemoteSigner"
+)
+
+// defaultFeatures are the features known to the operator
+var defaultFeatures = map[Feature]FeatureSpec{
+	AutoUnjail:   {Default: false, Stage: Alpha},
+	AutoResync:   {Default: false, Stage: Alpha},
+	RemoteSigner: {Default: false, Stage: Alpha},
+}
+
+// Gate reports which features are enabled. It implements flag.Value, parsing
+// a comma-separated list of Feature=bool pairs. A nil Gate reports the
+// defaults.
+type Gate struct {
+	known   map[Feature]FeatureSpec
+	enabled map[Feature]bool
+}
+
+// New returns a gate of the features known to the operator, at their defaults
+func New() *Gate {
+	return NewWith(defaultFeatures)
+}
+
+// NewWith returns a gate of the given features, at their defaults
+func NewWith(known map[Feature]FeatureSpec) *Gate {
+	g := &Gate{known: map[Feature]FeatureSpec{}, enabled: map[Feature]bool{}}
+	for feature, spec := range known {
+		g.known[feature] = spec
+	}
+	return g
+}
+
+// Set parses a comma-separated list of Feature=bool pairs. Unknown features
+// and disabling a GA feature are errors.
+func (g *Gate) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, found := strings.Cut(pair, "=")
+		if !found {
+			return fmt.Errorf("missing bool value for feature gate %s", name)
+		}
+		feature := Feature(strings.TrimSpace(name))
+		spec, ok := g.known[feature]
+		if !ok {
+			return fmt.Errorf("unknown feature gate %s", feature)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid value of feature gate %s: %w", feature, err)
+		}
+		if spec.Stage == GA && !enabled {
+			return fmt.Errorf("feature gate %s is GA and cannot be disabled", feature)
+		}
+		g.enabled[feature] = enabled
+	}
+	return nil
+}
+
+// String returns the features set explicitly, as Feature=bool pairs
+func (g *Gate) String() string {
+	if g == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(g.enabled))
+	for feature, enabled := range g.enabled {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", feature, enabled))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Enabled reports whether a feature is enabled. Unknown features are disabled.
+func (g *Gate) Enabled(feature Feature) bool {
+	if g == nil {
+		return defaultFeatures[feature].Default
+	}
+	if enabled, ok := g.enabled[feature]; ok {
+		return enabled
+	}
+	return g.known[feature].Default
+}
+
+// EnabledFeatures returns the enabled features, sorted
+func (g *Gate) EnabledFeatures() []string {
+	var names []string
+	for feature := range g.known {
+		if g.Enabled(feature) {
+			names = append(names, string(feature))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Known describes the known features for the flag usage, one per line
+func (g *Gate) Known() []string {
+	known := make([]string, 0, len(g.known))
+	for feature, spec := range g.known {
+		known = append(known, fmt.Sprintf("%s=true|false (%s - default=%t)", feature, spec.Stage, spec.Default))
+	}
+	sort.Strings(known)
+	return known
+}
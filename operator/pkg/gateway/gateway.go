@@ -0,0 +1,416 @@
+// Package gateway implements the sidecar that authenticates and rate limits
+// clients of a public node RPC, and restricts the RPC methods they may call.
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+)
+
+// unsafeMethods are never served by the gateway, whatever the allowlist says
+var unsafeMethods = []string{"dial_seeds", "dial_peers", "unsafe_*"}
+
+// websocketMethod names calls to /websocket. Calls made over a websocket are
+// not inspected, so the whole endpoint is allowed or blocked.
+const websocketMethod = "websocket"
+
+// maxBodyBytes bounds the JSON-RPC requests the gateway inspects
+const maxBodyBytes = 8 << 20
+
+// limiterIdle is how long the limiter of an inactive client is kept
+const limiterIdle = 10 * time.Minute
+
+// Options configures the gateway
+type Options struct {
+	// Listen is the address the gateway is served on
+	Listen string
+	// Upstream is the URL of the node RPC
+	Upstream string
+	// KeysDir holds one file per API key, named after the client it identifies
+	KeysDir string
+	// JWTSecretFile holds the HMAC secret HS256 tokens are signed with
+	JWTSecretFile string
+	// Rate is the number of requests per second allowed per client
+	Rate float64
+	// Burst is the number of requests a client may make at once
+	Burst int
+	// AllowMethods restricts the methods clients may call, when not empty
+	AllowMethods []string
+	// BlockMethods lists methods clients may not call
+	BlockMethods []string
+	// ReloadInterval is how often the API keys and JWT secret are reloaded
+	ReloadInterval time.Duration
+}
+
+// BindFlags registers the gateway options on fs
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Listen, "listen", ":26672", "The address the gateway is served on.")
+	fs.StringVar(&o.Upstream, "upstream", "http://127.0.0.1:26657", "The URL of the node RPC.")
+	fs.StringVar(&o.KeysDir, "keys-dir", "", "The directory holding one file per API key, named after its client.")
+	fs.StringVar(&o.JWTSecretFile, "jwt-secret-file", "", "The file holding the HMAC secret of HS256 tokens.")
+	fs.Float64Var(&o.Rate, "rate", 10, "The requests per second allowed per client.")
+	fs.IntVar(&o.Burst, "burst", 20, "The requests a client may make at once.")
+	fs.Func("allow-methods", "Comma separated methods clients may call. A trailing * matches a prefix.", func(s string) error {
+		o.AllowMethods = splitMethods(s)
+		return nil
+	})
+	fs.Func("block-methods", "Comma separated methods clients may not call. A trailing * matches a prefix.", func(s string) error {
+		o.BlockMethods = splitMethods(s)
+		return nil
+	})
+	fs.DurationVar(&o.ReloadInterval, "reload-interval", time.Minute, "How often the API keys and JWT secret are reloaded.")
+}
+
+// splitMethods parses a comma separated list of methods
+func splitMethods(s string) []string {
+	var methods []string
+	for _, method := range strings.Split(s, ",") {
+		if method = strings.TrimSpace(method); method != "" {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// Gateway forwards the requests of authenticated clients to the node
+type Gateway struct {
+	opts Options
+	log  logr.Logger
+
+	mu        sync.Mutex
+	keys      map[string]string
+	jwtSecret []byte
+	limiters  map[string]*limiter
+}
+
+type limiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+// New creates a gateway for opts
+func New(opts Options, log logr.Logger) *Gateway {
+	return &Gateway{opts: opts, log: log, limiters: map[string]*limiter{}}
+}
+
+// Run serves the gateway until ctx is cancelled
+func (g *Gateway) Run(ctx context.Context) error {
+	upstream, err := url.Parse(g.opts.Upstream)
+	if err != nil {
+		return err
+	}
+	if err := g.reload(); err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              g.opts.Listen,
+		Handler:           g.Handler(httputil.NewSingleHostReverseProxy(upstream)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errs := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+
+	ticker := time.NewTicker(g.opts.ReloadInterval)
+	defer ticker.Stop()
+	for err == nil {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case err = <-errs:
+		case <-ticker.C:
+			if err := g.reload(); err != nil {
+				g.log.Error(err, "Unable to reload the credentials, keeping the previous ones")
+			}
+			g.evictLimiters(time.Now())
+		}
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Shutdown(shutdown)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// Handler authenticates, rate limits and filters requests before passing
+// them to next
+func (g *Gateway) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, err := g.authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		methods, err := requestMethods(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, method := range methods {
+			if !g.allowed(method) {
+				http.Error(w, fmt.Sprintf("method %q is not allowed", method), http.StatusForbidden)
+				return
+			}
+		}
+
+		// A batch counts as one request per call
+		if !g.limiter(client).AllowN(time.Now(), len(methods)) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns the client making a request. When neither API keys
+// nor a JWT secret are configured, clients are told apart by their address.
+func (g *Gateway) authenticate(r *http.Request) (string, error) {
+	if g.opts.KeysDir == "" && g.opts.JWTSecretFile == "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		return "ip:" + host, nil
+	}
+
+	token := r.Header.Get("X-API-Key")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token == "" {
+		token = bearer
+	}
+	// Browsers cannot set headers on websockets
+	if query := r.URL.Query(); token == "" && query.Has("apikey") {
+		token = query.Get("apikey")
+		query.Del("apikey")
+		r.URL.RawQuery = query.Encode()
+	}
+	if token == "" {
+		return "", errors.New("missing API key or token")
+	}
+
+	g.mu.Lock()
+	keys, secret := g.keys, g.jwtSecret
+	g.mu.Unlock()
+	for name, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			return "key:" + name, nil
+		}
+	}
+	if len(secret) > 0 && strings.Count(token, ".") == 2 {
+		subject, err := verifyJWT(token, secret, time.Now())
+		if err != nil {
+			return "", err
+		}
+		return "jwt:" + subject, nil
+	}
+	return "", errors.New("invalid API key")
+}
+
+// verifyJWT checks an HS256 token and returns its subject
+func verifyJWT(token string, secret []byte, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", errors.New("invalid token header")
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return "", errors.New("unsupported token algorithm")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("invalid token signature")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errors.New("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("invalid token claims")
+	}
+	var claims struct {
+		Subject   string `json:"sub"`
+		ExpiresAt *int64 `json:"exp"`
+		NotBefore *int64 `json:"nbf"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", errors.New("invalid token claims")
+	}
+	if claims.ExpiresAt != nil && now.Unix() >= *claims.ExpiresAt {
+		return "", errors.New("token expired")
+	}
+	if claims.NotBefore != nil && now.Unix() < *claims.NotBefore {
+		return "", errors.New("token not yet valid")
+	}
+	if claims.Subject == "" {
+		return "", errors.New("token has no subject")
+	}
+	return claims.Subject, nil
+}
+
+// requestMethods returns the RPC methods a request calls. URI requests name
+// the method in the path, JSON-RPC requests in the body, possibly batched.
+func requestMethods(r *http.Request) ([]string, error) {
+	path := strings.Trim(r.URL.Path, "/")
+	if path == websocketMethod {
+		return []string{websocketMethod}, nil
+	}
+	if path != "" || r.Method != http.MethodPost {
+		return []string{path}, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBodyBytes {
+		return nil, fmt.Errorf("request larger than %d bytes", maxBodyBytes)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	type call struct {
+		Method string `json:"method"`
+	}
+	var calls []call
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &calls); err != nil {
+			return nil, errors.New("invalid JSON-RPC batch")
+		}
+		if len(calls) == 0 {
+			return nil, errors.New("empty JSON-RPC batch")
+		}
+	} else {
+		var c call
+		if err := json.Unmarshal(trimmed, &c); err != nil {
+			return nil, errors.New("invalid JSON-RPC request")
+		}
+		calls = []call{c}
+	}
+
+	methods := make([]string, len(calls))
+	for i, c := range calls {
+		methods[i] = c.Method
+	}
+	return methods, nil
+}
+
+// allowed reports whether clients may call method
+func (g *Gateway) allowed(method string) bool {
+	if matchMethod(unsafeMethods, method) || matchMethod(g.opts.BlockMethods, method) {
+		return false
+	}
+	return len(g.opts.AllowMethods) == 0 || matchMethod(g.opts.AllowMethods, method)
+}
+
+// matchMethod reports whether method is one of patterns
+func matchMethod(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(method, prefix) {
+			return true
+		}
+		if pattern == method {
+			return true
+		}
+	}
+	return false
+}
+
+// limiter returns the rate limiter of a client
+func (g *Gateway) limiter(client string) *limiter {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	l, ok := g.limiters[client]
+	if !ok {
+		l = &limiter{Limiter: rate.NewLimiter(rate.Limit(g.opts.Rate), g.opts.Burst)}
+		g.limiters[client] = l
+	}
+	l.lastSeen = time.Now()
+	return l
+}
+
+// evictLimiters forgets the clients that have been idle, so rotating
+// addresses or token subjects do not grow the gateway unbounded
+func (g *Gateway) evictLimiters(now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for client, l := range g.limiters {
+		if now.Sub(l.lastSeen) > limiterIdle {
+			delete(g.limiters, client)
+		}
+	}
+}
+
+// reload reads the API keys and JWT secret, which change when the mounted
+// Secrets are rotated
+func (g *Gateway) reload() error {
+	keys := map[string]string{}
+	if g.opts.KeysDir != "" {
+		entries, err := os.ReadDir(g.opts.KeysDir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			// Secret volumes keep their data in hidden directories
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			key, err := os.ReadFile(filepath.Join(g.opts.KeysDir, entry.Name()))
+			if err != nil {
+				return err
+			}
+			if key := strings.TrimSpace(string(key)); key != "" {
+				keys[entry.Name()] = key
+			}
+		}
+	}
+
+	var secret []byte
+	if g.opts.JWTSecretFile != "" {
+		data, err := os.ReadFile(g.opts.JWTSecretFile)
+		if err != nil {
+			return err
+		}
+		secret = bytes.TrimSpace(data)
+	}
+
+	g.mu.Lock()
+	g.keys, g.jwtSecret = keys, secret
+	g.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,186 @@
+package importer
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// homeConfig is what the import reads from config.toml, app.toml and
+// client.toml of an axelard home directory
+type homeConfig struct {
+	Tendermint struct {
+		Moniker string `toml:"moniker"`
+		RPC     struct {
+			Laddr                    string   `toml:"laddr"`
+			CORSAllowedOrigins       []string `toml:"cors_allowed_origins"`
+			Unsafe                   bool     `toml:"unsafe"`
+			MaxOpenConnections       int32    `toml:"max_open_connections"`
+			MaxSubscriptionClients   int32    `toml:"max_subscription_clients"`
+			TimeoutBroadcastTxCommit string   `toml:"timeout_broadcast_tx_commit"`
+		} `toml:"rpc"`
+		P2P struct {
+			Laddr                        string `toml:"laddr"`
+			ExternalAddress              string `toml:"external_address"`
+			Seeds                        string `toml:"seeds"`
+			PersistentPeers              string `toml:"persistent_peers"`
+			MaxNumInboundPeers           *int32 `toml:"max_num_inbound_peers"`
+			MaxNumOutboundPeers          *int32 `toml:"max_num_outbound_peers"`
+			UnconditionalPeerIDs         string `toml:"unconditional_peer_ids"`
+			PersistentPeersMaxDialPeriod string `toml:"persistent_peers_max_dial_period"`
+		} `toml:"p2p"`
+		Mempool struct {
+			Size        int32 `toml:"size"`
+			CacheSize   int32 `toml:"cache_size"`
+			MaxTxsBytes int64 `toml:"max_txs_bytes"`
+			MaxTxBytes  int32 `toml:"max_tx_bytes"`
+			Broadcast   *bool `toml:"broadcast"`
+		} `toml:"mempool"`
+		Consensus struct {
+			TimeoutCommit     string `toml:"timeout_commit"`
+			SkipTimeoutCommit bool   `toml:"skip_timeout_commit"`
+		} `toml:"consensus"`
+		Instrumentation struct {
+			PrometheusListenAddr string `toml:"prometheus_listen_addr"`
+		} `toml:"instrumentation"`
+	}
+	App struct {
+		MinimumGasPrices string `toml:"minimum-gas-prices"`
+		Pruning          string `toml:"pruning"`
+		HaltHeight       int64  `toml:"halt-height"`
+		API              struct {
+			Swagger            bool   `toml:"swagger"`
+			Address            string `toml:"address"`
+			MaxOpenConnections int32  `toml:"max-open-connections"`
+			RPCReadTimeout     int64  `toml:"rpc-read-timeout"`
+			EnabledUnsafeCORS  bool   `toml:"enabled-unsafe-cors"`
+		} `toml:"api"`
+	}
+	Client struct {
+		ChainID string `toml:"chain-id"`
+	}
+}
+
+// FromConfigDir imports a node from its axelard home directory, such as the
+// ~/.axelar directory of the axelarate-community setup
+func FromConfigDir(home string, opts Options) (*Result, error) {
+	var config homeConfig
+	files := []struct {
+		name     string
+		into     interface{}
+		required bool
+	}{
+		{"config.toml", &config.Tendermint, true},
+		{"app.toml", &config.App, true},
+		{"client.toml", &config.Client, false},
+	}
+	for _, file := range files {
+		path := filepath.Join(home, "config", file.name)
+		if _, err := toml.DecodeFile(path, file.into); err != nil {
+			if os.IsNotExist(err) && !file.required {
+				continue
+			}
+			return nil, fmt.Errorf("unable to read %s: %w", path, err)
+		}
+	}
+
+	if opts.Name == "" {
+		opts.Name = strings.ToLower(strings.ReplaceAll(config.Tendermint.Moniker, "_", "-"))
+	}
+	result := newResult(opts)
+	spec := &result.Node.Spec
+	spec.NodeType = "observer"
+	spec.Moniker = config.Tendermint.Moniker
+	spec.Image.Tag = opts.ImageTag
+	if spec.Image.Tag == "" {
+		result.warnf("the home directory does not record the axelar-core version, set it with --image-tag or spec.image.tag")
+	}
+	if config.Client.ChainID != "" {
+		spec.Network, spec.ChainID = networkOf(config.Client.ChainID)
+	} else {
+		result.warnf("client.toml has no chain-id, set spec.network")
+	}
+
+	// app.toml
+	app := config.App
+	spec.MinimumGasPrices = app.MinimumGasPrices
+	spec.Pruning = app.Pruning
+	spec.HaltHeight = app.HaltHeight
+	spec.Networking.API.Port = listenPort(app.API.Address)
+	spec.Networking.API.Swagger = app.API.Swagger
+	spec.Networking.API.MaxOpenConnections = app.API.MaxOpenConnections
+	spec.Networking.API.EnableUnsafeCORS = app.API.EnabledUnsafeCORS
+	if d, ok := duration(app.API.RPCReadTimeout); ok && app.API.RPCReadTimeout > 0 {
+		spec.Networking.API.RPCReadTimeout = d
+	}
+
+	// config.toml
+	tm := config.Tendermint
+	spec.Networking.RPC.Port = listenPort(tm.RPC.Laddr)
+	spec.Networking.RPC.CORS = len(tm.RPC.CORSAllowedOrigins) > 0
+	spec.Networking.RPC.CORSAllowedOrigins = tm.RPC.CORSAllowedOrigins
+	spec.Networking.RPC.Unsafe = tm.RPC.Unsafe
+	spec.Networking.RPC.MaxOpenConnections = tm.RPC.MaxOpenConnections
+	spec.Networking.RPC.MaxSubscriptionClients = tm.RPC.MaxSubscriptionClients
+	if d, ok := duration(tm.RPC.TimeoutBroadcastTxCommit); ok {
+		spec.Networking.RPC.TimeoutBroadcastTxCommit = d
+	}
+	p2p := &spec.Networking.P2P
+	p2p.Port = listenPort(tm.P2P.Laddr)
+	p2p.ExternalAddress = tm.P2P.ExternalAddress
+	p2p.Seeds = peerList(tm.P2P.Seeds)
+	p2p.PersistentPeers = peerList(tm.P2P.PersistentPeers)
+	p2p.MaxNumInboundPeers = tm.P2P.MaxNumInboundPeers
+	p2p.MaxNumOutboundPeers = tm.P2P.MaxNumOutboundPeers
+	p2p.UnconditionalPeerIDs = peerList(tm.P2P.UnconditionalPeerIDs)
+	if d, ok := duration(tm.P2P.PersistentPeersMaxDialPeriod); ok {
+		p2p.PersistentPeersMaxDialPeriod = d
+	}
+	spec.Config.Tendermint.Mempool = blockchainv1alpha1.MempoolSpec{
+		Size:        tm.Mempool.Size,
+		CacheSize:   tm.Mempool.CacheSize,
+		MaxTxsBytes: tm.Mempool.MaxTxsBytes,
+		MaxTxBytes:  tm.Mempool.MaxTxBytes,
+		Broadcast:   tm.Mempool.Broadcast,
+	}
+	spec.Config.Tendermint.Consensus.SkipTimeoutCommit = tm.Consensus.SkipTimeoutCommit
+	if d, ok := duration(tm.Consensus.TimeoutCommit); ok {
+		spec.Config.Tendermint.Consensus.TimeoutCommit = d
+	}
+	spec.Monitoring.Prometheus.Port = listenPort(tm.Instrumentation.PrometheusListenAddr)
+
+	// Keys stay where they are
+	if _, err := os.Stat(filepath.Join(home, "config", "priv_validator_key.json")); err == nil {
+		result.warnf("priv_validator_key.json and the keyring are not carried over, copy the home directory into the data volume or restore the keys with an AxelarNodeKeyBackup")
+	}
+	if _, err := os.Stat(filepath.Join(home, "vald")); err == nil {
+		spec.NodeType = "validator"
+		spec.Validator = &blockchainv1alpha1.ValidatorSpec{Enabled: true}
+		result.warnf("the home directory runs vald, the node is imported as a validator; check the tofnd mnemonic is in the shared volume before starting it")
+	}
+	return result, nil
+}
+
+// listenPort returns the port of a listen address such as tcp://0.0.0.0:26657,
+// or 0 when it has none
+func listenPort(address string) int32 {
+	if i := strings.Index(address, "://"); i >= 0 {
+		address = address[i+3:]
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0
+	}
+	p, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(p)
+}
@@ -0,0 +1,293 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// chartName is the name of the axelar-node Helm chart
+const chartName = "axelar-node"
+
+// helmValues are the values of the axelar-node chart the import carries over
+type helmValues struct {
+	NameOverride     string `json:"nameOverride"`
+	FullnameOverride string `json:"fullnameOverride"`
+	Image            struct {
+		Registry   string            `json:"registry"`
+		Repository string            `json:"repository"`
+		Tag        string            `json:"tag"`
+		PullPolicy corev1.PullPolicy `json:"pullPolicy"`
+	} `json:"image"`
+	DeploymentType string `json:"deploymentType"`
+	Network        struct {
+		Name    string `json:"name"`
+		ChainID string `json:"chainId"`
+	} `json:"network"`
+	Node struct {
+		Moniker   string                      `json:"moniker"`
+		Resources corev1.ResourceRequirements `json:"resources"`
+		Storage   struct {
+			Size         string `json:"size"`
+			StorageClass string `json:"storageClass"`
+		} `json:"storage"`
+	} `json:"node"`
+	Validator struct {
+		Enabled   bool                        `json:"enabled"`
+		Resources corev1.ResourceRequirements `json:"resources"`
+	} `json:"validator"`
+	Global struct {
+		StorageClass string `json:"storageClass"`
+	} `json:"global"`
+	Service struct {
+		Ports struct {
+			RPC        int32 `json:"rpc"`
+			P2P        int32 `json:"p2p"`
+			API        int32 `json:"api"`
+			Prometheus int32 `json:"prometheus"`
+		} `json:"ports"`
+	} `json:"service"`
+	Secrets struct {
+		ExistingSecret    string `json:"existingSecret"`
+		ValidatorMnemonic string `json:"validatorMnemonic"`
+		TofndMnemonic     string `json:"tofndMnemonic"`
+		TendermintKey     string `json:"tendermintKey"`
+	} `json:"secrets"`
+	Config struct {
+		App struct {
+			MinimumGasPrices string `json:"minimumGasPrices"`
+			Pruning          string `json:"pruning"`
+			HaltHeight       int64  `json:"haltHeight"`
+			API              struct {
+				Enable             *bool       `json:"enable"`
+				Swagger            bool        `json:"swagger"`
+				MaxOpenConnections int32       `json:"maxOpenConnections"`
+				RPCReadTimeout     interface{} `json:"rpcReadTimeout"`
+				EnableUnsafeCORS   bool        `json:"enableUnsafeCors"`
+			} `json:"api"`
+		} `json:"app"`
+		Tendermint struct {
+			RPC struct {
+				CORSAllowedOrigins       []string    `json:"corsAllowedOrigins"`
+				MaxOpenConnections       int32       `json:"maxOpenConnections"`
+				MaxSubscriptionClients   int32       `json:"maxSubscriptionClients"`
+				TimeoutBroadcastTxCommit interface{} `json:"timeoutBroadcastTxCommit"`
+				Unsafe                   bool        `json:"unsafe"`
+			} `json:"rpc"`
+			P2P struct {
+				ExternalAddress              string      `json:"externalAddress"`
+				Seeds                        string      `json:"seeds"`
+				PersistentPeers              string      `json:"persistentPeers"`
+				MaxNumInboundPeers           *int32      `json:"maxNumInboundPeers"`
+				MaxNumOutboundPeers          *int32      `json:"maxNumOutboundPeers"`
+				UnconditionalPeerIDs         string      `json:"unconditionalPeerIds"`
+				PersistentPeersMaxDialPeriod interface{} `json:"persistentPeersMaxDialPeriod"`
+			} `json:"p2p"`
+			Mempool   blockchainv1alpha1.MempoolSpec `json:"mempool"`
+			Consensus struct {
+				TimeoutCommit     interface{} `json:"timeoutCommit"`
+				SkipTimeoutCommit bool        `json:"skipTimeoutCommit"`
+			} `json:"consensus"`
+		} `json:"tendermint"`
+	} `json:"config"`
+	NodeSelector map[string]string      `json:"nodeSelector"`
+	Tolerations  []interface{}          `json:"tolerations"`
+	Affinity     map[string]interface{} `json:"affinity"`
+	ExtraEnvVars []interface{}          `json:"extraEnvVars"`
+	ExtraVolumes []interface{}          `json:"extraVolumes"`
+	Ingress      struct {
+		Enabled bool `json:"enabled"`
+	} `json:"ingress"`
+}
+
+// FromHelmValues imports a release of the axelar-node chart from its values
+// files, merged in order like helm -f does
+func FromHelmValues(release string, files [][]byte, opts Options) (*Result, error) {
+	merged := map[string]interface{}{}
+	for i, data := range files {
+		values := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("values file %d: %w", i+1, err)
+		}
+		mergeValues(merged, values)
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	var values helmValues
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("unexpected values: %w", err)
+	}
+
+	fullname := helmFullname(release, values)
+	if opts.Name == "" {
+		opts.Name = fullname
+	}
+	result := newResult(opts)
+	spec := &result.Node.Spec
+
+	// Image and network
+	if values.Image.Repository != "" {
+		spec.Image.Repository = values.Image.Repository
+		if values.Image.Registry != "" {
+			spec.Image.Repository = values.Image.Registry + "/" + values.Image.Repository
+		}
+	}
+	spec.Image.Tag = values.Image.Tag
+	if opts.ImageTag != "" {
+		spec.Image.Tag = opts.ImageTag
+	}
+	spec.Image.PullPolicy = values.Image.PullPolicy
+	spec.Network = values.Network.Name
+	if spec.Network == "" {
+		spec.Network, spec.ChainID = networkOf(values.Network.ChainID)
+	}
+	spec.Moniker = values.Node.Moniker
+
+	// Node type and resources
+	spec.NodeType = "observer"
+	spec.Resources = values.Node.Resources
+	if values.DeploymentType == "validator" || values.Validator.Enabled {
+		spec.NodeType = "validator"
+		spec.Validator = &blockchainv1alpha1.ValidatorSpec{Enabled: true}
+		if len(values.Validator.Resources.Requests) > 0 || len(values.Validator.Resources.Limits) > 0 {
+			spec.Resources = values.Validator.Resources
+		}
+		result.warnf("the vald and tofnd resources are not carried over, the operator sizes the validator sidecars itself")
+	}
+
+	// Storage
+	spec.Storage.Size = values.Node.Storage.Size
+	spec.Storage.StorageClass = values.Node.Storage.StorageClass
+	if spec.Storage.StorageClass == "" {
+		spec.Storage.StorageClass = values.Global.StorageClass
+	}
+
+	// Application configuration
+	app := values.Config.App
+	spec.MinimumGasPrices = app.MinimumGasPrices
+	spec.Pruning = app.Pruning
+	spec.HaltHeight = app.HaltHeight
+	if app.API.Enable != nil {
+		spec.Networking.API.Enabled = *app.API.Enable
+	}
+	spec.Networking.API.Swagger = app.API.Swagger
+	spec.Networking.API.MaxOpenConnections = app.API.MaxOpenConnections
+	spec.Networking.API.EnableUnsafeCORS = app.API.EnableUnsafeCORS
+	if d, ok := duration(app.API.RPCReadTimeout); ok {
+		spec.Networking.API.RPCReadTimeout = d
+	}
+
+	// Tendermint configuration
+	tm := values.Config.Tendermint
+	spec.Networking.RPC.CORS = len(tm.RPC.CORSAllowedOrigins) > 0
+	spec.Networking.RPC.CORSAllowedOrigins = tm.RPC.CORSAllowedOrigins
+	spec.Networking.RPC.MaxOpenConnections = tm.RPC.MaxOpenConnections
+	spec.Networking.RPC.MaxSubscriptionClients = tm.RPC.MaxSubscriptionClients
+	spec.Networking.RPC.Unsafe = tm.RPC.Unsafe
+	if d, ok := duration(tm.RPC.TimeoutBroadcastTxCommit); ok {
+		spec.Networking.RPC.TimeoutBroadcastTxCommit = d
+	}
+	p2p := &spec.Networking.P2P
+	p2p.ExternalAddress = tm.P2P.ExternalAddress
+	p2p.Seeds = peerList(tm.P2P.Seeds)
+	p2p.PersistentPeers = peerList(tm.P2P.PersistentPeers)
+	p2p.MaxNumInboundPeers = tm.P2P.MaxNumInboundPeers
+	p2p.MaxNumOutboundPeers = tm.P2P.MaxNumOutboundPeers
+	p2p.UnconditionalPeerIDs = peerList(tm.P2P.UnconditionalPeerIDs)
+	if d, ok := duration(tm.P2P.PersistentPeersMaxDialPeriod); ok {
+		p2p.PersistentPeersMaxDialPeriod = d
+	}
+	spec.Config.Tendermint.Mempool = tm.Mempool
+	spec.Config.Tendermint.Consensus.SkipTimeoutCommit = tm.Consensus.SkipTimeoutCommit
+	if d, ok := duration(tm.Consensus.TimeoutCommit); ok {
+		spec.Config.Tendermint.Consensus.TimeoutCommit = d
+	}
+
+	// Ports
+	ports := values.Service.Ports
+	spec.Networking.RPC.Port = ports.RPC
+	spec.Networking.P2P.Port = ports.P2P
+	spec.Networking.API.Port = ports.API
+	spec.Monitoring.Prometheus.Port = ports.Prometheus
+
+	// Secrets
+	secretName := values.Secrets.ExistingSecret
+	if secretName == "" {
+		secretName = fullname + "-secrets"
+	}
+	if opts.Adopt || values.Secrets.ExistingSecret != "" {
+		spec.Security.SecretManagement.SecretName = secretName
+	}
+	if values.Secrets.ValidatorMnemonic != "" || values.Secrets.TofndMnemonic != "" || values.Secrets.TendermintKey != "" {
+		result.warnf("the validator mnemonic, tofnd mnemonic and Tendermint key of secret %s are not carried over, import them with an AxelarNodeKeyBackup restore or into the data volume", secretName)
+	}
+
+	// Adoption of the release
+	if opts.Adopt {
+		result.Node.Annotations = map[string]string{blockchainv1alpha1.AdoptAnnotation: "true"}
+		deploymentType := values.DeploymentType
+		if deploymentType == "" {
+			deploymentType = "node"
+		}
+		spec.Storage.ExistingClaim = fmt.Sprintf("%s-%s-data", fullname, deploymentType)
+		if opts.Name != fullname {
+			result.warnf("the node is named %s but the release resources are named %s, so only the data PVC is adopted: scale the release Deployment down before applying the node", opts.Name, fullname)
+		}
+		result.warnf("annotate the resources of release %s with helm.sh/resource-policy=keep before uninstalling it, or Helm deletes them", release)
+	}
+
+	// Settings the operator manages differently
+	if len(values.NodeSelector) > 0 || len(values.Tolerations) > 0 || len(values.Affinity) > 0 {
+		result.warnf("nodeSelector, tolerations and affinity are not carried over, the operator spreads nodes with spec.zone")
+	}
+	if len(values.ExtraEnvVars) > 0 || len(values.ExtraVolumes) > 0 {
+		result.warnf("extraEnvVars and extraVolumes are not carried over, use spec.storage.volumes for additional volumes")
+	}
+	if values.Ingress.Enabled {
+		result.warnf("the ingress is not carried over, use spec.networking.gatewayAPI or spec.networking.public")
+	}
+	return result, nil
+}
+
+// helmFullname returns the full name the chart gives the resources of a release
+func helmFullname(release string, values helmValues) string {
+	if values.FullnameOverride != "" {
+		return truncateName(values.FullnameOverride)
+	}
+	name := chartName
+	if values.NameOverride != "" {
+		name = values.NameOverride
+	}
+	if strings.Contains(release, name) {
+		return truncateName(release)
+	}
+	return truncateName(release + "-" + name)
+}
+
+// truncateName truncates a name like the chart does
+func truncateName(name string) string {
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.TrimSuffix(name, "-")
+}
+
+// mergeValues merges src into dst, recursively for maps
+func mergeValues(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if srcMap, ok := value.(map[string]interface{}); ok {
+			if dstMap, ok := dst[key].(map[string]interface{}); ok {
+				mergeValues(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
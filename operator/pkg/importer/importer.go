@@ -0,0 +1,132 @@
+// Package importer converts the configuration of a node run outside the
+// operator, from the axelar-node Helm chart or an axelard home directory, into
+// an equivalent AxelarNode.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/networks"
+)
+
+// Options configures the AxelarNode an import produces
+type Options struct {
+	// Name of the AxelarNode. Helm imports default to the full name of the
+	// release, so the Deployment and Service of the release can be adopted.
+	Name string
+	// Namespace of the AxelarNode
+	Namespace string
+	// Adopt prepares the node to take over the Deployment, Service and data
+	// PVC of a Helm release instead of creating new ones
+	Adopt bool
+	// ImageTag overrides the axelar-core version, which an axelard home
+	// directory does not record
+	ImageTag string
+}
+
+// Result is the outcome of an import
+type Result struct {
+	// Node is the imported AxelarNode
+	Node *blockchainv1alpha1.AxelarNode
+	// Warnings list the settings that were not carried over
+	Warnings []string
+}
+
+// warnf records a setting that was not carried over
+func (r *Result) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// newResult returns a result holding an empty node
+func newResult(opts Options) *Result {
+	return &Result{Node: &blockchainv1alpha1.AxelarNode{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: blockchainv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "AxelarNode",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.Name,
+			Namespace: opts.Namespace,
+		},
+	}}
+}
+
+// networkOf returns the built-in network with the chain ID. Unknown chain
+// IDs are kept as an override of the testnet profile.
+func networkOf(chainID string) (string, string) {
+	for _, name := range networks.Names() {
+		if profile, ok := networks.Lookup(name); ok && profile.ChainID == chainID {
+			return name, ""
+		}
+	}
+	return networks.Testnet, chainID
+}
+
+// peerList splits a comma separated list of peers
+func peerList(value string) []string {
+	var peers []string
+	for _, peer := range strings.Split(value, ",") {
+		if peer = strings.TrimSpace(peer); peer != "" {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
+
+// duration parses a Go duration, or a number of seconds
+func duration(value interface{}) (metav1.Duration, bool) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return metav1.Duration{}, false
+		}
+		d, err := time.ParseDuration(v)
+		return metav1.Duration{Duration: d}, err == nil
+	case float64:
+		return metav1.Duration{Duration: time.Duration(v * float64(time.Second))}, true
+	case int64:
+		return metav1.Duration{Duration: time.Duration(v) * time.Second}, true
+	}
+	return metav1.Duration{}, false
+}
+
+// Manifest renders the node as YAML, leaving out empty fields and the status
+func Manifest(node *blockchainv1alpha1.AxelarNode) ([]byte, error) {
+	data, err := json.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	delete(object, "status")
+	prune(object)
+	return yaml.Marshal(object)
+}
+
+// prune removes the empty values of an object, recursively
+func prune(object map[string]interface{}) {
+	for key, value := range object {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			prune(v)
+			if len(v) == 0 {
+				delete(object, key)
+			}
+		case nil:
+			delete(object, key)
+		case string:
+			if v == "" {
+				delete(object, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package integrity checks the chain data of a node against a trusted RPC,
+// catching silent corruption of the block store and the application state
+// before it surfaces as a consensus failure.
+package integrity
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tendermint"
+)
+
+// maxMismatches bounds the mismatched blocks reported, so the result fits a
+// termination message
+const maxMismatches = 10
+
+// batchSize is the number of blocks the RPC returns per /blockchain call
+const batchSize = 20
+
+// Options selects the node checked and the RPC it is checked against
+type Options struct {
+	// Node is the RPC URL of the node checked
+	Node string
+	// Trusted is the RPC URL of a node of the same network trusted to hold sound data
+	Trusted string
+	// Blocks is the number of latest blocks compared
+	Blocks int64
+}
+
+// BindFlags registers the options on fs
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Node, "node", "", "The RPC URL of the node checked.")
+	fs.StringVar(&o.Trusted, "trusted", "", "The RPC URL of the trusted node the blocks are compared with.")
+	fs.Int64Var(&o.Blocks, "blocks", 100, "The number of latest blocks compared.")
+}
+
+// Mismatch is a block the node and the trusted RPC disagree on
+type Mismatch struct {
+	Height int64 `json:"height"`
+	// Fields of the header that differ, or block_id when only the hash does
+	Fields []string `json:"fields"`
+}
+
+// Result describes a completed check
+type Result struct {
+	// FromHeight and ToHeight bound the blocks compared
+	FromHeight int64 `json:"fromHeight"`
+	ToHeight   int64 `json:"toHeight"`
+	// Checked is the number of blocks compared
+	Checked int64 `json:"checked"`
+	// Mismatches are the first blocks that differ
+	Mismatches []Mismatch `json:"mismatches,omitempty"`
+	// AppHashHeight is the height whose committed state was compared, or
+	// zero when the trusted RPC had no header after it yet
+	AppHashHeight int64 `json:"appHashHeight,omitempty"`
+	// AppHashMismatch reports that the committed state differs
+	AppHashMismatch bool `json:"appHashMismatch,omitempty"`
+}
+
+// Corrupted reports whether the check found data differing from the trusted RPC
+func (r *Result) Corrupted() bool {
+	return len(r.Mismatches) > 0 || r.AppHashMismatch
+}
+
+// Check compares the latest blocks of the node, and its committed app hash,
+// with those of the trusted RPC
+func Check(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Node == "" || opts.Trusted == "" {
+		return nil, errors.New("a node and a trusted RPC are required")
+	}
+	if opts.Blocks < 1 {
+		return nil, fmt.Errorf("blocks is %d, compare at least one block", opts.Blocks)
+	}
+	// The node is in the cluster, only the trusted RPC goes through the proxy
+	node := tendermint.NewClient(strings.TrimSuffix(opts.Node, "/"))
+	direct := http.DefaultTransport.(*http.Transport).Clone()
+	direct.Proxy = nil
+	node.HTTPClient = &http.Client{Timeout: tendermint.DefaultTimeout, Transport: direct}
+	trusted := tendermint.NewClient(strings.TrimSuffix(opts.Trusted, "/"))
+	trusted.HTTPClient = outbound.Client(tendermint.DefaultTimeout)
+
+	// The app hash first, as the node keeps committing blocks meanwhile
+	info, err := node.ABCIInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("node: %w", err)
+	}
+	nodeStatus, err := node.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("node: %w", err)
+	}
+	trustedStatus, err := trusted.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("trusted RPC: %w", err)
+	}
+	if nodeStatus.NodeInfo.Network != trustedStatus.NodeInfo.Network {
+		return nil, fmt.Errorf("the node is on %s but the trusted RPC on %s", nodeStatus.NodeInfo.Network, trustedStatus.NodeInfo.Network)
+	}
+
+	result := &Result{ToHeight: min(nodeStatus.SyncInfo.Height(), trustedStatus.SyncInfo.Height())}
+	result.FromHeight = max(result.ToHeight-opts.Blocks+1, nodeStatus.SyncInfo.EarliestHeight(), trustedStatus.SyncInfo.EarliestHeight(), 1)
+	if result.ToHeight < result.FromHeight {
+		return nil, fmt.Errorf("the node and the trusted RPC share no blocks")
+	}
+
+	for high := result.ToHeight; high >= result.FromHeight; high -= batchSize {
+		low := max(high-batchSize+1, result.FromHeight)
+		ours, err := blockMetas(ctx, node, low, high)
+		if err != nil {
+			return nil, fmt.Errorf("node: %w", err)
+		}
+		theirs, err := blockMetas(ctx, trusted, low, high)
+		if err != nil {
+			return nil, fmt.Errorf("trusted RPC: %w", err)
+		}
+		for height := high; height >= low; height-- {
+			a, okA := ours[height]
+			b, okB := theirs[height]
+			if !okA || !okB {
+				return nil, fmt.Errorf("block %d is missing from the node or the trusted RPC", height)
+			}
+			result.Checked++
+			if fields := differences(a, b); len(fields) > 0 && len(result.Mismatches) < maxMismatches {
+				result.Mismatches = append(result.Mismatches, Mismatch{Height: height, Fields: fields})
+			}
+		}
+	}
+
+	// The header of the next block commits to the state after this one
+	committed, _ := strconv.ParseInt(info.Response.LastBlockHeight, 10, 64)
+	if committed > 0 && committed+1 <= trustedStatus.SyncInfo.Height() {
+		next, err := blockMetas(ctx, trusted, committed+1, committed+1)
+		if err != nil {
+			return nil, fmt.Errorf("trusted RPC: %w", err)
+		}
+		if meta, ok := next[committed+1]; ok {
+			appHash, err := base64.StdEncoding.DecodeString(info.Response.LastBlockAppHash)
+			if err != nil {
+				return nil, fmt.Errorf("node: invalid app hash %q", info.Response.LastBlockAppHash)
+			}
+			result.AppHashHeight = committed
+			result.AppHashMismatch = !strings.EqualFold(hex.EncodeToString(appHash), meta.Header.AppHash)
+		}
+	}
+	return result, nil
+}
+
+// blockMetas returns the block metadata between low and high by height
+func blockMetas(ctx context.Context, c *tendermint.Client, low, high int64) (map[int64]tendermint.BlockMeta, error) {
+	result, err := c.Blockchain(ctx, low, high)
+	if err != nil {
+		return nil, err
+	}
+	metas := make(map[int64]tendermint.BlockMeta, len(result.BlockMetas))
+	for _, meta := range result.BlockMetas {
+		height, err := strconv.ParseInt(meta.Header.Height, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block height %q", meta.Header.Height)
+		}
+		metas[height] = meta
+	}
+	return metas, nil
+}
+
+// differences returns the header fields of two blocks that differ
+func differences(a, b tendermint.BlockMeta) []string {
+	var fields []string
+	for _, field := range []struct{ name, a, b string }{
+		{"app_hash", a.Header.AppHash, b.Header.AppHash},
+		{"data_hash", a.Header.DataHash, b.Header.DataHash},
+		{"last_results_hash", a.Header.LastResultsHash, b.Header.LastResultsHash},
+		{"validators_hash", a.Header.ValidatorsHash, b.Header.ValidatorsHash},
+	} {
+		if !strings.EqualFold(field.a, field.b) {
+			fields = append(fields, field.name)
+		}
+	}
+	if len(fields) == 0 && !strings.EqualFold(a.BlockID.Hash, b.BlockID.Hash) {
+		fields = append(fields, "block_id")
+	}
+	return fields
+}
@@ -0,0 +1,53 @@
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ParseSchedule parses a cron schedule in the format of the maintenance windows
+func ParseSchedule(spec string) (cron.Schedule, error) {
+	return parser.Parse(spec)
+}
+
+// Open reports whether now falls inside one of the maintenance windows.
+// When it does not, the start of the next window is returned as well.
+// A nil spec or a spec without windows is always open.
+func Open(spec *blockchainv1alpha1.MaintenanceWindowSpec, now time.Time) (bool, time.Time, error) {
+	if spec == nil || len(spec.Windows) == 0 {
+		return true, now, nil
+	}
+
+	loc := time.UTC
+	if spec.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(spec.Timezone); err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid maintenance window timezone %q: %w", spec.Timezone, err)
+		}
+	}
+	now = now.In(loc)
+
+	var next time.Time
+	for _, window := range spec.Windows {
+		schedule, err := parser.Parse(window.Schedule)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid maintenance window schedule %q: %w", window.Schedule, err)
+		}
+
+		// The window is open if it started within the last Duration
+		if start := schedule.Next(now.Add(-window.Duration.Duration)); !start.After(now) {
+			return true, now, nil
+		}
+
+		if start := schedule.Next(now); next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return false, next, nil
+}
@@ -0,0 +1,167 @@
+// Package metricsauth restricts the metrics endpoint to the identities allowed
+// to get it. Bearer tokens are checked with a TokenReview and the request with
+// a SubjectAccessReview on its non-resource URL, as kube-rbac-proxy does.
+package metricsauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// Decisions are cached so every scrape does not cost two API requests
+const (
+	allowedTTL = time.Minute
+	deniedTTL  = 10 * time.Second
+	maxCached  = 1024
+)
+
+// decision is the cached outcome of a request, as an HTTP status
+type decision struct {
+	status  int
+	expires time.Time
+}
+
+// authorizer reviews the tokens and access of metrics requests
+type authorizer struct {
+	tokens authenticationv1client.TokenReviewInterface
+	access authorizationv1client.SubjectAccessReviewInterface
+
+	mu    sync.Mutex
+	cache map[string]decision
+}
+
+// WithAuthenticationAndAuthorization is a metrics server FilterProvider
+// answering 401 to requests without a valid bearer token, and 403 to those
+// whose identity may not use the verb of the request on its path.
+func WithAuthenticationAndAuthorization(config *rest.Config, httpClient *http.Client) (server.Filter, error) {
+	authn, err := authenticationv1client.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	authz, err := authorizationv1client.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	a := &authorizer{
+		tokens: authn.TokenReviews(),
+		access: authz.SubjectAccessReviews(),
+		cache:  map[string]decision{},
+	}
+
+	return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			token, ok := bearerToken(req)
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			status, err := a.authorize(req.Context(), token, strings.ToLower(req.Method), req.URL.Path)
+			if err != nil {
+				log.Error(err, "Unable to review metrics request", "path", req.URL.Path)
+				http.Error(w, "Authorization failed", http.StatusInternalServerError)
+				return
+			}
+			if status != http.StatusOK {
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			handler.ServeHTTP(w, req)
+		}), nil
+	}, nil
+}
+
+// bearerToken returns the bearer token of a request
+func bearerToken(req *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// authorize returns the HTTP status a request gets: 200 when allowed, 401
+// when its token is invalid and 403 when its identity is denied
+func (a *authorizer) authorize(ctx context.Context, token, verb, path string) (int, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:]) + " " + verb + " " + path
+	if status, ok := a.cached(key); ok {
+		return status, nil
+	}
+
+	review, err := a.tokens.Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if !review.Status.Authenticated {
+		a.store(key, http.StatusUnauthorized)
+		return http.StatusUnauthorized, nil
+	}
+
+	user := review.Status.User
+	extra := map[string]authorizationv1.ExtraValue{}
+	for name, values := range user.Extra {
+		extra[name] = authorizationv1.ExtraValue(values)
+	}
+	access, err := a.access.Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: path,
+				Verb: verb,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return 0, err
+	}
+	status := http.StatusForbidden
+	if access.Status.Allowed {
+		status = http.StatusOK
+	}
+	a.store(key, status)
+	return status, nil
+}
+
+// cached returns an unexpired decision
+func (a *authorizer) cached(key string) (int, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	d, ok := a.cache[key]
+	if !ok || time.Now().After(d.expires) {
+		return 0, false
+	}
+	return d.status, true
+}
+
+// store caches a decision. The cache is emptied when full.
+func (a *authorizer) store(key string, status int) {
+	ttl := deniedTTL
+	if status == http.StatusOK {
+		ttl = allowedTTL
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.cache) >= maxCached {
+		a.cache = map[string]decision{}
+	}
+	a.cache[key] = decision{status: status, expires: time.Now().Add(ttl)}
+}
@@ -0,0 +1,191 @@
+// Package migration rewrites the custom resources of the operator in the
+// storage version of their CRD, so versions dropped by a later release no
+// longer hold objects in etcd.
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// pageSize is the number of objects listed per request
+const pageSize = 100
+
+// crdGVK is read as unstructured, so the operator does not depend on the
+// apiextensions types
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// Result describes the migration of the objects of a CRD
+type Result struct {
+	CRD string `json:"crd"`
+	// StorageVersion is the version the objects were rewritten in
+	StorageVersion string `json:"storageVersion"`
+	// StoredVersions are the versions etcd held objects in before the migration
+	StoredVersions []string `json:"storedVersions"`
+	// Migrated is the number of objects rewritten
+	Migrated int `json:"migrated"`
+	// Skipped reports that every object was already in the storage version
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// Migrator migrates the objects of the CRDs of the operator
+type Migrator struct {
+	client client.Client
+	log    logr.Logger
+}
+
+// New creates a migrator
+func New(c client.Client, log logr.Logger) *Migrator {
+	return &Migrator{client: c, log: log}
+}
+
+// NeedLeaderElection migrates from the leader only, so replicas do not
+// rewrite the same objects
+func (m *Migrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start migrates once when the operator starts. A failed migration is
+// logged and retried at the next start, it does not stop the operator.
+func (m *Migrator) Start(ctx context.Context) error {
+	if _, err := m.Run(ctx); err != nil {
+		m.log.Error(err, "Unable to migrate the stored versions, they are migrated again at the next start")
+	}
+	return nil
+}
+
+// Run rewrites the objects of every CRD of the operator whose stored
+// versions are not its storage version alone, then records that etcd only
+// holds the storage version
+func (m *Migrator) Run(ctx context.Context) ([]Result, error) {
+	crds := &unstructured.UnstructuredList{}
+	crds.SetGroupVersionKind(crdGVK.GroupVersion().WithKind(crdGVK.Kind + "List"))
+	if err := m.client.List(ctx, crds); err != nil {
+		return nil, fmt.Errorf("listing CRDs: %w", err)
+	}
+
+	var results []Result
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		if group != blockchainv1alpha1.SchemeGroupVersion.Group {
+			continue
+		}
+		result, err := m.migrate(ctx, crd)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", crd.GetName(), err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// migrate rewrites the objects of crd in its storage version
+func (m *Migrator) migrate(ctx context.Context, crd *unstructured.Unstructured) (*Result, error) {
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	storedVersions, _, _ := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	result := &Result{CRD: crd.GetName(), StorageVersion: storageVersion(crd), StoredVersions: storedVersions}
+	if result.StorageVersion == "" {
+		return nil, fmt.Errorf("no version is the storage version")
+	}
+	if len(storedVersions) == 1 && storedVersions[0] == result.StorageVersion {
+		result.Skipped = true
+		return result, nil
+	}
+	log := m.log.WithValues("crd", result.CRD, "storageVersion", result.StorageVersion, "storedVersions", storedVersions)
+	log.Info("Migrating the stored versions")
+
+	gvk := schema.GroupVersionKind{Group: blockchainv1alpha1.SchemeGroupVersion.Group, Version: result.StorageVersion, Kind: kind}
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(kind + "List"))
+		if err := m.client.List(ctx, list, client.Limit(pageSize), client.Continue(continueToken)); err != nil {
+			return nil, fmt.Errorf("listing %s: %w", kind, err)
+		}
+		for i := range list.Items {
+			if err := m.rewrite(ctx, gvk, &list.Items[i]); err != nil {
+				return nil, err
+			}
+			result.Migrated++
+		}
+		remaining := int64(0)
+		if count := list.GetRemainingItemCount(); count != nil {
+			remaining = *count
+		}
+		log.Info("Migrated objects", "migrated", result.Migrated, "remaining", remaining)
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	// Every object is now in the storage version, the older ones can be
+	// removed from the CRD by a later release
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &unstructured.Unstructured{}
+		latest.SetGroupVersionKind(crdGVK)
+		if err := m.client.Get(ctx, types.NamespacedName{Name: result.CRD}, latest); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedStringSlice(latest.Object, []string{result.StorageVersion}, "status", "storedVersions"); err != nil {
+			return err
+		}
+		return m.client.Status().Update(ctx, latest)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("updating the stored versions: %w", err)
+	}
+	log.Info("Migrated the stored versions", "migrated", result.Migrated)
+	return result, nil
+}
+
+// rewrite updates obj unchanged, which has the API server store it in the
+// storage version. Objects deleted meanwhile are skipped.
+func (m *Migrator) rewrite(ctx context.Context, gvk schema.GroupVersionKind, obj *unstructured.Unstructured) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := m.client.Update(ctx, obj)
+		if apierrors.IsConflict(err) {
+			latest := &unstructured.Unstructured{}
+			latest.SetGroupVersionKind(gvk)
+			if getErr := m.client.Get(ctx, client.ObjectKeyFromObject(obj), latest); getErr != nil {
+				return getErr
+			}
+			*obj = *latest
+		}
+		return err
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("rewriting %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+	return nil
+}
+
+// storageVersion returns the version crd stores objects in
+func storageVersion(crd *unstructured.Unstructured) string {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, version := range versions {
+		v, ok := version.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if storage, _ := v["storage"].(bool); storage {
+			name, _ := v["name"].(string)
+			return name
+		}
+	}
+	return ""
+}
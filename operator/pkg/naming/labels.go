@@ -0,0 +1,120 @@
+package naming
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// JobComponent is the component of the Jobs and CronJobs a resource runs
+const JobComponent = "job"
+
+// Instance describes the resource the objects written with a context are
+// generated for, and the recommended labels they carry
+type Instance struct {
+	// Owner is the UID of the resource. Only the objects it controls are labelled.
+	Owner types.UID
+
+	// Name is the application, such as axelar-node
+	Name string
+
+	// Instance is the name of the resource
+	Instance string
+
+	// Component is the part of the application the objects belong to when
+	// they do not set one, such as node
+	Component string
+
+	// Version is the version of the application, usually the image tag
+	Version string
+}
+
+// Labels returns the recommended labels of the objects of component
+func (i Instance) Labels(component string) map[string]string {
+	labels := map[string]string{
+		blockchainv1alpha1.NameLabel:      i.Name,
+		blockchainv1alpha1.InstanceLabel:  LabelValue(i.Instance),
+		blockchainv1alpha1.ComponentLabel: component,
+		blockchainv1alpha1.ManagedByLabel: blockchainv1alpha1.ManagedBy,
+	}
+	if i.Version != "" {
+		labels[blockchainv1alpha1.VersionLabel] = LabelValue(i.Version)
+	}
+	return labels
+}
+
+type instanceKey struct{}
+
+// WithInstance labels the objects written with ctx that are controlled by
+// the resource of instance
+func WithInstance(ctx context.Context, instance Instance) context.Context {
+	return context.WithValue(ctx, instanceKey{}, instance)
+}
+
+// label sets the recommended labels of the instance carried by ctx on obj,
+// when the instance controls it. A component set by the caller is kept.
+func label(ctx context.Context, obj client.Object) {
+	instance, ok := ctx.Value(instanceKey{}).(Instance)
+	if !ok {
+		return
+	}
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.UID != instance.Owner {
+		return
+	}
+
+	// Nodes created by a network are resources of their own, labelled by users
+	component := instance.Component
+	switch obj.(type) {
+	case *blockchainv1alpha1.AxelarNode:
+		return
+	case *batchv1.Job, *batchv1.CronJob:
+		component = JobComponent
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if current := labels[blockchainv1alpha1.ComponentLabel]; current != "" {
+		component = current
+	}
+	for key, value := range instance.Labels(component) {
+		labels[key] = value
+	}
+	obj.SetLabels(labels)
+}
+
+// labelClient labels the objects written through the wrapped client
+type labelClient struct {
+	client.Client
+}
+
+// Client wraps c so the objects it creates, updates and patches carry the
+// recommended labels of the instance in their context
+func Client(c client.Client) client.Client {
+	return &labelClient{Client: c}
+}
+
+// Create labels obj and creates it
+func (c *labelClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	label(ctx, obj)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// Update labels obj and updates it
+func (c *labelClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	label(ctx, obj)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// Patch labels obj and patches it. Merge patches computed from obj include
+// the labels.
+func (c *labelClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	label(ctx, obj)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
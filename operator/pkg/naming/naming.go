@@ -0,0 +1,68 @@
+// Package naming derives the names and label values of the objects the
+// operator generates from the names of the resources they belong to. Names
+// that fit are kept as they are, so existing objects keep their names. Longer
+// ones are truncated and end with a hash of the full name, so they stay within
+// the Kubernetes limits and two long names sharing a prefix do not collide.
+// The objects are labelled with the Kubernetes recommended labels of the
+// resource they are generated for.
+package naming
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// MaxLength is the length of a DNS label, the limit of Service names and
+	// of the names Jobs and StatefulSets copy into pod labels
+	MaxLength = 63
+
+	// CronJobMaxLength leaves room for the suffix the CronJob controller
+	// appends to the names of its Jobs
+	CronJobMaxLength = 52
+
+	// hashLength is the number of hex digits of the hash ending a truncated name
+	hashLength = 8
+)
+
+// Name joins base and suffixes with dashes into a name of at most MaxLength
+// characters
+func Name(base string, suffixes ...string) string {
+	return Truncate(join(base, suffixes), MaxLength)
+}
+
+// CronJobName joins base and suffixes into a CronJob name of at most
+// CronJobMaxLength characters
+func CronJobName(base string, suffixes ...string) string {
+	return Truncate(join(base, suffixes), CronJobMaxLength)
+}
+
+// Truncate shortens name to max characters, replacing its end with a hash of
+// the full name. Names of max characters or less are returned unchanged.
+func Truncate(name string, max int) string {
+	if len(name) <= max {
+		return name
+	}
+	prefix := strings.TrimRight(name[:max-hashLength-1], "-.")
+	return prefix + "-" + hash(name)
+}
+
+// LabelValue returns value as a valid label value, truncated like a name when
+// it is longer than MaxLength characters
+func LabelValue(value string) string {
+	if len(value) <= MaxLength {
+		return value
+	}
+	prefix := strings.TrimRight(value[:MaxLength-hashLength-1], "-_.")
+	return prefix + "-" + hash(value)
+}
+
+func join(base string, suffixes []string) string {
+	return strings.Join(append([]string{base}, suffixes...), "-")
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:hashLength]
+}
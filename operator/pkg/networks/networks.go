@@ -0,0 +1,216 @@
+// Package networks holds what the operator knows about each Axelar network:
+// its chain ID, seeds, gas prices and genesis. Mainnet and testnet are built
+// in, and administrators register other networks, such as devnets or new
+// testnets, in a ConfigMap without a new operator release.
+package networks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/chainregistry"
+)
+
+// Names of the built-in networks
+const (
+	Mainnet = "mainnet"
+	Testnet = "testnet"
+)
+
+// Profile is the default configuration of the nodes joining a network. Spec
+// fields of a node override it.
+type Profile struct {
+	// Name the nodes select the network by
+	Name string `json:"name"`
+
+	// ChainID of the network
+	ChainID string `json:"chainId"`
+
+	// MinimumGasPrices the nodes accept transactions at
+	MinimumGasPrices string `json:"minimumGasPrices,omitempty"`
+
+	// GenesisURL the genesis file is downloaded from
+	GenesisURL string `json:"genesisURL,omitempty"`
+
+	// RegistryURL of the chain.json peers are refreshed from
+	RegistryURL string `json:"registryURL,omitempty"`
+
+	// Seeds used when the node lists none, as id@host:port
+	Seeds []string `json:"seeds,omitempty"`
+
+	// PersistentPeers used when the node lists none, as id@host:port
+	PersistentPeers []string `json:"persistentPeers,omitempty"`
+
+	// ReferenceRPCs are trusted public RPC endpoints the operator learns the
+	// chain head from, rather than from the nodes themselves
+	ReferenceRPCs []string `json:"referenceRPCs,omitempty"`
+
+	// Production networks never enable the unsafe RPC routes
+	Production bool `json:"production,omitempty"`
+}
+
+// builtin are the networks known without configuration
+var builtin = map[string]Profile{
+	Mainnet: {
+		Name:             Mainnet,
+		ChainID:          "axelar-dojo-1",
+		MinimumGasPrices: "0.007uaxl",
+		GenesisURL:       "https://axelar-mainnet.s3.us-east-2.amazonaws.com/genesis.json",
+		RegistryURL:      chainregistry.MainnetURL,
+		Production:       true,
+	},
+	Testnet: {
+		Name:             Testnet,
+		ChainID:          "axelar-testnet-lisbon-3",
+		MinimumGasPrices: "0.007uaxl",
+		GenesisURL:       "https://axelar-testnet.s3.us-east-2.amazonaws.com/genesis.json",
+		RegistryURL:      chainregistry.TestnetURL,
+	},
+}
+
+var (
+	mu         sync.RWMutex
+	registered = map[string]Profile{}
+)
+
+// Lookup returns the profile of a network. Registered networks shadow the
+// built-in ones of the same name.
+func Lookup(name string) (Profile, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if profile, ok := registered[name]; ok {
+		return profile, true
+	}
+	profile, ok := builtin[name]
+	return profile, ok
+}
+
+// Names returns the known networks, sorted
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	seen := map[string]bool{}
+	var names []string
+	for _, profiles := range []map[string]Profile{builtin, registered} {
+		for name := range profiles {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Register replaces the registered networks with profiles
+func Register(profiles ...Profile) {
+	mu.Lock()
+	defer mu.Unlock()
+	registered = make(map[string]Profile, len(profiles))
+	for _, profile := range profiles {
+		registered[profile.Name] = profile
+	}
+}
+
+// Parse reads the profiles of a ConfigMap. Each key names a network and holds
+// its profile as YAML or JSON.
+func Parse(configMap *corev1.ConfigMap) ([]Profile, error) {
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	profiles := make([]Profile, 0, len(keys))
+	for _, key := range keys {
+		profile := Profile{}
+		decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(configMap.Data[key]), 4096)
+		if err := decoder.Decode(&profile); err != nil {
+			return nil, fmt.Errorf("network %s: %w", key, err)
+		}
+		if profile.Name == "" {
+			profile.Name = key
+		}
+		if profile.Name != key {
+			return nil, fmt.Errorf("network %s names itself %s", key, profile.Name)
+		}
+		if profile.ChainID == "" {
+			return nil, fmt.Errorf("network %s has no chainId", key)
+		}
+		for _, endpoint := range profile.ReferenceRPCs {
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("network %s has an invalid reference RPC %q", key, endpoint)
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// Loader registers the networks of a ConfigMap and keeps them current
+type Loader struct {
+	// Reader reads the ConfigMap, bypassing the cache so its namespace
+	// needs no informer
+	Reader client.Reader
+
+	// ConfigMap holding the profiles
+	ConfigMap types.NamespacedName
+
+	// Interval between reloads
+	Interval time.Duration
+
+	Log logr.Logger
+}
+
+// Load registers the networks of the ConfigMap. A missing ConfigMap
+// registers none.
+func (l *Loader) Load(ctx context.Context) error {
+	configMap := &corev1.ConfigMap{}
+	if err := l.Reader.Get(ctx, l.ConfigMap, configMap); errors.IsNotFound(err) {
+		Register()
+		return nil
+	} else if err != nil {
+		return err
+	}
+	profiles, err := Parse(configMap)
+	if err != nil {
+		return err
+	}
+	Register(profiles...)
+	return nil
+}
+
+// Start reloads the ConfigMap until ctx is done. Invalid ConfigMaps are
+// logged and the networks loaded before are kept.
+func (l *Loader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(l.Interval)
+	defer ticker.Stop()
+	for {
+		if err := l.Load(ctx); err != nil {
+			l.Log.Error(err, "unable to load networks", "configMap", l.ConfigMap)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection is false, as every replica renders node configuration
+func (l *Loader) NeedLeaderElection() bool {
+	return false
+}
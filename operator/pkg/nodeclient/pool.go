@@ -0,0 +1,267 @@
+// Package nodeclient shares the HTTP connections the operator opens to the
+// RPC and REST APIs of the nodes, and bounds the requests sent to each node
+// with a rate limit, a timeout and a circuit breaker, so polling a large
+// fleet does not open unbounded connections or pile up on a stuck node.
+package nodeclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit
+// of a node is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Options bounds the requests sent to each node
+type Options struct {
+	// QPS is the sustained rate of requests per node
+	QPS float64
+	// Burst is the number of requests per node allowed above QPS
+	Burst int
+	// Timeout applies to requests that set none
+	Timeout time.Duration
+	// MaxConnsPerNode caps the connections open to each node
+	MaxConnsPerNode int
+	// FailureThreshold is the number of consecutive failures opening the circuit
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open before a request probes the node
+	OpenDuration time.Duration
+}
+
+// DefaultOptions are used until Configure is called
+var DefaultOptions = Options{
+	QPS:              5,
+	Burst:            10,
+	Timeout:          10 * time.Second,
+	MaxConnsPerNode:  4,
+	FailureThreshold: 5,
+	OpenDuration:     30 * time.Second,
+}
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "axelar_operator_node_requests_total",
+		Help: "Requests sent by the operator to the RPC and REST APIs of nodes, by result: success, error, rate_limited or circuit_open",
+	}, []string{"namespace", "name", "result"})
+	circuitOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "axelar_operator_node_circuit_open",
+		Help: "Whether the circuit of a node is open, so the operator sends it no requests",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, circuitOpen)
+}
+
+var (
+	mu      sync.Mutex
+	current = DefaultOptions
+	nodes   = map[string]*node{}
+	// shared carries the requests of every node without transport settings of its own
+	shared = newTransport(nil, DefaultOptions)
+)
+
+// node holds the limits and the custom transport of a node
+type node struct {
+	namespace, name string
+	limiter         *rate.Limiter
+
+	mu          sync.Mutex
+	failures    int
+	openUntil   time.Time
+	probing     bool
+	fingerprint string
+	transport   *http.Transport
+}
+
+// Configure sets the limits of the requests sent to nodes. Nodes already
+// polled keep their limiters until they are forgotten.
+func Configure(opts Options) {
+	if opts.QPS <= 0 {
+		opts.QPS = DefaultOptions.QPS
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = DefaultOptions.Burst
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultOptions.Timeout
+	}
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = DefaultOptions.FailureThreshold
+	}
+	if opts.OpenDuration <= 0 {
+		opts.OpenDuration = DefaultOptions.OpenDuration
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = opts
+	shared.CloseIdleConnections()
+	shared = newTransport(nil, opts)
+}
+
+// Client returns a client for the node namespace/name, sending requests
+// through base, or the shared transport when base is nil. A timeout of zero
+// applies the configured one.
+func Client(namespace, name string, timeout time.Duration, base http.RoundTripper) *http.Client {
+	mu.Lock()
+	n := lookup(namespace, name)
+	if base == nil {
+		base = shared
+	}
+	if timeout <= 0 {
+		timeout = current.Timeout
+	}
+	opts := current
+	mu.Unlock()
+	return &http.Client{Timeout: timeout, Transport: &limitedTransport{node: n, base: base, opts: opts}}
+}
+
+// Transport returns the transport of the node namespace/name for settings
+// identified by fingerprint, such as its TLS configuration. The transport is
+// built from base once and reused while the fingerprint is unchanged, so its
+// connections are kept alive between polls.
+func Transport(namespace, name, fingerprint string, base func() *http.Transport) *http.Transport {
+	mu.Lock()
+	n := lookup(namespace, name)
+	opts := current
+	mu.Unlock()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.transport == nil || n.fingerprint != fingerprint {
+		if n.transport != nil {
+			n.transport.CloseIdleConnections()
+		}
+		n.transport = newTransport(base(), opts)
+		n.fingerprint = fingerprint
+	}
+	return n.transport
+}
+
+// Forget drops the limits and the connections of a deleted node
+func Forget(namespace, name string) {
+	mu.Lock()
+	n, ok := nodes[namespace+"/"+name]
+	delete(nodes, namespace+"/"+name)
+	mu.Unlock()
+	if !ok {
+		return
+	}
+	n.mu.Lock()
+	if n.transport != nil {
+		n.transport.CloseIdleConnections()
+	}
+	n.mu.Unlock()
+	circuitOpen.DeleteLabelValues(namespace, name)
+	for _, result := range []string{"success", "error", "rate_limited", "circuit_open"} {
+		requestsTotal.DeleteLabelValues(namespace, name, result)
+	}
+}
+
+// lookup returns the state of a node, creating it. mu must be held.
+func lookup(namespace, name string) *node {
+	key := namespace + "/" + name
+	n, ok := nodes[key]
+	if !ok {
+		n = &node{namespace: namespace, name: name, limiter: rate.NewLimiter(rate.Limit(current.QPS), current.Burst)}
+		nodes[key] = n
+	}
+	return n
+}
+
+// newTransport returns base, or a clone of the default transport, with the
+// connections to each host capped
+func newTransport(base *http.Transport, opts Options) *http.Transport {
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport).Clone()
+		// Nodes are in the cluster
+		base.Proxy = nil
+		base.DialContext = (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	if opts.MaxConnsPerNode > 0 {
+		base.MaxConnsPerHost = opts.MaxConnsPerNode
+		base.MaxIdleConnsPerHost = opts.MaxConnsPerNode
+	}
+	base.IdleConnTimeout = 90 * time.Second
+	return base
+}
+
+// limitedTransport applies the rate limit and the circuit breaker of a node
+type limitedTransport struct {
+	node *node
+	base http.RoundTripper
+	opts Options
+}
+
+// RoundTrip sends req once the limiter allows it, unless the circuit is open.
+// Transport errors and 5xx answers count as failures.
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := t.node
+	if !n.allow(t.opts) {
+		requestsTotal.WithLabelValues(n.namespace, n.name, "circuit_open").Inc()
+		return nil, fmt.Errorf("%s/%s: %w after %d failures", n.namespace, n.name, ErrCircuitOpen, t.opts.FailureThreshold)
+	}
+	if err := n.limiter.Wait(req.Context()); err != nil {
+		n.release()
+		requestsTotal.WithLabelValues(n.namespace, n.name, "rate_limited").Inc()
+		return nil, fmt.Errorf("%s/%s: rate limited: %w", n.namespace, n.name, err)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode >= 500 {
+		requestsTotal.WithLabelValues(n.namespace, n.name, "error").Inc()
+		n.record(false, t.opts)
+		return resp, err
+	}
+	requestsTotal.WithLabelValues(n.namespace, n.name, "success").Inc()
+	n.record(true, t.opts)
+	return resp, nil
+}
+
+// allow reports whether a request may be sent. Once the circuit has been
+// open for OpenDuration, a single request probes the node.
+func (n *node) allow(opts Options) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failures < opts.FailureThreshold {
+		return true
+	}
+	if time.Now().Before(n.openUntil) || n.probing {
+		return false
+	}
+	n.probing = true
+	return true
+}
+
+// release gives back the probe of a request that was not sent
+func (n *node) release() {
+	n.mu.Lock()
+	n.probing = false
+	n.mu.Unlock()
+}
+
+// record updates the circuit with the outcome of a request
+func (n *node) record(success bool, opts Options) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.probing = false
+	if success {
+		n.failures = 0
+		circuitOpen.WithLabelValues(n.namespace, n.name).Set(0)
+		return
+	}
+	n.failures++
+	if n.failures >= opts.FailureThreshold {
+		n.openUntil = time.Now().Add(opts.OpenDuration)
+		circuitOpen.WithLabelValues(n.namespace, n.name).Set(1)
+	}
+}
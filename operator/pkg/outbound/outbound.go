@@ -0,0 +1,113 @@
+// Package outbound configures the HTTP clients the operator reaches services
+// outside the cluster with, such as snapshot indexes, the chain registry,
+// Slack and webhooks, so they go through a proxy and trust a private CA.
+package outbound
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Options configures outbound traffic. Proxies left empty are taken from the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+type Options struct {
+	// HTTPProxy is the proxy of plain HTTP requests
+	HTTPProxy string
+	// HTTPSProxy is the proxy of HTTPS requests
+	HTTPSProxy string
+	// NoProxy lists the hosts, domains and CIDRs reached directly
+	NoProxy string
+	// CAFile holds PEM certificates trusted besides the system roots
+	CAFile string
+}
+
+var (
+	mu      sync.RWMutex
+	current Options
+	proxy   = http.ProxyFromEnvironment
+	roots   *x509.CertPool
+)
+
+// Configure sets how outbound traffic is sent
+func Configure(opts Options) error {
+	env := httpproxy.FromEnvironment()
+	config := &httpproxy.Config{
+		HTTPProxy:  firstNonEmpty(opts.HTTPProxy, env.HTTPProxy),
+		HTTPSProxy: firstNonEmpty(opts.HTTPSProxy, env.HTTPSProxy),
+		NoProxy:    firstNonEmpty(opts.NoProxy, env.NoProxy),
+	}
+	proxyFunc := config.ProxyFunc()
+
+	var pool *x509.CertPool
+	if opts.CAFile != "" {
+		bundle, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return err
+		}
+		pool, err = x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("%s holds no PEM certificate", opts.CAFile)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = Options{HTTPProxy: config.HTTPProxy, HTTPSProxy: config.HTTPSProxy, NoProxy: config.NoProxy, CAFile: opts.CAFile}
+	proxy = func(req *http.Request) (*url.URL, error) { return proxyFunc(req.URL) }
+	roots = pool
+	return nil
+}
+
+// Current returns the proxies outbound traffic goes through, including those
+// of the environment, for pods that download on behalf of the operator
+func Current() Options {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// RootCAs returns the certificates outbound TLS connections trust, or nil
+// for the system roots
+func RootCAs() *x509.CertPool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return roots
+}
+
+// Transport returns a transport sending requests through the configured
+// proxy and trusting the configured CA
+func Transport() *http.Transport {
+	mu.RLock()
+	defer mu.RUnlock()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	if roots != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	}
+	return transport
+}
+
+// Client returns a client with timeout for outbound requests
+func Client(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: Transport()}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
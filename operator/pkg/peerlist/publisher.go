@@ -0,0 +1,182 @@
+// Package peerlist publishes the node IDs and external addresses of the
+// running nodes of each network in a well-known ConfigMap, so nodes outside
+// the cluster can use the fleet as seeds and persistent peers.
+package peerlist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Options configures the peer list
+type Options struct {
+	// ConfigMap is the namespace/name of the ConfigMap the peer list is published in
+	ConfigMap string
+	// Interval between two refreshes of the peer list
+	Interval time.Duration
+}
+
+// Peer is a node of the peer list
+type Peer struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	NodeType  string `json:"nodeType"`
+	NodeID    string `json:"nodeId"`
+	Address   string `json:"address"`
+}
+
+// String returns the peer as id@host:port
+func (p Peer) String() string {
+	return p.NodeID + "@" + p.Address
+}
+
+// Publisher refreshes the peer list ConfigMap on an interval
+type Publisher struct {
+	opts   Options
+	client client.Client
+	name   types.NamespacedName
+	log    logr.Logger
+}
+
+// New creates a publisher writing to the ConfigMap of opts
+func New(opts Options, c client.Client, log logr.Logger) (*Publisher, error) {
+	namespace, name, ok := strings.Cut(opts.ConfigMap, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("the peer list ConfigMap %q must be namespace/name", opts.ConfigMap)
+	}
+	return &Publisher{opts: opts, client: c, name: types.NamespacedName{Namespace: namespace, Name: name}, log: log}, nil
+}
+
+// NeedLeaderElection publishes from the leader only, so replicas do not
+// overwrite each other
+func (p *Publisher) NeedLeaderElection() bool {
+	return true
+}
+
+// Start refreshes the peer list until ctx is cancelled
+func (p *Publisher) Start(ctx context.Context) error {
+	interval := p.opts.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	p.log.Info("Publishing the peer list", "configmap", p.name.String(), "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := p.publish(ctx); err != nil {
+			p.log.Error(err, "Unable to publish the peer list")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish writes the peer list of the running nodes to the ConfigMap
+func (p *Publisher) publish(ctx context.Context) error {
+	nodes := &blockchainv1alpha1.AxelarNodeList{}
+	if err := p.client.List(ctx, nodes); err != nil {
+		return err
+	}
+	data, err := Render(Collect(nodes.Items))
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{}
+		err := p.client.Get(ctx, p.name, configMap)
+		if apierrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      p.name.Name,
+					Namespace: p.name.Namespace,
+					Labels:    map[string]string{"app.kubernetes.io/name": "axelar-operator", "app.kubernetes.io/component": "peer-list"},
+				},
+				Data: data,
+			}
+			return p.client.Create(ctx, configMap)
+		} else if err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(configMap.Data, data) {
+			return nil
+		}
+		configMap.Data = data
+		return p.client.Update(ctx, configMap)
+	})
+}
+
+// Collect returns the publishable peers of each network. Only running nodes
+// with a node ID and an external address are listed. Validators are never
+// listed, they should only be reached through their sentries.
+func Collect(nodes []blockchainv1alpha1.AxelarNode) map[string][]Peer {
+	peers := map[string][]Peer{}
+	for i := range nodes {
+		node := &nodes[i]
+		info := node.Status.NetworkInfo
+		validator := node.Spec.NodeType == "validator" || (node.Spec.Validator != nil && node.Spec.Validator.Enabled)
+		if node.DeletionTimestamp != nil || node.Status.Phase != "Running" || validator || info.NodeID == "" || info.ExternalAddress == "" {
+			continue
+		}
+		peers[node.Spec.Network] = append(peers[node.Spec.Network], Peer{
+			Name:      node.Name,
+			Namespace: node.Namespace,
+			NodeType:  node.Spec.NodeType,
+			NodeID:    info.NodeID,
+			Address:   info.ExternalAddress,
+		})
+	}
+	for _, list := range peers {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Namespace != list[j].Namespace {
+				return list[i].Namespace < list[j].Namespace
+			}
+			return list[i].Name < list[j].Name
+		})
+	}
+	return peers
+}
+
+// Render returns the ConfigMap data of the peers of each network:
+// <network>.seeds and <network>.peers hold the seed nodes and the other nodes
+// as id@host:port lists, ready for the seeds and persistent_peers of
+// config.toml, and <network>.json describes every node.
+func Render(peers map[string][]Peer) (map[string]string, error) {
+	data := map[string]string{}
+	for network, list := range peers {
+		var seeds, persistent []string
+		for _, peer := range list {
+			if peer.NodeType == "seed" {
+				seeds = append(seeds, peer.String())
+			} else {
+				persistent = append(persistent, peer.String())
+			}
+		}
+		document, err := json.Marshal(list)
+		if err != nil {
+			return nil, err
+		}
+		data[network+".seeds"] = strings.Join(seeds, ",")
+		data[network+".peers"] = strings.Join(persistent, ",")
+		data[network+".json"] = string(document)
+	}
+	return data, nil
+}
@@ -0,0 +1,116 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// DefaultKubeconfigKey is the Secret key read when a KubeconfigSecretRef has no key
+const DefaultKubeconfigKey = "kubeconfig"
+
+// clusterAPIKubeconfigKey is the key Cluster API stores the kubeconfig under
+const clusterAPIKubeconfigKey = "value"
+
+// Clusters caches clients for the remote clusters AxelarNodes are placed in
+type Clusters struct {
+	scheme *runtime.Scheme
+
+	// Wrap, when set, wraps every remote client, for instance to audit its
+	// mutations. cluster identifies the kubeconfig Secret the client uses.
+	Wrap func(c client.Client, cluster string) client.Client
+
+	// Reader, when set, reads the kubeconfig Secrets, which users provide
+	// and the manager does not cache. The local client is used otherwise.
+	Reader client.Reader
+
+	mu      sync.Mutex
+	clients map[types.NamespacedName]cachedClient
+}
+
+type cachedClient struct {
+	resourceVersion string
+	client          client.Client
+}
+
+// NewClusters creates an empty client cache using scheme for all remote clients
+func NewClusters(scheme *runtime.Scheme) *Clusters {
+	return &Clusters{
+		scheme:  scheme,
+		clients: map[types.NamespacedName]cachedClient{},
+	}
+}
+
+// KubeconfigSource returns the Secret and key holding the kubeconfig of the cluster
+func KubeconfigSource(ref *blockchainv1alpha1.ClusterRef, namespace string) (types.NamespacedName, string, error) {
+	switch {
+	case ref.KubeconfigSecret != nil && ref.ClusterAPI != nil:
+		return types.NamespacedName{}, "", fmt.Errorf("cluster sets both kubeconfigSecret and clusterAPI")
+	case ref.KubeconfigSecret != nil:
+		key := ref.KubeconfigSecret.Key
+		if key == "" {
+			key = DefaultKubeconfigKey
+		}
+		return types.NamespacedName{Name: ref.KubeconfigSecret.Name, Namespace: namespace}, key, nil
+	case ref.ClusterAPI != nil:
+		if ref.ClusterAPI.Namespace != "" {
+			namespace = ref.ClusterAPI.Namespace
+		}
+		// Cluster API writes the admin kubeconfig to <cluster>-kubeconfig
+		return types.NamespacedName{Name: ref.ClusterAPI.Name + "-kubeconfig", Namespace: namespace}, clusterAPIKubeconfigKey, nil
+	}
+	return types.NamespacedName{}, "", fmt.Errorf("cluster sets neither kubeconfigSecret nor clusterAPI")
+}
+
+// Client returns a client for the cluster referenced by ref. The kubeconfig
+// Secret is read through Reader or local, and clients are rebuilt when it
+// changes.
+func (c *Clusters) Client(ctx context.Context, local client.Client, ref *blockchainv1alpha1.ClusterRef, namespace string) (client.Client, error) {
+	source, key, err := KubeconfigSource(ref, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader client.Reader = local
+	if c.Reader != nil {
+		reader = c.Reader
+	}
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, source, secret); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig secret %s: %w", source, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.clients[source]; ok && cached.resourceVersion == secret.ResourceVersion {
+		return cached.client, nil
+	}
+
+	kubeconfig, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig secret %s has no key %q", source, key)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in secret %s: %w", source, err)
+	}
+	remoteClient, err := client.New(config, client.Options{Scheme: c.scheme})
+	if err != nil {
+		return nil, err
+	}
+	if c.Wrap != nil {
+		remoteClient = c.Wrap(remoteClient, source.String())
+	}
+
+	c.clients[source] = cachedClient{resourceVersion: secret.ResourceVersion, client: remoteClient}
+	return remoteClient, nil
+}
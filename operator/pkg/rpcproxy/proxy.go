@@ -0,0 +1,217 @@
+// Package rpcproxy implements the sidecar that fronts the Tendermint RPC of a
+// node and publishes the per-pod metrics used for horizontal autoscaling.
+package rpcproxy
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/tendermint"
+)
+
+// Options configures the proxy
+type Options struct {
+	// Listen is the address the proxied RPC is served on
+	Listen string
+	// MetricsListen is the address metrics are served on
+	MetricsListen string
+	// Upstream is the URL of the node RPC
+	Upstream string
+	// UpstreamMetrics is the URL of the node Prometheus endpoint, appended to the proxy metrics
+	UpstreamMetrics string
+	// BlockTime is the expected block interval used to estimate the sync lag
+	BlockTime time.Duration
+	// PollInterval is how often the node status is polled
+	PollInterval time.Duration
+}
+
+// BindFlags registers the proxy options on fs
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Listen, "listen", ":26667", "The address the proxied RPC is served on.")
+	fs.StringVar(&o.MetricsListen, "metrics-listen", ":26668", "The address metrics are served on.")
+	fs.StringVar(&o.Upstream, "upstream", "http://127.0.0.1:26657", "The URL of the node RPC.")
+	fs.StringVar(&o.UpstreamMetrics, "upstream-metrics", "", "The URL of the node Prometheus endpoint to include in the metrics.")
+	fs.DurationVar(&o.BlockTime, "block-time", 6*time.Second, "The expected block interval.")
+	fs.DurationVar(&o.PollInterval, "poll-interval", 10*time.Second, "How often the node status is polled.")
+}
+
+// Proxy forwards RPC requests to the node and records autoscaling metrics
+type Proxy struct {
+	opts     Options
+	log      logr.Logger
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	syncLag  prometheus.Gauge
+	synced   prometheus.Gauge
+}
+
+// New creates a proxy for opts
+func New(opts Options, log logr.Logger) *Proxy {
+	p := &Proxy{
+		opts:     opts,
+		log:      log,
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "axelar_rpc_requests_total",
+			Help: "RPC requests served by the node, by response code class.",
+		}, []string{"code"}),
+		syncLag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "axelar_sync_lag_blocks",
+			Help: "Estimated number of blocks the node is behind the chain head.",
+		}),
+		synced: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "axelar_synced",
+			Help: "Whether the node reports it is no longer catching up.",
+		}),
+	}
+	p.registry.MustRegister(p.requests, p.syncLag, p.synced)
+	return p
+}
+
+// Run serves the proxy and metrics until ctx is cancelled
+func (p *Proxy) Run(ctx context.Context) error {
+	upstream, err := url.Parse(p.opts.Upstream)
+	if err != nil {
+		return err
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(upstream)
+	rpcServer := &http.Server{Addr: p.opts.Listen, Handler: p.countRequests(proxy)}
+
+	metricsMux := http.NewServeMux()
+	metricsMux.HandleFunc("/metrics", p.serveMetrics)
+	metricsServer := &http.Server{Addr: p.opts.MetricsListen, Handler: metricsMux}
+
+	errs := make(chan error, 2)
+	for _, server := range []*http.Server{rpcServer, metricsServer} {
+		server := server
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+
+	go p.pollStatus(ctx)
+
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rpcServer.Shutdown(shutdown)
+	metricsServer.Shutdown(shutdown)
+	return err
+}
+
+// countRequests records every proxied request by response code class
+func (p *Proxy) countRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		p.requests.WithLabelValues(codeClass(recorder.status)).Inc()
+	})
+}
+
+// pollStatus updates the sync metrics from the node status
+func (p *Proxy) pollStatus(ctx context.Context) {
+	rpc := tendermint.NewClient(p.opts.Upstream)
+	ticker := time.NewTicker(p.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := rpc.Status(ctx)
+		if err != nil {
+			p.log.V(1).Info("Unable to query node status", "error", err.Error())
+			p.synced.Set(0)
+		} else {
+			p.syncLag.Set(float64(estimateLag(status.SyncInfo, p.opts.BlockTime, time.Now())))
+			if status.SyncInfo.CatchingUp {
+				p.synced.Set(0)
+			} else {
+				p.synced.Set(1)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serveMetrics writes the proxy metrics followed by the node metrics, so a
+// single scrape target covers both
+func (p *Proxy) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	families, err := p.registry.Gather()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", string(expfmt.FmtText))
+	encoder := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return
+		}
+	}
+
+	if p.opts.UpstreamMetrics == "" {
+		return
+	}
+	resp, err := http.Get(p.opts.UpstreamMetrics)
+	if err != nil {
+		p.log.V(1).Info("Unable to scrape node metrics", "error", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(w, resp.Body)
+}
+
+// estimateLag derives the number of blocks behind from the age of the latest block
+func estimateLag(info tendermint.SyncInfo, blockTime time.Duration, now time.Time) int64 {
+	if blockTime <= 0 || info.LatestBlockTime.IsZero() {
+		return 0
+	}
+	// A block younger than two intervals is considered the head
+	age := now.Sub(info.LatestBlockTime) - blockTime
+	if age <= 0 {
+		return 0
+	}
+	return int64(age / blockTime)
+}
+
+// codeClass returns the class of an HTTP status code, such as 2xx
+func codeClass(status int) string {
+	return string(rune('0'+status/100)) + "xx"
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap exposes the underlying writer, so websocket upgrades can hijack it
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
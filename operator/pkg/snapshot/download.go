@@ -0,0 +1,459 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Phases of a download, as reported by the progress endpoint
+const (
+	PhaseDownloading = "Downloading"
+	PhaseInstalling  = "Installing"
+	PhaseCompleted   = "Completed"
+)
+
+// chainData is present once the home directory holds chain data
+const chainData = "data/blockstore.db"
+
+// stagingDir is where the archive is extracted before it is installed
+const stagingDir = ".snapshot-staging"
+
+// DownloadOptions configures the snapshot downloader
+type DownloadOptions struct {
+	// URL of the archive
+	URL string
+	// Checksum is the expected SHA-256 of the archive
+	Checksum string
+	// Home is the node home directory the archive is extracted into
+	Home string
+	// Connections is the number of ranges downloaded in parallel
+	Connections int
+	// ChunkSize is the size of each range
+	ChunkSize int64
+	// Retries is how often a range is retried without making progress
+	Retries int
+	// ProgressListen is the address progress is served on, or empty
+	ProgressListen string
+}
+
+// BindFlags registers the downloader options on fs
+func (o *DownloadOptions) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.URL, "url", "", "The URL of the snapshot archive, or the file:// path of an archive on a mounted volume.")
+	fs.StringVar(&o.Checksum, "sha256", "", "The expected SHA-256 checksum of the archive.")
+	fs.StringVar(&o.Home, "home", "/home/axelard/.axelar", "The node home directory the archive is extracted into.")
+	fs.IntVar(&o.Connections, "connections", 4, "The number of parallel connections.")
+	fs.Int64Var(&o.ChunkSize, "chunk-size", 16<<20, "The size of the ranges requested per connection.")
+	fs.IntVar(&o.Retries, "retries", 10, "How often a range is retried without making progress.")
+	fs.StringVar(&o.ProgressListen, "progress-listen", ":26670", "The address progress is served on.")
+}
+
+// Progress is the state of a download
+type Progress struct {
+	Phase string `json:"phase"`
+	// TotalBytes is the archive size, or 0 when the server does not report it
+	TotalBytes      int64   `json:"totalBytes"`
+	DownloadedBytes int64   `json:"downloadedBytes"`
+	BytesPerSecond  float64 `json:"bytesPerSecond"`
+	// Retries counts the ranges resumed after a failed request
+	Retries int64 `json:"retries"`
+}
+
+// String describes the progress for status conditions
+func (p Progress) String() string {
+	if p.Phase != PhaseDownloading {
+		return p.Phase
+	}
+	msg := fmt.Sprintf("Downloaded %s", formatBytes(p.DownloadedBytes))
+	if p.TotalBytes > 0 {
+		msg += fmt.Sprintf(" of %s (%d%%)", formatBytes(p.TotalBytes), p.DownloadedBytes*100/p.TotalBytes)
+	}
+	msg += fmt.Sprintf(" at %s/s", formatBytes(int64(p.BytesPerSecond)))
+	if p.Retries > 0 {
+		msg += fmt.Sprintf(", %d retries", p.Retries)
+	}
+	return msg
+}
+
+// formatBytes formats a size in binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// GetProgress queries the progress endpoint of a downloader
+func GetProgress(ctx context.Context, url string) (*Progress, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("snapshot progress %s returned %s", url, resp.Status)
+	}
+	progress := &Progress{}
+	if err := json.NewDecoder(resp.Body).Decode(progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// Downloader streams a snapshot archive into a node home directory. Ranges
+// are fetched over parallel connections and reassembled in order, so the
+// archive is verified and extracted as it arrives without being stored.
+// Dropped connections resume from the last byte received.
+type Downloader struct {
+	opts   DownloadOptions
+	log    logr.Logger
+	client *http.Client
+
+	downloaded atomic.Int64
+	retries    atomic.Int64
+
+	mu      sync.Mutex
+	phase   string
+	total   int64
+	started time.Time
+}
+
+// NewDownloader creates a downloader for opts
+func NewDownloader(opts DownloadOptions, log logr.Logger) *Downloader {
+	if opts.Connections < 1 {
+		opts.Connections = 1
+	}
+	return &Downloader{
+		opts: opts,
+		log:  log,
+		// No overall timeout, ranges of a slow mirror take long
+		client: &http.Client{Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			MaxIdleConnsPerHost:   opts.Connections,
+			ResponseHeaderTimeout: time.Minute,
+		}},
+		phase: PhaseDownloading,
+	}
+}
+
+// Run downloads, verifies and installs the archive. It does nothing when the
+// home directory already holds chain data.
+func (d *Downloader) Run(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(d.opts.Home, chainData)); err == nil {
+		d.log.Info("Chain data present, skipping snapshot bootstrap")
+		return nil
+	}
+	expected, err := Checksum(d.opts.Checksum)
+	if err != nil {
+		return err
+	}
+
+	if d.opts.ProgressListen != "" {
+		server := &http.Server{Addr: d.opts.ProgressListen, Handler: http.HandlerFunc(d.serveProgress), ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				d.log.Error(err, "Unable to serve download progress")
+			}
+		}()
+		defer server.Close()
+	}
+
+	// Archives on a mounted volume are read in place
+	var size int64
+	var fetch func(ctx context.Context, w io.Writer) error
+	if path, local := strings.CutPrefix(d.opts.URL, "file://"); local {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		size = info.Size()
+		fetch = func(ctx context.Context, w io.Writer) error { return d.copyFile(path, w) }
+		d.log.Info("Reading snapshot", "path", path, "size", size)
+	} else {
+		var ranged bool
+		if size, ranged, err = d.probe(ctx); err != nil {
+			return err
+		}
+		fetch = func(ctx context.Context, w io.Writer) error {
+			if ranged && size > 0 {
+				return d.fetchParallel(ctx, size, w)
+			}
+			return d.fetchRange(ctx, 0, -1, w)
+		}
+		d.log.Info("Downloading snapshot", "url", d.opts.URL, "size", size, "ranges", ranged)
+	}
+	started := time.Now()
+	d.mu.Lock()
+	d.total, d.started = size, started
+	d.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(fetch(ctx, pw))
+	}()
+	defer pr.Close()
+
+	staging := filepath.Join(d.opts.Home, stagingDir)
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+	hash := sha256.New()
+	archive := io.TeeReader(pr, hash)
+	if err := extract(archive, staging); err != nil {
+		return fmt.Errorf("unable to extract snapshot: %w", err)
+	}
+	// The checksum covers the padding after the end of the archive
+	if _, err := io.Copy(io.Discard, archive); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != expected {
+		os.RemoveAll(staging)
+		return fmt.Errorf("snapshot checksum mismatch: expected %s, got %s", expected, sum)
+	}
+
+	d.setPhase(PhaseInstalling)
+	if err := install(staging, d.opts.Home); err != nil {
+		return fmt.Errorf("unable to install snapshot: %w", err)
+	}
+	d.setPhase(PhaseCompleted)
+	d.log.Info("Snapshot installed", "bytes", d.downloaded.Load(), "duration", time.Since(started).Round(time.Second))
+	return nil
+}
+
+// Progress returns the current state of the download
+func (d *Downloader) Progress() Progress {
+	d.mu.Lock()
+	phase, total, started := d.phase, d.total, d.started
+	d.mu.Unlock()
+
+	p := Progress{
+		Phase:           phase,
+		TotalBytes:      total,
+		DownloadedBytes: d.downloaded.Load(),
+		Retries:         d.retries.Load(),
+	}
+	if elapsed := time.Since(started).Seconds(); !started.IsZero() && elapsed > 0 {
+		p.BytesPerSecond = float64(p.DownloadedBytes) / elapsed
+	}
+	return p
+}
+
+// setPhase records the phase reported as progress
+func (d *Downloader) setPhase(phase string) {
+	d.mu.Lock()
+	d.phase = phase
+	d.mu.Unlock()
+}
+
+// serveProgress writes the progress as JSON
+func (d *Downloader) serveProgress(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/progress" {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(d.Progress())
+}
+
+// probe returns the archive size and whether the server supports ranges
+func (d *Downloader) probe(ctx context.Context) (int64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.opts.URL, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// Content-Range: bytes 0-0/<size>
+		contentRange := resp.Header.Get("Content-Range")
+		if i := strings.LastIndex(contentRange, "/"); i >= 0 {
+			if size, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				return size, true, nil
+			}
+		}
+		return 0, false, nil
+	case http.StatusOK:
+		return max(resp.ContentLength, 0), false, nil
+	}
+	return 0, false, fmt.Errorf("snapshot %s returned %s", d.opts.URL, resp.Status)
+}
+
+// chunk is a range downloaded by a worker
+type chunk struct {
+	buf  bytes.Buffer
+	err  error
+	done chan struct{}
+}
+
+// fetchParallel downloads the archive in ranges over parallel connections
+// and writes them to w in order. At most Connections ranges are fetched at
+// once, and as many completed ones are buffered.
+func (d *Downloader) fetchParallel(ctx context.Context, size int64, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := make(chan *chunk, d.opts.Connections)
+	go func() {
+		defer close(pending)
+		workers := make(chan struct{}, d.opts.Connections)
+		for start := int64(0); start < size; start += d.opts.ChunkSize {
+			start, end := start, min(start+d.opts.ChunkSize, size)-1
+			c := &chunk{done: make(chan struct{})}
+			select {
+			case workers <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case pending <- c:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				defer func() {
+					<-workers
+					close(c.done)
+				}()
+				c.buf.Grow(int(end - start + 1))
+				c.err = d.fetchRange(ctx, start, end, &c.buf)
+			}()
+		}
+	}()
+
+	for c := range pending {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if c.err != nil {
+			return c.err
+		}
+		if _, err := c.buf.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// fetchRange writes the bytes from start to end of the archive to w, or all
+// of it when end is negative. Failed requests resume after the last byte
+// received; Retries bounds the attempts that make no progress.
+func (d *Downloader) fetchRange(ctx context.Context, start, end int64, w io.Writer) error {
+	offset := start
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		n, err := d.get(ctx, offset, end, w)
+		offset += n
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if n > 0 {
+			attempt, backoff = 1, time.Second
+		}
+		if attempt > d.opts.Retries {
+			return fmt.Errorf("unable to download bytes %d-%d: %w", offset, end, err)
+		}
+		d.retries.Add(1)
+		d.log.Info("Resuming snapshot download", "offset", offset, "attempt", attempt, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+// get requests the range from offset to end and copies it to w, returning
+// the bytes copied
+func (d *Downloader) get(ctx context.Context, offset, end int64, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.opts.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case end >= 0:
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, end))
+	case offset > 0:
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if req.Header.Get("Range") != "" && resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("range request returned %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("snapshot returned %s", resp.Status)
+	}
+
+	n, err := io.Copy(w, &countingReader{r: resp.Body, n: &d.downloaded})
+	if err == nil && end >= 0 && offset+n <= end {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// copyFile copies the archive at path to w
+func (d *Downloader) copyFile(path string, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, &countingReader{r: f, n: &d.downloaded})
+	return err
+}
+
+// countingReader adds the bytes read to n
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
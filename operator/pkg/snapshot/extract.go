@@ -0,0 +1,186 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Magic numbers of the supported compression formats
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	lz4Magic  = []byte{0x04, 0x22, 0x4d, 0x18}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// protected are the paths of the home directory a snapshot never replaces:
+// the node configuration and keys, and the signing state
+var protected = map[string]bool{
+	"config":                         true,
+	"keyring-file":                   true,
+	"keyring-test":                   true,
+	"data/priv_validator_state.json": true,
+}
+
+// decompress detects the compression of an archive from its first bytes
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReaderSize(r, 1<<20)
+	magic, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, lz4Magic):
+		return io.NopCloser(lz4.NewReader(br)), nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		decoder, err := zstd.NewReader(br, zstd.WithDecoderLowmem(true))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	}
+	return io.NopCloser(br), nil
+}
+
+// extract unpacks a possibly compressed tar archive into dir
+func extract(r io.Reader, dir string) error {
+	archive, err := decompress(r)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path, err := within(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(path, tr, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if _, err := within(dir, filepath.Join(filepath.Dir(hdr.Name), hdr.Linkname)); err != nil || filepath.IsAbs(hdr.Linkname) {
+				return fmt.Errorf("symlink %s points outside the archive", hdr.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := os.Symlink(hdr.Linkname, path); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			target, err := within(dir, hdr.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := os.Link(target, path); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// within resolves an archive path inside dir, rejecting paths that escape it
+func within(dir, name string) (string, error) {
+	path := filepath.Join(dir, name)
+	if path != dir && !strings.HasPrefix(path, dir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %s is outside the archive", name)
+	}
+	return path, nil
+}
+
+// writeFile writes the content of a regular archive entry
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode|0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// install moves an extracted snapshot into the home directory. Archives of
+// the bare data directory are installed as data. Existing files are replaced,
+// except the protected ones.
+func install(staging, home string) error {
+	if _, err := os.Stat(filepath.Join(staging, "blockstore.db")); err == nil {
+		if err := os.MkdirAll(filepath.Join(home, "data"), 0o755); err != nil {
+			return err
+		}
+		if err := merge(staging, filepath.Join(home, "data"), "data"); err != nil {
+			return err
+		}
+	} else if err := merge(staging, home, ""); err != nil {
+		return err
+	}
+	return os.RemoveAll(staging)
+}
+
+// merge moves the entries of src into dst. rel is the path of dst in the
+// home directory.
+func merge(src, dst, rel string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		from, to := filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())
+		path := filepath.Join(rel, entry.Name())
+
+		existing, err := os.Lstat(to)
+		if err == nil && protected[path] {
+			continue
+		}
+		if err == nil && existing.IsDir() && entry.IsDir() {
+			if err := merge(from, to, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if err == nil {
+			if err := os.RemoveAll(to); err != nil {
+				return err
+			}
+		}
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Package snapshot reads the snapshot indexes published by chain snapshot
+// providers, in the style of Quicksync and Polkachu.
+package snapshot
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/airgap"
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+)
+
+// maxIndexSize bounds the index document
+const maxIndexSize = 8 << 20
+
+// Snapshot is an entry of a provider index
+type Snapshot struct {
+	// URL of the archive
+	URL string `json:"url"`
+	// Network the snapshot belongs to, such as mainnet
+	Network string `json:"network"`
+	// Pruning profile of the snapshot: pruned, default or archive
+	Pruning string `json:"pruning"`
+	// Height of the snapshot
+	Height int64 `json:"height"`
+	// Checksum of the archive, as sha256:<hex> or plain hex
+	Checksum string `json:"checksum"`
+	// Time the snapshot was taken
+	Time time.Time `json:"time"`
+}
+
+// index is the provider index document, either a list of snapshots or an
+// object with a snapshots list
+type index struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// IndexURL expands the {network} and {pruning} placeholders of an index URL pattern
+func IndexURL(pattern, network, pruning string) string {
+	return strings.NewReplacer("{network}", network, "{pruning}", Profile(pruning)).Replace(pattern)
+}
+
+// Profile maps a node pruning strategy to the profile name providers use
+func Profile(pruning string) string {
+	switch pruning {
+	case "everything":
+		return "pruned"
+	case "nothing":
+		return "archive"
+	}
+	return "default"
+}
+
+// Fetch downloads and parses the index at url
+func Fetch(ctx context.Context, url string) ([]Snapshot, error) {
+	if airgap.Offline() {
+		return nil, fmt.Errorf("fetching snapshot index %s: %w", url, airgap.ErrOffline)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := outbound.Client(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("snapshot index %s returned %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexSize))
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot index %s: %w", url, err)
+	}
+	return snapshots, nil
+}
+
+// Parse parses an index document
+func Parse(body []byte) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	if err := json.Unmarshal(body, &snapshots); err == nil {
+		return snapshots, nil
+	}
+	var doc index
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Snapshots, nil
+}
+
+// Latest returns the highest snapshot of the network and pruning strategy
+// with a valid SHA-256 checksum. Entries without a network or pruning profile
+// are assumed to match, since many providers publish one index per network.
+func Latest(snapshots []Snapshot, network, pruning string) (*Snapshot, error) {
+	profile := Profile(pruning)
+
+	var latest *Snapshot
+	for i := range snapshots {
+		s := &snapshots[i]
+		if s.URL == "" || (s.Network != "" && s.Network != network) || (s.Pruning != "" && s.Pruning != profile) {
+			continue
+		}
+		if _, err := Checksum(s.Checksum); err != nil {
+			continue
+		}
+		if latest == nil || s.Height > latest.Height || (s.Height == latest.Height && s.Time.After(latest.Time)) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no %s snapshot with a SHA-256 checksum for %s", profile, network)
+	}
+	return latest, nil
+}
+
+// AtMost returns the snapshots at or below height. Snapshots above the chain
+// head cannot be genuine.
+func AtMost(snapshots []Snapshot, height int64) []Snapshot {
+	var out []Snapshot
+	for _, s := range snapshots {
+		if s.Height <= height {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Checksum normalizes a SHA-256 checksum to lowercase hex
+func Checksum(checksum string) (string, error) {
+	sum := strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != 32 {
+		return "", fmt.Errorf("invalid SHA-256 checksum %q", checksum)
+	}
+	return sum, nil
+}
@@ -0,0 +1,186 @@
+// Package statusapi serves the status the operator last collected from each
+// node as JSON, so dashboards can follow the fleet without Kubernetes API
+// access. The API is read-only and answers from the operator cache.
+package statusapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Options configures the status API
+type Options struct {
+	// BindAddress is the address the API is served on; empty disables it
+	BindAddress string
+	// TokenFile optionally holds the bearer token clients authenticate with.
+	// It is read on every request, so a mounted Secret can be rotated in place.
+	TokenFile string
+	// CertFile and KeyFile enable TLS when both are set
+	CertFile string
+	KeyFile  string
+}
+
+// Server serves the status API
+type Server struct {
+	opts   Options
+	client client.Client
+	log    logr.Logger
+}
+
+// NodeStatus is the status of a node returned by the API
+type NodeStatus struct {
+	Name         string           `json:"name"`
+	Namespace    string           `json:"namespace"`
+	NodeType     string           `json:"nodeType"`
+	Network      string           `json:"network"`
+	Version      string           `json:"version"`
+	Phase        string           `json:"phase"`
+	Height       int64            `json:"height"`
+	LatestHeight int64            `json:"latestHeight"`
+	CatchingUp   bool             `json:"catchingUp"`
+	Peers        int32            `json:"peers"`
+	NodeID       string           `json:"nodeId,omitempty"`
+	Validator    *ValidatorStatus `json:"validator,omitempty"`
+	BlockTime    *metav1.Time     `json:"blockTime,omitempty"`
+}
+
+// ValidatorStatus is the validator part of the status of a node
+type ValidatorStatus struct {
+	Address          string `json:"address"`
+	VotingPower      int64  `json:"votingPower"`
+	MissedBlocks     int32  `json:"missedBlocks"`
+	LastSignedHeight int64  `json:"lastSignedHeight"`
+}
+
+// New creates the status API server
+func New(opts Options, c client.Client, log logr.Logger) *Server {
+	return &Server{opts: opts, client: c, log: log}
+}
+
+// NeedLeaderElection lets every operator replica serve the API, since it
+// only reads the node status
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the API until ctx is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/nodes/", s.authenticate(http.HandlerFunc(s.serveNode)))
+
+	server := &http.Server{Addr: s.opts.BindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() {
+		var err error
+		if s.opts.CertFile != "" && s.opts.KeyFile != "" {
+			err = server.ListenAndServeTLS(s.opts.CertFile, s.opts.KeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+	s.log.Info("Serving status API", "address", s.opts.BindAddress)
+
+	select {
+	case <-ctx.Done():
+	case err := <-errs:
+		return err
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdown)
+}
+
+// authenticate rejects requests without the configured bearer token, if any
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.opts.TokenFile == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := os.ReadFile(s.opts.TokenFile)
+		if err != nil {
+			s.log.Error(err, "Unable to read status API token")
+			http.Error(w, "status API token unavailable", http.StatusInternalServerError)
+			return
+		}
+		expected := strings.TrimSpace(string(token))
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || expected == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(expected)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveNode serves GET /nodes/{namespace}/{name}/status
+func (s *Server) serveNode(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/nodes/"), "/"), "/")
+	if len(parts) != 3 || parts[2] != "status" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	node := &blockchainv1alpha1.AxelarNode{}
+	if err := s.client.Get(r.Context(), types.NamespacedName{Namespace: parts[0], Name: parts[1]}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(Summarize(node))
+}
+
+// Summarize returns the API view of a node
+func Summarize(node *blockchainv1alpha1.AxelarNode) NodeStatus {
+	status := NodeStatus{
+		Name:         node.Name,
+		Namespace:    node.Namespace,
+		NodeType:     node.Spec.NodeType,
+		Network:      node.Spec.Network,
+		Version:      node.Spec.Image.Tag,
+		Phase:        node.Status.Phase,
+		Height:       node.Status.SyncInfo.CurrentHeight,
+		LatestHeight: node.Status.SyncInfo.LatestHeight,
+		CatchingUp:   node.Status.SyncInfo.CatchingUp,
+		Peers:        node.Status.NetworkInfo.Peers,
+		NodeID:       node.Status.NetworkInfo.NodeID,
+		BlockTime:    node.Status.SyncInfo.LastSyncTime,
+	}
+	if info := node.Status.ValidatorInfo; info != nil {
+		status.Validator = &ValidatorStatus{
+			Address:          info.Address,
+			VotingPower:      info.VotingPower,
+			MissedBlocks:     info.MissedBlocks,
+			LastSignedHeight: info.LastSignedHeight,
+		}
+	}
+	return status
+}
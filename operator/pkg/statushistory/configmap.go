@@ -0,0 +1,109 @@
+package statushistory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// samplesKey is the ConfigMap key holding the samples, one JSON object per line
+const samplesKey = "samples.jsonl"
+
+// maxConfigMapBytes keeps the ring buffer under the 1MiB limit of ConfigMaps
+const maxConfigMapBytes = 900 << 10
+
+// defaultMaxSamples applies when the options leave the ring size unset
+const defaultMaxSamples = 5000
+
+// ConfigMapRing keeps the most recent samples in a ConfigMap, dropping the
+// oldest ones once it is full
+type ConfigMapRing struct {
+	client     client.Client
+	name       types.NamespacedName
+	maxSamples int
+}
+
+// NewConfigMapRing creates a ring buffer in the ConfigMap namespace/name
+func NewConfigMapRing(c client.Client, configMap string, maxSamples int) (*ConfigMapRing, error) {
+	namespace, name, ok := strings.Cut(configMap, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("the status history ConfigMap %q must be namespace/name", configMap)
+	}
+	if maxSamples <= 0 {
+		maxSamples = defaultMaxSamples
+	}
+	return &ConfigMapRing{client: c, name: types.NamespacedName{Namespace: namespace, Name: name}, maxSamples: maxSamples}, nil
+}
+
+// Write appends samples to the ring buffer
+func (r *ConfigMapRing) Write(ctx context.Context, samples []Sample) error {
+	lines := make([]string, 0, len(samples))
+	for _, sample := range samples {
+		line, err := json.Marshal(sample)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, string(line))
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{}
+		err := r.client.Get(ctx, r.name, configMap)
+		if apierrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      r.name.Name,
+					Namespace: r.name.Namespace,
+					Labels:    map[string]string{"app.kubernetes.io/name": "axelar-operator", "app.kubernetes.io/component": "status-history"},
+				},
+				Data: map[string]string{samplesKey: r.ring(nil, lines)},
+			}
+			return r.client.Create(ctx, configMap)
+		} else if err != nil {
+			return err
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[samplesKey] = r.ring(splitLines(configMap.Data[samplesKey]), lines)
+		return r.client.Update(ctx, configMap)
+	})
+}
+
+// ring appends lines to the current ones and drops the oldest beyond the
+// sample and size limits
+func (r *ConfigMapRing) ring(current, lines []string) string {
+	all := append(current, lines...)
+	if len(all) > r.maxSamples {
+		all = all[len(all)-r.maxSamples:]
+	}
+	size := 0
+	start := len(all)
+	for start > 0 && size+len(all[start-1])+1 <= maxConfigMapBytes {
+		start--
+		size += len(all[start]) + 1
+	}
+	return strings.Join(all[start:], "\n") + "\n"
+}
+
+// splitLines returns the non-empty lines of data
+func splitLines(data string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 64<<10), maxConfigMapBytes)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
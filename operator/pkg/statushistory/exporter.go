@@ -0,0 +1,156 @@
+// Package statushistory periodically samples the status of every node and
+// writes the samples to a sink, so the state of the fleet at the time of an
+// incident can be reconstructed afterwards.
+package statushistory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+// Options configures the status history exporter
+type Options struct {
+	// Interval between two samples of the fleet
+	Interval time.Duration
+	// RemoteWriteURL is the Prometheus remote write endpoint samples are sent to
+	RemoteWriteURL string
+	// RemoteWriteTokenFile holds the bearer token sent to the remote write
+	// endpoint. It is read on every write, so a mounted Secret can be rotated.
+	RemoteWriteTokenFile string
+	// ConfigMap is the namespace/name of the ConfigMap ring buffer samples are kept in
+	ConfigMap string
+	// MaxSamples is the number of samples the ConfigMap ring buffer keeps
+	MaxSamples int
+}
+
+// Enabled reports whether a sink is configured
+func (o Options) Enabled() bool {
+	return o.RemoteWriteURL != "" || o.ConfigMap != ""
+}
+
+// Sample is the status of a node at a point in time
+type Sample struct {
+	Time       time.Time `json:"time"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Network    string    `json:"network,omitempty"`
+	NodeType   string    `json:"nodeType,omitempty"`
+	Version    string    `json:"version,omitempty"`
+	Phase      string    `json:"phase,omitempty"`
+	Height     int64     `json:"height"`
+	CatchingUp bool      `json:"catchingUp,omitempty"`
+	Peers      int32     `json:"peers"`
+}
+
+// Sink stores samples
+type Sink interface {
+	Write(ctx context.Context, samples []Sample) error
+}
+
+// Exporter samples the fleet on an interval and writes to its sinks
+type Exporter struct {
+	opts   Options
+	client client.Client
+	sinks  []Sink
+	log    logr.Logger
+}
+
+// New creates an exporter writing to the sinks of opts
+func New(opts Options, c client.Client, log logr.Logger) (*Exporter, error) {
+	e := &Exporter{opts: opts, client: c, log: log}
+	if opts.RemoteWriteURL != "" {
+		e.sinks = append(e.sinks, NewRemoteWrite(opts.RemoteWriteURL, opts.RemoteWriteTokenFile))
+	}
+	if opts.ConfigMap != "" {
+		sink, err := NewConfigMapRing(c, opts.ConfigMap, opts.MaxSamples)
+		if err != nil {
+			return nil, err
+		}
+		e.sinks = append(e.sinks, sink)
+	}
+	if len(e.sinks) == 0 {
+		return nil, errors.New("the status history needs a remote write URL or a ConfigMap")
+	}
+	return e, nil
+}
+
+// NeedLeaderElection samples the fleet from the leader only, so samples are
+// not written twice
+func (e *Exporter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start samples the fleet until ctx is cancelled
+func (e *Exporter) Start(ctx context.Context) error {
+	interval := e.opts.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	e.log.Info("Exporting the status history", "interval", interval, "sinks", len(e.sinks))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			e.export(ctx)
+		}
+	}
+}
+
+// export writes a sample of every node to the sinks. A failing sink is
+// logged and retried at the next interval, the samples it missed are lost.
+func (e *Exporter) export(ctx context.Context) {
+	nodes := &blockchainv1alpha1.AxelarNodeList{}
+	if err := e.client.List(ctx, nodes); err != nil {
+		e.log.Error(err, "Unable to list nodes for the status history")
+		return
+	}
+	now := time.Now().UTC()
+	samples := make([]Sample, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		samples = append(samples, sampleOf(&nodes.Items[i], now))
+	}
+	if len(samples) == 0 {
+		return
+	}
+	for _, sink := range e.sinks {
+		if err := sink.Write(ctx, samples); err != nil {
+			e.log.Error(err, "Unable to write the status history", "sink", sinkName(sink))
+		}
+	}
+}
+
+// sampleOf returns the sample of a node
+func sampleOf(node *blockchainv1alpha1.AxelarNode, now time.Time) Sample {
+	return Sample{
+		Time:       now,
+		Namespace:  node.Namespace,
+		Name:       node.Name,
+		Network:    node.Spec.Network,
+		NodeType:   node.Spec.NodeType,
+		Version:    node.Spec.Image.Tag,
+		Phase:      node.Status.Phase,
+		Height:     node.Status.SyncInfo.CurrentHeight,
+		CatchingUp: node.Status.SyncInfo.CatchingUp,
+		Peers:      node.Status.NetworkInfo.Peers,
+	}
+}
+
+// sinkName names a sink in logs
+func sinkName(sink Sink) string {
+	switch sink.(type) {
+	case *RemoteWrite:
+		return "remote-write"
+	case *ConfigMapRing:
+		return "configmap"
+	}
+	return "unknown"
+}
@@ -0,0 +1,149 @@
+package statushistory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+)
+
+// RemoteWrite sends samples to a Prometheus remote write endpoint, as the
+// axelar_node_status_* series labelled with the namespace, name, network,
+// node type, version and phase of the node
+type RemoteWrite struct {
+	url        string
+	tokenFile  string
+	httpClient *http.Client
+}
+
+// NewRemoteWrite creates a sink for the remote write endpoint at url
+func NewRemoteWrite(url, tokenFile string) *RemoteWrite {
+	return &RemoteWrite{url: url, tokenFile: tokenFile, httpClient: outbound.Client(30 * time.Second)}
+}
+
+// label is a label of a series
+type label struct {
+	name, value string
+}
+
+// series is a time series with a single sample
+type series struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// Write sends samples in a single remote write request
+func (w *RemoteWrite) Write(ctx context.Context, samples []Sample) error {
+	var all []series
+	for _, sample := range samples {
+		all = append(all, seriesOf(sample)...)
+	}
+	body := snappy.Encode(nil, encodeWriteRequest(all))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.tokenFile != "" {
+		token, err := os.ReadFile(w.tokenFile)
+		if err != nil {
+			return fmt.Errorf("unable to read the remote write token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote write returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// seriesOf returns the series of a sample
+func seriesOf(sample Sample) []series {
+	labels := []label{
+		{"namespace", sample.Namespace},
+		{"name", sample.Name},
+		{"network", sample.Network},
+		{"node_type", sample.NodeType},
+		{"version", sample.Version},
+		{"phase", sample.Phase},
+	}
+	up, catchingUp := 0.0, 0.0
+	if sample.Phase == "Running" {
+		up = 1
+	}
+	if sample.CatchingUp {
+		catchingUp = 1
+	}
+	timestamp := sample.Time.UnixMilli()
+	values := map[string]float64{
+		"axelar_node_status_height":      float64(sample.Height),
+		"axelar_node_status_peers":       float64(sample.Peers),
+		"axelar_node_status_running":     up,
+		"axelar_node_status_catching_up": catchingUp,
+	}
+	result := make([]series, 0, len(values))
+	for name, value := range values {
+		l := append([]label{{"__name__", name}}, labels...)
+		sort.Slice(l, func(i, j int) bool { return l[i].name < l[j].name })
+		result = append(result, series{labels: l, value: value, timestamp: timestamp})
+	}
+	return result
+}
+
+// encodeWriteRequest encodes a prometheus.WriteRequest:
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(all []series) []byte {
+	var out []byte
+	for _, s := range all {
+		var ts []byte
+		for _, l := range s.labels {
+			if l.value == "" {
+				continue
+			}
+			var lb []byte
+			lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.name)
+			lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, lb)
+		}
+		var sb []byte
+		sb = protowire.AppendTag(sb, 1, protowire.Fixed64Type)
+		sb = protowire.AppendFixed64(sb, math.Float64bits(s.value))
+		sb = protowire.AppendTag(sb, 2, protowire.VarintType)
+		sb = protowire.AppendVarint(sb, uint64(s.timestamp))
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, sb)
+
+		out = protowire.AppendTag(out, 1, protowire.BytesType)
+		out = protowire.AppendBytes(out, ts)
+	}
+	return out
+}
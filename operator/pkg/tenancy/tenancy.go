@@ -0,0 +1,69 @@
+// Package tenancy labels every object the operator writes with the tenant of
+// the resource being reconciled, so hosting providers can attribute and
+// isolate the infrastructure of each customer running on one operator.
+package tenancy
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	blockchainv1alpha1 "github.com/axelar-network/axelar-k8s-operator/pkg/apis/blockchain/v1alpha1"
+)
+
+type tenantKey struct{}
+
+// WithTenant labels the objects written with ctx with tenant. An empty tenant
+// leaves them unlabeled.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// Of returns the tenant obj belongs to, or an empty string
+func Of(obj client.Object) string {
+	return obj.GetLabels()[blockchainv1alpha1.TenantLabel]
+}
+
+// label sets the tenant carried by ctx on obj
+func label(ctx context.Context, obj client.Object) {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	if tenant == "" || Of(obj) == tenant {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[blockchainv1alpha1.TenantLabel] = tenant
+	obj.SetLabels(labels)
+}
+
+// tenantClient labels the objects written through the wrapped client
+type tenantClient struct {
+	client.Client
+}
+
+// Client wraps c so the objects it creates, updates and patches carry the
+// tenant of their context
+func Client(c client.Client) client.Client {
+	return &tenantClient{Client: c}
+}
+
+// Create labels obj and creates it
+func (c *tenantClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	label(ctx, obj)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// Update labels obj and updates it
+func (c *tenantClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	label(ctx, obj)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// Patch labels obj and patches it. Merge patches computed from obj include
+// the label.
+func (c *tenantClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	label(ctx, obj)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
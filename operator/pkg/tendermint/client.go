@@ -0,0 +1,191 @@
+package tendermint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultTimeout is the timeout applied to RPC requests
+const DefaultTimeout = 5 * time.Second
+
+// Client queries the Tendermint RPC of an Axelar node
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the RPC endpoint at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// StatusResult is the result of the /status endpoint
+type StatusResult struct {
+	NodeInfo      NodeInfo      `json:"node_info"`
+	SyncInfo      SyncInfo      `json:"sync_info"`
+	ValidatorInfo ValidatorInfo `json:"validator_info"`
+}
+
+// NodeInfo contains the node identity
+type NodeInfo struct {
+	ID      string `json:"id"`
+	Network string `json:"network"`
+	Version string `json:"version"`
+	Moniker string `json:"moniker"`
+}
+
+// SyncInfo contains the node sync state
+type SyncInfo struct {
+	LatestBlockHeight   string    `json:"latest_block_height"`
+	LatestBlockTime     time.Time `json:"latest_block_time"`
+	EarliestBlockHeight string    `json:"earliest_block_height"`
+	CatchingUp          bool      `json:"catching_up"`
+}
+
+// Height returns the latest block height as an integer
+func (s SyncInfo) Height() int64 {
+	h, _ := strconv.ParseInt(s.LatestBlockHeight, 10, 64)
+	return h
+}
+
+// EarliestHeight returns the earliest block height the node keeps as an integer
+func (s SyncInfo) EarliestHeight() int64 {
+	h, _ := strconv.ParseInt(s.EarliestBlockHeight, 10, 64)
+	return h
+}
+
+// ValidatorInfo contains the node validator key
+type ValidatorInfo struct {
+	Address     string `json:"address"`
+	VotingPower string `json:"voting_power"`
+}
+
+// NetInfoResult is the result of the /net_info endpoint
+type NetInfoResult struct {
+	Listening bool   `json:"listening"`
+	NPeers    string `json:"n_peers"`
+}
+
+// Peers returns the number of connected peers as an integer
+func (n NetInfoResult) Peers() int32 {
+	p, _ := strconv.ParseInt(n.NPeers, 10, 32)
+	return int32(p)
+}
+
+// ABCIInfoResult is the result of the /abci_info endpoint
+type ABCIInfoResult struct {
+	Response ABCIInfo `json:"response"`
+}
+
+// ABCIInfo describes the application behind the node
+type ABCIInfo struct {
+	Data            string `json:"data"`
+	Version         string `json:"version"`
+	LastBlockHeight string `json:"last_block_height"`
+	// LastBlockAppHash is the base64 app hash of the last committed state
+	LastBlockAppHash string `json:"last_block_app_hash"`
+}
+
+// BlockchainResult is the result of the /blockchain endpoint
+type BlockchainResult struct {
+	LastHeight string      `json:"last_height"`
+	BlockMetas []BlockMeta `json:"block_metas"`
+}
+
+// BlockMeta describes a block of the block store
+type BlockMeta struct {
+	BlockID struct {
+		Hash string `json:"hash"`
+	} `json:"block_id"`
+	Header Header `json:"header"`
+}
+
+// Header is a block header. Hashes are upper-case hex.
+type Header struct {
+	ChainID         string `json:"chain_id"`
+	Height          string `json:"height"`
+	AppHash         string `json:"app_hash"`
+	DataHash        string `json:"data_hash"`
+	LastResultsHash string `json:"last_results_hash"`
+	ValidatorsHash  string `json:"validators_hash"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// Status queries the /status endpoint
+func (c *Client) Status(ctx context.Context) (*StatusResult, error) {
+	result := &StatusResult{}
+	if err := c.call(ctx, "status", result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// NetInfo queries the /net_info endpoint
+func (c *Client) NetInfo(ctx context.Context) (*NetInfoResult, error) {
+	result := &NetInfoResult{}
+	if err := c.call(ctx, "net_info", result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ABCIInfo queries the /abci_info endpoint
+func (c *Client) ABCIInfo(ctx context.Context) (*ABCIInfoResult, error) {
+	result := &ABCIInfoResult{}
+	if err := c.call(ctx, "abci_info", result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Blockchain queries the metadata of the blocks between minHeight and
+// maxHeight, newest first. The node returns at most 20 blocks per call.
+func (c *Client) Blockchain(ctx context.Context, minHeight, maxHeight int64) (*BlockchainResult, error) {
+	result := &BlockchainResult{}
+	if err := c.call(ctx, fmt.Sprintf("blockchain?minHeight=%d&maxHeight=%d", minHeight, maxHeight), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// call performs a GET against the named RPC endpoint and decodes its result
+func (c *Client) call(ctx context.Context, method string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/"+method, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned HTTP %d", method, resp.StatusCode)
+	}
+
+	rpcResp := &rpcResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(rpcResp); err != nil {
+		return fmt.Errorf("decoding %s response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s failed: %s %s", method, rpcResp.Error.Message, rpcResp.Error.Data)
+	}
+	return json.Unmarshal(rpcResp.Result, result)
+}
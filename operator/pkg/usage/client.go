@@ -0,0 +1,117 @@
+// Package usage reads the resource usage history of containers from the
+// Prometheus HTTP API, to size the requests of nodes that ran out of memory
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/axelar-network/axelar-k8s-operator/pkg/outbound"
+)
+
+// DefaultTimeout is the timeout applied to Prometheus queries
+const DefaultTimeout = 10 * time.Second
+
+// Client queries the usage history of containers from Prometheus
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the Prometheus server at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: outbound.Client(DefaultTimeout),
+	}
+}
+
+// Container selects the series of one container of the pods with a name prefix
+type Container struct {
+	Namespace string
+	PodPrefix string
+	Name      string
+}
+
+// selector returns the label matchers of the container series
+func (c Container) selector() string {
+	return fmt.Sprintf(`namespace=%q,pod=~%q,container=%q`, c.Namespace, regexp.QuoteMeta(c.PodPrefix)+".*", c.Name)
+}
+
+// PeakMemory returns the highest working set of the container over window,
+// in bytes. ok is false when Prometheus has no samples for it.
+func (c *Client) PeakMemory(ctx context.Context, container Container, window time.Duration) (bytes float64, ok bool, err error) {
+	return c.scalar(ctx, fmt.Sprintf("max(max_over_time(container_memory_working_set_bytes{%s}[%s]))",
+		container.selector(), promDuration(window)))
+}
+
+// PeakCPU returns the highest CPU usage of the container over window, in
+// cores averaged over 5 minutes. ok is false when Prometheus has no samples
+// for it.
+func (c *Client) PeakCPU(ctx context.Context, container Container, window time.Duration) (cores float64, ok bool, err error) {
+	return c.scalar(ctx, fmt.Sprintf("max(max_over_time(rate(container_cpu_usage_seconds_total{%s}[5m])[%s:5m]))",
+		container.selector(), promDuration(window)))
+}
+
+// HTTPError is returned when Prometheus answers with a status other than 200 OK
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("prometheus returned HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// scalar runs an instant query returning at most one sample and returns its value
+func (c *Client) scalar(ctx context.Context, query string) (float64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.BaseURL+"/api/v1/query?"+url.Values{"query": {query}}.Encode(), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, false, &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	result := &struct {
+		Data struct {
+			Result []struct {
+				Value [2]interface{} `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return 0, false, fmt.Errorf("decoding query response: %w", err)
+	}
+	if len(result.Data.Result) == 0 {
+		return 0, false, nil
+	}
+	raw, _ := result.Data.Result[0].Value[1].(string)
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing sample %q: %w", raw, err)
+	}
+	return value, true, nil
+}
+
+// promDuration formats a duration as a Prometheus range
+func promDuration(d time.Duration) string {
+	if d < time.Minute {
+		d = time.Minute
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
@@ -0,0 +1,195 @@
+#!/bin/bash
+
+# End-to-end tests of the operator in a kind cluster. Nodes run the fake
+# axelard image of operator/cmd/fake-axelard, which serves the Tendermint RPC
+# of a node producing blocks, so no chain is synced.
+#
+# Usage: ./scripts/test-e2e.sh
+#
+#   CLUSTER_NAME   kind cluster to use, created when missing (axelar-e2e)
+#   KEEP_CLUSTER   keep the cluster after the tests when true (false)
+#   TIMEOUT        timeout of each wait (300s)
+
+set -e
+
+# Colors for output
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+YELLOW='\033[1;33m'
+BLUE='\033[0;34m'
+NC='\033[0m' # No Color
+
+CLUSTER_NAME="${CLUSTER_NAME:-axelar-e2e}"
+KEEP_CLUSTER="${KEEP_CLUSTER:-false}"
+TIMEOUT="${TIMEOUT:-300s}"
+
+ROOT_DIR="$(cd "$(dirname "$0")/.." && pwd)"
+OPERATOR_DIR="$ROOT_DIR/operator"
+OPERATOR_IMAGE="axelar-k8s-operator:e2e"
+FAKE_IMAGE="fake-axelard"
+NAMESPACE="axelar-e2e"
+NODE="fake-observer"
+
+print_status() {
+    echo -e "${BLUE}[INFO]${NC} $1"
+}
+
+print_success() {
+    echo -e "${GREEN}[SUCCESS]${NC} $1"
+}
+
+print_warning() {
+    echo -e "${YELLOW}[WARNING]${NC} $1"
+}
+
+print_error() {
+    echo -e "${RED}[ERROR]${NC} $1"
+}
+
+command_exists() {
+    command -v "$1" >/dev/null 2>&1
+}
+
+# fail prints the state of the node and the operator logs before exiting
+fail() {
+    print_error "$1"
+    kubectl get axelarnode "$NODE" -n "$NAMESPACE" -o yaml || true
+    kubectl get pods -n "$NAMESPACE" -o wide || true
+    kubectl logs -n axelar-operator-system deployment/axelar-operator --tail=100 || true
+    exit 1
+}
+
+# wait_for polls a jsonpath of the node until it prints the expected value
+wait_for() {
+    local jsonpath="$1" expected="$2" description="$3"
+    local deadline=$((SECONDS + ${TIMEOUT%s}))
+    print_status "Waiting for $description"
+    while [ $SECONDS -lt $deadline ]; do
+        if [ "$(kubectl get axelarnode "$NODE" -n "$NAMESPACE" -o jsonpath="$jsonpath" 2>/dev/null)" = "$expected" ]; then
+            print_success "$description"
+            return 0
+        fi
+        sleep 5
+    done
+    fail "Timed out waiting for $description"
+}
+
+check_prerequisites() {
+    print_status "Checking prerequisites..."
+    local missing_tools=()
+    for tool in kind kubectl docker; do
+        if ! command_exists "$tool"; then
+            missing_tools+=("$tool")
+        fi
+    done
+    if [ ${#missing_tools[@]} -ne 0 ]; then
+        print_error "Missing required tools: ${missing_tools[*]}"
+        exit 1
+    fi
+    print_success "Prerequisites check passed"
+}
+
+setup_cluster() {
+    if kind get clusters 2>/dev/null | grep -qx "$CLUSTER_NAME"; then
+        print_status "Using existing kind cluster $CLUSTER_NAME"
+    else
+        print_status "Creating kind cluster $CLUSTER_NAME..."
+        kind create cluster --name "$CLUSTER_NAME" --wait 120s
+    fi
+    kubectl config use-context "kind-$CLUSTER_NAME"
+}
+
+cleanup() {
+    if [ "$KEEP_CLUSTER" = "true" ]; then
+        print_warning "Keeping kind cluster $CLUSTER_NAME"
+        return
+    fi
+    print_status "Deleting kind cluster $CLUSTER_NAME..."
+    kind delete cluster --name "$CLUSTER_NAME" || true
+}
+
+build_images() {
+    print_status "Building the operator image..."
+    docker build -t "$OPERATOR_IMAGE" "$OPERATOR_DIR"
+
+    # Two versions of the fake node, to exercise upgrades
+    for version in v0.0.1-fake v0.0.2-fake; do
+        print_status "Building $FAKE_IMAGE:$version..."
+        docker build -f "$OPERATOR_DIR/Dockerfile.fake-axelard" --build-arg VERSION="$version" \
+            -t "$FAKE_IMAGE:$version" "$OPERATOR_DIR"
+    done
+
+    for image in "$OPERATOR_IMAGE" "$FAKE_IMAGE:v0.0.1-fake" "$FAKE_IMAGE:v0.0.2-fake"; do
+        kind load docker-image "$image" --name "$CLUSTER_NAME"
+    done
+    print_success "Images loaded into the cluster"
+}
+
+deploy_operator() {
+    print_status "Deploying the operator..."
+    kubectl apply -f "$OPERATOR_DIR/config/crd/"
+    kubectl apply -f "$OPERATOR_DIR/deploy/operator.yaml"
+    kubectl set image -n axelar-operator-system deployment/axelar-operator manager="$OPERATOR_IMAGE"
+    kubectl rollout status -n axelar-operator-system deployment/axelar-operator --timeout="$TIMEOUT" ||
+        fail "The operator did not start"
+    print_success "Operator running"
+}
+
+# test_sync checks the status the operator collects from the node RPC
+test_sync() {
+    print_status "Test 1: sync status"
+    kubectl apply -f "$ROOT_DIR/tests/e2e/fake-observer.yaml"
+    wait_for '{.status.phase}' "Running" "the node to run"
+    wait_for '{.status.conditions[?(@.type=="Synced")].status}' "True" "the node to report synced"
+
+    local height peers
+    height=$(kubectl get axelarnode "$NODE" -n "$NAMESPACE" -o jsonpath='{.status.syncInfo.currentHeight}')
+    peers=$(kubectl get axelarnode "$NODE" -n "$NAMESPACE" -o jsonpath='{.status.networkInfo.peers}')
+    [ "${height:-0}" -gt 0 ] || fail "Expected a block height in the status, got '$height'"
+    [ "$peers" = "4" ] || fail "Expected 4 peers in the status, got '$peers'"
+    print_success "Node synced at height $height with $peers peers"
+}
+
+# test_probes switches the readiness probe to the exec probe, which runs
+# axelard status in the node container
+test_probes() {
+    print_status "Test 2: probes"
+    kubectl patch axelarnode "$NODE" -n "$NAMESPACE" --type merge \
+        -p '{"spec":{"probes":{"readiness":{"type":"exec"}}}}'
+    local deadline=$((SECONDS + ${TIMEOUT%s}))
+    while [ $SECONDS -lt $deadline ]; do
+        if kubectl get deployment "$NODE" -n "$NAMESPACE" \
+            -o jsonpath='{.spec.template.spec.containers[0].readinessProbe.exec.command}' 2>/dev/null | grep -q "axelard status"; then
+            break
+        fi
+        sleep 5
+    done
+    kubectl rollout status deployment/"$NODE" -n "$NAMESPACE" --timeout="$TIMEOUT" ||
+        fail "The node did not become ready with the exec probe"
+    print_success "Node ready with the exec probe"
+}
+
+# test_upgrade moves the node to the next image and checks the upgrade history
+test_upgrade() {
+    print_status "Test 3: upgrade"
+    kubectl patch axelarnode "$NODE" -n "$NAMESPACE" --type merge \
+        -p '{"spec":{"image":{"tag":"v0.0.2-fake"}}}'
+    wait_for '{.status.upgrades[-1:].to}' "v0.0.2-fake" "the upgrade to be recorded"
+    wait_for '{.status.upgrades[-1:].outcome}' "Succeeded" "the upgrade to succeed"
+    wait_for '{.status.conditions[?(@.type=="Synced")].status}' "True" "the upgraded node to report synced"
+    print_success "Node upgraded to v0.0.2-fake"
+}
+
+main() {
+    check_prerequisites
+    setup_cluster
+    trap cleanup EXIT
+    build_images
+    deploy_operator
+    test_sync
+    test_probes
+    test_upgrade
+    print_success "All end-to-end tests passed"
+}
+
+main "$@"